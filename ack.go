@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ackErrorOutput records why the acknowledgment could not be awaited or shown.
+var ackErrorOutput = registerDegradation("ack_error", describeError("acknowledgment failed"))
+
+const (
+	// defaultAckTimeout is how long an error notification waits for an
+	// acknowledgment when error_ack_timeout is not set.
+	defaultAckTimeout = 2 * time.Minute
+	// ackButtonText labels the button added to error notifications.
+	ackButtonText = "✅ Acknowledge"
+	// ackCallbackData identifies presses of the acknowledge button.
+	ackCallbackData = "ack"
+	// ackedCallbackData is carried by the button once the error was
+	// acknowledged; later presses are ignored.
+	ackedCallbackData = "acked"
+)
+
+// errAcknowledged stops the update poller once the acknowledgment arrived.
+var errAcknowledged = errors.New("acknowledged")
+
+// acknowledgment records who acknowledged an error notification and when.
+type acknowledgment struct {
+	by string
+	at time.Time
+}
+
+// label is the button text shown once the error was acknowledged, e.g.
+// "✅ Ack'd by @alice at 14:02 UTC".
+func (a acknowledgment) label() string {
+	return fmt.Sprintf("✅ Ack'd by %s at %s", a.by, a.at.UTC().Format("15:04 UTC"))
+}
+
+// ackKeyboard returns the keyboard with the acknowledge button.
+func ackKeyboard() *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{
+		InlineKeyboard: [][]InlineKeyboardButton{{
+			{Text: ackButtonText, CallbackData: ackCallbackData},
+		}},
+	}
+}
+
+// awaitAck polls for a press of the acknowledge button on the error
+// notification messageID for up to error_ack_timeout. The first press is
+// answered, the button is replaced by who acknowledged the error and when,
+// and both are written to outputs. A timeout sets ack_timed_out; other
+// failures set ack_error. Neither fails the notification, which was already
+// sent.
+func (p *TelegramPlugin) awaitAck(ctx context.Context, cfg *Config, messageID int64, outputs map[string]any) {
+	timeout := cfg.ErrorAckTimeout
+	if timeout <= 0 {
+		timeout = defaultAckTimeout
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	poller := &updatePoller{
+		plugin:         p,
+		cfg:            cfg,
+		allowedUpdates: []string{"callback_query"},
+		// Long polls never outlast the wait, but stay at least a second so
+		// short waits do not spin.
+		timeout: max(min(defaultPollTimeout, timeout), time.Second),
+	}
+
+	var ack acknowledgment
+	err := poller.run(waitCtx, func(update TelegramUpdate) error {
+		if len(update.CallbackQuery) == 0 {
+			return nil
+		}
+		var query TelegramCallbackQuery
+		if err := json.Unmarshal(update.CallbackQuery, &query); err != nil {
+			return nil
+		}
+		if query.Data != ackCallbackData || query.Message == nil || query.Message.MessageID != messageID {
+			return nil
+		}
+		ack = acknowledgment{by: displayName(query.From), at: p.now()}
+		// Answering stops the loading indicator on the button; the
+		// acknowledgment stands even if it fails.
+		_ = p.answerCallbackQuery(ctx, cfg, query.ID, "Acknowledged")
+		return errAcknowledged
+	})
+
+	switch {
+	case errors.Is(err, errAcknowledged):
+		outputs["acknowledged_by"] = ack.by
+		outputs["acknowledged_at"] = ack.at.UTC().Format(time.RFC3339)
+		markup := &InlineKeyboardMarkup{
+			InlineKeyboard: [][]InlineKeyboardButton{{
+				{Text: ack.label(), CallbackData: ackedCallbackData},
+			}},
+		}
+		switch err := p.editMessageReplyMarkup(ctx, cfg, cfg.ChatID, messageID, markup); {
+		case err == nil:
+			outputs["edited"] = true
+		case isMessageNotModifiedError(err):
+			// The button already shows the acknowledgment, e.g. after a
+			// rerun of the hook; there is nothing to change.
+			outputs["edited"] = false
+		default:
+			outputs[ackErrorOutput] = errorText(err)
+		}
+	case errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+		outputs["ack_timed_out"] = true
+	default:
+		outputs[ackErrorOutput] = errorText(err)
+	}
+}
+
+// displayName returns how a user is shown in acknowledgments: the @username
+// when set, otherwise the first name or the numeric ID.
+func displayName(user TelegramUser) string {
+	switch {
+	case user.Username != "":
+		return "@" + user.Username
+	case user.FirstName != "":
+		return user.FirstName
+	default:
+		return strconv.FormatInt(user.ID, 10)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// callbackUpdate returns a getUpdates response with a button press.
+func callbackUpdate(id int64, query TelegramCallbackQuery) TelegramResponse {
+	raw, _ := json.Marshal(query)
+	result, _ := json.Marshal([]TelegramUpdate{{UpdateID: id, CallbackQuery: raw}})
+	return TelegramResponse{OK: true, Result: result}
+}
+
+func TestErrorNotificationAck(t *testing.T) {
+	var mu sync.Mutex
+	var sent TelegramMessage
+	var answered, edited map[string]any
+	polls := 0
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		switch method {
+		case "sendMessage":
+			_ = json.NewDecoder(r.Body).Decode(&sent)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":42}`)})
+		case "getUpdates":
+			polls++
+			var resp TelegramResponse
+			switch polls {
+			case 1:
+				// A press on another message is ignored.
+				resp = callbackUpdate(1, TelegramCallbackQuery{
+					ID:      "q1",
+					From:    TelegramUser{ID: 7, FirstName: "Bob"},
+					Message: &TelegramSentMessage{MessageID: 41},
+					Data:    ackCallbackData,
+				})
+			case 2:
+				resp = callbackUpdate(2, TelegramCallbackQuery{
+					ID:      "q2",
+					From:    TelegramUser{ID: 8, Username: "alice"},
+					Message: &TelegramSentMessage{MessageID: 42},
+					Data:    ackCallbackData,
+				})
+			default:
+				resp = updatesResult()
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case "answerCallbackQuery":
+			_ = json.NewDecoder(r.Body).Decode(&answered)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+		case "editMessageReplyMarkup":
+			_ = json.NewDecoder(r.Body).Decode(&edited)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+		default:
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+		}
+	})
+
+	p := &TelegramPlugin{clock: newFakeClock(time.Date(2024, 5, 1, 14, 2, 0, 0, time.UTC))}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookOnError,
+		Config:  map[string]any{"bot_token": "123:abc", "chat_id": "@test", "error_ack": true},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if sent.ReplyMarkup == nil || sent.ReplyMarkup.InlineKeyboard[0][0].CallbackData != ackCallbackData {
+		t.Errorf("reply markup = %+v, want the acknowledge button", sent.ReplyMarkup)
+	}
+	if answered["callback_query_id"] != "q2" {
+		t.Errorf("answered = %v, want q2", answered)
+	}
+	if resp.Outputs["acknowledged_by"] != "@alice" || resp.Outputs["acknowledged_at"] != "2024-05-01T14:02:00Z" || resp.Outputs["edited"] != true {
+		t.Errorf("outputs = %v, want the acknowledgment", resp.Outputs)
+	}
+
+	markup, _ := json.Marshal(edited["reply_markup"])
+	if edited["message_id"] != float64(42) || !strings.Contains(string(markup), "Ack'd by @alice at 14:02 UTC") {
+		t.Errorf("edited = %v, want the button replaced", edited)
+	}
+}
+
+func TestErrorNotificationAckNotModified(t *testing.T) {
+	polls := 0
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:] {
+		case "sendMessage":
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":42}`)})
+		case "getUpdates":
+			polls++
+			resp := updatesResult()
+			if polls == 1 {
+				resp = callbackUpdate(1, TelegramCallbackQuery{
+					ID:      "q1",
+					From:    TelegramUser{ID: 8, Username: "alice"},
+					Message: &TelegramSentMessage{MessageID: 42},
+					Data:    ackCallbackData,
+				})
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case "editMessageReplyMarkup":
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{
+				OK:          false,
+				ErrorCode:   400,
+				Description: "Bad Request: message is not modified: specified new message content and reply markup are exactly the same as a current content and reply markup of the message",
+			})
+		default:
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+		}
+	})
+
+	p := &TelegramPlugin{clock: newFakeClock(time.Date(2024, 5, 1, 14, 2, 0, 0, time.UTC))}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookOnError,
+		Config:  map[string]any{"bot_token": "123:abc", "chat_id": "@test", "error_ack": true},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if resp.Outputs["edited"] != false || resp.Outputs["ack_error"] != nil {
+		t.Errorf("outputs = %v, want edited false without ack_error", resp.Outputs)
+	}
+}
+
+func TestErrorNotificationAckTimeout(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":42}`)})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(updatesResult())
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookOnError,
+		Config: map[string]any{
+			"bot_token":         "123:abc",
+			"chat_id":           "@test",
+			"error_ack":         true,
+			"error_ack_timeout": "50ms",
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if resp.Outputs["ack_timed_out"] != true {
+		t.Errorf("outputs = %v, want ack_timed_out", resp.Outputs)
+	}
+	if _, ok := resp.Outputs["acknowledged_by"]; ok {
+		t.Errorf("outputs = %v, want no acknowledgment", resp.Outputs)
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct {
+		user     TelegramUser
+		expected string
+	}{
+		{TelegramUser{ID: 1, FirstName: "Alice", Username: "alice"}, "@alice"},
+		{TelegramUser{ID: 1, FirstName: "Alice"}, "Alice"},
+		{TelegramUser{ID: 1}, "1"},
+	}
+
+	for _, tt := range tests {
+		if got := displayName(tt.user); got != tt.expected {
+			t.Errorf("displayName(%+v) = %q, want %q", tt.user, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// breakingAlertErrorOutput records why the breaking changes alert was not sent.
+var breakingAlertErrorOutput = registerDegradation("breaking_alert_error", describeError("breaking changes alert failed"))
+
+// breakingAlertConfig returns cfg retargeted to the breaking changes alert
+// chat. Without a dedicated alert chat the alert goes to the configured chat.
+func breakingAlertConfig(cfg *Config) *Config {
+	alertCfg := *cfg
+	if cfg.BreakingAlertChatID != "" {
+		alertCfg.ChatID = cfg.BreakingAlertChatID
+		alertCfg.MessageThreadID = cfg.BreakingAlertThreadID
+	}
+	alertCfg.DisableNotification = false // Alerts are always loud
+	return &alertCfg
+}
+
+// sendBreakingAlert sends the separate breaking changes alert when it is
+// enabled and the release has breaking changes, recording the result in
+// outputs. A failed alert does not fail the hook: the announcement itself
+// was already delivered.
+func (p *TelegramPlugin) sendBreakingAlert(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, outputs map[string]any) {
+	if !cfg.BreakingAlert || releaseCtx.Changes == nil || len(releaseCtx.Changes.Breaking) == 0 {
+		return
+	}
+
+	alertCfg := breakingAlertConfig(cfg)
+	outputs["breaking_alert_chat_id"] = alertCfg.ChatID
+	if dryRun {
+		return
+	}
+
+	msg := newMessage(alertCfg, p.buildBreakingAlertMessage(alertCfg, releaseCtx))
+	fallbacks := []deliveryFallback{{
+		name: fallbackMinimalPlainText,
+		text: fmt.Sprintf("🚨 Release %s has %d breaking changes", releaseCtx.Version, len(releaseCtx.Changes.Breaking)),
+	}}
+	sent, err := p.deliverWithFallbacks(ctx, alertCfg, msg, fallbacks)
+	recordDelivery(outputs, "breaking changes alert", alertCfg.ChatID, err)
+	recordPermalink(outputs, "breaking changes alert", alertCfg.ChatID, alertCfg.MessageThreadID, sent.messageID)
+	if err != nil {
+		outputs[breakingAlertErrorOutput] = errorText(err)
+		return
+	}
+	outputs["breaking_alert_sent"] = true
+}
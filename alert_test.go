@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteBreakingAlert(t *testing.T) {
+	var sent []TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		sent = append(sent, msg)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	breaking := &plugin.CategorizedChanges{
+		Breaking: []plugin.ConventionalCommit{{Description: "drop v1"}},
+	}
+	tests := []struct {
+		name      string
+		config    map[string]any
+		changes   *plugin.CategorizedChanges
+		wantSent  int
+		alertChat string
+	}{
+		{
+			name:     "disabled",
+			config:   map[string]any{},
+			changes:  breaking,
+			wantSent: 1,
+		},
+		{
+			name:     "no breaking changes",
+			config:   map[string]any{"breaking_alert": true},
+			changes:  &plugin.CategorizedChanges{},
+			wantSent: 1,
+		},
+		{
+			name:      "same chat",
+			config:    map[string]any{"breaking_alert": true},
+			changes:   breaking,
+			wantSent:  2,
+			alertChat: "@test",
+		},
+		{
+			name: "dedicated chat",
+			config: map[string]any{
+				"breaking_alert":         true,
+				"breaking_alert_chat_id": "https://t.me/c/1234567890/42",
+			},
+			changes:   breaking,
+			wantSent:  2,
+			alertChat: "-1001234567890",
+		},
+	}
+
+	p := &TelegramPlugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent = nil
+			config := map[string]any{
+				"bot_token":            "123:abc",
+				"chat_id":              "@test",
+				"disable_notification": true,
+			}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "2.0.0", Changes: tt.changes},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v", resp, err)
+			}
+			if len(sent) != tt.wantSent {
+				t.Fatalf("sent %d messages, want %d", len(sent), tt.wantSent)
+			}
+			if tt.alertChat == "" {
+				return
+			}
+
+			alert := sent[1]
+			if alert.ChatID != tt.alertChat || alert.DisableNotification {
+				t.Errorf("alert = %+v, want loud message to %s", alert, tt.alertChat)
+			}
+			if !strings.Contains(alert.Text, "drop v1") {
+				t.Errorf("alert text = %q, want breaking change", alert.Text)
+			}
+			if resp.Outputs["breaking_alert_sent"] != true {
+				t.Errorf("Outputs = %v, want breaking_alert_sent", resp.Outputs)
+			}
+		})
+	}
+}
+
+func TestBreakingAlertConfig(t *testing.T) {
+	cfg := &Config{ChatID: "@news", MessageThreadID: 3, DisableNotification: true}
+	got := breakingAlertConfig(cfg)
+	if got.ChatID != "@news" || got.MessageThreadID != 3 || got.DisableNotification {
+		t.Errorf("breakingAlertConfig() = %+v, want loud alert to the same thread", got)
+	}
+
+	cfg.BreakingAlertChatID = "@alerts"
+	got = breakingAlertConfig(cfg)
+	if got.ChatID != "@alerts" || got.MessageThreadID != 0 {
+		t.Errorf("breakingAlertConfig() = %+v, want alert chat without the announcement thread", got)
+	}
+}
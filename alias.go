@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// chatAliasPattern matches chat alias names.
+var chatAliasPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// Options holding chats that may reference a chat alias, by their shape.
+var (
+	chatKeys     = []string{"chat_id", "error_chat_id", "breaking_alert_chat_id", "summary_chat_id"}
+	chatListKeys = []string{"chat_ids", "forward_to_chat_ids"}
+)
+
+// unknownChatAlias is a reference to a name that is not declared in chats.
+type unknownChatAlias struct {
+	// Key is the option holding the reference.
+	Key string
+	// Name is the referenced name.
+	Name string
+}
+
+// resolveChatAliases returns a copy of raw with the names declared in the
+// chats map replaced by their chats wherever a chat is configured: chat_id,
+// chat_ids, error_chat_id, hook_chat_ids, route_by_release_type, targets,
+// forward_to_chat_ids, breaking_alert_chat_id, and summary_chat_id. When
+// aliases are declared, it also returns the bare names that are not, since
+// those are more likely a misspelled alias than a username without the @.
+func resolveChatAliases(raw map[string]any) (map[string]any, []unknownChatAlias) {
+	aliases := parseStringMap(raw["chats"])
+	if len(aliases) == 0 {
+		return raw, nil
+	}
+
+	var unknown []unknownChatAlias
+	resolved := maps.Clone(raw)
+	resolve := func(key string, v any) any {
+		name, ok := v.(string)
+		if !ok {
+			return v
+		}
+		name = strings.TrimSpace(name)
+		if chat, ok := aliases[name]; ok {
+			return chat
+		}
+		if bareUsernamePattern.MatchString(name) {
+			unknown = append(unknown, unknownChatAlias{Key: key, Name: name})
+		}
+		return v
+	}
+	resolveList := func(key string, v any) any {
+		items, ok := v.([]any)
+		if !ok {
+			return resolve(key, v)
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			out[i] = resolve(fmt.Sprintf("%s[%d]", key, i), item)
+		}
+		return out
+	}
+
+	for _, key := range chatKeys {
+		if v, ok := raw[key]; ok {
+			resolved[key] = resolve(key, v)
+		}
+	}
+	for _, key := range chatListKeys {
+		if v, ok := raw[key]; ok {
+			resolved[key] = resolveList(key, v)
+		}
+	}
+	for _, key := range []string{"hook_chat_ids", "route_by_release_type"} {
+		m, ok := raw[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		out := make(map[string]any, len(m))
+		for _, name := range slices.Sorted(maps.Keys(m)) {
+			out[name] = resolveList(key+"."+name, m[name])
+		}
+		resolved[key] = out
+	}
+	if targets, ok := raw["targets"].([]any); ok {
+		out := make([]any, len(targets))
+		for i, item := range targets {
+			target, ok := item.(map[string]any)
+			if !ok || target["chat_id"] == nil {
+				out[i] = item
+				continue
+			}
+			target = maps.Clone(target)
+			target["chat_id"] = resolve(fmt.Sprintf("targets[%d].chat_id", i), target["chat_id"])
+			out[i] = target
+		}
+		resolved["targets"] = out
+	}
+	return resolved, unknown
+}
+
+// validateChatAliases reports the first problem with the chats map: an
+// invalid alias name or chat.
+func validateChatAliases(v any) error {
+	if err := validateStringMap(v); err != nil {
+		return err
+	}
+	aliases, _ := v.(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(aliases)) {
+		if !chatAliasPattern.MatchString(name) {
+			return fmt.Errorf("alias %q must start with a letter and hold only letters, digits, underscores, and hyphens", name)
+		}
+		chat, ok := aliases[name].(string)
+		if !ok {
+			return fmt.Errorf("alias %q must be a chat ID", name)
+		}
+		resolved, _ := resolveChatID(chat)
+		if err := validateChatID(resolved); err != nil {
+			return fmt.Errorf("alias %q: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestResolveChatAliases(t *testing.T) {
+	raw := map[string]any{
+		"chats": map[string]any{
+			"announcements": "-1001234567890",
+			"ops":           "@myops_team",
+			"beta":          "-1009876543210@7",
+		},
+		"chat_id":       "announcements",
+		"chat_ids":      []any{"announcements", "@mirror_chat"},
+		"error_chat_id": "ops",
+		"hook_chat_ids": map[string]any{"post_version": "beta"},
+		"route_by_release_type": map[string]any{
+			"major":      []any{"announcements", "ops"},
+			"prerelease": "beta",
+		},
+		"targets":             []any{map[string]any{"chat_id": "ops", "bot_token_env": "OPS_BOT_TOKEN"}},
+		"forward_to_chat_ids": []any{"opz"},
+		"summary_chat_id":     "ops",
+		"template":            "ops",
+	}
+
+	got, unknown := resolveChatAliases(raw)
+	want := map[string]any{
+		"chats":         raw["chats"],
+		"chat_id":       "-1001234567890",
+		"chat_ids":      []any{"-1001234567890", "@mirror_chat"},
+		"error_chat_id": "@myops_team",
+		"hook_chat_ids": map[string]any{"post_version": "-1009876543210@7"},
+		"route_by_release_type": map[string]any{
+			"major":      []any{"-1001234567890", "@myops_team"},
+			"prerelease": "-1009876543210@7",
+		},
+		"targets":             []any{map[string]any{"chat_id": "@myops_team", "bot_token_env": "OPS_BOT_TOKEN"}},
+		"forward_to_chat_ids": []any{"opz"},
+		"summary_chat_id":     "@myops_team",
+		"template":            "ops",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveChatAliases() = %v, want %v", got, want)
+	}
+	if want := []unknownChatAlias{{Key: "forward_to_chat_ids[0]", Name: "opz"}}; !reflect.DeepEqual(unknown, want) {
+		t.Errorf("unknown aliases = %v, want %v", unknown, want)
+	}
+	if raw["chat_id"] != "announcements" {
+		t.Error("expected the config to be left unchanged")
+	}
+
+	if got, unknown := resolveChatAliases(map[string]any{"chat_id": "myproject"}); got["chat_id"] != "myproject" || unknown != nil {
+		t.Errorf("without aliases = %v, %v; want the config as is", got, unknown)
+	}
+}
+
+func TestValidateChatAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr bool
+	}{
+		{"unset", nil, false},
+		{"valid", map[string]any{"ops": "@myops_team", "release-notes": "https://t.me/c/1234567890/5"}, false},
+		{"not a map", "ops", true},
+		{"invalid name", map[string]any{"1st": "@myops_team"}, true},
+		{"invalid chat", map[string]any{"ops": "not a chat"}, true},
+		{"not a string", map[string]any{"ops": true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateChatAliases(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("validateChatAliases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteChatAliases(t *testing.T) {
+	var sent []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		sent = append(sent, msg.ChatID)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	config := map[string]any{
+		"bot_token":     "123:abc",
+		"chats":         map[string]any{"announcements": "-1001234567890", "ops": "@myops_team"},
+		"chat_id":       "announcements",
+		"error_chat_id": "ops",
+	}
+	for _, hook := range []plugin.Hook{plugin.HookPostPublish, plugin.HookOnError} {
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook:    hook,
+			Config:  config,
+			Context: plugin.ReleaseContext{Version: "1.0.0"},
+		})
+		if err != nil || !resp.Success {
+			t.Fatalf("Execute(%s) = %+v, %v; want success", hook, resp, err)
+		}
+	}
+	if want := []string{"-1001234567890", "@myops_team"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errCircuitOpen is returned when the circuit breaker skips a send.
+var errCircuitOpen = errors.New("circuit breaker open")
+
+// circuitBreakerSkippedOutput records the chats the open circuit breaker
+// skipped.
+var circuitBreakerSkippedOutput = registerDegradation("circuit_breaker_skipped", func(v any) []string {
+	chats, _ := v.([]string)
+	return []string{"circuit breaker open, skipped " + strings.Join(chats, ", ")}
+})
+
+// circuitBreaker trips once more than threshold API errors are recorded
+// within window. While tripped, sends are skipped until the errors age out
+// of the window, protecting the bot token during pipeline retry storms.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	errors    []time.Time
+}
+
+// newCircuitBreaker creates a circuit breaker. A threshold of zero or less
+// disables it.
+func newCircuitBreaker(threshold int, window time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, window: window}
+}
+
+// recordError records err at now when it is a Telegram API error. Network
+// failures do not count: they say nothing about how the bot is using the
+// API.
+func (b *circuitBreaker) recordError(err error, now time.Time) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.errors = append(b.errors, now)
+	b.prune(now)
+}
+
+// check returns an error describing the open circuit if sends should be
+// skipped at now.
+func (b *circuitBreaker) check(now time.Time) error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prune(now)
+	if len(b.errors) > b.threshold {
+		return fmt.Errorf("%w: %d Telegram API errors within %s, skipping send", errCircuitOpen, len(b.errors), b.window)
+	}
+	return nil
+}
+
+// prune drops errors older than the window. Callers must hold b.mu.
+func (b *circuitBreaker) prune(now time.Time) {
+	cutoff := now.Add(-b.window)
+	i := 0
+	for i < len(b.errors) && !b.errors[i].After(cutoff) {
+		i++
+	}
+	b.errors = b.errors[i:]
+}
+
+// breakerKey identifies the circuit breaker of a bot and breaker settings.
+type breakerKey struct {
+	// bot is the bot token of a target with its own bot, or empty.
+	bot       string
+	threshold int
+	window    time.Duration
+}
+
+// recordCircuitSkip reports whether err is a send skipped by the open
+// circuit breaker, and if so records chatID in
+// outputs["circuit_breaker_skipped"]. The skipped chats are reported as one
+// circuit breaker failure rather than a failure each.
+func recordCircuitSkip(outputs map[string]any, chatID string, err error) bool {
+	if !errors.Is(err, errCircuitOpen) {
+		return false
+	}
+	outputs["circuit_breaker_open"] = true
+	skipped, _ := outputs[circuitBreakerSkippedOutput].([]string)
+	outputs[circuitBreakerSkippedOutput] = append(skipped, chatID)
+	return true
+}
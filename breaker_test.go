@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(2, time.Minute)
+	apiErr := &APIError{Code: 500, Description: "Internal Server Error"}
+
+	for i := 0; i < 2; i++ {
+		b.recordError(apiErr, start)
+		if err := b.check(start); err != nil {
+			t.Fatalf("check() after %d errors = %v, want nil", i+1, err)
+		}
+	}
+
+	b.recordError(apiErr, start.Add(10*time.Second))
+	if err := b.check(start.Add(10 * time.Second)); err == nil {
+		t.Fatal("check() after exceeding threshold = nil, want error")
+	}
+
+	// The first two errors age out of the window.
+	if err := b.check(start.Add(61 * time.Second)); err != nil {
+		t.Errorf("check() after window = %v, want nil", err)
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(0, time.Minute)
+	for i := 0; i < 10; i++ {
+		b.recordError(&APIError{Code: 500}, now)
+	}
+	if err := b.check(now); err != nil {
+		t.Errorf("check() on disabled breaker = %v, want nil", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresNetworkErrors(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(1, time.Minute)
+	for i := 0; i < 3; i++ {
+		b.recordError(errors.New("failed to send request: EOF"), now)
+	}
+	if err := b.check(now); err != nil {
+		t.Errorf("check() after network errors = %v, want nil", err)
+	}
+}
+
+func TestCircuitBreakerPerConfig(t *testing.T) {
+	p := &TelegramPlugin{}
+	cfg := &Config{CircuitBreakerThreshold: 1, CircuitBreakerWindowSeconds: 60}
+	if p.circuitBreaker(cfg) != p.circuitBreaker(&Config{CircuitBreakerThreshold: 1, CircuitBreakerWindowSeconds: 60}) {
+		t.Error("expected the same breaker for the same settings")
+	}
+	b := p.circuitBreaker(&Config{CircuitBreakerThreshold: 5, CircuitBreakerWindowSeconds: 60})
+	if b == p.circuitBreaker(cfg) || b.threshold != 5 {
+		t.Errorf("breaker for threshold 5 = %+v, want a breaker of its own", b)
+	}
+}
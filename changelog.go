@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// changelogThreadErrorOutput records why the changelog root could not be
+// posted or pinned.
+var changelogThreadErrorOutput = registerDegradation("changelog_thread_error", describeError("changelog thread unavailable"))
+
+// changelogRootKey identifies the changelog root message of a chat thread
+// within the state file.
+func changelogRootKey(chatID string, threadID int64) string {
+	return strings.Join([]string{"changelog", chatID, strconv.FormatInt(threadID, 10)}, "|")
+}
+
+// changelogRoot returns the pinned root message that success announcements
+// reply to. It is looked up in the state file and posted and pinned on first
+// use. A root that was posted but could not be pinned is still returned,
+// together with the pinning error. In dry-run mode nothing is posted and 0
+// is returned for a root that does not exist yet.
+func (p *TelegramPlugin) changelogRoot(ctx context.Context, cfg *Config, dryRun bool) (int64, error) {
+	key := changelogRootKey(cfg.ChatID, cfg.MessageThreadID)
+	state, err := loadState(cfg.StateFile)
+	if err != nil {
+		return 0, err
+	}
+	if id, ok := state.ChangelogRoots[key]; ok {
+		return id, nil
+	}
+	if dryRun {
+		return 0, nil
+	}
+
+	root, err := p.postMessage(ctx, cfg, TelegramMessage{
+		ChatID:              cfg.ChatID,
+		Text:                cfg.ChangelogThreadTitle,
+		MessageThreadID:     cfg.MessageThreadID,
+		DisableNotification: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to post changelog root message: %w", err)
+	}
+	// The root exists now, so reply to it even if it could not be remembered.
+	_ = p.updateState(cfg.StateFile, func(s *pluginState) {
+		if s.ChangelogRoots == nil {
+			s.ChangelogRoots = make(map[string]int64)
+		}
+		s.ChangelogRoots[key] = root.MessageID
+	})
+
+	if err := p.pinChatMessage(ctx, cfg, cfg.ChatID, root.MessageID); err != nil {
+		return root.MessageID, fmt.Errorf("failed to pin changelog root message: %w", err)
+	}
+	return root.MessageID, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestChangelogRoot(t *testing.T) {
+	var posted, pinned int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			posted++
+			var msg TelegramMessage
+			_ = json.NewDecoder(r.Body).Decode(&msg)
+			if msg.Text != "📜 Changelog" || !msg.DisableNotification {
+				t.Errorf("root message = %+v, want silent changelog title", msg)
+			}
+			result, _ := json.Marshal(TelegramSentMessage{MessageID: 42})
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+		case strings.HasSuffix(r.URL.Path, "/pinChatMessage"):
+			pinned++
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+		default:
+			t.Errorf("unexpected call to %s", r.URL.Path)
+		}
+	})
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	tests := []struct {
+		name     string
+		threadID int64
+		dryRun   bool
+		expected int64
+		posted   int
+	}{
+		{"dry run does not post", 0, true, 0, 0},
+		{"posted on first use", 0, false, 42, 1},
+		{"remembered", 0, false, 42, 1},
+		{"remembered in dry run", 0, true, 42, 1},
+		{"separate root per thread", 7, false, 42, 2},
+	}
+
+	p := &TelegramPlugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				BotToken:             "123:abc",
+				ChatID:               "-1001234567890",
+				MessageThreadID:      tt.threadID,
+				ChangelogThreadTitle: "📜 Changelog",
+				StateFile:            stateFile,
+			}
+			got, err := p.changelogRoot(context.Background(), &cfg, tt.dryRun)
+			if err != nil {
+				t.Fatalf("changelogRoot() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("changelogRoot() = %d, want %d", got, tt.expected)
+			}
+			if posted != tt.posted || pinned != tt.posted {
+				t.Errorf("posted %d and pinned %d roots, want %d", posted, pinned, tt.posted)
+			}
+		})
+	}
+}
+
+func TestExecuteChangelogThread(t *testing.T) {
+	var announcement TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/pinChatMessage") {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: not enough rights to pin a message"})
+			return
+		}
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg.ReplyParameters != nil {
+			announcement = msg
+		}
+		result, _ := json.Marshal(TelegramSentMessage{MessageID: 42})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":        "123:abc",
+			"chat_id":          "-1001234567890",
+			"changelog_thread": true,
+			"state_file":       filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+	if announcement.ReplyParameters == nil || announcement.ReplyParameters.MessageID != 42 {
+		t.Errorf("announcement reply parameters = %+v, want reply to 42", announcement.ReplyParameters)
+	}
+	if resp.Outputs["reply_to_message_id"] != int64(42) {
+		t.Errorf("reply_to_message_id = %v, want 42", resp.Outputs["reply_to_message_id"])
+	}
+	if errMsg, _ := resp.Outputs["changelog_thread_error"].(string); !strings.Contains(errMsg, "failed to pin") {
+		t.Errorf("changelog_thread_error = %q, want pinning error", errMsg)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// numericChatIDPattern matches numeric user, group, and channel IDs.
+	numericChatIDPattern = regexp.MustCompile(`^-?\d+$`)
+	// usernamePattern matches public chat usernames: 5-32 characters of
+	// letters, digits, and underscores, starting with a letter.
+	usernamePattern = regexp.MustCompile(`^@[A-Za-z][A-Za-z0-9_]{4,31}$`)
+	// bareUsernamePattern matches a username configured without the @ prefix.
+	bareUsernamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+// resolveChatID turns a configured chat identifier into a chat ID and an
+// optional thread ID. Besides plain IDs and usernames it accepts the t.me
+// links users copy from the Telegram apps:
+//
+//	https://t.me/mychannel          -> @mychannel
+//	https://t.me/mychannel/123      -> @mychannel
+//	https://t.me/c/1234567890/5     -> -1001234567890, thread 5
+//	https://t.me/c/1234567890/5/10  -> -1001234567890, thread 5
+//	https://t.me/c/1234567890/10?thread=5 -> -1001234567890, thread 5
+//
+// A chat ID or username may also name a topic with the chat_id@thread
+// shorthand:
+//
+//	-1001234567890@42      -> -1001234567890, thread 42
+//	@mygroup@42            -> @mygroup, thread 42
+//	-1001234567890@general -> -1001234567890, the General topic
+//
+// Values that are not recognized links are normalized and returned as-is
+// for validation.
+func resolveChatID(value string) (string, int64) {
+	value = strings.TrimSpace(value)
+	if chatID, threadID, ok := parseChatLink(value); ok {
+		return chatID, threadID
+	}
+	if chatID, threadID, ok := parseThreadSuffix(value); ok {
+		return normalizeChatID(chatID), threadID
+	}
+	return normalizeChatID(value), 0
+}
+
+// parseThreadSuffix splits the chat_id@thread shorthand into the chat and a
+// positive thread ID.
+func parseThreadSuffix(value string) (string, int64, bool) {
+	i := strings.LastIndex(value, "@")
+	if i > 0 && strings.EqualFold(value[i+1:], generalTopic) {
+		return value[:i], generalTopicThreadID, true
+	}
+	if i <= 0 || !isDigits(value[i+1:]) {
+		return "", 0, false
+	}
+	threadID, err := strconv.ParseInt(value[i+1:], 10, 64)
+	if err != nil || threadID == 0 {
+		return "", 0, false
+	}
+	return value[:i], threadID, true
+}
+
+const (
+	// generalTopic is the thread ID value naming the General topic of a
+	// forum.
+	generalTopic = "general"
+	// generalTopicThreadID is the thread ID of the General topic. The Bot
+	// API rejects it as message_thread_id, so messages to the General topic
+	// are sent without one; see wireThreadID.
+	generalTopicThreadID = 1
+)
+
+// threadIDKeys lists the options holding forum topic thread IDs.
+var threadIDKeys = []string{
+	"message_thread_id",
+	"error_message_thread_id",
+	"breaking_alert_thread_id",
+	"summary_thread_id",
+}
+
+// parseThreadID reads a configured thread ID. Besides numbers it accepts
+// numeric strings such as "42", which is how IDs pasted into YAML or set
+// through environment variables usually arrive, and "general" for the
+// General topic. An unset or empty value is 0, meaning no thread. The sign is not checked; Validate reports negative
+// IDs.
+func parseThreadID(v any) (int64, error) {
+	switch val := v.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case float64:
+		if val != math.Trunc(val) || math.Abs(val) > math.MaxInt64 {
+			return 0, fmt.Errorf("%v is not a whole number", val)
+		}
+		return int64(val), nil
+	case string:
+		val = strings.TrimSpace(val)
+		if val == "" {
+			return 0, nil
+		}
+		if strings.EqualFold(val, generalTopic) {
+			return generalTopicThreadID, nil
+		}
+		id, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a numeric thread ID", val)
+		}
+		return id, nil
+	default:
+		return 0, fmt.Errorf("must be a numeric thread ID")
+	}
+}
+
+// wireThreadID returns the message_thread_id to send for threadID: 0, so
+// the field is omitted, for the General topic. Messages sent to a forum
+// without a thread land in the General topic.
+func wireThreadID(threadID int64) int64 {
+	if threadID == generalTopicThreadID {
+		return 0
+	}
+	return threadID
+}
+
+// withoutGeneralTopic returns payload without message_thread_id when it
+// names the General topic.
+func withoutGeneralTopic(payload map[string]any) map[string]any {
+	if fmt.Sprint(payload["message_thread_id"]) != strconv.Itoa(generalTopicThreadID) {
+		return payload
+	}
+	payload = maps.Clone(payload)
+	delete(payload, "message_thread_id")
+	return payload
+}
+
+// parseChatLink parses a t.me or telegram.me chat link.
+func parseChatLink(link string) (string, int64, bool) {
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", 0, false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	if host != "t.me" && host != "telegram.me" {
+		return "", 0, false
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		return "", 0, false
+	}
+
+	if parts[0] == "c" {
+		// Private chat link: /c/<internal id>[/<thread or message>[/<message>]]
+		if len(parts) < 2 || !isDigits(parts[1]) {
+			return "", 0, false
+		}
+		chatID := "-100" + parts[1]
+
+		var threadID int64
+		if t := u.Query().Get("thread"); t != "" {
+			threadID, _ = strconv.ParseInt(t, 10, 64)
+		} else if len(parts) >= 3 {
+			threadID, _ = strconv.ParseInt(parts[2], 10, 64)
+		}
+		return chatID, threadID, true
+	}
+
+	// Invite links cannot be resolved to a chat ID.
+	if strings.HasPrefix(parts[0], "+") || parts[0] == "joinchat" {
+		return "", 0, false
+	}
+	if !bareUsernamePattern.MatchString(parts[0]) {
+		return "", 0, false
+	}
+	return "@" + parts[0], 0, true
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeChatID trims whitespace and prepends @ to bare usernames such as
+// "mychannel". Other values are returned unchanged for validation.
+func normalizeChatID(chatID string) string {
+	chatID = strings.TrimSpace(chatID)
+	if bareUsernamePattern.MatchString(chatID) {
+		return "@" + chatID
+	}
+	return chatID
+}
+
+// validateChatID validates a normalized chat ID.
+func validateChatID(chatID string) error {
+	if numericChatIDPattern.MatchString(chatID) {
+		return nil
+	}
+
+	lower := strings.ToLower(chatID)
+	if strings.Contains(lower, "t.me/+") || strings.Contains(lower, "/joinchat/") {
+		return fmt.Errorf("chat ID %q is an invite link; use the @username or the numeric chat ID instead", chatID)
+	}
+	if strings.Contains(lower, "://") || strings.HasPrefix(lower, "t.me/") || strings.HasPrefix(lower, "telegram.me/") {
+		return fmt.Errorf("chat ID %q is not a recognized chat link; use a t.me link, the @username, or the numeric chat ID", chatID)
+	}
+
+	if !strings.HasPrefix(chatID, "@") {
+		return fmt.Errorf("chat ID %q must be a numeric ID or an @username", chatID)
+	}
+	if !usernamePattern.MatchString(chatID) {
+		return fmt.Errorf("invalid username %q: usernames are 5-32 characters of letters, digits, and underscores, starting with a letter", chatID)
+	}
+	return nil
+}
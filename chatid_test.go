@@ -0,0 +1,122 @@
+package main
+
+import "testing"
+
+func TestNormalizeChatID(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"mychannel", "@mychannel"},
+		{" @mychannel ", "@mychannel"},
+		{"-1001234567890", "-1001234567890"},
+		{"123456", "123456"},
+		{"https://t.me/mychannel", "https://t.me/mychannel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := normalizeChatID(tt.input); got != tt.expected {
+				t.Errorf("normalizeChatID(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateChatID(t *testing.T) {
+	tests := []struct {
+		name    string
+		chatID  string
+		wantErr bool
+	}{
+		{"username", "@my_channel", false},
+		{"group ID", "-1001234567890", false},
+		{"user ID", "123456789", false},
+		{"username too short", "@abcd", true},
+		{"username too long", "@" + "a12345678901234567890123456789012", true},
+		{"username starting with digit", "@1channel", true},
+		{"username with dash", "@my-channel", true},
+		{"unresolved link", "https://example.com/mychannel", true},
+		{"invite link", "https://t.me/+AbCdEf123", true},
+		{"garbage", "my channel", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateChatID(tt.chatID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateChatID(%q) error = %v, wantErr %v", tt.chatID, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveChatID(t *testing.T) {
+	tests := []struct {
+		input    string
+		chatID   string
+		threadID int64
+	}{
+		{"https://t.me/mychannel", "@mychannel", 0},
+		{"t.me/mychannel", "@mychannel", 0},
+		{"https://telegram.me/mychannel/123", "@mychannel", 0},
+		{"https://t.me/c/1234567890/5", "-1001234567890", 5},
+		{"https://t.me/c/1234567890/5/10", "-1001234567890", 5},
+		{"https://t.me/c/1234567890/10?thread=7", "-1001234567890", 7},
+		{"https://t.me/c/1234567890", "-1001234567890", 0},
+		{"mychannel", "@mychannel", 0},
+		{"-1001234567890", "-1001234567890", 0},
+		{"https://t.me/+AbCdEf123", "https://t.me/+AbCdEf123", 0},
+		{"-1001234567890@42", "-1001234567890", 42},
+		{"@mygroup@42", "@mygroup", 42},
+		{"mygroup@42", "@mygroup", 42},
+		{"-1001234567890@general", "-1001234567890", 1},
+		{"-1001234567890@0", "-1001234567890@0", 0},
+		{"-1001234567890@abc", "-1001234567890@abc", 0},
+		{"@42", "@42", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			chatID, threadID := resolveChatID(tt.input)
+			if chatID != tt.chatID || threadID != tt.threadID {
+				t.Errorf("resolveChatID(%q) = (%q, %d), want (%q, %d)", tt.input, chatID, threadID, tt.chatID, tt.threadID)
+			}
+		})
+	}
+}
+
+func TestParseThreadID(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    int64
+		wantErr bool
+	}{
+		{"unset", nil, 0, false},
+		{"int", 42, 42, false},
+		{"int64", int64(42), 42, false},
+		{"float", float64(42), 42, false},
+		{"string", "42", 42, false},
+		{"padded string", " 42 ", 42, false},
+		{"empty string", "", 0, false},
+		{"negative", "-5", -5, false},
+		{"fractional", 4.5, 0, true},
+		{"general topic", "general", 1, false},
+		{"general topic any case", " General ", 1, false},
+		{"non-numeric string", "releases", 0, true},
+		{"bool", true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseThreadID(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseThreadID(%v) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseThreadID(%v) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
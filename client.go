@@ -0,0 +1,520 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
+)
+
+// Shared HTTP client for connection reuse across requests. Like the clients
+// of newHTTPClient, it honors the HTTPS_PROXY and NO_PROXY environment.
+var defaultHTTPClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 5,
+		IdleConnTimeout:     90 * time.Second,
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		},
+	},
+}
+
+// telegramAPIBaseURL is the Bot API endpoint; tests point it at a local server.
+var telegramAPIBaseURL = "https://api.telegram.org"
+
+// APIError is an error reported by the Bot API.
+type APIError struct {
+	Code        int
+	Description string
+	// RetryAfter is the number of seconds to wait before retrying a
+	// rate-limited request, if the Bot API said so.
+	RetryAfter int
+	// MigrateToChatID is the supergroup a group was upgraded to, if the
+	// request went to the group.
+	MigrateToChatID int64
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram API error (%d): %s", e.Code, e.Description)
+}
+
+// isParseEntitiesError reports whether err is the Bot API rejecting the
+// message formatting.
+func isParseEntitiesError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest &&
+		strings.Contains(strings.ToLower(apiErr.Description), "can't parse entities")
+}
+
+// isMessageNotModifiedError reports whether err is the Bot API rejecting an
+// edit that would leave the message unchanged.
+func isMessageNotModifiedError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest &&
+		strings.Contains(strings.ToLower(apiErr.Description), "message is not modified")
+}
+
+// isMessageToEditNotFoundError reports whether err is the Bot API rejecting
+// an edit of a message that was deleted.
+func isMessageToEditNotFoundError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest &&
+		strings.Contains(strings.ToLower(apiErr.Description), "message to edit not found")
+}
+
+// HTTPConfig tunes the HTTP transport used for Bot API requests. The zero
+// value uses the shared default client.
+type HTTPConfig struct {
+	// TimeoutSeconds bounds each Bot API request, including reading the
+	// response.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" description:"Timeout of each Bot API request in seconds" default:"30"`
+	// EnableHTTP2 attempts HTTP/2 connections.
+	EnableHTTP2 bool `json:"enable_http2" description:"Attempt HTTP/2 connections" default:"false"`
+	// DisableKeepAlives closes connections after each request.
+	DisableKeepAlives bool `json:"disable_keep_alives" description:"Close connections after each request" default:"false"`
+	// KeepAliveSeconds is the TCP keep-alive period.
+	KeepAliveSeconds int `json:"keep_alive_seconds,omitempty" description:"TCP keep-alive period" default:"30"`
+	// IdleConnTimeoutSeconds is how long idle connections are kept for reuse.
+	IdleConnTimeoutSeconds int `json:"idle_conn_timeout_seconds,omitempty" description:"How long idle connections are kept for reuse" default:"90"`
+	// MaxIdleConnsPerHost bounds the idle connections kept per host.
+	MaxIdleConnsPerHost int `json:"max_idle_conns_per_host,omitempty" description:"Idle connections kept per host" default:"5"`
+	// CompressRequests gzips request bodies. Only self-hosted Bot API servers
+	// behind a proxy that accepts Content-Encoding: gzip support this.
+	CompressRequests bool `json:"compress_requests" description:"Gzip request bodies (self-hosted Bot API servers only)" default:"false"`
+	// CACertFile is a PEM file of CA certificates trusted besides the
+	// system roots, e.g. for TLS interception proxies.
+	CACertFile string `json:"ca_cert_file,omitempty" description:"PEM file of CA certificates trusted besides the system roots"`
+	// ClientCertFile and ClientKeyFile are the PEM certificate and key
+	// presented to servers requiring mutual TLS.
+	ClientCertFile string `json:"client_cert_file,omitempty" description:"PEM client certificate for mutual TLS"`
+	ClientKeyFile  string `json:"client_key_file,omitempty" description:"PEM private key of client_cert_file"`
+}
+
+// parseHTTPConfig parses the http config block.
+func parseHTTPConfig(v any) HTTPConfig {
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return HTTPConfig{}
+	}
+
+	parser := helpers.NewConfigParser(raw)
+	return HTTPConfig{
+		TimeoutSeconds:         getInt(raw, "timeout_seconds", 0),
+		EnableHTTP2:            parser.GetBool("enable_http2", false),
+		DisableKeepAlives:      parser.GetBool("disable_keep_alives", false),
+		KeepAliveSeconds:       getInt(raw, "keep_alive_seconds", 0),
+		IdleConnTimeoutSeconds: getInt(raw, "idle_conn_timeout_seconds", 0),
+		MaxIdleConnsPerHost:    getInt(raw, "max_idle_conns_per_host", 0),
+		CompressRequests:       parser.GetBool("compress_requests", false),
+		CACertFile:             parser.GetString("ca_cert_file", "", ""),
+		ClientCertFile:         parser.GetString("client_cert_file", "", ""),
+		ClientKeyFile:          parser.GetString("client_key_file", "", ""),
+	}
+}
+
+// transportConfig returns the part of the config that affects the transport.
+// Request compression is applied per request and does not need its own client.
+func (c HTTPConfig) transportConfig() HTTPConfig {
+	c.CompressRequests = false
+	return c
+}
+
+// requestTimeout returns the timeout of each Bot API request.
+func (c HTTPConfig) requestTimeout() time.Duration {
+	if c.TimeoutSeconds > 0 {
+		return time.Duration(c.TimeoutSeconds) * time.Second
+	}
+	return defaultHTTPClient.Timeout
+}
+
+// tlsConfig returns the TLS settings of the transport: the CAs of
+// ca_cert_file are trusted besides the system roots, and the client
+// certificate is presented when set.
+func (c HTTPConfig) tlsConfig() (*tls.Config, error) {
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CACertFile != "" {
+		data, err := os.ReadFile(c.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("ca_cert_file %q holds no PEM certificates", c.CACertFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if c.ClientCertFile != "" || c.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
+// newHTTPClient builds an HTTP client with the given transport tuning,
+// starting from the defaults of the shared client.
+func newHTTPClient(cfg HTTPConfig) (*http.Client, error) {
+	keepAlive := 30 * time.Second
+	if cfg.KeepAliveSeconds > 0 {
+		keepAlive = time.Duration(cfg.KeepAliveSeconds) * time.Second
+	}
+	idleTimeout := 90 * time.Second
+	if cfg.IdleConnTimeoutSeconds > 0 {
+		idleTimeout = time.Duration(cfg.IdleConnTimeoutSeconds) * time.Second
+	}
+	maxIdlePerHost := 5
+	if cfg.MaxIdleConnsPerHost > 0 {
+		maxIdlePerHost = cfg.MaxIdleConnsPerHost
+	}
+
+	tlsCfg, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := cfg.requestTimeout()
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: keepAlive,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   cfg.EnableHTTP2,
+			DisableKeepAlives:   cfg.DisableKeepAlives,
+			MaxIdleConns:        max(10, maxIdlePerHost),
+			MaxIdleConnsPerHost: maxIdlePerHost,
+			IdleConnTimeout:     idleTimeout,
+			TLSClientConfig:     tlsCfg,
+		},
+	}, nil
+}
+
+// clientKey identifies a cached HTTP client.
+type clientKey struct {
+	// bot is the bot token of a target with its own bot, or empty.
+	bot  string
+	http HTTPConfig
+}
+
+// httpClient returns the HTTP client for cfg. Clients are cached per
+// transport config so connections are reused across sends, and targets with
+// their own bot get a separate connection pool. It fails when the TLS
+// files of the http block cannot be loaded.
+func (p *TelegramPlugin) httpClient(cfg *Config) (*http.Client, error) {
+	key := clientKey{bot: cfg.botPool, http: cfg.HTTP.transportConfig()}
+	if key == (clientKey{}) {
+		return defaultHTTPClient, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[key]; ok {
+		return client, nil
+	}
+	client, err := newHTTPClient(key.http)
+	if err != nil {
+		return nil, err
+	}
+	if p.clients == nil {
+		p.clients = make(map[clientKey]*http.Client)
+	}
+	p.clients[key] = client
+	return client, nil
+}
+
+// sendMessage sends a message to Telegram.
+func (p *TelegramPlugin) sendMessage(ctx context.Context, cfg *Config, msg TelegramMessage) error {
+	return p.callAPI(ctx, cfg, "sendMessage", msg, nil)
+}
+
+// postMessage sends a message to Telegram and returns the sent message.
+func (p *TelegramPlugin) postMessage(ctx context.Context, cfg *Config, msg TelegramMessage) (*TelegramSentMessage, error) {
+	payload, err := messagePayload(msg)
+	if err != nil {
+		return nil, err
+	}
+	return p.sendMessageMigrating(ctx, cfg, payload)
+}
+
+// forwardMessage forwards a message to another chat, keeping the
+// "forwarded from" header.
+func (p *TelegramPlugin) forwardMessage(ctx context.Context, cfg *Config, msg TelegramForward) (*TelegramSentMessage, error) {
+	if err := p.paceSend(ctx, cfg, msg.ChatID); err != nil {
+		return nil, err
+	}
+	var sent TelegramSentMessage
+	if err := p.callAPI(ctx, cfg, "forwardMessage", msg, &sent); err != nil {
+		return nil, err
+	}
+	return &sent, nil
+}
+
+// pinChatMessage silently pins a message in a chat.
+func (p *TelegramPlugin) pinChatMessage(ctx context.Context, cfg *Config, chatID string, messageID int64) error {
+	params := map[string]any{"chat_id": chatID, "message_id": messageID, "disable_notification": true}
+	return p.callAPI(ctx, cfg, "pinChatMessage", params, nil)
+}
+
+// answerCallbackQuery answers a button press, showing text as a brief
+// notification to the user who pressed it.
+func (p *TelegramPlugin) answerCallbackQuery(ctx context.Context, cfg *Config, queryID, text string) error {
+	params := map[string]any{"callback_query_id": queryID, "text": text}
+	return p.callAPI(ctx, cfg, "answerCallbackQuery", params, nil)
+}
+
+// editMessageReplyMarkup replaces the inline keyboard of a sent message.
+func (p *TelegramPlugin) editMessageReplyMarkup(ctx context.Context, cfg *Config, chatID string, messageID int64, markup *InlineKeyboardMarkup) error {
+	params := map[string]any{"chat_id": chatID, "message_id": messageID, "reply_markup": markup}
+	return p.callAPI(ctx, cfg, "editMessageReplyMarkup", params, nil)
+}
+
+// editMessageText replaces the text of a sent message with the text and
+// formatting of msg.
+func (p *TelegramPlugin) editMessageText(ctx context.Context, cfg *Config, msg TelegramMessage, messageID int64) error {
+	params := map[string]any{"chat_id": msg.ChatID, "message_id": messageID, "text": msg.Text}
+	if msg.ParseMode != "" {
+		params["parse_mode"] = msg.ParseMode
+	}
+	if msg.DisableWebPagePreview {
+		params["link_preview_options"] = map[string]any{"is_disabled": true}
+	}
+	return p.callAPI(ctx, cfg, "editMessageText", params, nil)
+}
+
+// getUpdates long-polls for incoming updates starting at offset. The Bot
+// API holds the request open for up to timeout while no updates arrive.
+func (p *TelegramPlugin) getUpdates(ctx context.Context, cfg *Config, offset int64, timeout time.Duration, allowedUpdates []string) ([]TelegramUpdate, error) {
+	params := map[string]any{
+		"offset":  offset,
+		"timeout": int(timeout / time.Second),
+	}
+	if allowedUpdates != nil {
+		params["allowed_updates"] = allowedUpdates
+	}
+	var updates []TelegramUpdate
+	if err := p.callAPI(ctx, cfg, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// getChat fetches chat information from Telegram.
+func (p *TelegramPlugin) getChat(ctx context.Context, cfg *Config, chatID string) (*TelegramChat, error) {
+	var chat TelegramChat
+	if err := p.callAPI(ctx, cfg, "getChat", map[string]string{"chat_id": chatID}, &chat); err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+// getMe returns the bot account the token belongs to.
+func (p *TelegramPlugin) getMe(ctx context.Context, cfg *Config) (*TelegramUser, error) {
+	var user TelegramUser
+	if err := p.callAPI(ctx, cfg, "getMe", map[string]string{}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// createForumTopic creates a forum topic in a chat.
+func (p *TelegramPlugin) createForumTopic(ctx context.Context, cfg *Config, chatID, name string) (*TelegramForumTopic, error) {
+	var topic TelegramForumTopic
+	params := map[string]string{"chat_id": chatID, "name": name}
+	if err := p.callAPI(ctx, cfg, "createForumTopic", params, &topic); err != nil {
+		return nil, err
+	}
+	return &topic, nil
+}
+
+// sendDocument uploads content as a document named name.
+func (p *TelegramPlugin) sendDocument(ctx context.Context, cfg *Config, doc TelegramDocument, name string, content []byte) (*TelegramSentMessage, error) {
+	return p.sendFile(ctx, cfg, "sendDocument", "document", doc, name, content)
+}
+
+// sendPhoto uploads content as a photo named name.
+func (p *TelegramPlugin) sendPhoto(ctx context.Context, cfg *Config, doc TelegramDocument, name string, content []byte) (*TelegramSentMessage, error) {
+	return p.sendFile(ctx, cfg, "sendPhoto", "photo", doc, name, content)
+}
+
+// sendFile uploads content named name in the field of a multipart request
+// to method.
+func (p *TelegramPlugin) sendFile(ctx context.Context, cfg *Config, method, field string, doc TelegramDocument, name string, content []byte) (*TelegramSentMessage, error) {
+	fields := map[string]string{"chat_id": doc.ChatID}
+	if threadID := wireThreadID(doc.MessageThreadID); threadID != 0 {
+		fields["message_thread_id"] = strconv.FormatInt(threadID, 10)
+	}
+	if doc.Caption != "" {
+		fields["caption"] = doc.Caption
+	}
+	if doc.DisableNotification {
+		fields["disable_notification"] = "true"
+	}
+	if doc.ReplyParameters != nil && !p.apiVersion(cfg).supports("reply_parameters") {
+		fields["reply_to_message_id"] = strconv.FormatInt(doc.ReplyParameters.MessageID, 10)
+		if doc.ReplyParameters.AllowSendingWithoutReply {
+			fields["allow_sending_without_reply"] = "true"
+		}
+	} else if doc.ReplyParameters != nil {
+		reply, err := json.Marshal(doc.ReplyParameters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal document: %w", err)
+		}
+		fields["reply_parameters"] = string(reply)
+	}
+
+	if err := p.paceSend(ctx, cfg, doc.ChatID); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		if err := w.WriteField(key, fields[key]); err != nil {
+			return nil, fmt.Errorf("failed to write document: %w", err)
+		}
+	}
+	part, err := w.CreateFormFile(field, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write document: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return nil, fmt.Errorf("failed to write document: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write document: %w", err)
+	}
+
+	var sent TelegramSentMessage
+	if err := p.doAPI(ctx, cfg, method, w.FormDataContentType(), "", body.Bytes(), &sent); err != nil {
+		return nil, err
+	}
+	return &sent, nil
+}
+
+// apiBaseURL returns the Bot API server to call: api_url when set,
+// otherwise the public endpoint.
+func (cfg *Config) apiBaseURL() string {
+	if cfg.APIURL != "" {
+		return strings.TrimSuffix(cfg.APIURL, "/")
+	}
+	return telegramAPIBaseURL
+}
+
+// callAPI calls a Bot API method and decodes its result into result, if non-nil.
+func (p *TelegramPlugin) callAPI(ctx context.Context, cfg *Config, method string, params any, result any) error {
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	var encoding string
+	if cfg.HTTP.CompressRequests {
+		if payload, err = gzipBytes(payload); err != nil {
+			return fmt.Errorf("failed to compress request: %w", err)
+		}
+		encoding = "gzip"
+	}
+	return p.doAPI(ctx, cfg, method, "application/json", encoding, payload, result)
+}
+
+// doAPI posts an encoded request body to a Bot API method and decodes its
+// result into result, if non-nil.
+func (p *TelegramPlugin) doAPI(ctx context.Context, cfg *Config, method, contentType, contentEncoding string, payload []byte, result any) error {
+	if err := p.injectFault(ctx, cfg, method); err != nil {
+		return err
+	}
+
+	apiURL := fmt.Sprintf("%s/bot%s/%s", cfg.apiBaseURL(), cfg.BotToken, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", redactURLError(err, cfg.BotToken))
+	}
+	req.Header.Set("Content-Type", contentType)
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+
+	client, err := p.httpClient(cfg)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", redactURLError(err, cfg.BotToken))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var telegramResp TelegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&telegramResp); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !telegramResp.OK {
+		apiErr := &APIError{Code: telegramResp.ErrorCode, Description: telegramResp.Description}
+		if telegramResp.Parameters != nil {
+			apiErr.RetryAfter = telegramResp.Parameters.RetryAfter
+			apiErr.MigrateToChatID = telegramResp.Parameters.MigrateToChatID
+		}
+		return apiErr
+	}
+
+	if result != nil && len(telegramResp.Result) > 0 {
+		if err := json.Unmarshal(telegramResp.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+	}
+
+	return nil
+}
+
+// redactURLError masks the bot token in the URL of a *url.Error, which
+// quotes the request URL and with it the /bot<token>/ path. Errors end up in
+// outputs and messages, which must not reveal the token.
+func redactURLError(err error, token string) error {
+	var urlErr *url.Error
+	if token != "" && errors.As(err, &urlErr) {
+		urlErr.URL = strings.ReplaceAll(urlErr.URL, token, maskBotToken(token))
+	}
+	return err
+}
+
+// gzipBytes compresses data with gzip.
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
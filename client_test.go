@@ -0,0 +1,259 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseHTTPConfig(t *testing.T) {
+	cfg := parseHTTPConfig(map[string]any{
+		"timeout_seconds":           float64(60),
+		"enable_http2":              true,
+		"keep_alive_seconds":        float64(15),
+		"idle_conn_timeout_seconds": 120,
+		"compress_requests":         true,
+	})
+
+	want := HTTPConfig{
+		TimeoutSeconds:         60,
+		EnableHTTP2:            true,
+		KeepAliveSeconds:       15,
+		IdleConnTimeoutSeconds: 120,
+		CompressRequests:       true,
+	}
+	if cfg != want {
+		t.Errorf("parseHTTPConfig() = %+v, want %+v", cfg, want)
+	}
+
+	if got := parseHTTPConfig(nil); got != (HTTPConfig{}) {
+		t.Errorf("parseHTTPConfig(nil) = %+v, want zero value", got)
+	}
+}
+
+// mustHTTPClient returns the HTTP client of p for cfg, failing the test
+// when it cannot be built.
+func mustHTTPClient(t *testing.T, p *TelegramPlugin, cfg *Config) *http.Client {
+	t.Helper()
+	client, err := p.httpClient(cfg)
+	if err != nil {
+		t.Fatalf("httpClient() error = %v", err)
+	}
+	return client
+}
+
+func TestHTTPClientSelection(t *testing.T) {
+	p := &TelegramPlugin{}
+
+	if got := mustHTTPClient(t, p, &Config{}); got != defaultHTTPClient {
+		t.Error("expected default client for zero HTTP config")
+	}
+	if got := mustHTTPClient(t, p, &Config{HTTP: HTTPConfig{CompressRequests: true}}); got != defaultHTTPClient {
+		t.Error("expected default client when only compression is enabled")
+	}
+
+	tuned := &Config{HTTP: HTTPConfig{EnableHTTP2: true}}
+	first := mustHTTPClient(t, p, tuned)
+	if first == defaultHTTPClient {
+		t.Fatal("expected a dedicated client for tuned HTTP config")
+	}
+	if mustHTTPClient(t, p, tuned) != first {
+		t.Error("expected tuned client to be cached")
+	}
+
+	transport := first.Transport.(*http.Transport)
+	if !transport.ForceAttemptHTTP2 {
+		t.Error("expected ForceAttemptHTTP2 on tuned transport")
+	}
+}
+
+func TestHTTPClientProxy(t *testing.T) {
+	p := &TelegramPlugin{}
+	for name, cfg := range map[string]*Config{
+		"default": {},
+		"tuned":   {HTTP: HTTPConfig{EnableHTTP2: true}},
+	} {
+		transport := mustHTTPClient(t, p, cfg).Transport.(*http.Transport)
+		if transport.Proxy == nil {
+			t.Errorf("%s client ignores the proxy environment", name)
+		}
+	}
+}
+
+func TestHTTPClientTimeout(t *testing.T) {
+	p := &TelegramPlugin{}
+
+	if got := mustHTTPClient(t, p, &Config{}).Timeout; got != 30*time.Second {
+		t.Errorf("default Timeout = %v, want 30s", got)
+	}
+	client := mustHTTPClient(t, p, &Config{HTTP: HTTPConfig{TimeoutSeconds: 90}})
+	if client == defaultHTTPClient {
+		t.Fatal("expected a dedicated client for a custom timeout")
+	}
+	if client.Timeout != 90*time.Second {
+		t.Errorf("Timeout = %v, want 90s", client.Timeout)
+	}
+}
+
+// writePEM writes a PEM block to a file in dir and returns its path.
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExecuteMutualTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			t.Error("request without a client certificate")
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":5}`)})
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	// The test server's self-signed certificate serves as the CA and, with
+	// its key, as the client certificate.
+	dir := t.TempDir()
+	cert := server.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	certFile := writePEM(t, dir, "client.pem", "CERTIFICATE", cert.Certificate[0])
+	keyFile := writePEM(t, dir, "client-key.pem", "PRIVATE KEY", key)
+
+	execute := func(httpCfg map[string]any) (*plugin.ExecuteResponse, error) {
+		p := &TelegramPlugin{}
+		return p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook: plugin.HookPostPublish,
+			Config: map[string]any{
+				"bot_token": "123:abc",
+				"chat_id":   "@test",
+				"api_url":   server.URL,
+				"http":      httpCfg,
+			},
+			Context: plugin.ReleaseContext{Version: "1.0.0"},
+		})
+	}
+
+	resp, err := execute(map[string]any{"ca_cert_file": caFile, "client_cert_file": certFile, "client_key_file": keyFile})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success over mutual TLS", resp, err)
+	}
+
+	resp, err = execute(map[string]any{"client_cert_file": certFile, "client_key_file": keyFile})
+	if err != nil || resp.Success {
+		t.Errorf("Execute() = %+v, %v; want failure without trusting the CA", resp, err)
+	}
+
+	resp, err = execute(map[string]any{"ca_cert_file": filepath.Join(dir, "missing.pem")})
+	if err != nil || resp.Success || !strings.Contains(resp.Error, "failed to read ca_cert_file") {
+		t.Errorf("Execute() = %+v, %v; want a ca_cert_file error", resp, err)
+	}
+}
+
+func TestValidateTLSFiles(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		httpCfg map[string]any
+		field   string
+	}{
+		{name: "cert without key", httpCfg: map[string]any{"client_cert_file": "client.pem"}, field: "http.client_key_file"},
+		{name: "key without cert", httpCfg: map[string]any{"client_key_file": "client-key.pem"}, field: "http.client_key_file"},
+		{name: "missing client cert", httpCfg: map[string]any{"client_cert_file": filepath.Join(dir, "a.pem"), "client_key_file": filepath.Join(dir, "b.pem")}, field: "http"},
+		{name: "CA file without certificates", httpCfg: map[string]any{"ca_cert_file": notPEM}, field: "http"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &TelegramPlugin{}
+			resp, err := p.Validate(context.Background(), map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@repo_releases",
+				"http":      tt.httpCfg,
+			})
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if resp.Valid || len(resp.Errors) != 1 || resp.Errors[0].Field != tt.field {
+				t.Errorf("Validate() = %+v, want one error on %s", resp, tt.field)
+			}
+		})
+	}
+}
+
+func TestCallAPICompressRequests(t *testing.T) {
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("Content-Encoding = %q, want gzip", r.Header.Get("Content-Encoding"))
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("gzip.NewReader() error = %v", err)
+		}
+		body, _ := io.ReadAll(zr)
+		_ = json.Unmarshal(body, &got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	cfg := &Config{BotToken: "123:abc", HTTP: HTTPConfig{CompressRequests: true}}
+	if err := p.sendMessage(context.Background(), cfg, TelegramMessage{ChatID: "@test", Text: "hello"}); err != nil {
+		t.Fatalf("sendMessage() error = %v", err)
+	}
+	if got.Text != "hello" {
+		t.Errorf("decompressed text = %q, want hello", got.Text)
+	}
+}
+
+func TestExecuteRedactsBotTokenFromErrors(t *testing.T) {
+	server := useTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	server.Close()
+
+	const token = "123456:ABCdefGHIjklMNOpqrSTUvwxYZ0123456789ab"
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":       token,
+			"chat_ids":        []any{"@news", "@ops"},
+			"summary_chat_id": "@summary",
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Success {
+		t.Fatalf("Execute() = %+v, want a failure", resp)
+	}
+	if got := fmt.Sprintf("%+v", resp); strings.Contains(got, token) || !strings.Contains(got, "123456:***") {
+		t.Errorf("Execute() = %s, want the bot token masked", got)
+	}
+}
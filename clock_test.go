@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock. Sleep and After advance the clock
+// immediately instead of blocking, so tests never wait in real time.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept []time.Duration
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.Advance(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+// Advance moves the clock forward by d and records it as slept time.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.slept = append(c.slept, d)
+}
+
+// Slept returns the durations the clock has been advanced by.
+func (c *fakeClock) Slept() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.slept...)
+}
+
+func TestSleepContext(t *testing.T) {
+	c := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if err := sleepContext(context.Background(), c, 5*time.Second); err != nil {
+		t.Fatalf("sleepContext() error = %v", err)
+	}
+	if got := c.Now(); !got.Equal(time.Date(2024, 1, 1, 0, 0, 5, 0, time.UTC)) {
+		t.Errorf("clock after sleep = %v, want 5s later", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sleepContext(ctx, c, 0); err != context.Canceled {
+		t.Errorf("sleepContext() with canceled context = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// compareStatsErrorOutput records why the compare stats were left out.
+var compareStatsErrorOutput = registerDegradation("compare_stats_error", describeError("compare stats unavailable"))
+
+// parseCompareStats parses compare stats in the format of git diff
+// --numstat: insertions, deletions, and the path, separated by tabs. Binary
+// files have "-" for both counts. Blank lines are skipped.
+func parseCompareStats(content string) ([]render.FileStat, error) {
+	var stats []render.FileStat
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		fields := strings.SplitN(text, "\t", 3)
+		if len(fields) != 3 || fields[2] == "" {
+			return nil, fmt.Errorf("line %d: want insertions, deletions, and path separated by tabs", line)
+		}
+		stat := render.FileStat{Path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			stat.Binary = true
+		} else {
+			var errIns, errDel error
+			stat.Insertions, errIns = strconv.Atoi(fields[0])
+			stat.Deletions, errDel = strconv.Atoi(fields[1])
+			if errIns != nil || errDel != nil || stat.Insertions < 0 || stat.Deletions < 0 {
+				return nil, fmt.Errorf("line %d: invalid line counts %q and %q", line, fields[0], fields[1])
+			}
+		}
+		stats = append(stats, stat)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// loadCompareStats reads and parses compare_stats_file. Relative paths are
+// resolved against the working directory, which is the repository root when
+// relicta runs.
+func loadCompareStats(path string) (string, []render.FileStat, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read compare_stats_file: %w", err)
+	}
+	stats, err := parseCompareStats(string(data))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid compare_stats_file: %w", err)
+	}
+	return string(data), stats, nil
+}
+
+// withCompareStats returns cfg with the compare stats loaded for the
+// success message section. A file that cannot be read or parsed leaves the
+// section out and is reported in compare_stats_error rather than failing
+// the announcement.
+func withCompareStats(cfg *Config, outputs map[string]any) *Config {
+	if cfg.CompareStatsFile == "" || cfg.CompareStatsDocument {
+		return cfg
+	}
+	_, stats, err := loadCompareStats(cfg.CompareStatsFile)
+	if err != nil {
+		outputs[compareStatsErrorOutput] = errorText(err)
+		return cfg
+	}
+	statsCfg := *cfg
+	statsCfg.compareStats = stats
+	return &statsCfg
+}
+
+// sendCompareStatsDocument posts compare_stats_file as a document replying
+// to the success notification. Its name is reported in
+// compare_stats_document; a failure sets compare_stats_error.
+func (p *TelegramPlugin) sendCompareStatsDocument(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, outputs map[string]any) {
+	content, stats, err := loadCompareStats(cfg.CompareStatsFile)
+	if err != nil {
+		outputs[compareStatsErrorOutput] = errorText(err)
+		return
+	}
+	if len(stats) == 0 {
+		return
+	}
+	const name = "compare-stats.txt"
+	if dryRun {
+		outputs["compare_stats_document"] = name
+		return
+	}
+
+	doc := TelegramDocument{
+		ChatID:              cfg.ChatID,
+		MessageThreadID:     cfg.MessageThreadID,
+		Caption:             fmt.Sprintf("📊 %d changed files in %s", len(stats), releaseCtx.Version),
+		DisableNotification: true,
+	}
+	if messageID, ok := outputs["message_id"].(int64); ok {
+		doc.ReplyParameters = &ReplyParameters{MessageID: messageID, AllowSendingWithoutReply: true}
+	}
+	if err := p.uploadDocument(ctx, cfg, doc, name, []byte(content)); err != nil {
+		outputs[compareStatsErrorOutput] = fmt.Sprintf("%s: %v", name, err)
+		return
+	}
+	outputs["compare_stats_document"] = name
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseCompareStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []render.FileStat
+		wantErr bool
+	}{
+		{
+			name:    "numstat",
+			content: "12\t3\tgo.mod\n\n-\t-\tlogo.png\r\n0\t40\tdocs/{old.md => new.md}\n",
+			want: []render.FileStat{
+				{Path: "go.mod", Insertions: 12, Deletions: 3},
+				{Path: "logo.png", Binary: true},
+				{Path: "docs/{old.md => new.md}", Deletions: 40},
+			},
+		},
+		{name: "empty", content: "\n"},
+		{name: "missing path", content: "1\t2\n", wantErr: true},
+		{name: "spaces instead of tabs", content: "1 2 go.mod\n", wantErr: true},
+		{name: "invalid count", content: "many\t2\tgo.mod\n", wantErr: true},
+		{name: "negative count", content: "-1\t2\tgo.mod\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCompareStats(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCompareStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCompareStats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteCompareStatsSection(t *testing.T) {
+	var text string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		text = msg.Text
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":5}`)})
+	})
+
+	path := filepath.Join(t.TempDir(), "stats.txt")
+	if err := os.WriteFile(path, []byte("1\t1\tREADME.md\n120\t14\tinternal/render/render.go\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":          "123:abc",
+			"chat_id":            "@test",
+			"compare_stats_file": path,
+		},
+		Context: plugin.ReleaseContext{Version: "2.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	want := "\n📊 *Changed files \\(2\\):*\n• `internal/render/render\\.go` \\+120 −14\n• `README\\.md` \\+1 −1\n"
+	if !strings.HasSuffix(text, want) {
+		t.Errorf("text = %q, want suffix %q", text, want)
+	}
+}
+
+func TestExecuteCompareStatsMissingFile(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":5}`)})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":          "123:abc",
+			"chat_id":            "@test",
+			"compare_stats_file": filepath.Join(t.TempDir(), "missing.txt"),
+		},
+		Context: plugin.ReleaseContext{Version: "2.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success despite the missing stats", resp, err)
+	}
+	if got, _ := resp.Outputs["compare_stats_error"].(string); !strings.Contains(got, "failed to read compare_stats_file") {
+		t.Errorf("compare_stats_error = %q", got)
+	}
+}
+
+func TestExecuteCompareStatsDocument(t *testing.T) {
+	var mu sync.Mutex
+	var uploads []uploadedDocument
+	var text string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !strings.HasSuffix(r.URL.Path, "/sendDocument") {
+			var msg TelegramMessage
+			_ = json.NewDecoder(r.Body).Decode(&msg)
+			text = msg.Text
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":5}`)})
+			return
+		}
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			t.Errorf("FormFile() error = %v", err)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		uploads = append(uploads, uploadedDocument{
+			name:    header.Filename,
+			caption: r.FormValue("caption"),
+			reply:   r.FormValue("reply_parameters"),
+			content: string(content),
+		})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":6}`)})
+	})
+
+	stats := "1\t1\tREADME.md\n-\t-\tlogo.png\n"
+	path := filepath.Join(t.TempDir(), "stats.txt")
+	if err := os.WriteFile(path, []byte(stats), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":              "123:abc",
+			"chat_id":                "@test",
+			"compare_stats_file":     path,
+			"compare_stats_document": true,
+		},
+		Context: plugin.ReleaseContext{Version: "2.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if got := resp.Outputs["compare_stats_document"]; got != "compare-stats.txt" {
+		t.Errorf("compare_stats_document = %v, want compare-stats.txt", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Contains(text, "Changed files") {
+		t.Errorf("text = %q, want no compare stats section in document mode", text)
+	}
+	want := []uploadedDocument{{
+		name:    "compare-stats.txt",
+		caption: "📊 2 changed files in 2.0.0",
+		reply:   `{"message_id":5,"allow_sending_without_reply":true}`,
+		content: stats,
+	}}
+	if !reflect.DeepEqual(uploads, want) {
+		t.Errorf("uploads = %+v, want %+v", uploads, want)
+	}
+}
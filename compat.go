@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// apiVersion is a Bot API version such as 7.0.
+type apiVersion struct {
+	major, minor int
+}
+
+// parseAPIVersion parses a major.minor Bot API version.
+func parseAPIVersion(s string) (apiVersion, error) {
+	majorStr, minorStr, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	major, err := strconv.Atoi(majorStr)
+	if err != nil || major < 0 {
+		return apiVersion{}, fmt.Errorf("invalid Bot API version %q, expected major.minor such as 6.9", s)
+	}
+	minor := 0
+	if minorStr != "" {
+		if minor, err = strconv.Atoi(minorStr); err != nil || minor < 0 {
+			return apiVersion{}, fmt.Errorf("invalid Bot API version %q, expected major.minor such as 6.9", s)
+		}
+	}
+	return apiVersion{major, minor}, nil
+}
+
+// before reports whether v is older than other.
+func (v apiVersion) before(other apiVersion) bool {
+	return v.major < other.major || (v.major == other.major && v.minor < other.minor)
+}
+
+func (v apiVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+// apiFields lists the sendMessage fields newer than Bot API 6.x servers
+// support, with the version that introduced them.
+var apiFields = []struct {
+	name    string
+	version apiVersion
+}{
+	{"link_preview_options", apiVersion{7, 0}},
+	{"reply_parameters", apiVersion{7, 0}},
+	{"message_effect_id", apiVersion{7, 4}},
+}
+
+// apiVersion returns the Bot API version of the configured server: the one
+// detected from an earlier rejected request, or bot_api_version. The zero
+// value means the server is assumed to be current.
+func (p *TelegramPlugin) apiVersion(cfg *Config) apiVersion {
+	p.mu.Lock()
+	detected, ok := p.apiVersions[cfg.apiBaseURL()]
+	p.mu.Unlock()
+	if ok {
+		return detected
+	}
+	// Invalid versions are reported by Validate.
+	version, _ := parseAPIVersion(cfg.BotAPIVersion)
+	return version
+}
+
+// supports reports whether a server of version v accepts field. The zero
+// version supports every field.
+func (v apiVersion) supports(field string) bool {
+	if v == (apiVersion{}) {
+		return true
+	}
+	for _, f := range apiFields {
+		if f.name == field {
+			return !v.before(f.version)
+		}
+	}
+	return true
+}
+
+// downgradePayload returns payload with the fields version does not support
+// replaced by their older equivalents: link_preview_options by
+// disable_web_page_preview and reply_parameters by reply_to_message_id.
+// Fields without an equivalent, such as message_effect_id, are dropped.
+func downgradePayload(payload map[string]any, version apiVersion) map[string]any {
+	out := maps.Clone(payload)
+	if opts, ok := out["link_preview_options"].(map[string]any); ok && !version.supports("link_preview_options") {
+		delete(out, "link_preview_options")
+		delete(out, "disable_web_page_preview")
+		if disabled, _ := opts["is_disabled"].(bool); disabled {
+			out["disable_web_page_preview"] = true
+		}
+	}
+	if reply, ok := out["reply_parameters"].(map[string]any); ok && !version.supports("reply_parameters") {
+		delete(out, "reply_parameters")
+		out["reply_to_message_id"] = reply["message_id"]
+		if allow, _ := reply["allow_sending_without_reply"].(bool); allow {
+			out["allow_sending_without_reply"] = true
+		}
+	}
+	for _, f := range apiFields {
+		if !version.supports(f.name) {
+			delete(out, f.name)
+		}
+	}
+	return out
+}
+
+// unsupportedField returns the newer field a server rejected a request for,
+// judging by the error description, or "".
+func unsupportedField(err error, payload map[string]any) string {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+		return ""
+	}
+	description := strings.ToLower(apiErr.Description)
+	for _, f := range apiFields {
+		if _, ok := payload[f.name]; !ok {
+			continue
+		}
+		if strings.Contains(description, f.name) || strings.Contains(description, strings.ReplaceAll(f.name, "_", " ")) {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// sendMessagePayload sends a sendMessage payload, downgraded for the
+// server's Bot API version and without message_thread_id for the General
+// topic. When the server rejects a newer field, it is
+// remembered as older than the version that introduced the field for the
+// rest of the run, and the message is sent again without it.
+func (p *TelegramPlugin) sendMessagePayload(ctx context.Context, cfg *Config, payload map[string]any) (*TelegramSentMessage, error) {
+	version := p.apiVersion(cfg)
+	payload = withoutGeneralTopic(payload)
+	for {
+		var sent TelegramSentMessage
+		body := downgradePayload(payload, version)
+		if err := p.paceSend(ctx, cfg, fmt.Sprint(body["chat_id"])); err != nil {
+			return nil, err
+		}
+		err := p.callAPI(ctx, cfg, "sendMessage", body, &sent)
+		if err == nil {
+			return &sent, nil
+		}
+		field := unsupportedField(err, body)
+		if field == "" {
+			return nil, err
+		}
+		version = olderThan(field)
+		p.mu.Lock()
+		if p.apiVersions == nil {
+			p.apiVersions = make(map[string]apiVersion)
+		}
+		p.apiVersions[cfg.apiBaseURL()] = version
+		p.mu.Unlock()
+	}
+}
+
+// olderThan returns the last version before field was introduced.
+func olderThan(field string) apiVersion {
+	for _, f := range apiFields {
+		if f.name == field {
+			if f.version.minor == 0 {
+				return apiVersion{f.version.major - 1, 9}
+			}
+			return apiVersion{f.version.major, f.version.minor - 1}
+		}
+	}
+	return apiVersion{}
+}
+
+// messagePayload converts msg to the generic payload form.
+func messagePayload(msg TelegramMessage) (map[string]any, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+	var payload map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    apiVersion
+		wantErr bool
+	}{
+		{"6.9", apiVersion{6, 9}, false},
+		{"7", apiVersion{7, 0}, false},
+		{"v7.4", apiVersion{7, 4}, false},
+		{"7.x", apiVersion{}, true},
+		{"latest", apiVersion{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseAPIVersion(tt.in)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("parseAPIVersion(%q) = %v, %v; want %v, wantErr %v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDowngradePayload(t *testing.T) {
+	payload := map[string]any{
+		"chat_id":              "@news",
+		"link_preview_options": map[string]any{"is_disabled": true},
+		"reply_parameters":     map[string]any{"message_id": json.Number("42"), "allow_sending_without_reply": true},
+		"message_effect_id":    "5104841245755180586",
+	}
+
+	tests := []struct {
+		name    string
+		version apiVersion
+		want    map[string]any
+	}{
+		{"current", apiVersion{}, payload},
+		{"7.4", apiVersion{7, 4}, payload},
+		{"7.3", apiVersion{7, 3}, map[string]any{
+			"chat_id":              "@news",
+			"link_preview_options": payload["link_preview_options"],
+			"reply_parameters":     payload["reply_parameters"],
+		}},
+		{"6.9", apiVersion{6, 9}, map[string]any{
+			"chat_id":                     "@news",
+			"disable_web_page_preview":    true,
+			"reply_to_message_id":         json.Number("42"),
+			"allow_sending_without_reply": true,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := downgradePayload(payload, tt.version); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("downgradePayload() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, ok := payload["link_preview_options"]; !ok {
+		t.Error("expected the payload to be left unchanged")
+	}
+}
+
+func TestExecuteUnsupportedField(t *testing.T) {
+	var sent []map[string]any
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		sent = append(sent, body)
+		if _, ok := body["link_preview_options"]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: http.StatusBadRequest, Description: "Bad Request: unknown field link_preview_options"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	for range 2 {
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook: plugin.HookPostPublish,
+			Config: map[string]any{
+				"bot_token":       "123:abc",
+				"chat_id":         "@news",
+				"show_above_text": true,
+			},
+			Context: plugin.ReleaseContext{Version: "1.0.0"},
+		})
+		if err != nil || !resp.Success {
+			t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+		}
+	}
+	// The second run remembers the server is older than Bot API 7.0.
+	if len(sent) != 3 {
+		t.Fatalf("sent %d requests, want 3", len(sent))
+	}
+	if _, ok := sent[1]["link_preview_options"]; ok {
+		t.Errorf("retry = %v, want it without link_preview_options", sent[1])
+	}
+}
+
+func TestExecuteBotAPIVersion(t *testing.T) {
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":                "123:abc",
+			"chat_id":                  "@news",
+			"bot_api_version":          "6.9",
+			"disable_web_page_preview": true,
+			"show_above_text":          true,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if got.LinkPreviewOptions != nil || !got.DisableWebPagePreview {
+		t.Errorf("sent preview options %+v, disable_web_page_preview %v; want only disable_web_page_preview", got.LinkPreviewOptions, got.DisableWebPagePreview)
+	}
+}
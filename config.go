@@ -0,0 +1,67 @@
+package main
+
+import "fmt"
+
+// parseStringList parses a list of strings, skipping non-string entries.
+func parseStringList(v any) []string {
+	switch val := v.(type) {
+	case []string:
+		return val
+	case []any:
+		var list []string
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// parseStringMap parses a map config. Scalar values are stringified so YAML
+// numbers and booleans can be used; other values are dropped and reported
+// by validateStringMap.
+func parseStringMap(v any) map[string]string {
+	raw, ok := v.(map[string]any)
+	if !ok || len(raw) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch value.(type) {
+		case string, bool, int, int64, float64:
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values
+}
+
+// validateStringMap reports whether a map config only holds scalar values.
+func validateStringMap(v any) error {
+	if v == nil {
+		return nil
+	}
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("must be a map of names to values")
+	}
+	for key, value := range raw {
+		switch value.(type) {
+		case string, bool, int, int64, float64:
+		default:
+			return fmt.Errorf("%q must be a string, number, or boolean", key)
+		}
+	}
+	return nil
+}
+
+// parseLanguage parses the language config, which is either a single
+// language or a fallback chain.
+func parseLanguage(v any) []string {
+	if lang, ok := v.(string); ok && lang != "" {
+		return []string{lang}
+	}
+	return parseStringList(v)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStringList(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected []string
+	}{
+		{"unset", nil, nil},
+		{"strings", []string{"a", "b"}, []string{"a", "b"}},
+		{"mixed", []any{"a", 1, "b"}, []string{"a", "b"}},
+		{"scalar", "a", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStringList(tt.input); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseStringList() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseStringMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected map[string]string
+	}{
+		{"unset", nil, nil},
+		{"not a map", "team", nil},
+		{
+			name:     "scalars",
+			input:    map[string]any{"team": "payments", "tier": float64(1), "public": true},
+			expected: map[string]string{"team": "payments", "tier": "1", "public": "true"},
+		},
+		{
+			name:     "nested values dropped",
+			input:    map[string]any{"team": "payments", "owners": []any{"a"}},
+			expected: map[string]string{"team": "payments"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStringMap(tt.input); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseStringMap() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateStringMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   any
+		wantErr bool
+	}{
+		{"unset", nil, false},
+		{"scalars", map[string]any{"team": "payments", "tier": 1}, false},
+		{"not a map", []any{"payments"}, true},
+		{"nested value", map[string]any{"owners": map[string]any{"lead": "a"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateStringMap(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("validateStringMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected []string
+	}{
+		{"unset", nil, nil},
+		{"single", "de", []string{"de"}},
+		{"chain", []any{"pt-BR", "pt", "en"}, []string{"pt-BR", "pt", "en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLanguage(tt.input); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseLanguage() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
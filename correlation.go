@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// correlationIDPattern matches the correlation IDs accepted from config:
+// CI run numbers, UUIDs, and trace IDs.
+var correlationIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:/-]{1,128}$`)
+
+const (
+	// correlationLinkBase is the URL of the hidden correlation footer link;
+	// the ID is carried in its query.
+	correlationLinkBase = "https://t.me/?correlation_id="
+	// wordJoiner is the invisible text of the correlation footer link.
+	wordJoiner = "\u2060"
+)
+
+// newCorrelationID returns a random ID for runs that configure neither
+// correlation_id nor run_id.
+func newCorrelationID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// correlationFooter appends a link carrying the correlation ID of cfg to
+// text, written in parseMode. The link text is a word joiner, so readers
+// see nothing while the link stays in the message entities. Plain text has
+// no hidden entities and text without room for the link is left as is.
+func correlationFooter(cfg *Config, text, parseMode string) string {
+	link := correlationLinkBase + url.QueryEscape(cfg.CorrelationID)
+	var footer string
+	switch parseMode {
+	case "HTML":
+		footer = fmt.Sprintf(`<a href="%s">%s</a>`, link, wordJoiner)
+	case "MarkdownV2":
+		footer = fmt.Sprintf("[%s](%s)", wordJoiner, link)
+	default:
+		return text
+	}
+	if utf8.RuneCountInString(text)+1 > maxMessageLength {
+		return text
+	}
+	return text + footer
+}
+
+// withCorrelationFooter returns msg and fallbacks with the correlation
+// footer appended. When msg has no link of its own, its link preview is
+// disabled so Telegram does not preview the hidden link instead.
+func withCorrelationFooter(cfg *Config, msg TelegramMessage, fallbacks []deliveryFallback) (TelegramMessage, []deliveryFallback) {
+	if msg.LinkPreviewOptions == nil && !strings.Contains(msg.Text, "://") {
+		msg.DisableWebPagePreview = true
+	}
+	msg.Text = correlationFooter(cfg, msg.Text, msg.ParseMode)
+	footed := make([]deliveryFallback, len(fallbacks))
+	for i, fb := range fallbacks {
+		fb.text = correlationFooter(cfg, fb.text, fb.parseMode)
+		footed[i] = fb
+	}
+	return msg, footed
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteCorrelationID(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]any
+		env    string
+		want   string
+	}{
+		{
+			name:   "configured",
+			config: map[string]any{"correlation_id": "build-42", "run_id": "run-7"},
+			want:   "^build-42$",
+		},
+		{
+			name: "env",
+			env:  "trace:abc",
+			want: "^trace:abc$",
+		},
+		{
+			name:   "run id",
+			config: map[string]any{"run_id": "run-7"},
+			want:   "^run-7$",
+		},
+		{
+			name: "generated",
+			want: "^[0-9a-f]{16}$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TELEGRAM_CORRELATION_ID", tt.env)
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":1}`)})
+			})
+
+			dir := t.TempDir()
+			receipts := filepath.Join(dir, "receipts.jsonl")
+			config := map[string]any{
+				"bot_token":     "123:abc",
+				"chat_id":       "@test",
+				"receipts_file": receipts,
+				"state_file":    filepath.Join(dir, "state.json"),
+			}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+			p := &TelegramPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+			}
+			id, _ := resp.Outputs["correlation_id"].(string)
+			if !regexp.MustCompile(tt.want).MatchString(id) {
+				t.Errorf("correlation_id = %q, want match for %s", id, tt.want)
+			}
+			if got := readReceipts(t, receipts); len(got) != 1 || got[0].CorrelationID != id {
+				t.Errorf("receipts = %+v, want correlation_id %q", got, id)
+			}
+		})
+	}
+}
+
+func TestCorrelationFooter(t *testing.T) {
+	cfg := &Config{CorrelationID: "build-42"}
+	if got := correlationFooter(cfg, "Released", ""); got != "Released" {
+		t.Errorf("plain text footer = %q, want the text unchanged", got)
+	}
+	long := strings.Repeat("a", maxMessageLength)
+	if got := correlationFooter(cfg, long, "HTML"); got != long {
+		t.Error("expected no footer on a message at the length limit")
+	}
+}
+
+func TestExecuteCorrelationIDSkipped(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":      "123:abc",
+			"chat_id":        "@test",
+			"correlation_id": "build-42",
+			"notify_on":      map[string]any{"post_publish": false},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || resp.Outputs["skipped"] != true {
+		t.Fatalf("Execute() = %+v, %v; want skipped", resp, err)
+	}
+	if got := resp.Outputs["correlation_id"]; got != "build-42" {
+		t.Errorf("correlation_id = %v, want build-42", got)
+	}
+}
+
+func TestExecuteCorrelationFooter(t *testing.T) {
+	tests := []struct {
+		name       string
+		parseMode  string
+		previewURL string
+		wantSuffix string
+		// wantPreview reports whether the link preview is left enabled.
+		wantPreview bool
+	}{
+		{
+			name:       "MarkdownV2",
+			parseMode:  "MarkdownV2",
+			wantSuffix: "[\u2060](https://t.me/?correlation_id=build%2F42)",
+		},
+		{
+			name:       "HTML",
+			parseMode:  "HTML",
+			wantSuffix: `<a href="https://t.me/?correlation_id=build%2F42">` + "\u2060</a>",
+		},
+		{
+			name:        "keeps preview URL",
+			parseMode:   "HTML",
+			previewURL:  "https://example.com/releases/1.0.0",
+			wantSuffix:  `<a href="https://t.me/?correlation_id=build%2F42">` + "\u2060</a>",
+			wantPreview: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var msg TelegramMessage
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				_ = json.NewDecoder(r.Body).Decode(&msg)
+				_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":1}`)})
+			})
+
+			p := &TelegramPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"bot_token":            "123:abc",
+					"chat_id":              "@test",
+					"parse_mode":           tt.parseMode,
+					"preview_url_template": tt.previewURL,
+					"correlation_id":       "build/42",
+					"correlation_footer":   true,
+				},
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !strings.HasSuffix(msg.Text, tt.wantSuffix) {
+				t.Errorf("text = %q, want suffix %q", msg.Text, tt.wantSuffix)
+			}
+			preview := !msg.DisableWebPagePreview && (msg.LinkPreviewOptions == nil || !msg.LinkPreviewOptions.IsDisabled)
+			if preview != tt.wantPreview {
+				t.Errorf("link preview enabled = %v, want %v", preview, tt.wantPreview)
+			}
+		})
+	}
+}
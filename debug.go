@@ -0,0 +1,90 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// maskedSecret replaces secret values in the debug echo.
+const maskedSecret = "***"
+
+// botTokenPattern matches bot tokens embedded in config values, such as in
+// URLs.
+var botTokenPattern = regexp.MustCompile(`(\d+):[A-Za-z0-9_-]{35,}`)
+
+// debugEcho returns the request as the plugin received it from Relicta, with
+// secrets masked, for the debug output.
+func debugEcho(req plugin.ExecuteRequest) map[string]any {
+	config, _ := maskSecrets(req.Config, false).(map[string]any)
+	return map[string]any{
+		"hook":    string(req.Hook),
+		"dry_run": req.DryRun,
+		"config":  config,
+		"context": req.Context,
+	}
+}
+
+// isSecretKey reports whether the config option key holds a secret. Options
+// naming where a secret is read from, such as bot_token_env, do not.
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	return strings.HasSuffix(key, "token") || strings.Contains(key, "secret") || strings.Contains(key, "password")
+}
+
+// maskSecrets returns a copy of v with the values of secret options masked
+// and bot tokens inside other strings reduced to the bot ID. All values are
+// masked when secret is set, as for the headers of bot_token_source.
+func maskSecrets(v any, secret bool) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for key, item := range val {
+			out[key] = maskSecrets(item, secret || isSecretKey(key) || key == "headers")
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]any, len(val))
+		for key, item := range val {
+			out[key] = maskSecrets(item, secret || isSecretKey(key))
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = maskSecrets(item, secret)
+		}
+		return out
+	case []string:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = maskSecrets(item, secret)
+		}
+		return out
+	case string:
+		if secret && val != "" {
+			if m := botTokenPattern.FindStringSubmatch(val); m != nil && m[0] == val {
+				return m[1] + ":" + maskedSecret
+			}
+			return maskedSecret
+		}
+		return redactBotTokens(val)
+	default:
+		return v
+	}
+}
+
+// redactBotTokens reduces the bot tokens in s to their bot IDs.
+func redactBotTokens(s string) string {
+	return botTokenPattern.ReplaceAllString(s, "${1}:"+maskedSecret)
+}
+
+// maskBotToken reduces a bot token to its bot ID, or masks it entirely when
+// it has none.
+func maskBotToken(token string) string {
+	if id, _, ok := strings.Cut(token, ":"); ok {
+		return id + ":" + maskedSecret
+	}
+	return maskedSecret
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestMaskSecrets(t *testing.T) {
+	config := map[string]any{
+		"bot_token":   "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+		"chat_id":     "@repo_releases",
+		"template":    "{{.Version}}",
+		"dry_token":   "",
+		"webhook_url": "https://example.com/bot123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789/send",
+		"targets": []any{
+			map[string]any{"chat_id": "@eu_releases", "bot_token": "short", "bot_token_env": "EU_BOT_TOKEN"},
+		},
+		"bot_token_source": map[string]any{
+			"type":    "http",
+			"url":     "https://vault.example.com/token",
+			"headers": map[string]any{"Authorization": "Bearer s3cr3t"},
+		},
+	}
+	expected := map[string]any{
+		"bot_token":   "123456789:***",
+		"chat_id":     "@repo_releases",
+		"template":    "{{.Version}}",
+		"dry_token":   "",
+		"webhook_url": "https://example.com/bot123456789:***/send",
+		"targets": []any{
+			map[string]any{"chat_id": "@eu_releases", "bot_token": "***", "bot_token_env": "EU_BOT_TOKEN"},
+		},
+		"bot_token_source": map[string]any{
+			"type":    "http",
+			"url":     "https://vault.example.com/token",
+			"headers": map[string]any{"Authorization": "***"},
+		},
+	}
+
+	if got := maskSecrets(config, false); !reflect.DeepEqual(got, expected) {
+		t.Errorf("maskSecrets() = %v, want %v", got, expected)
+	}
+	if config["bot_token"] != "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789" {
+		t.Error("maskSecrets() modified the config")
+	}
+}
+
+func TestExecuteDebug(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	tests := []struct {
+		name    string
+		debug   bool
+		success bool
+	}{
+		{"debug", true, true},
+		{"debug on failure", true, false},
+		{"disabled", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]any{
+				"bot_token": "123:abc",
+				"chat_id":   "@repo_releases",
+				"debug":     tt.debug,
+			}
+			if !tt.success {
+				config["template_file"] = "missing.tmpl"
+			}
+			p := &TelegramPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.2.0", ReleaseNotes: "Notes"},
+				DryRun:  true,
+			})
+			if err != nil || resp.Success != tt.success {
+				t.Fatalf("Execute() = %+v, %v", resp, err)
+			}
+
+			echo, ok := resp.Outputs["debug"].(map[string]any)
+			if ok != tt.debug {
+				t.Fatalf("Outputs[debug] = %v, want it when debug is %v", resp.Outputs["debug"], tt.debug)
+			}
+			if !tt.debug {
+				return
+			}
+			if echo["hook"] != string(plugin.HookPostPublish) || echo["dry_run"] != true {
+				t.Errorf("debug hook = %v, dry_run = %v", echo["hook"], echo["dry_run"])
+			}
+			if cfg := echo["config"].(map[string]any); cfg["bot_token"] != maskedSecret || cfg["chat_id"] != "@repo_releases" {
+				t.Errorf("debug config = %v, want the token masked", cfg)
+			}
+			if releaseCtx := echo["context"].(plugin.ReleaseContext); releaseCtx.Version != "1.2.0" || releaseCtx.ReleaseNotes != "Notes" {
+				t.Errorf("debug context = %+v", releaseCtx)
+			}
+		})
+	}
+}
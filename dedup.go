@@ -0,0 +1,158 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// recipient is a chat thread a notification is delivered to.
+type recipient struct {
+	chatID   string
+	threadID int64
+}
+
+// primary returns the recipient of the primary chat.
+func (cfg *Config) primary() recipient {
+	return recipient{cfg.ChatID, cfg.MessageThreadID}
+}
+
+// recipient returns the chat thread of the target.
+func (t Target) recipient() recipient {
+	return recipient{t.ChatID, t.MessageThreadID}
+}
+
+// recipients returns the chats the notification goes to: the primary chat
+// and the targets of the configured component, without those in shadow
+// mode, each once.
+func (cfg *Config) recipients() []recipient {
+	all := []recipient{cfg.primary()}
+	for _, target := range cfg.componentTargets() {
+		if !target.AlwaysDryRun && !slices.Contains(all, target.recipient()) {
+			all = append(all, target.recipient())
+		}
+	}
+	return all
+}
+
+// markHandled records that the notification was delivered or spooled for r,
+// when deliveries are deduplicated.
+func (cfg *Config) markHandled(r recipient) {
+	if cfg.handled != nil {
+		cfg.handled[r] = true
+	}
+}
+
+// runDeliveryKey identifies a delivery of a hook notification for a release
+// to a chat thread within an external CI run. Releases of monorepo
+// components are told apart by the component.
+func runDeliveryKey(cfg *Config, hook plugin.Hook, version string, r recipient) string {
+	parts := []string{"run", cfg.RunID, string(hook), version, r.chatID, strconv.FormatInt(r.threadID, 10)}
+	if cfg.Component != "" {
+		parts = append(parts, cfg.Component)
+	}
+	return strings.Join(parts, "|")
+}
+
+// idempotencyKey identifies a delivery of a hook notification for a release
+// to a chat thread regardless of the run. The parts are hashed, so the state
+// file does not list the chats.
+func idempotencyKey(cfg *Config, hook plugin.Hook, version string, r recipient) string {
+	parts := []string{string(hook), version, r.chatID, strconv.FormatInt(r.threadID, 10), cfg.Component}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return "sent|" + hex.EncodeToString(sum[:16])
+}
+
+// deduplicated runs send unless the notification was already delivered to
+// every recipient within the dedup TTL. A rerun only sends to the recipients
+// without a delivery, listed in outputs["already_delivered"] otherwise.
+// Each recipient the send reached, or spooled the notification for, is
+// recorded on its own, so a rerun after a partial failure only retries the
+// failed chats. A send that strict mode failed is not recorded. With
+// idempotent, a delivery is identified by its hook, version, and chat;
+// otherwise by those and the run ID, and deduplication only applies when a
+// run ID is configured.
+func (p *TelegramPlugin) deduplicated(cfg *Config, req plugin.ExecuteRequest, send func() (*plugin.ExecuteResponse, error)) (*plugin.ExecuteResponse, error) {
+	var key func(recipient) string
+	var reason, rule, message string
+	switch {
+	case cfg.Idempotent:
+		key = func(r recipient) string { return idempotencyKey(cfg, req.Hook, req.Context.Version, r) }
+		reason = skipDuplicateNotification
+		rule = fmt.Sprintf("idempotent within dedup_ttl_seconds %d", cfg.DedupTTLSeconds)
+		message = fmt.Sprintf("Telegram %s notification for %s already delivered", req.Hook, req.Context.Version)
+	case cfg.RunID != "":
+		key = func(r recipient) string { return runDeliveryKey(cfg, req.Hook, req.Context.Version, r) }
+		reason = skipDuplicateRun
+		rule = fmt.Sprintf("run_id %s within dedup_ttl_seconds %d", cfg.RunID, cfg.DedupTTLSeconds)
+		message = fmt.Sprintf("Telegram notification already delivered for run %s", cfg.RunID)
+	default:
+		return send()
+	}
+	ttl := time.Duration(cfg.DedupTTLSeconds) * time.Second
+
+	state, err := loadState(cfg.StateFile)
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to check delivery state: %v", err),
+		}, nil
+	}
+	recipients := cfg.recipients()
+	var deliveredAt time.Time
+	delivered := map[recipient]bool{}
+	for _, r := range recipients {
+		if at, ok := state.Deliveries[key(r)]; ok && p.now().Sub(at) < ttl {
+			if at.After(deliveredAt) {
+				deliveredAt = at
+			}
+			delivered[r] = true
+		}
+	}
+	if len(delivered) == len(recipients) {
+		return skippedResponse(cfg, message, reason, rule, map[string]any{"delivered_at": deliveredAt.Format(time.RFC3339)}), nil
+	}
+
+	cfg.delivered = delivered
+	cfg.handled = map[recipient]bool{}
+	resp, err := send()
+	if err != nil || resp == nil || req.DryRun {
+		return resp, err
+	}
+	if resp.Outputs == nil {
+		resp.Outputs = map[string]any{}
+	}
+	if len(delivered) > 0 {
+		var chats []string
+		for _, r := range recipients {
+			if delivered[r] {
+				chats = append(chats, r.chatID)
+			}
+		}
+		resp.Outputs["already_delivered"] = chats
+	}
+	if _, degraded := resp.Outputs["degradations"]; degraded || len(cfg.handled) == 0 {
+		return resp, nil
+	}
+
+	now := p.now()
+	if err := p.updateState(cfg.StateFile, func(s *pluginState) {
+		s.pruneDeliveries(now.Add(-ttl))
+		if s.Deliveries == nil {
+			s.Deliveries = make(map[string]time.Time)
+		}
+		for r := range cfg.handled {
+			s.Deliveries[key(r)] = now
+		}
+	}); err != nil {
+		// The message went out; failing the hook now would only invite a duplicate.
+		resp.Outputs[stateErrorOutput] = errorText(err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteRunDeduplication(t *testing.T) {
+	var sent int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	clk := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":         "123:abc",
+			"chat_id":           "@test",
+			"run_id":            "run-42",
+			"dedup_ttl_seconds": 3600,
+			"state_file":        filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil || !resp.Success || resp.Outputs["skipped"] != nil {
+		t.Fatalf("first Execute() = %+v, %v; want delivery", resp, err)
+	}
+
+	resp, err = p.Execute(context.Background(), req)
+	if err != nil || !resp.Success || resp.Outputs["skip_reason"] != "duplicate_run" {
+		t.Fatalf("second Execute() = %+v, %v; want duplicate skip", resp, err)
+	}
+
+	// A different hook for the same run is delivered.
+	errReq := req
+	errReq.Hook = plugin.HookOnError
+	if resp, _ := p.Execute(context.Background(), errReq); resp.Outputs["skipped"] != nil {
+		t.Errorf("Execute() for another hook = %+v, want delivery", resp)
+	}
+
+	// Another monorepo component releasing the same version is delivered.
+	componentReq := req
+	componentReq.Config = maps.Clone(req.Config)
+	componentReq.Config["component"] = "payments-service"
+	if resp, _ := p.Execute(context.Background(), componentReq); resp.Outputs["skipped"] != nil {
+		t.Errorf("Execute() for another component = %+v, want delivery", resp)
+	}
+
+	// After the TTL the record expires.
+	clk.Advance(2 * time.Hour)
+	if resp, _ := p.Execute(context.Background(), req); resp.Outputs["skipped"] != nil {
+		t.Errorf("Execute() after TTL = %+v, want delivery", resp)
+	}
+
+	if sent != 4 {
+		t.Errorf("expected 4 messages sent, got %d", sent)
+	}
+}
+
+func TestExecuteWithoutRunIDDoesNotDeduplicate(t *testing.T) {
+	var sent int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":  "123:abc",
+			"chat_id":    "@test",
+			"state_file": filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.Execute(context.Background(), req); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+	if sent != 2 {
+		t.Errorf("expected 2 messages sent, got %d", sent)
+	}
+}
+
+func TestExecuteIdempotent(t *testing.T) {
+	var sent int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	clk := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":         "123:abc",
+			"chat_id":           "@test",
+			"idempotent":        true,
+			"dedup_ttl_seconds": 3600,
+			"state_file":        stateFile,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+
+	if resp, err := p.Execute(context.Background(), req); err != nil || !resp.Success || resp.Outputs["skipped"] != nil {
+		t.Fatalf("first Execute() = %+v, %v; want delivery", resp, err)
+	}
+
+	// A re-run, even under another run ID, is skipped.
+	rerun := req
+	rerun.Config = maps.Clone(req.Config)
+	rerun.Config["run_id"] = "run-43"
+	resp, err := p.Execute(context.Background(), rerun)
+	if err != nil || !resp.Success || resp.Outputs["skip_reason"] != "duplicate_notification" {
+		t.Fatalf("second Execute() = %+v, %v; want duplicate skip", resp, err)
+	}
+	if got := resp.Outputs["delivered_at"]; got != "2024-01-01T12:00:00Z" {
+		t.Errorf("delivered_at = %v", got)
+	}
+
+	// Another version or chat is delivered.
+	nextReq := req
+	nextReq.Context.Version = "1.0.1"
+	if resp, _ := p.Execute(context.Background(), nextReq); resp.Outputs["skipped"] != nil {
+		t.Errorf("Execute() for another version = %+v, want delivery", resp)
+	}
+	chatReq := req
+	chatReq.Config = maps.Clone(req.Config)
+	chatReq.Config["chat_id"] = "@other"
+	if resp, _ := p.Execute(context.Background(), chatReq); resp.Outputs["skipped"] != nil {
+		t.Errorf("Execute() for another chat = %+v, want delivery", resp)
+	}
+
+	// After the TTL the record expires.
+	clk.Advance(2 * time.Hour)
+	if resp, _ := p.Execute(context.Background(), req); resp.Outputs["skipped"] != nil {
+		t.Errorf("Execute() after TTL = %+v, want delivery", resp)
+	}
+
+	if sent != 4 {
+		t.Errorf("expected 4 messages sent, got %d", sent)
+	}
+}
+
+func TestExecuteIdempotentTargetErrors(t *testing.T) {
+	var mu sync.Mutex
+	sent := map[string]int{}
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		sent[msg.ChatID]++
+		first := sent[msg.ChatID] == 1
+		mu.Unlock()
+		if msg.ChatID == "@b" && first {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: http.StatusForbidden, Description: "Forbidden: bot was kicked"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":1}`)})
+	})
+
+	p := &TelegramPlugin{}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":  "123:abc",
+			"chat_ids":   []any{"@a", "@b"},
+			"idempotent": true,
+			"state_file": filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil || resp.Outputs["target_errors"] == nil {
+		t.Fatalf("first Execute() = %+v, %v; want a target error", resp, err)
+	}
+	// Only the failed chat gets the re-run; then every chat is recorded.
+	for run, wantSkip := range []bool{false, true} {
+		resp, err := p.Execute(context.Background(), req)
+		if err != nil || !resp.Success {
+			t.Fatalf("re-run %d: Execute() = %+v, %v; want success", run+1, resp, err)
+		}
+		if skipped := resp.Outputs["skipped"] == true; skipped != wantSkip {
+			t.Errorf("re-run %d: skipped = %v, want %v", run+1, skipped, wantSkip)
+		}
+		if !wantSkip && !reflect.DeepEqual(resp.Outputs["already_delivered"], []string{"@a"}) {
+			t.Errorf("re-run %d: already_delivered = %v, want [@a]", run+1, resp.Outputs["already_delivered"])
+		}
+	}
+	if want := map[string]int{"@a": 1, "@b": 2}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+}
+
+func TestExecuteRunDeduplicationPrimaryFailure(t *testing.T) {
+	var mu sync.Mutex
+	sent := map[string]int{}
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		sent[msg.ChatID]++
+		first := sent[msg.ChatID] == 1
+		mu.Unlock()
+		if msg.ChatID == "@a" && first {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: http.StatusBadRequest, Description: "Bad Request: chat not found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":1}`)})
+	})
+
+	p := &TelegramPlugin{}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":  "123:abc",
+			"chat_ids":   []any{"@a", "@b"},
+			"run_id":     "run-42",
+			"state_file": filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+
+	if resp, err := p.Execute(context.Background(), req); err != nil || resp.Success {
+		t.Fatalf("first Execute() = %+v, %v; want the primary chat failure", resp, err)
+	}
+	// The chat that received the notification is not sent to again.
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil || !resp.Success || resp.Outputs["skipped"] != nil {
+		t.Fatalf("re-run Execute() = %+v, %v; want delivery", resp, err)
+	}
+	if want := map[string]int{"@a": 2, "@b": 1}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	cfg := &Config{}
+	chat := recipient{"-1001234567890", 42}
+	key := idempotencyKey(cfg, plugin.HookPostPublish, "1.0.0", chat)
+	if key != idempotencyKey(cfg, plugin.HookPostPublish, "1.0.0", chat) {
+		t.Error("idempotencyKey() is not stable")
+	}
+	if strings.Contains(key, chat.chatID) {
+		t.Errorf("idempotencyKey() = %q, want the chat hashed", key)
+	}
+	if key == idempotencyKey(cfg, plugin.HookPostPublish, "1.0.0", recipient{chat.chatID, 7}) {
+		t.Error("idempotencyKey() ignores the thread")
+	}
+	if key == idempotencyKey(cfg, plugin.HookPostPublish, "1.0.0", recipient{"@other", 42}) {
+		t.Error("idempotencyKey() ignores the chat")
+	}
+	if key == idempotencyKey(cfg, plugin.HookOnSuccess, "1.0.0", chat) {
+		t.Error("idempotencyKey() ignores the hook")
+	}
+}
+
+func TestRunDeliveryKey(t *testing.T) {
+	cfg := &Config{RunID: "run-42"}
+	key := runDeliveryKey(cfg, plugin.HookPostPublish, "1.0.0", recipient{"@a", 0})
+	if key == runDeliveryKey(cfg, plugin.HookPostPublish, "1.0.0", recipient{"@b", 0}) {
+		t.Error("runDeliveryKey() ignores the chat")
+	}
+	if key == runDeliveryKey(cfg, plugin.HookPostPublish, "1.0.0", recipient{"@a", 7}) {
+		t.Error("runDeliveryKey() ignores the thread")
+	}
+}
+
+func TestRecipients(t *testing.T) {
+	cfg := &Config{
+		ChatID:      "@news",
+		chatTargets: []Target{{ChatID: "@a"}, {ChatID: "@news"}},
+		Targets:     []Target{{ChatID: "@a"}, {ChatID: "@a", MessageThreadID: 3}, {ChatID: "@shadow", AlwaysDryRun: true}},
+	}
+	want := []recipient{{"@news", 0}, {"@a", 0}, {"@a", 3}}
+	if got := cfg.recipients(); !reflect.DeepEqual(got, want) {
+		t.Errorf("recipients() = %v, want %v", got, want)
+	}
+}
+
+func TestExecuteRunDeduplicationNewTarget(t *testing.T) {
+	var mu sync.Mutex
+	sent := map[string]int{}
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		sent[msg.ChatID]++
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	config := map[string]any{
+		"bot_token":  "123:abc",
+		"chat_id":    "@a",
+		"run_id":     "run-42",
+		"state_file": filepath.Join(t.TempDir(), "state.json"),
+	}
+	execute := func() *plugin.ExecuteResponse {
+		t.Helper()
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook:    plugin.HookPostPublish,
+			Config:  config,
+			Context: plugin.ReleaseContext{Version: "1.0.0"},
+		})
+		if err != nil || !resp.Success {
+			t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+		}
+		return resp
+	}
+
+	execute()
+	// A chat added for the re-run has not been delivered to.
+	config["chat_ids"] = []any{"@b"}
+	if resp := execute(); resp.Outputs["skipped"] != nil {
+		t.Errorf("Execute() with a new chat = %+v, want delivery", resp)
+	}
+	if resp := execute(); resp.Outputs["skip_reason"] != "duplicate_run" {
+		t.Errorf("third Execute() = %+v, want duplicate skip", resp)
+	}
+	if sent["@b"] != 1 {
+		t.Errorf("sent = %v, want @b once", sent)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// digestErrorOutput records why a due digest was not sent.
+var digestErrorOutput = registerDegradation("digest_error", describeError("digest not sent"))
+
+// digestState holds the releases collected for a chat's digest.
+type digestState struct {
+	// Schedule is the digest_schedule the releases were collected under.
+	Schedule string `json:"schedule"`
+	// PeriodStart is the start of the period the digest covers, in UTC.
+	PeriodStart time.Time `json:"period_start"`
+	// Releases are the collected releases, oldest first.
+	Releases []digestRelease `json:"releases"`
+}
+
+// digestRelease is a release collected into a digest.
+type digestRelease struct {
+	Component   string `json:"component,omitempty"`
+	Version     string `json:"version"`
+	ReleaseType string `json:"release_type,omitempty"`
+	Features    int    `json:"features,omitempty"`
+	Fixes       int    `json:"fixes,omitempty"`
+	Breaking    int    `json:"breaking,omitempty"`
+}
+
+// digestKey identifies the digest of a chat thread within the state file.
+func digestKey(chatID string, threadID int64) string {
+	return strings.Join([]string{"digest", chatID, strconv.FormatInt(threadID, 10)}, "|")
+}
+
+// digestPeriodStart returns the start of the digest period containing t:
+// midnight UTC for daily digests, and midnight UTC on Monday for weekly ones.
+func digestPeriodStart(schedule string, t time.Time) time.Time {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if schedule == render.DigestWeekly {
+		// Weekday counts from Sunday; weeks start on Monday.
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	}
+	return start
+}
+
+// digestDue reports whether d holds releases from a period that has ended
+// by now, or from a different schedule.
+func (d *digestState) digestDue(schedule string, now time.Time) bool {
+	if d == nil || len(d.Releases) == 0 {
+		return false
+	}
+	return d.Schedule != schedule || d.PeriodStart.Before(digestPeriodStart(schedule, now))
+}
+
+// newDigestRelease summarizes releaseCtx of component for a digest.
+func newDigestRelease(component string, releaseCtx plugin.ReleaseContext) digestRelease {
+	release := digestRelease{Component: component, Version: releaseCtx.Version, ReleaseType: releaseCtx.ReleaseType}
+	if changes := releaseCtx.Changes; changes != nil {
+		release.Features = len(changes.Features)
+		release.Fixes = len(changes.Fixes)
+		release.Breaking = len(changes.Breaking)
+	}
+	return release
+}
+
+// addToDigest collects a release into the digest of the current period
+// instead of announcing it. Releases of all components share the chat's
+// digest. A release already in the digest, e.g. from both post_publish and
+// on_success, is replaced. Releases left over from a digest that could not
+// be sent stay in it and go out with the next flush.
+func (p *TelegramPlugin) addToDigest(cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	key := digestKey(cfg.ChatID, cfg.MessageThreadID)
+	release := newDigestRelease(cfg.Component, releaseCtx)
+	now := p.now()
+
+	var digest digestState
+	add := func(s *pluginState) {
+		d := s.Digests[key]
+		if d == nil || len(d.Releases) == 0 {
+			d = &digestState{Schedule: cfg.DigestSchedule, PeriodStart: digestPeriodStart(cfg.DigestSchedule, now)}
+		}
+		i := slices.IndexFunc(d.Releases, func(r digestRelease) bool {
+			return r.Component == release.Component && r.Version == release.Version
+		})
+		if i >= 0 {
+			d.Releases[i] = release
+		} else {
+			d.Releases = append(d.Releases, release)
+		}
+		if s.Digests == nil {
+			s.Digests = make(map[string]*digestState)
+		}
+		s.Digests[key] = d
+		digest = *d
+	}
+
+	var err error
+	if dryRun {
+		var state *pluginState
+		if state, err = loadState(cfg.StateFile); err == nil {
+			add(state)
+		}
+	} else {
+		err = p.updateState(cfg.StateFile, add)
+	}
+	if err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to update digest state: %v", err),
+		}, nil
+	}
+
+	message := fmt.Sprintf("Release %s added to the %s digest", releaseCtx.Version, cfg.DigestSchedule)
+	if dryRun {
+		message = fmt.Sprintf("Would add release %s to the %s digest", releaseCtx.Version, cfg.DigestSchedule)
+	}
+	return skippedResponse(cfg, message, skipDigest, "digest_schedule "+cfg.DigestSchedule, map[string]any{
+		"chat_id":             cfg.ChatID,
+		"version":             releaseCtx.Version,
+		"digest_pending":      len(digest.Releases),
+		"digest_period_start": digest.PeriodStart.Format(time.RFC3339),
+	}), nil
+}
+
+// flushingDigest runs execute after sending the digest of a period that has
+// ended, so the first hook execution after a schedule boundary delivers the
+// previous period's releases as one message. The outcome is added to the
+// response outputs: digest_sent and digest_releases, or digest_error when
+// the digest could not be sent and is kept for the next execution.
+func (p *TelegramPlugin) flushingDigest(ctx context.Context, cfg *Config, dryRun bool, execute func() (*plugin.ExecuteResponse, error)) (*plugin.ExecuteResponse, error) {
+	if cfg.DigestSchedule == "" {
+		return execute()
+	}
+
+	outputs := p.flushDigest(ctx, cfg, dryRun)
+	resp, err := execute()
+	if err != nil || resp == nil || len(outputs) == 0 {
+		return resp, err
+	}
+	if resp.Outputs == nil {
+		resp.Outputs = map[string]any{}
+	}
+	maps.Copy(resp.Outputs, outputs)
+	return resp, nil
+}
+
+// flushDigest sends the chat's digest when its period has ended and removes
+// it from the state file. In dry-run mode nothing is sent or removed.
+func (p *TelegramPlugin) flushDigest(ctx context.Context, cfg *Config, dryRun bool) map[string]any {
+	key := digestKey(cfg.ChatID, cfg.MessageThreadID)
+	state, err := loadState(cfg.StateFile)
+	if err != nil {
+		return map[string]any{digestErrorOutput: errorText(err)}
+	}
+	digest := state.Digests[key]
+	if !digest.digestDue(cfg.DigestSchedule, p.now()) {
+		return nil
+	}
+	if dryRun {
+		return map[string]any{"digest_due": len(digest.Releases)}
+	}
+
+	releases := make([]render.DigestRelease, len(digest.Releases))
+	for i, r := range digest.Releases {
+		releases[i] = render.DigestRelease(r)
+	}
+	text := p.renderer(cfg).Digest(digest.Schedule, digest.PeriodStart, releases)
+	messageID, err := p.deliver(ctx, cfg, newMessage(cfg, text))
+	if err != nil {
+		return map[string]any{digestErrorOutput: errorText(err)}
+	}
+
+	outputs := map[string]any{
+		"digest_sent":       true,
+		"digest_releases":   len(digest.Releases),
+		"digest_message_id": messageID,
+	}
+	if err := p.updateState(cfg.StateFile, func(s *pluginState) {
+		// Releases added while the digest was being sent belong to the next one.
+		d := s.Digests[key]
+		if d != nil {
+			d.Releases = slices.DeleteFunc(d.Releases, func(r digestRelease) bool {
+				return slices.Contains(digest.Releases, r)
+			})
+		}
+		if d == nil || len(d.Releases) == 0 {
+			delete(s.Digests, key)
+			return
+		}
+		d.Schedule = cfg.DigestSchedule
+		d.PeriodStart = digestPeriodStart(cfg.DigestSchedule, p.now())
+	}); err != nil {
+		// The digest went out; it is resent next time unless the state is fixed.
+		outputs[stateErrorOutput] = errorText(err)
+	}
+	return outputs
+}
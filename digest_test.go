@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestDigestPeriodStart(t *testing.T) {
+	// 2024-03-13 is a Wednesday.
+	at := time.Date(2024, 3, 13, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule string
+		t        time.Time
+		expected time.Time
+	}{
+		{"daily", "daily", at, time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"weekly", "weekly", at, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"weekly on monday", "weekly", time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"weekly on sunday", "weekly", time.Date(2024, 3, 17, 23, 0, 0, 0, time.UTC), time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{"converted to UTC", "daily", time.Date(2024, 3, 14, 1, 0, 0, 0, time.FixedZone("CET", 3600)), time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := digestPeriodStart(tt.schedule, tt.t); !got.Equal(tt.expected) {
+				t.Errorf("digestPeriodStart() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecuteDigest(t *testing.T) {
+	var sent []TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		sent = append(sent, msg)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	clk := newFakeClock(time.Date(2024, 3, 13, 9, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	execute := func(hook plugin.Hook, version string) *plugin.ExecuteResponse {
+		t.Helper()
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook: hook,
+			Config: map[string]any{
+				"bot_token":       "123:abc",
+				"chat_id":         "@test",
+				"parse_mode":      "HTML",
+				"digest_schedule": "daily",
+				"state_file":      stateFile,
+			},
+			Context: plugin.ReleaseContext{
+				Version:     version,
+				ReleaseType: "patch",
+				Changes: &plugin.CategorizedChanges{
+					Fixes: []plugin.ConventionalCommit{{Description: "fix"}},
+				},
+			},
+		})
+		if err != nil || !resp.Success {
+			t.Fatalf("Execute(%s, %s) = %+v, %v; want success", hook, version, resp, err)
+		}
+		return resp
+	}
+
+	execute(plugin.HookPostPublish, "1.0.1")
+	execute(plugin.HookOnSuccess, "1.0.1")
+	clk.Advance(time.Hour)
+	resp := execute(plugin.HookOnSuccess, "1.0.2")
+	if len(sent) != 0 {
+		t.Fatalf("sent %d messages during the period, want 0", len(sent))
+	}
+	if resp.Outputs["digest_pending"] != 2 {
+		t.Errorf("digest_pending = %v, want 2", resp.Outputs["digest_pending"])
+	}
+
+	// The first execution on the next day sends the digest.
+	clk.Advance(24 * time.Hour)
+	resp = execute(plugin.HookOnError, "1.0.3")
+	if resp.Outputs["digest_sent"] != true || resp.Outputs["digest_releases"] != 2 {
+		t.Fatalf("outputs = %v, want the digest sent", resp.Outputs)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("sent %d messages, want the digest and the error notification", len(sent))
+	}
+	want := "🗓 <b>Release digest for 2024-03-13</b>\n\n" +
+		"📦 <code>1.0.1</code> (patch): 1 bug fixes\n" +
+		"📦 <code>1.0.2</code> (patch): 1 bug fixes\n"
+	if sent[0].Text != want {
+		t.Errorf("digest = %q, want %q", sent[0].Text, want)
+	}
+
+	// The digest is removed once sent.
+	resp = execute(plugin.HookOnSuccess, "1.0.4")
+	if _, ok := resp.Outputs["digest_sent"]; ok || len(sent) != 2 {
+		t.Errorf("digest sent again: outputs = %v", resp.Outputs)
+	}
+	if resp.Outputs["digest_pending"] != 1 {
+		t.Errorf("digest_pending = %v, want 1", resp.Outputs["digest_pending"])
+	}
+}
+
+func TestExecuteDigestKeptOnFailure(t *testing.T) {
+	fail := true
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: chat not found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	clk := newFakeClock(time.Date(2024, 3, 13, 9, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookOnSuccess,
+		Config: map[string]any{
+			"bot_token":       "123:abc",
+			"chat_id":         "@test",
+			"digest_schedule": "weekly",
+			"state_file":      filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+	if _, err := p.Execute(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	clk.Advance(7 * 24 * time.Hour)
+	req.Context.Version = "1.1.0"
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if _, ok := resp.Outputs["digest_error"]; !ok {
+		t.Fatalf("outputs = %v, want digest_error", resp.Outputs)
+	}
+
+	// The failed digest is retried and includes the later release.
+	fail = false
+	req.Hook = plugin.HookPostVersion
+	resp, err = p.Execute(context.Background(), req)
+	if err != nil || resp.Outputs["digest_releases"] != 2 {
+		t.Errorf("Execute() = %+v, %v; want both releases sent", resp, err)
+	}
+}
+
+func TestExecuteDigestDryRun(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("dry run sent a message")
+	})
+
+	p := &TelegramPlugin{}
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookOnSuccess,
+		Config: map[string]any{
+			"bot_token":       "123:abc",
+			"chat_id":         "@test",
+			"digest_schedule": "daily",
+			"state_file":      stateFile,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+		DryRun:  true,
+	})
+	if err != nil || !resp.Success || resp.Outputs["digest_pending"] != 1 {
+		t.Fatalf("Execute() = %+v, %v; want the release counted", resp, err)
+	}
+
+	state, err := loadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Digests) != 0 {
+		t.Errorf("dry run stored digests %v", state.Digests)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// changelogDocumentErrorOutput records why a changelog document part was not
+// posted.
+var changelogDocumentErrorOutput = registerDegradation("changelog_document_error", describeError("changelog document upload failed"))
+
+const (
+	// cloudDocumentLimit is the largest file the public Bot API accepts.
+	cloudDocumentLimit = 50 << 20
+	// localDocumentLimit is the largest file a local Bot API server accepts.
+	localDocumentLimit = 2000 << 20
+	// documentUploadAttempts bounds the attempts per document part.
+	documentUploadAttempts = 3
+	// minUploadBackoff is the wait before the first retry of an upload that
+	// failed without a retry_after hint; it doubles per attempt.
+	minUploadBackoff = 2 * time.Second
+)
+
+// documentLimit returns the largest document part to upload: the configured
+// size, or the limit of the Bot API server in use.
+func (cfg *Config) documentLimit() int {
+	if cfg.ChangelogDocumentMaxBytes > 0 {
+		return cfg.ChangelogDocumentMaxBytes
+	}
+	if cfg.APIURL != "" {
+		return localDocumentLimit
+	}
+	return cloudDocumentLimit
+}
+
+// splitDocument splits content into parts of at most limit bytes, breaking
+// between lines where possible and never inside a UTF-8 character.
+func splitDocument(content string, limit int) []string {
+	var parts []string
+	for len(content) > limit {
+		cut := strings.LastIndexByte(content[:limit], '\n') + 1
+		if cut == 0 {
+			// A single line is over the limit; cut at a character boundary.
+			cut = limit
+			for cut > 0 && !utf8.RuneStart(content[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				_, cut = utf8.DecodeRuneInString(content)
+			}
+		}
+		parts = append(parts, content[:cut])
+		content = content[cut:]
+	}
+	if content != "" || len(parts) == 0 {
+		parts = append(parts, content)
+	}
+	return parts
+}
+
+// documentPartNames returns the file names of n changelog parts:
+// changelog.md for a single part, changelog-1of3.md and so on otherwise.
+func documentPartNames(n int) []string {
+	if n == 1 {
+		return []string{"changelog.md"}
+	}
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("changelog-%dof%d.md", i+1, n)
+	}
+	return names
+}
+
+// documentCaption returns the plain-text caption of part i, listing every
+// part so readers know what to download.
+func documentCaption(version string, names []string, i int) string {
+	if len(names) == 1 {
+		return fmt.Sprintf("📄 Changelog %s", version)
+	}
+	return fmt.Sprintf("📄 Changelog %s, part %d of %d\nParts: %s", version, i+1, len(names), strings.Join(names, ", "))
+}
+
+// sendChangelogDocument posts the release notes as a Markdown document
+// replying to the success notification. Notes over the document limit are
+// split into numbered parts uploaded one after another, each retried with
+// back-off. The part names are reported in changelog_document_parts; a
+// failed upload stops the remaining parts and sets changelog_document_error.
+func (p *TelegramPlugin) sendChangelogDocument(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, outputs map[string]any) {
+	if strings.TrimSpace(releaseCtx.ReleaseNotes) == "" {
+		return
+	}
+	parts := splitDocument(releaseCtx.ReleaseNotes, cfg.documentLimit())
+	names := documentPartNames(len(parts))
+	if dryRun {
+		outputs["changelog_document_parts"] = names
+		return
+	}
+
+	doc := TelegramDocument{
+		ChatID:              cfg.ChatID,
+		MessageThreadID:     cfg.MessageThreadID,
+		DisableNotification: true,
+	}
+	if messageID, ok := outputs["message_id"].(int64); ok {
+		doc.ReplyParameters = &ReplyParameters{MessageID: messageID, AllowSendingWithoutReply: true}
+	}
+
+	var uploaded []string
+	for i, part := range parts {
+		doc.Caption = documentCaption(releaseCtx.Version, names, i)
+		if err := p.uploadDocument(ctx, cfg, doc, names[i], []byte(part)); err != nil {
+			outputs[changelogDocumentErrorOutput] = fmt.Sprintf("%s: %s", names[i], errorText(err))
+			break
+		}
+		uploaded = append(uploaded, names[i])
+	}
+	if len(uploaded) > 0 {
+		outputs["changelog_document_parts"] = uploaded
+	}
+}
+
+// uploadDocument uploads one document part, retrying rate limits, server
+// errors, and network failures. The wait honors the Bot API's retry_after
+// hint and otherwise doubles from minUploadBackoff.
+func (p *TelegramPlugin) uploadDocument(ctx context.Context, cfg *Config, doc TelegramDocument, name string, content []byte) error {
+	return p.upload(ctx, cfg, p.sendDocument, doc, name, content)
+}
+
+// uploadPhoto uploads a photo with the retries of uploadDocument.
+func (p *TelegramPlugin) uploadPhoto(ctx context.Context, cfg *Config, doc TelegramDocument, name string, content []byte) error {
+	return p.upload(ctx, cfg, p.sendPhoto, doc, name, content)
+}
+
+// upload sends a file with send, retrying as uploadDocument describes.
+func (p *TelegramPlugin) upload(ctx context.Context, cfg *Config, send func(context.Context, *Config, TelegramDocument, string, []byte) (*TelegramSentMessage, error), doc TelegramDocument, name string, content []byte) error {
+	breaker := p.circuitBreaker(cfg)
+	backoff := minUploadBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = breaker.check(p.now()); err != nil {
+			return err
+		}
+		if _, err = send(ctx, cfg, doc, name, content); err == nil {
+			return nil
+		}
+		breaker.recordError(err, p.now())
+		if attempt == documentUploadAttempts || !retryable(err) {
+			return err
+		}
+
+		wait := backoff
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			wait = time.Duration(apiErr.RetryAfter) * time.Second
+		}
+		if sleepErr := sleepContext(ctx, p.clockOrDefault(), wait); sleepErr != nil {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// retryable reports whether a failed send or upload may succeed when
+// retried: rate limits, server errors, and failures that never reached the
+// API.
+func retryable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return !errors.Is(err, errCircuitOpen)
+	}
+	return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= http.StatusInternalServerError
+}
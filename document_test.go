@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestSplitDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		limit    int
+		expected []string
+	}{
+		{"fits", "a\nb\n", 10, []string{"a\nb\n"}},
+		{"between lines", "aaa\nbbb\nccc\n", 8, []string{"aaa\nbbb\n", "ccc\n"}},
+		{"long line", "abcdefgh", 3, []string{"abc", "def", "gh"}},
+		{"multibyte", "ééé", 3, []string{"é", "é", "é"}},
+		{"limit below a character", "éé", 1, []string{"é", "é"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitDocument(tt.content, tt.limit); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("splitDocument() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDocumentPartNames(t *testing.T) {
+	if got := documentPartNames(1); !reflect.DeepEqual(got, []string{"changelog.md"}) {
+		t.Errorf("documentPartNames(1) = %v", got)
+	}
+	want := []string{"changelog-1of3.md", "changelog-2of3.md", "changelog-3of3.md"}
+	if got := documentPartNames(3); !reflect.DeepEqual(got, want) {
+		t.Errorf("documentPartNames(3) = %v, want %v", got, want)
+	}
+}
+
+func TestRetryableUpload(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", &APIError{Code: http.StatusTooManyRequests}, true},
+		{"server error", &APIError{Code: http.StatusBadGateway}, true},
+		{"bad request", &APIError{Code: http.StatusBadRequest}, false},
+		{"network", errors.New("connection reset"), true},
+		{"circuit open", errCircuitOpen, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// uploadedDocument is a decoded sendDocument request.
+type uploadedDocument struct {
+	name, caption, reply, content string
+}
+
+func TestChangelogDocumentParts(t *testing.T) {
+	var mu sync.Mutex
+	var uploads []uploadedDocument
+	attempts := 0
+	server := useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !strings.HasSuffix(r.URL.Path, "/sendDocument") {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":5}`)})
+			return
+		}
+		attempts++
+		if attempts == 2 {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{
+				ErrorCode:   http.StatusTooManyRequests,
+				Description: "Too Many Requests: retry after 3",
+				Parameters:  &ResponseParameters{RetryAfter: 3},
+			})
+			return
+		}
+		file, header, err := r.FormFile("document")
+		if err != nil {
+			t.Errorf("FormFile() error = %v", err)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		uploads = append(uploads, uploadedDocument{
+			name:    header.Filename,
+			caption: r.FormValue("caption"),
+			reply:   r.FormValue("reply_parameters"),
+			content: string(content),
+		})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":6}`)})
+	})
+
+	// The default endpoint is unreachable, so requests only arrive when
+	// api_url is used.
+	oldURL := telegramAPIBaseURL
+	telegramAPIBaseURL = "http://127.0.0.1:0"
+	t.Cleanup(func() { telegramAPIBaseURL = oldURL })
+
+	clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":                    "123:abc",
+			"chat_id":                      "@test",
+			"api_url":                      server.URL + "/",
+			"changelog_document":           true,
+			"changelog_document_max_bytes": 12,
+		},
+		Context: plugin.ReleaseContext{Version: "2.0.0", ReleaseNotes: "- one\n- two\n- three\n"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+
+	wantParts := []string{"changelog-1of2.md", "changelog-2of2.md"}
+	if got := resp.Outputs["changelog_document_parts"]; !reflect.DeepEqual(got, wantParts) {
+		t.Errorf("changelog_document_parts = %v, want %v", got, wantParts)
+	}
+	if _, ok := resp.Outputs["changelog_document_error"]; ok {
+		t.Errorf("changelog_document_error = %v", resp.Outputs["changelog_document_error"])
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []uploadedDocument{
+		{
+			name:    "changelog-1of2.md",
+			caption: "📄 Changelog 2.0.0, part 1 of 2\nParts: changelog-1of2.md, changelog-2of2.md",
+			reply:   `{"message_id":5,"allow_sending_without_reply":true}`,
+			content: "- one\n- two\n",
+		},
+		{
+			name:    "changelog-2of2.md",
+			caption: "📄 Changelog 2.0.0, part 2 of 2\nParts: changelog-1of2.md, changelog-2of2.md",
+			reply:   `{"message_id":5,"allow_sending_without_reply":true}`,
+			content: "- three\n",
+		},
+	}
+	if !reflect.DeepEqual(uploads, want) {
+		t.Errorf("uploads = %+v, want %+v", uploads, want)
+	}
+	if got := clk.Slept(); !reflect.DeepEqual(got, []time.Duration{3 * time.Second}) {
+		t.Errorf("slept = %v, want the retry_after wait", got)
+	}
+}
+
+func TestChangelogDocumentGivesUp(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sendDocument") {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{ErrorCode: http.StatusBadGateway, Description: "Bad Gateway"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":5}`)})
+	})
+
+	clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":          "123:abc",
+			"chat_id":            "@test",
+			"changelog_document": true,
+		},
+		Context: plugin.ReleaseContext{Version: "2.0.0", ReleaseNotes: "- one\n"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if got, _ := resp.Outputs["changelog_document_error"].(string); !strings.HasPrefix(got, "changelog.md: ") {
+		t.Errorf("changelog_document_error = %q", got)
+	}
+	if got := clk.Slept(); !reflect.DeepEqual(got, []time.Duration{2 * time.Second, 4 * time.Second}) {
+		t.Errorf("slept = %v, want exponential backoff", got)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestCompileExcludePatterns(t *testing.T) {
+	if _, err := compileExcludePatterns([]string{`^Revert`, `\(bot\)$`}); err != nil {
+		t.Errorf("compileExcludePatterns() error = %v", err)
+	}
+	if _, err := compileExcludePatterns([]string{`^Revert`, `(unclosed`}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestExecuteChangelogExcludePatterns(t *testing.T) {
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":                  "123:abc",
+			"chat_id":                    "@test",
+			"parse_mode":                 "HTML",
+			"include_changelog":          true,
+			"changelog_exclude_patterns": []any{`^- Revert "`, `^- Merge pull request`},
+		},
+		Context: plugin.ReleaseContext{
+			Version:      "1.0.0",
+			ReleaseNotes: "- fix a\n- Revert \"fix b\"\n- Merge pull request #7 from x/y\n- fix c",
+		},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+
+	want := "<b>Release Notes:</b>\n- fix a\n- fix c"
+	if !strings.HasSuffix(got.Text, want) {
+		t.Errorf("message text = %q, want suffix %q", got.Text, want)
+	}
+}
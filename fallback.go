@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// fallbackOutput records the formatting fallback the message was sent with.
+var fallbackOutput = registerDegradation("fallback", func(v any) []string {
+	return []string{fmt.Sprintf("formatting fallback %v", v)}
+})
+
+// Fallback names reported in Outputs when a message had to be degraded.
+const (
+	fallbackAlternateParseMode = "alternate_parse_mode"
+	fallbackPlainText          = "plain_text"
+	fallbackMinimalPlainText   = "minimal_plain_text"
+)
+
+// deliveryFallback is an alternative rendering tried when Telegram rejects
+// the formatting of the previous attempt.
+type deliveryFallback struct {
+	name      string
+	parseMode string
+	text      string
+}
+
+// delivery describes a message delivered by deliverWithFallbacks.
+type delivery struct {
+	// fallback is the name of the fallback that was delivered, or "" if the
+	// original message went out.
+	fallback string
+	// fallbackReason is why Telegram rejected the original message when a
+	// fallback was delivered.
+	fallbackReason string
+	// messageID is the ID of the sent message.
+	messageID int64
+	// api is the duration of the first send attempt.
+	api time.Duration
+	// retries is the time spent resending with fallbacks.
+	retries time.Duration
+}
+
+// deliverWithFallbacks sends msg, trying each fallback in order while
+// Telegram keeps rejecting the message formatting. Before the first
+// plain-text fallback, the content of msg itself is tried as plain text.
+func (p *TelegramPlugin) deliverWithFallbacks(ctx context.Context, cfg *Config, msg TelegramMessage, fallbacks []deliveryFallback) (delivery, error) {
+	var sent delivery
+	fallbacks = withPlainText(msg, fallbacks)
+	if cfg.CorrelationFooter {
+		msg, fallbacks = withCorrelationFooter(cfg, msg, fallbacks)
+	}
+	start := p.now()
+	messageID, err := p.deliver(ctx, cfg, msg)
+	firstDone := p.now()
+	for _, fb := range fallbacks {
+		if err == nil || !isParseEntitiesError(err) {
+			break
+		}
+		if sent.fallbackReason == "" {
+			sent.fallbackReason = failureReason(err)
+		}
+		msg.ParseMode = fb.parseMode
+		msg.Text = fb.text
+		sent.fallback = fb.name
+		messageID, err = p.deliver(ctx, cfg, msg)
+	}
+	if err != nil {
+		return delivery{}, err
+	}
+	sent.messageID = messageID
+	sent.api = firstDone.Sub(start)
+	sent.retries = p.now().Sub(firstDone)
+	return sent, nil
+}
+
+// withPlainText returns fallbacks with the content of msg stripped to plain
+// text inserted before the first plain-text fallback, so a message whose
+// formatting Telegram cannot parse still goes out in full. Plain-text
+// messages, and messages with no text left without their formatting, keep
+// their fallbacks.
+func withPlainText(msg TelegramMessage, fallbacks []deliveryFallback) []deliveryFallback {
+	if msg.ParseMode == "" {
+		return fallbacks
+	}
+	text := strings.TrimSpace(render.StripFormatting(msg.Text, msg.ParseMode))
+	if text == "" {
+		return fallbacks
+	}
+	i := slices.IndexFunc(fallbacks, func(fb deliveryFallback) bool { return fb.parseMode == "" })
+	if i < 0 {
+		i = len(fallbacks)
+	}
+	return slices.Insert(slices.Clone(fallbacks), i, deliveryFallback{name: fallbackPlainText, text: text})
+}
+
+// successFallbacks returns the fallbacks for a success notification: the
+// default message in the other parse mode, then a minimal plain-text message.
+func (p *TelegramPlugin) successFallbacks(cfg *Config, releaseCtx plugin.ReleaseContext) []deliveryFallback {
+	var fallbacks []deliveryFallback
+	if cfg.Template == "" {
+		if alt := alternateParseMode(cfg.ParseMode); alt != "" {
+			altCfg := *cfg
+			altCfg.ParseMode = alt
+			fallbacks = append(fallbacks, deliveryFallback{
+				name:      fallbackAlternateParseMode,
+				parseMode: alt,
+				text:      p.buildSuccessMessage(&altCfg, releaseCtx),
+			})
+		}
+	}
+	return append(fallbacks, deliveryFallback{
+		name: fallbackMinimalPlainText,
+		text: minimalSuccessMessage(cfg, releaseCtx),
+	})
+}
+
+// errorFallbacks returns the fallbacks for an error notification.
+func errorFallbacks(releaseCtx plugin.ReleaseContext) []deliveryFallback {
+	return []deliveryFallback{{
+		name: fallbackMinimalPlainText,
+		text: fmt.Sprintf("❌ Release %s failed. Please check the CI logs for details.", releaseCtx.Version),
+	}}
+}
+
+// alternateParseMode returns the other formatting parse mode, or "" for
+// plain text.
+func alternateParseMode(parseMode string) string {
+	switch parseMode {
+	case "MarkdownV2":
+		return "HTML"
+	case "HTML":
+		return "MarkdownV2"
+	default:
+		return ""
+	}
+}
+
+// minimalSuccessMessage is the last-resort plain-text success message.
+func minimalSuccessMessage(cfg *Config, releaseCtx plugin.ReleaseContext) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🚀 Release %s published", releaseCtx.Version))
+	if releaseCtx.ReleaseType != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", releaseCtx.ReleaseType))
+	}
+	if cfg.ReleaseURL != "" {
+		sb.WriteString("\n" + cfg.ReleaseURL)
+	}
+	return sb.String()
+}
+
+// markDegraded flags a fallback delivery in outputs, with the reason the
+// original message was rejected.
+func markDegraded(outputs map[string]any, sent delivery) {
+	if sent.fallback == "" {
+		return
+	}
+	outputs["degraded"] = true
+	outputs[fallbackOutput] = sent.fallback
+	outputs["fallback_reason"] = sent.fallbackReason
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// entityRejectingServer rejects every message sent with a parse mode in
+// rejectModes and records the messages it receives.
+func entityRejectingServer(t *testing.T, rejectModes ...string) *[]TelegramMessage {
+	t.Helper()
+	var received []TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		received = append(received, msg)
+		for _, mode := range rejectModes {
+			if msg.ParseMode == mode {
+				_ = json.NewEncoder(w).Encode(TelegramResponse{
+					OK:          false,
+					ErrorCode:   400,
+					Description: "Bad Request: can't parse entities: Character '.' is reserved",
+				})
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+	return &received
+}
+
+func TestExecuteFormattingFallbacks(t *testing.T) {
+	tests := []struct {
+		name         string
+		rejectModes  []string
+		wantFallback any
+		wantAttempts int
+		wantMode     string
+	}{
+		{
+			name:         "no fallback needed",
+			wantFallback: nil,
+			wantAttempts: 1,
+			wantMode:     "MarkdownV2",
+		},
+		{
+			name:         "alternate parse mode",
+			rejectModes:  []string{"MarkdownV2"},
+			wantFallback: fallbackAlternateParseMode,
+			wantAttempts: 2,
+			wantMode:     "HTML",
+		},
+		{
+			name:         "plain text",
+			rejectModes:  []string{"MarkdownV2", "HTML"},
+			wantFallback: fallbackPlainText,
+			wantAttempts: 3,
+			wantMode:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := entityRejectingServer(t, tt.rejectModes...)
+
+			p := &TelegramPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"bot_token":   "123:abc",
+					"chat_id":     "@test",
+					"release_url": "https://example.com/r",
+				},
+				Context: plugin.ReleaseContext{Version: "1.2.3", ReleaseType: "minor"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+			}
+			if resp.Outputs["fallback"] != tt.wantFallback {
+				t.Errorf("fallback = %v, want %v", resp.Outputs["fallback"], tt.wantFallback)
+			}
+			if len(*received) != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", len(*received), tt.wantAttempts)
+			}
+			last := (*received)[len(*received)-1]
+			if last.ParseMode != tt.wantMode {
+				t.Errorf("delivered parse mode = %q, want %q", last.ParseMode, tt.wantMode)
+			}
+			if tt.wantFallback == fallbackPlainText && !strings.HasPrefix(last.Text, "🚀 Release 1.2.3 Published!\n\n📦 Version: 1.2.3\n") {
+				t.Errorf("plain text = %q, want the message without formatting", last.Text)
+			}
+			if tt.wantFallback != nil && resp.Outputs["fallback_reason"] != "Bad Request: can't parse entities: Character '.' is reserved" {
+				t.Errorf("fallback_reason = %v", resp.Outputs["fallback_reason"])
+			}
+		})
+	}
+}
+
+func TestWithPlainText(t *testing.T) {
+	minimal := deliveryFallback{name: fallbackMinimalPlainText, text: "🚀 Release 1.2.3 published"}
+	alternate := deliveryFallback{name: fallbackAlternateParseMode, parseMode: "HTML", text: "<b>1.2.3</b>"}
+
+	tests := []struct {
+		name      string
+		msg       TelegramMessage
+		fallbacks []deliveryFallback
+		want      []deliveryFallback
+	}{
+		{
+			"markdown",
+			TelegramMessage{Text: "*Release 1\\.2\\.3* is `out`\\!", ParseMode: "MarkdownV2"},
+			[]deliveryFallback{alternate, minimal},
+			[]deliveryFallback{alternate, {name: fallbackPlainText, text: "Release 1.2.3 is out!"}, minimal},
+		},
+		{
+			"html",
+			TelegramMessage{Text: "<b>Fish &amp; Chips</b> <i>1.2.3", ParseMode: "HTML"},
+			[]deliveryFallback{minimal},
+			[]deliveryFallback{{name: fallbackPlainText, text: "Fish & Chips 1.2.3"}, minimal},
+		},
+		{"plain text", TelegramMessage{Text: "Release 1.2.3"}, []deliveryFallback{minimal}, []deliveryFallback{minimal}},
+		{"only formatting", TelegramMessage{Text: "**", ParseMode: "MarkdownV2"}, []deliveryFallback{minimal}, []deliveryFallback{minimal}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withPlainText(tt.msg, tt.fallbacks); !slices.Equal(got, tt.want) {
+				t.Errorf("withPlainText() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteErrorNotificationFallback(t *testing.T) {
+	received := entityRejectingServer(t, "MarkdownV2")
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookOnError,
+		Config:  map[string]any{"bot_token": "123:abc", "chat_id": "@test"},
+		Context: plugin.ReleaseContext{Version: "1.2.3"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if resp.Outputs["degraded"] != true || len(*received) != 2 {
+		t.Errorf("Execute() outputs = %v after %d attempts, want degraded delivery", resp.Outputs, len(*received))
+	}
+}
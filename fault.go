@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// faultInjectionEnv must be true for fault_injection to take effect, so a
+// config shared with production pipelines cannot inject faults there.
+const faultInjectionEnv = "TELEGRAM_ALLOW_FAULT_INJECTION"
+
+// FaultInjection simulates Bot API outages for resilience testing of the
+// pipeline around the plugin. It only takes effect when faultInjectionEnv is
+// set to true.
+type FaultInjection struct {
+	// ErrorRatePercent is the percentage of Bot API requests that fail.
+	ErrorRatePercent int `json:"error_rate_percent,omitempty" description:"Percentage of Bot API requests that fail" default:"0"`
+	// LatencyMS delays every Bot API request.
+	LatencyMS int `json:"latency_ms,omitempty" description:"Delay added to every Bot API request in milliseconds" default:"0"`
+	// ErrorCodes are the error codes failed requests report, picked at
+	// random. Empty reports 500.
+	ErrorCodes []int `json:"error_codes,omitempty" description:"Error codes of failed requests, picked at random; defaults to 500"`
+}
+
+// parseFaultInjection parses the fault_injection block. Invalid values are
+// skipped; Validate reports them.
+func parseFaultInjection(v any) FaultInjection {
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return FaultInjection{}
+	}
+	codes, _ := parseErrorCodes(raw["error_codes"])
+	return FaultInjection{
+		ErrorRatePercent: getInt(raw, "error_rate_percent", 0),
+		LatencyMS:        getInt(raw, "latency_ms", 0),
+		ErrorCodes:       codes,
+	}
+}
+
+// parseErrorCodes parses a list of Bot API error codes.
+func parseErrorCodes(v any) ([]int, error) {
+	if v == nil {
+		return nil, nil
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("error_codes must be a list of error codes")
+	}
+	codes := make([]int, 0, len(items))
+	for _, item := range items {
+		code := getInt(map[string]any{"code": item}, "code", 0)
+		if code < 400 || code > 599 {
+			return codes, fmt.Errorf("error code %v must be between 400 and 599", item)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// validateFaultInjection reports the first problem with the fault_injection
+// block.
+func validateFaultInjection(v any) error {
+	if v == nil {
+		return nil
+	}
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("must be an object")
+	}
+	if rate := getInt(raw, "error_rate_percent", 0); rate < 0 || rate > 100 {
+		return fmt.Errorf("error_rate_percent must be between 0 and 100, got %d", rate)
+	}
+	if getInt(raw, "latency_ms", 0) < 0 {
+		return fmt.Errorf("latency_ms must not be negative")
+	}
+	_, err := parseErrorCodes(raw["error_codes"])
+	return err
+}
+
+// faultInjectionAllowed reports whether faultInjectionEnv unlocks fault
+// injection.
+func faultInjectionAllowed() bool {
+	allowed, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv(faultInjectionEnv)))
+	return allowed
+}
+
+// active reports whether f injects any faults in this environment.
+func (f FaultInjection) active() bool {
+	return (f.ErrorRatePercent > 0 || f.LatencyMS > 0) && faultInjectionAllowed()
+}
+
+// injectFault delays a Bot API request by the configured latency and fails
+// it at the configured error rate. The injected errors look like Bot API
+// errors, so retries, fallbacks, and alerts handle them as real outages; a
+// rate limit asks to retry after a second.
+func (p *TelegramPlugin) injectFault(ctx context.Context, cfg *Config, method string) error {
+	f := cfg.FaultInjection
+	if !f.active() {
+		return nil
+	}
+	if err := sleepContext(ctx, p.clockOrDefault(), time.Duration(f.LatencyMS)*time.Millisecond); err != nil {
+		return err
+	}
+	if rand.IntN(100) >= f.ErrorRatePercent {
+		return nil
+	}
+
+	code := http.StatusInternalServerError
+	if len(f.ErrorCodes) > 0 {
+		code = f.ErrorCodes[rand.IntN(len(f.ErrorCodes))]
+	}
+	apiErr := &APIError{Code: code, Description: fmt.Sprintf("injected fault in %s: %s", method, http.StatusText(code))}
+	if code == http.StatusTooManyRequests {
+		apiErr.RetryAfter = 1
+	}
+	return apiErr
+}
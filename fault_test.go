@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestValidateFaultInjection(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr bool
+	}{
+		{"unset", nil, false},
+		{"valid", map[string]any{"error_rate_percent": 50, "latency_ms": 200, "error_codes": []any{429, 502}}, false},
+		{"not an object", "50%", true},
+		{"rate over 100", map[string]any{"error_rate_percent": 150}, true},
+		{"negative latency", map[string]any{"latency_ms": -1}, true},
+		{"not an error code", map[string]any{"error_codes": []any{200}}, true},
+		{"codes not a list", map[string]any{"error_codes": 500}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateFaultInjection(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("validateFaultInjection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteFaultInjection(t *testing.T) {
+	tests := []struct {
+		name      string
+		allow     string
+		fault     map[string]any
+		wantSent  bool
+		wantError string
+		wantSlept []time.Duration
+	}{
+		{"locked", "", map[string]any{"error_rate_percent": 100}, true, "", nil},
+		{"errors", "true", map[string]any{"error_rate_percent": 100, "error_codes": []any{503}}, false, "(503)", nil},
+		{"latency", "true", map[string]any{"latency_ms": 250}, true, "", []time.Duration{250 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := false
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				sent = true
+				_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+			})
+			t.Setenv(faultInjectionEnv, tt.allow)
+
+			clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			p := &TelegramPlugin{clock: clk}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"bot_token":       "123:abc",
+					"chat_id":         "@news",
+					"fault_injection": tt.fault,
+				},
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if sent != tt.wantSent || resp.Success != tt.wantSent {
+				t.Errorf("sent = %v, Success = %v; want %v", sent, resp.Success, tt.wantSent)
+			}
+			if tt.wantError != "" && !strings.Contains(resp.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", resp.Error, tt.wantError)
+			}
+			if got := clk.Slept(); !slices.Equal(got, tt.wantSlept) {
+				t.Errorf("slept %v, want %v", got, tt.wantSlept)
+			}
+		})
+	}
+}
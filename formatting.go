@@ -0,0 +1,25 @@
+package main
+
+import "github.com/relicta-tech/plugin-telegram/internal/render"
+
+// formattingWarningsOutput records formatting Telegram is likely to reject.
+var formattingWarningsOutput = registerDegradation("formatting_warnings", describeEach("template formatting issue"))
+
+// formattingRepairedOutput records that template formatting was repaired.
+var formattingRepairedOutput = registerDegradation("formatting_repaired", describeFlag("template formatting repaired"))
+
+// checkTemplateFormatting analyzes text rendered from a custom template and
+// records formatting Telegram is likely to reject in outputs. With
+// auto_repair_formatting the repaired text is returned instead.
+func checkTemplateFormatting(cfg *Config, text string, outputs map[string]any) string {
+	issues, repaired := render.AnalyzeFormatting(text, cfg.ParseMode)
+	if len(issues) == 0 {
+		return text
+	}
+	outputs[formattingWarningsOutput] = issues
+	if !cfg.AutoRepairFormatting {
+		return text
+	}
+	outputs[formattingRepairedOutput] = true
+	return repaired
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestCheckTemplateFormatting(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		text        string
+		wantText    string
+		wantOutputs map[string]any
+	}{
+		{
+			name:        "clean",
+			cfg:         Config{ParseMode: "HTML"},
+			text:        "<b>1.0.0</b>",
+			wantText:    "<b>1.0.0</b>",
+			wantOutputs: map[string]any{},
+		},
+		{
+			name:        "warn only",
+			cfg:         Config{ParseMode: "HTML"},
+			text:        "<b>1.0.0",
+			wantText:    "<b>1.0.0",
+			wantOutputs: map[string]any{"formatting_warnings": []string{"unterminated <b>"}},
+		},
+		{
+			name:     "repair",
+			cfg:      Config{ParseMode: "HTML", AutoRepairFormatting: true},
+			text:     "<b>1.0.0",
+			wantText: "<b>1.0.0</b>",
+			wantOutputs: map[string]any{
+				"formatting_warnings": []string{"unterminated <b>"},
+				"formatting_repaired": true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs := map[string]any{}
+			if got := checkTemplateFormatting(&tt.cfg, tt.text, outputs); got != tt.wantText {
+				t.Errorf("checkTemplateFormatting() = %q, want %q", got, tt.wantText)
+			}
+			if !reflect.DeepEqual(outputs, tt.wantOutputs) {
+				t.Errorf("outputs = %v, want %v", outputs, tt.wantOutputs)
+			}
+		})
+	}
+}
+
+func TestExecuteAutoRepairFormatting(t *testing.T) {
+	var sent TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":              "123:abc",
+			"chat_id":                "@test",
+			"parse_mode":             "HTML",
+			"template":               "<b>Released <i>{{.Version}}</b>",
+			"auto_repair_formatting": true,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+	if want := "<b>Released <i>1.0.0</i></b>"; sent.Text != want {
+		t.Errorf("sent text = %q, want %q", sent.Text, want)
+	}
+	if repaired, _ := resp.Outputs["formatting_repaired"].(bool); !repaired {
+		t.Errorf("Outputs = %v, want formatting_repaired", resp.Outputs)
+	}
+}
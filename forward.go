@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// forwardErrorsOutput records the mirror chats the announcement was not
+// forwarded to.
+var forwardErrorsOutput = registerDegradation("forward_errors", describeByKey("forward to %s failed: %s"))
+
+// errNoMessageID is reported when the announcement cannot be forwarded
+// because its message ID is unknown.
+var errNoMessageID = errors.New("announcement message ID is unknown")
+
+// forwardAnnouncement forwards the sent success announcement to the mirror
+// chats, recording a delivery per chat in outputs and the failures in
+// outputs["forward_errors"], or outputs["circuit_breaker_skipped"] for
+// forwards the open circuit breaker skipped. Failed forwards do not fail the
+// hook: the announcement itself was already delivered.
+func (p *TelegramPlugin) forwardAnnouncement(ctx context.Context, cfg *Config, dryRun bool, outputs map[string]any) {
+	if len(cfg.ForwardToChatIDs) == 0 {
+		return
+	}
+	if dryRun {
+		outputs["forward_to_chat_ids"] = cfg.ForwardToChatIDs
+		return
+	}
+
+	messageID, _ := outputs["message_id"].(int64)
+	failed := map[string]string{}
+	for _, target := range cfg.ForwardToChatIDs {
+		chatID, threadID := resolveChatID(target)
+		err := errNoMessageID
+		var forwardedID int64
+		if messageID != 0 {
+			forwardedID, err = p.forward(ctx, cfg, TelegramForward{
+				ChatID:              chatID,
+				FromChatID:          cfg.ChatID,
+				MessageID:           messageID,
+				MessageThreadID:     wireThreadID(threadID),
+				DisableNotification: cfg.DisableNotification,
+			})
+		}
+		recordDelivery(outputs, "forward", chatID, err)
+		recordPermalink(outputs, "forward", chatID, threadID, forwardedID)
+		if err != nil && !recordCircuitSkip(outputs, chatID, err) {
+			failed[chatID] = failureReason(err)
+		}
+	}
+	if len(failed) > 0 {
+		outputs[forwardErrorsOutput] = failed
+	}
+}
+
+// forward forwards a message unless the circuit breaker is open, recording
+// API errors against the breaker. It returns the ID of the forwarded copy.
+func (p *TelegramPlugin) forward(ctx context.Context, cfg *Config, msg TelegramForward) (int64, error) {
+	breaker := p.circuitBreaker(cfg)
+	if err := breaker.check(p.now()); err != nil {
+		return 0, err
+	}
+	sent, err := p.forwardMessage(ctx, cfg, msg)
+	if err != nil {
+		breaker.recordError(err, p.now())
+		return 0, err
+	}
+	return sent.MessageID, nil
+}
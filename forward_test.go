@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteForwardToChatIDs(t *testing.T) {
+	var mu sync.Mutex
+	var forwards []TelegramForward
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/forwardMessage") {
+			result, _ := json.Marshal(TelegramSentMessage{MessageID: 42})
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+			return
+		}
+		var fwd TelegramForward
+		_ = json.NewDecoder(r.Body).Decode(&fwd)
+		mu.Lock()
+		forwards = append(forwards, fwd)
+		mu.Unlock()
+		if fwd.ChatID == "@blocked_mirror" {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot is not a member of the channel chat"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":           "123:abc",
+			"chat_id":             "@releases",
+			"forward_to_chat_ids": []any{"-1001234567890@7", "@blocked_mirror"},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+
+	want := []TelegramForward{
+		{ChatID: "-1001234567890", FromChatID: "@releases", MessageID: 42, MessageThreadID: 7},
+		{ChatID: "@blocked_mirror", FromChatID: "@releases", MessageID: 42},
+	}
+	if !reflect.DeepEqual(forwards, want) {
+		t.Errorf("forwards = %+v, want %+v", forwards, want)
+	}
+
+	deliveries, _ := resp.Outputs["deliveries"].([]map[string]any)
+	if len(deliveries) != 3 || deliveries[1]["ok"] != true || deliveries[2]["ok"] != false {
+		t.Errorf("deliveries = %v, want the announcement and one result per mirror", deliveries)
+	}
+	wantErrors := map[string]string{"@blocked_mirror": "Forbidden: bot is not a member of the channel chat"}
+	if got := resp.Outputs["forward_errors"]; !reflect.DeepEqual(got, wantErrors) {
+		t.Errorf("forward_errors = %v, want %v", got, wantErrors)
+	}
+}
+
+func TestForwardAnnouncementWithoutMessageID(t *testing.T) {
+	p := &TelegramPlugin{}
+	outputs := map[string]any{}
+	p.forwardAnnouncement(context.Background(), &Config{ForwardToChatIDs: []string{"@mirror"}}, false, outputs)
+
+	wantErrors := map[string]string{"@mirror": errNoMessageID.Error()}
+	if got := outputs["forward_errors"]; !reflect.DeepEqual(got, wantErrors) {
+		t.Errorf("forward_errors = %v, want %v", got, wantErrors)
+	}
+}
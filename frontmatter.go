@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+)
+
+// frontMatterDelimiter opens and closes a template's front matter.
+const frontMatterDelimiter = "---"
+
+// frontMatter holds the delivery options a template sets for its message in
+// a front-matter block. Unset options keep the configured value.
+type frontMatter struct {
+	ParseMode             *string
+	DisableNotification   *bool
+	DisableWebPagePreview *bool
+}
+
+// parseFrontMatter splits the optional front matter off a template: a block
+// of "key: value" lines between two "---" lines at the very top. It returns
+// the options and the template body. Errors are *render.TemplateError
+// located in tmpl.
+func parseFrontMatter(tmpl string) (frontMatter, string, error) {
+	var fm frontMatter
+	lines := strings.SplitAfter(tmpl, "\n")
+	if strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		return fm, tmpl, nil
+	}
+
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == frontMatterDelimiter {
+			return fm, strings.Join(lines[i+1:], ""), nil
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := fm.set(line); err != nil {
+			return fm, tmpl, &render.TemplateError{Line: i + 1, Message: err.Error()}
+		}
+	}
+	return fm, tmpl, &render.TemplateError{Line: 1, Message: "front matter is not closed with " + frontMatterDelimiter}
+}
+
+// set applies one "key: value" front-matter line.
+func (fm *frontMatter) set(line string) error {
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return fmt.Errorf("front matter line %q is not a key: value pair", line)
+	}
+	key = strings.TrimSpace(key)
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+	switch key {
+	case "parse_mode":
+		if value != "" && value != "MarkdownV2" && value != "HTML" {
+			return fmt.Errorf("parse_mode must be 'MarkdownV2', 'HTML', or empty, got %q", value)
+		}
+		fm.ParseMode = &value
+	case "disable_notification", "disable_web_page_preview":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false, got %q", key, value)
+		}
+		if key == "disable_notification" {
+			fm.DisableNotification = &b
+		} else {
+			fm.DisableWebPagePreview = &b
+		}
+	default:
+		return fmt.Errorf("unknown front matter key %q; supported keys are parse_mode, disable_notification, disable_web_page_preview", key)
+	}
+	return nil
+}
+
+// apply returns cfg with the front-matter options applied, for rendering and
+// sending the template's message. cfg itself is returned when none are set.
+func (fm frontMatter) apply(cfg *Config) *Config {
+	if fm == (frontMatter{}) {
+		return cfg
+	}
+	msgCfg := *cfg
+	if fm.ParseMode != nil {
+		msgCfg.ParseMode = *fm.ParseMode
+	}
+	if fm.DisableNotification != nil {
+		msgCfg.DisableNotification = *fm.DisableNotification
+	}
+	if fm.DisableWebPagePreview != nil {
+		msgCfg.DisableWebPagePreview = *fm.DisableWebPagePreview
+	}
+	return &msgCfg
+}
+
+// parseMessageTemplate checks the front matter and syntax of a message
+// template. Errors are located in tmpl, front matter included.
+func parseMessageTemplate(tmpl string) error {
+	if _, _, err := parseFrontMatter(tmpl); err != nil {
+		return err
+	}
+	return render.ParseTemplate(tmpl)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	html := "HTML"
+	yes, no := true, false
+
+	tests := []struct {
+		name     string
+		template string
+		want     frontMatter
+		wantBody string
+		wantErr  string
+	}{
+		{
+			name:     "no front matter",
+			template: "Release {{.Version}}",
+			wantBody: "Release {{.Version}}",
+		},
+		{
+			name:     "all options",
+			template: "---\nparse_mode: HTML\ndisable_notification: true\ndisable_web_page_preview: \"false\"\n---\n<b>{{.Version}}</b>",
+			want:     frontMatter{ParseMode: &html, DisableNotification: &yes, DisableWebPagePreview: &no},
+			wantBody: "<b>{{.Version}}</b>",
+		},
+		{
+			name:     "comments and blank lines",
+			template: "---\r\n# delivered quietly\r\n\r\ndisable_notification: true\r\n---\r\nRelease",
+			want:     frontMatter{DisableNotification: &yes},
+			wantBody: "Release",
+		},
+		{
+			name:     "invalid boolean",
+			template: "---\ndisable_notification: yes\n---\nRelease",
+			wantErr:  `line 2: disable_notification must be true or false, got "yes"`,
+		},
+		{
+			name:     "empty",
+			template: "---\n---\nRelease",
+			wantBody: "Release",
+		},
+		{
+			name:     "unknown key",
+			template: "---\nparse_mode: HTML\nsilent: true\n---\nRelease",
+			wantErr:  `line 3: unknown front matter key "silent"; supported keys are parse_mode, disable_notification, disable_web_page_preview`,
+		},
+		{
+			name:     "invalid parse mode",
+			template: "---\nparse_mode: Markdown\n---\nRelease",
+			wantErr:  `line 2: parse_mode must be 'MarkdownV2', 'HTML', or empty, got "Markdown"`,
+		},
+		{
+			name:     "not closed",
+			template: "---\nparse_mode: HTML\n",
+			wantErr:  "line 1: front matter is not closed with ---",
+		},
+		{
+			name:     "delimiter later in the template",
+			template: "Release\n---\nparse_mode: HTML\n---",
+			wantBody: "Release\n---\nparse_mode: HTML\n---",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm, body, err := parseFrontMatter(tt.template)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseFrontMatter() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFrontMatter() error = %v", err)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if !equalOption(fm.ParseMode, tt.want.ParseMode) ||
+				!equalOption(fm.DisableNotification, tt.want.DisableNotification) ||
+				!equalOption(fm.DisableWebPagePreview, tt.want.DisableWebPagePreview) {
+				t.Errorf("parseFrontMatter() = %+v, want %+v", fm, tt.want)
+			}
+		})
+	}
+}
+
+func equalOption[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestExecuteTemplateFrontMatter(t *testing.T) {
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	tests := []struct {
+		name   string
+		hook   plugin.Hook
+		config map[string]any
+		want   TelegramMessage
+	}{
+		{
+			name: "success template",
+			hook: plugin.HookPostPublish,
+			config: map[string]any{
+				"template": "---\nparse_mode: HTML\ndisable_notification: true\ndisable_web_page_preview: false\n---\n<b>{{.Version}}</b> {{escape .Branch}}",
+			},
+			want: TelegramMessage{Text: "<b>1.0.0</b> fix/a&lt;b&gt;", ParseMode: "HTML", DisableNotification: true},
+		},
+		{
+			name: "version template",
+			hook: plugin.HookPostVersion,
+			config: map[string]any{
+				"notify_on":        map[string]any{"post_version": true},
+				"version_template": "---\nparse_mode: \"\"\n---\nNext: {{.Version}}",
+			},
+			want: TelegramMessage{Text: "Next: 1.0.0", DisableWebPagePreview: true},
+		},
+		{
+			name: "error template silences the alert",
+			hook: plugin.HookOnError,
+			config: map[string]any{
+				"error_template": "---\nparse_mode: HTML\ndisable_notification: true\n---\n<b>{{.Version}}</b> failed",
+			},
+			want: TelegramMessage{Text: "<b>1.0.0</b> failed", ParseMode: "HTML", DisableNotification: true, DisableWebPagePreview: true},
+		},
+		{
+			name: "error template without front matter always notifies",
+			hook: plugin.HookOnError,
+			config: map[string]any{
+				"parse_mode":           "HTML",
+				"disable_notification": true,
+				"error_template":       "{{.Version}} failed",
+			},
+			want: TelegramMessage{Text: "1.0.0 failed", ParseMode: "HTML", DisableWebPagePreview: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = TelegramMessage{}
+			tt.config["bot_token"] = "123:abc"
+			tt.config["chat_id"] = "@test"
+			p := &TelegramPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    tt.hook,
+				Config:  tt.config,
+				Context: plugin.ReleaseContext{Version: "1.0.0", Branch: "fix/a<b>"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v", resp, err)
+			}
+			if got.Text != tt.want.Text || got.ParseMode != tt.want.ParseMode ||
+				got.DisableNotification != tt.want.DisableNotification ||
+				got.DisableWebPagePreview != tt.want.DisableWebPagePreview {
+				t.Errorf("sent %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
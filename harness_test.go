@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/plugin-telegram/telegramplugintest"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExampleConfigs(t *testing.T) {
+	for _, name := range telegramplugintest.ConfigNames() {
+		t.Run(name, func(t *testing.T) {
+			srv := telegramplugintest.NewServer(t)
+			config := telegramplugintest.Config(name, srv)
+
+			p := &TelegramPlugin{clock: newFakeClock(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC))}
+			if resp, err := p.Validate(context.Background(), config); err != nil || !resp.Valid {
+				t.Fatalf("Validate() = %+v, %v", resp, err)
+			}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.0.0", ReleaseNotes: "Notes"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v", resp, err)
+			}
+			if len(srv.Requests("sendMessage")) == 0 {
+				t.Error("no message sent")
+			}
+		})
+	}
+}
+
+func TestExecuteRateLimitedFakeServer(t *testing.T) {
+	srv := telegramplugintest.NewServer(t)
+	srv.RateLimit("sendMessage", 2, 5)
+
+	clk := newFakeClock(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  telegramplugintest.Config("minimal", srv),
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+	if n := len(srv.Requests("sendMessage")); n != 3 {
+		t.Errorf("sent %d requests, want 3", n)
+	}
+	if slept := clk.Slept(); !slices.Equal(slept, []time.Duration{5 * time.Second, 5 * time.Second}) {
+		t.Errorf("waited %v, want 5s twice", slept)
+	}
+}
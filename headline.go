@@ -0,0 +1,24 @@
+package main
+
+import "github.com/relicta-tech/plugin-telegram/internal/render"
+
+// parseHeadlineRules parses the headline_rules config.
+func parseHeadlineRules(v any) []render.HeadlineRule {
+	items, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+
+	rules := make([]render.HeadlineRule, 0, len(items))
+	for _, item := range items {
+		raw, _ := item.(map[string]any)
+		metric, _ := raw["metric"].(string)
+		emoji, _ := raw["emoji"].(string)
+		rules = append(rules, render.HeadlineRule{
+			Metric: metric,
+			Min:    getInt(raw, "min", 1),
+			Emoji:  emoji,
+		})
+	}
+	return rules
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+)
+
+func TestParseHeadlineRules(t *testing.T) {
+	rules := parseHeadlineRules([]any{
+		map[string]any{"metric": "breaking", "emoji": "🚨"},
+		map[string]any{"metric": "features", "min": float64(5), "emoji": "🎉"},
+	})
+
+	want := []render.HeadlineRule{
+		{Metric: "breaking", Min: 1, Emoji: "🚨"},
+		{Metric: "features", Min: 5, Emoji: "🎉"},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("parseHeadlineRules() = %+v, want %+v", rules, want)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rules[%d] = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+}
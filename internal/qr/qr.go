@@ -0,0 +1,492 @@
+// Package qr encodes short texts such as URLs as QR codes, in byte mode at
+// error correction level M, and renders them as PNG images.
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// ErrTooLong is returned for data over the capacity of the largest
+// supported version.
+var ErrTooLong = errors.New("qr: data too long")
+
+// block describes the error correction blocks of a version at level M:
+// count blocks of data codewords each, followed by long blocks of one more.
+type block struct {
+	ecc, count, data, long int
+}
+
+// versions holds the blocks of versions 1 through 10, enough for URLs of up
+// to 213 bytes.
+var versions = []block{
+	{10, 1, 16, 0},
+	{16, 1, 28, 0},
+	{26, 1, 44, 0},
+	{18, 2, 32, 0},
+	{24, 2, 43, 0},
+	{16, 4, 27, 0},
+	{18, 4, 31, 0},
+	{22, 2, 38, 2},
+	{22, 3, 36, 2},
+	{26, 4, 43, 1},
+}
+
+// Code is an encoded QR code.
+type Code struct {
+	// Version is the QR version, which sets the size to 17+4*Version.
+	Version int
+	// Size is the width and height in modules.
+	Size    int
+	modules [][]bool
+	// function marks the finder, timing, alignment, format, and version
+	// modules, which carry no data and are never masked.
+	function [][]bool
+}
+
+// Black reports whether the module at column x and row y is dark.
+func (c *Code) Black(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Encode returns data as a QR code of the smallest version that holds it.
+func Encode(data []byte) (*Code, error) {
+	for v := 1; v <= len(versions); v++ {
+		if len(data) <= capacity(v) {
+			return encode(data, v, -1), nil
+		}
+	}
+	return nil, ErrTooLong
+}
+
+// capacity returns how many bytes version v holds.
+func capacity(v int) int {
+	b := versions[v-1]
+	dataBits := 8 * (b.count*b.data + b.long*(b.data+1))
+	return (dataBits - 4 - countBits(v)) / 8
+}
+
+// countBits returns the length of the byte count field of version v.
+func countBits(v int) int {
+	if v < 10 {
+		return 8
+	}
+	return 16
+}
+
+// encode encodes data as version v with mask, or with the mask of the
+// lowest penalty when mask is negative.
+func encode(data []byte, v, mask int) *Code {
+	size := 17 + 4*v
+	c := &Code{Version: v, Size: size, modules: grid(size), function: grid(size)}
+	c.drawFunctionPatterns()
+	c.drawCodewords(interleave(dataCodewords(data, v), versions[v-1]))
+
+	if mask < 0 {
+		best := -1
+		for m := range 8 {
+			c.applyMask(m)
+			c.drawFormat(m)
+			if p := c.penalty(); best < 0 || p < best {
+				best, mask = p, m
+			}
+			c.applyMask(m)
+		}
+	}
+	c.applyMask(mask)
+	c.drawFormat(mask)
+	return c
+}
+
+func grid(size int) [][]bool {
+	g := make([][]bool, size)
+	for y := range g {
+		g[y] = make([]bool, size)
+	}
+	return g
+}
+
+// set sets a function module.
+func (c *Code) set(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := range c.Size {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+	for _, p := range [][2]int{{3, 3}, {c.Size - 4, 3}, {3, c.Size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := p[0]+dx, p[1]+dy
+				if x >= 0 && x < c.Size && y >= 0 && y < c.Size {
+					d := max(abs(dx), abs(dy))
+					c.set(x, y, d != 2 && d != 4)
+				}
+			}
+		}
+	}
+	pos := alignmentPositions(c.Version)
+	last := len(pos) - 1
+	for i, x := range pos {
+		for j, y := range pos {
+			// Skip the corners taken by finder patterns.
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	// Reserve the format modules; the mask is drawn in later.
+	c.drawFormat(0)
+	c.drawVersion()
+}
+
+// alignmentPositions returns the centers of the alignment patterns of
+// version v along each axis.
+func alignmentPositions(v int) []int {
+	if v == 1 {
+		return nil
+	}
+	n := v/7 + 2
+	step := (v*8 + n*3 + 5) / (n*4 - 4) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, 17+4*v-7; i > 0; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// formatBits returns the BCH coded format information of level M and mask.
+func formatBits(mask int) int {
+	data := mask // Level M is 00.
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (c *Code) drawFormat(mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+	for i := range 6 {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+	for i := range 8 {
+		c.set(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i))
+	}
+	c.set(8, c.Size-8, true)
+}
+
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for range 12 {
+		rem = rem<<1 ^ (rem>>11)*0x1f25
+	}
+	bits := c.Version<<12 | rem
+	for i := range 18 {
+		dark := bits>>i&1 != 0
+		a, b := c.Size-11+i%3, i/3
+		c.set(a, b, dark)
+		c.set(b, a, dark)
+	}
+}
+
+// dataCodewords returns the byte mode segment of data, padded to the data
+// capacity of version v.
+func dataCodewords(data []byte, v int) []byte {
+	b := versions[v-1]
+	n := b.count*b.data + b.long*(b.data+1)
+	var w bitWriter
+	w.write(0b0100, 4)
+	w.write(len(data), countBits(v))
+	for _, d := range data {
+		w.write(int(d), 8)
+	}
+	w.write(0, min(4, 8*n-w.n))
+	w.write(0, (8-w.n%8)%8)
+	for pad := 0xec; len(w.buf) < n; pad ^= 0xec ^ 0x11 {
+		w.write(pad, 8)
+	}
+	return w.buf
+}
+
+type bitWriter struct {
+	buf []byte
+	n   int
+}
+
+func (w *bitWriter) write(v, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		w.buf[len(w.buf)-1] |= byte(v>>i&1) << (7 - w.n%8)
+		w.n++
+	}
+}
+
+// interleave splits data into the blocks of b, appends their error
+// correction codewords, and interleaves the blocks.
+func interleave(data []byte, b block) []byte {
+	divisor := rsDivisor(b.ecc)
+	var blocks, eccs [][]byte
+	for i := range b.count + b.long {
+		n := b.data
+		if i >= b.count {
+			n++
+		}
+		blocks = append(blocks, data[:n])
+		eccs = append(eccs, rsRemainder(data[:n], divisor))
+		data = data[n:]
+	}
+	var out []byte
+	for i := range b.data + 1 {
+		for _, blk := range blocks {
+			if i < len(blk) {
+				out = append(out, blk[i])
+			}
+		}
+	}
+	for i := range b.ecc {
+		for _, ecc := range eccs {
+			out = append(out, ecc[i])
+		}
+	}
+	return out
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of degree n,
+// without its leading term, highest power first.
+func rsDivisor(n int) []byte {
+	div := make([]byte, n)
+	div[n-1] = 1
+	root := byte(1)
+	for range n {
+		for j := range div {
+			div[j] = gfMul(div[j], root)
+			if j+1 < n {
+				div[j] ^= div[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return div
+}
+
+// rsRemainder returns the error correction codewords of data.
+func rsRemainder(data, divisor []byte) []byte {
+	rem := make([]byte, len(divisor))
+	for _, d := range data {
+		factor := d ^ rem[0]
+		copy(rem, rem[1:])
+		rem[len(rem)-1] = 0
+		for i, coef := range divisor {
+			rem[i] ^= gfMul(coef, factor)
+		}
+	}
+	return rem
+}
+
+// gfMul multiplies in GF(2^8) modulo x^8+x^4+x^3+x^2+1.
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11d
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// drawCodewords places data in the zigzag order of the standard, two
+// columns at a time from the bottom right, skipping function modules.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range c.Size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !c.function[y][x] && i < len(data)*8 {
+					c.modules[y][x] = data[i>>3]>>(7-i&7)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// masked reports whether mask inverts the module at column x and row y.
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// applyMask inverts the data modules selected by mask. Applying it twice
+// undoes it.
+func (c *Code) applyMask(mask int) {
+	for y := range c.Size {
+		for x := range c.Size {
+			if !c.function[y][x] && masked(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code is to scan, by the four rules of the
+// standard: long runs, 2x2 blocks, finder-like patterns, and imbalance.
+func (c *Code) penalty() int {
+	score := 0
+	at := func(x, y int, vertical bool) bool {
+		if vertical {
+			return c.modules[x][y]
+		}
+		return c.modules[y][x]
+	}
+	finder := []bool{true, false, true, true, true, false, true}
+	for _, vertical := range []bool{false, true} {
+		for y := range c.Size {
+			run := 0
+			for x := range c.Size {
+				if x > 0 && at(x, y, vertical) == at(x-1, y, vertical) {
+					run++
+				} else {
+					run = 1
+				}
+				if run == 5 {
+					score += 3
+				} else if run > 5 {
+					score++
+				}
+			}
+			for x := 0; x+7 <= c.Size; x++ {
+				match := true
+				for i, dark := range finder {
+					if at(x+i, y, vertical) != dark {
+						match = false
+						break
+					}
+				}
+				if match && (c.light(x-4, x, y, vertical) || c.light(x+7, x+11, y, vertical)) {
+					score += 40
+				}
+			}
+		}
+	}
+	dark := 0
+	for y := range c.Size {
+		for x := range c.Size {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x > 0 && y > 0 {
+				m := c.modules[y][x]
+				if c.modules[y][x-1] == m && c.modules[y-1][x] == m && c.modules[y-1][x-1] == m {
+					score += 3
+				}
+			}
+		}
+	}
+	total := c.Size * c.Size
+	score += abs(dark*100/total-50) / 5 * 10
+	return score
+}
+
+// light reports whether modules from through to (exclusive) of a row, or a
+// column when vertical, are light. Modules outside the code are light.
+func (c *Code) light(from, to, y int, vertical bool) bool {
+	for x := from; x < to; x++ {
+		if x < 0 || x >= c.Size {
+			continue
+		}
+		dark := c.modules[y][x]
+		if vertical {
+			dark = c.modules[x][y]
+		}
+		if dark {
+			return false
+		}
+	}
+	return true
+}
+
+// quietZone is the light border around the code, in modules.
+const quietZone = 4
+
+// PNG renders the code as a black and white PNG image with scale pixels per
+// module and the quiet zone the standard requires.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	side := (c.Size + 2*quietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := range c.Size {
+		for x := range c.Size {
+			if !c.modules[y][x] {
+				continue
+			}
+			for py := range scale {
+				row := (y+quietZone)*scale + py
+				for px := range scale {
+					img.SetColorIndex((x+quietZone)*scale+px, row, 1)
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
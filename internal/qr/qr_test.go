@@ -0,0 +1,224 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// Version 1-M example of the standard's tutorial literature.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !reflect.DeepEqual(got, want) {
+		t.Errorf("rsRemainder() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	// Level M strings from the format information table.
+	for mask, want := range []int{
+		0b101010000010010,
+		0b101000100100101,
+		0b101111001111100,
+		0b101101101001011,
+		0b100010111111001,
+		0b100000011001110,
+		0b100111110010111,
+		0b100101010100000,
+	} {
+		if got := formatBits(mask); got != want {
+			t.Errorf("formatBits(%d) = %015b, want %015b", mask, got, want)
+		}
+	}
+}
+
+func TestAlignmentPositions(t *testing.T) {
+	for v, want := range map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		6:  {6, 34},
+		7:  {6, 22, 38},
+		8:  {6, 24, 42},
+		10: {6, 28, 50},
+	} {
+		if got := alignmentPositions(v); !reflect.DeepEqual(got, want) {
+			t.Errorf("alignmentPositions(%d) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestVersionCodewords(t *testing.T) {
+	// Every data module holds a codeword bit, apart from the remainder
+	// bits of versions 2 through 6.
+	for v := 1; v <= len(versions); v++ {
+		c := encode(nil, v, 0)
+		modules := 0
+		for y := range c.Size {
+			for x := range c.Size {
+				if !c.function[y][x] {
+					modules++
+				}
+			}
+		}
+		b := versions[v-1]
+		codewords := (b.count+b.long)*(b.data+b.ecc) + b.long
+		remainder := 0
+		if v >= 2 && v <= 6 {
+			remainder = 7
+		}
+		if modules != codewords*8+remainder {
+			t.Errorf("version %d has %d data modules, want %d", v, modules, codewords*8+remainder)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		data        string
+		wantVersion int
+	}{
+		{data: "", wantVersion: 1},
+		{data: "https://x.io/a", wantVersion: 1},
+		{data: "https://github.com/relicta-tech/plugin-telegram/releases/tag/v1.2.3", wantVersion: 5},
+		{data: strings.Repeat("a", 213), wantVersion: 10},
+	}
+	for _, tt := range tests {
+		c, err := Encode([]byte(tt.data))
+		if err != nil {
+			t.Fatalf("Encode(%q) error = %v", tt.data, err)
+		}
+		if c.Version != tt.wantVersion {
+			t.Errorf("Encode(%q) version = %d, want %d", tt.data, c.Version, tt.wantVersion)
+		}
+		if got := decode(t, c); got != tt.data {
+			t.Errorf("decode(Encode(%q)) = %q", tt.data, got)
+		}
+	}
+
+	if _, err := Encode(bytes.Repeat([]byte("a"), 214)); err != ErrTooLong {
+		t.Errorf("Encode() of 214 bytes error = %v, want ErrTooLong", err)
+	}
+}
+
+// decode reads the format information, unmasks the code, checks the error
+// correction of every block, and returns the byte mode segment.
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+	format := 0
+	pos := [][2]int{{8, 0}, {8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {8, 7}, {8, 8}, {7, 8}, {5, 8}, {4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}}
+	for i, p := range pos {
+		if c.Black(p[0], p[1]) {
+			format |= 1 << i
+		}
+	}
+	mask := -1
+	for m := range 8 {
+		if formatBits(m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format information %015b is not level M", format)
+	}
+
+	u := &Code{Version: c.Version, Size: c.Size, modules: grid(c.Size), function: c.function}
+	for y := range c.Size {
+		copy(u.modules[y], c.modules[y])
+	}
+	u.applyMask(mask)
+
+	var raw []byte
+	var w bitWriter
+	for right := u.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range u.Size {
+			for j := range 2 {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = u.Size - 1 - vert
+				}
+				if !u.function[y][x] {
+					dark := 0
+					if u.modules[y][x] {
+						dark = 1
+					}
+					w.write(dark, 1)
+				}
+			}
+		}
+	}
+	raw = w.buf
+
+	b := versions[c.Version-1]
+	n := b.count + b.long
+	blocks := make([][]byte, n)
+	i := 0
+	for k := range b.data + 1 {
+		for j := range blocks {
+			if k < b.data || j >= b.count {
+				blocks[j] = append(blocks[j], raw[i])
+				i++
+			}
+		}
+	}
+	divisor := rsDivisor(b.ecc)
+	var data []byte
+	for j := range blocks {
+		ecc := make([]byte, b.ecc)
+		for k := range ecc {
+			ecc[k] = raw[i+k*n+j]
+		}
+		if got := rsRemainder(blocks[j], divisor); !reflect.DeepEqual(got, ecc) {
+			t.Errorf("block %d error correction = %v, want %v", j, ecc, got)
+		}
+		data = append(data, blocks[j]...)
+	}
+
+	read := func(bit, bits int) int {
+		v := 0
+		for k := range bits {
+			v = v<<1 | int(data[(bit+k)/8]>>(7-(bit+k)%8)&1)
+		}
+		return v
+	}
+	if mode := read(0, 4); mode != 0b0100 {
+		t.Fatalf("mode = %04b, want byte mode", mode)
+	}
+	count := read(4, countBits(c.Version))
+	out := make([]byte, count)
+	for k := range out {
+		out[k] = byte(read(4+countBits(c.Version)+8*k, 8))
+	}
+	return string(out)
+}
+
+func TestPNG(t *testing.T) {
+	c, err := Encode([]byte("https://example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := c.PNG(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	side := (c.Size + 2*quietZone) * 4
+	if b := img.Bounds(); b.Dx() != side || b.Dy() != side {
+		t.Fatalf("image is %dx%d, want %dx%d", b.Dx(), b.Dy(), side, side)
+	}
+	// The top left finder pattern starts after the quiet zone.
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Error("quiet zone is dark")
+	}
+	if r, _, _, _ := img.At(quietZone*4, quietZone*4).RGBA(); r != 0 {
+		t.Error("finder pattern corner is light")
+	}
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Commit line formats.
+const (
+	// CommitFormatPretty shows the scope in bold followed by the description.
+	CommitFormatPretty = "pretty"
+	// CommitFormatRaw shows the conventional commit subject verbatim, e.g.
+	// "feat(api)!: add X".
+	CommitFormatRaw = "raw"
+	// CommitFormatTemplate renders each commit with CommitTemplate.
+	CommitFormatTemplate = "template"
+)
+
+// sampleCommit is the commit custom commit templates are checked against.
+var sampleCommit = plugin.ConventionalCommit{
+	Hash:        "a1b2c3d4e5f6",
+	Type:        "feat",
+	Scope:       "api",
+	Description: "add search",
+	Author:      "Alice <alice@example.com>",
+}
+
+// commitLine renders the one-line form of a commit in the commit format of
+// opts. A commit template that fails to render falls back to the pretty
+// format; Validate reports broken templates.
+func commitLine(opts *Options, f formatter, commit plugin.ConventionalCommit) string {
+	switch opts.CommitFormat {
+	case CommitFormatRaw:
+		return f.escape(rawCommitSubject(commit))
+	case CommitFormatTemplate:
+		if line, err := renderCommitTemplate(opts, opts.CommitTemplate, commit); err == nil {
+			return line
+		}
+	}
+	return commitSubject(f, commit)
+}
+
+// rawCommitSubject rebuilds the conventional commit subject of a commit:
+// type, optional scope, breaking marker, and description.
+func rawCommitSubject(commit plugin.ConventionalCommit) string {
+	subject := strings.TrimSpace(strings.SplitN(commit.Description, "\n", 2)[0])
+	if commit.Type == "" {
+		return subject
+	}
+	prefix := commit.Type
+	if commit.Scope != "" {
+		prefix += "(" + commit.Scope + ")"
+	}
+	if commit.Breaking {
+		prefix += "!"
+	}
+	return prefix + ": " + subject
+}
+
+// renderCommitTemplate renders a commit template with the commit's fields,
+// e.g. {{.Type}}, {{.Scope}}, and {{.Description}}. The output is inserted
+// verbatim, so it must already be formatted for the parse mode; line breaks
+// are collapsed so each commit stays on one line.
+func renderCommitTemplate(opts *Options, templateStr string, commit plugin.ConventionalCommit) (string, error) {
+	tmpl, err := template.New("commit").
+		Option("missingkey=error").
+		Funcs(templateFuncs(opts)).
+		Parse(templateStr)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, commit); err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(b.String()), " "), nil
+}
+
+// ValidateCommitTemplate reports whether a commit template parses and
+// renders a sample commit.
+func ValidateCommitTemplate(templateStr string) error {
+	_, err := renderCommitTemplate(&Options{}, templateStr, sampleCommit)
+	return err
+}
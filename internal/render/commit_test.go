@@ -0,0 +1,117 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestCommitLine(t *testing.T) {
+	commit := plugin.ConventionalCommit{
+		Hash:        "a1b2c3d4e5f6",
+		Type:        "feat",
+		Scope:       "api",
+		Description: "add search.v2\n\nLonger body",
+		Breaking:    true,
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		commit   plugin.ConventionalCommit
+		expected string
+	}{
+		{
+			name:     "pretty",
+			opts:     Options{ParseMode: "MarkdownV2"},
+			commit:   commit,
+			expected: "*api:* add search\\.v2",
+		},
+		{
+			name:     "raw",
+			opts:     Options{ParseMode: "MarkdownV2", CommitFormat: CommitFormatRaw},
+			commit:   commit,
+			expected: "feat\\(api\\)\\!: add search\\.v2",
+		},
+		{
+			name:     "raw without scope",
+			opts:     Options{CommitFormat: CommitFormatRaw},
+			commit:   plugin.ConventionalCommit{Type: "fix", Description: "typo"},
+			expected: "fix: typo",
+		},
+		{
+			name:     "raw without type",
+			opts:     Options{CommitFormat: CommitFormatRaw},
+			commit:   plugin.ConventionalCommit{Description: "typo"},
+			expected: "typo",
+		},
+		{
+			name: "template",
+			opts: Options{
+				ParseMode:      "HTML",
+				CommitFormat:   CommitFormatTemplate,
+				CommitTemplate: "<code>{{trunc 7 .Hash}}</code>\n{{escape .Description}}",
+			},
+			commit:   plugin.ConventionalCommit{Hash: "a1b2c3d4e5f6", Description: "a < b"},
+			expected: "<code>a1b2c3d</code> a &lt; b",
+		},
+		{
+			name:     "broken template falls back to pretty",
+			opts:     Options{CommitFormat: CommitFormatTemplate, CommitTemplate: "{{.Missing}}"},
+			commit:   commit,
+			expected: "api: add search.v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commitLine(&tt.opts, newFormatter(&tt.opts), tt.commit); got != tt.expected {
+				t.Errorf("commitLine() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommitFormatInMessages(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version: "2.0.0",
+		Changes: &plugin.CategorizedChanges{
+			Features: []plugin.ConventionalCommit{{Type: "feat", Scope: "api", Description: "add X"}},
+			Breaking: []plugin.ConventionalCommit{{Type: "feat", Description: "drop v1", Breaking: true}},
+		},
+	}
+	r := New(Options{
+		CommitFormat:       CommitFormatRaw,
+		ChangesSummaryMode: ChangesSummarySampled,
+		Sections:           []Section{{Name: SectionChanges}, {Name: SectionBreaking}},
+	})
+
+	want := "\nChanges:\n" +
+		"• 1 features\n  ◦ feat(api): add X\n" +
+		"• 0 bug fixes\n" +
+		"• 1 breaking changes\n" +
+		"\n⚠️ Breaking Changes:\n• feat!: drop v1\n"
+	if got := r.Success(releaseCtx); got != want {
+		t.Errorf("Success() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateCommitTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		wantErr  bool
+	}{
+		{"{{.Type}}: {{.Description}} ({{.Author}})", false},
+		{"{{if .Breaking}}⚠️ {{end}}{{.Description}}", false},
+		{"{{.Missing}}", true},
+		{"{{.Type", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if err := ValidateCommitTemplate(tt.template); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommitTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
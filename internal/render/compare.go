@@ -0,0 +1,57 @@
+package render
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// compareStatsTop is the number of files listed by the compare stats
+// section.
+const compareStatsTop = 10
+
+// FileStat is how much one file changed between the previous release and
+// this one, as listed by git diff --numstat.
+type FileStat struct {
+	// Path is the file path; renames keep git's "old => new" notation.
+	Path string
+	// Insertions and Deletions are the changed line counts.
+	Insertions int
+	Deletions  int
+	// Binary reports a binary file, which has no line counts.
+	Binary bool
+}
+
+// TopFileStats returns the n files with the most changed lines, most first.
+// Ties are ordered by path so the result does not depend on input order.
+func TopFileStats(stats []FileStat, n int) []FileStat {
+	sorted := slices.SortedStableFunc(slices.Values(stats), func(a, b FileStat) int {
+		if c := cmp.Compare(b.Insertions+b.Deletions, a.Insertions+a.Deletions); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// compareStatsSection renders the changed files heading and the
+// compareStatsTop most changed files, followed by how many are left out.
+func compareStatsSection(f formatter, stats []FileStat) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n📊 %s\n", f.label(f.t(msgChangedFiles, len(stats)))))
+	for _, stat := range TopFileStats(stats, compareStatsTop) {
+		counts := fmt.Sprintf("+%d −%d", stat.Insertions, stat.Deletions)
+		if stat.Binary {
+			counts = "binary"
+		}
+		sb.WriteString(fmt.Sprintf("• %s %s\n", f.code(stat.Path), f.escape(counts)))
+	}
+	if more := len(stats) - compareStatsTop; more > 0 {
+		sb.WriteString(fmt.Sprintf("• %s\n", f.escape(f.t(msgMore, more))))
+	}
+	return sb.String()
+}
@@ -0,0 +1,88 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestTopFileStats(t *testing.T) {
+	stats := []FileStat{
+		{Path: "b.go", Insertions: 1, Deletions: 1},
+		{Path: "logo.png", Binary: true},
+		{Path: "c.go", Insertions: 10},
+		{Path: "a.go", Deletions: 2},
+	}
+	got := TopFileStats(stats, 3)
+	want := []FileStat{
+		{Path: "c.go", Insertions: 10},
+		{Path: "a.go", Deletions: 2},
+		{Path: "b.go", Insertions: 1, Deletions: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TopFileStats() = %+v, want %+v", got, want)
+	}
+	if stats[0].Path != "b.go" {
+		t.Error("TopFileStats() reordered its input")
+	}
+}
+
+func TestSuccessCompareStats(t *testing.T) {
+	stats := []FileStat{{Path: "logo_v2.png", Binary: true}}
+	for i := range 11 {
+		stats = append(stats, FileStat{Path: fmt.Sprintf("pkg/file%02d.go", i), Insertions: 100 - i})
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "markdown",
+			opts: Options{ParseMode: "MarkdownV2", CompareStats: stats},
+			contains: []string{
+				"\n📊 *Changed files \\(12\\):*\n• `pkg/file00\\.go` \\+100 −0\n",
+				"• `pkg/file09\\.go` \\+91 −0\n• …and 2 more\n",
+			},
+			excludes: []string{"file10", "logo"},
+		},
+		{
+			name:     "localized",
+			opts:     Options{CompareStats: stats[:1], Language: []string{"de"}},
+			contains: []string{"📊 Geänderte Dateien (1):\n• logo_v2.png binary\n"},
+		},
+		{
+			name: "placed by sections",
+			opts: Options{
+				CompareStats: stats[:1],
+				Sections:     []Section{{Name: SectionCompareStats}, {Name: SectionHeader}},
+			},
+			contains: []string{"\n📊 Changed files (1):\n• logo_v2.png binary\n🚀"},
+		},
+		{
+			name:     "no stats",
+			opts:     Options{},
+			excludes: []string{"📊"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := New(tt.opts).Success(plugin.ReleaseContext{Version: "1.0.0"})
+			for _, want := range tt.contains {
+				if !strings.Contains(text, want) {
+					t.Errorf("Success() = %q, want it to contain %q", text, want)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("Success() = %q, want no %q", text, unwanted)
+				}
+			}
+		})
+	}
+}
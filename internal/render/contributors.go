@@ -0,0 +1,99 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// coAuthorTrailer prefixes co-author lines in commit bodies.
+const coAuthorTrailer = "co-authored-by:"
+
+// Contributor is a commit author or co-author of a release.
+type Contributor struct {
+	// Name is the author name, e.g. "Alice Smith".
+	Name string
+	// Email is the author email, if known.
+	Email string
+	// Handle is the Telegram username from ContributorHandles, without @.
+	Handle string
+}
+
+// String returns how the contributor is mentioned: @handle when known,
+// otherwise the name or email. It makes {{join ", " .Contributors}} work.
+func (c Contributor) String() string {
+	switch {
+	case c.Handle != "":
+		return "@" + c.Handle
+	case c.Name != "":
+		return c.Name
+	default:
+		return c.Email
+	}
+}
+
+// Contributors returns the authors and co-authors of the release's commits
+// in order of first appearance. Contributors are deduplicated by email, or
+// by name when no email is known, and bot accounts are skipped.
+func Contributors(changes *plugin.CategorizedChanges, handles map[string]string) []Contributor {
+	if changes == nil {
+		return nil
+	}
+
+	var contributors []Contributor
+	seen := make(map[string]bool)
+	add := func(c Contributor) {
+		key := strings.ToLower(c.Email)
+		if key == "" {
+			key = strings.ToLower(c.Name)
+		}
+		if key == "" || seen[key] || strings.HasSuffix(c.Name, "[bot]") {
+			return
+		}
+		seen[key] = true
+		c.Handle = contributorHandle(c, handles)
+		contributors = append(contributors, c)
+	}
+
+	for _, commits := range [][]plugin.ConventionalCommit{changes.Breaking, changes.Features, changes.Fixes, changes.Other} {
+		for _, commit := range commits {
+			add(parseContributor(commit.Author))
+			for _, line := range strings.Split(commit.Body, "\n") {
+				line = strings.TrimSpace(line)
+				if len(line) > len(coAuthorTrailer) && strings.EqualFold(line[:len(coAuthorTrailer)], coAuthorTrailer) {
+					add(parseContributor(line[len(coAuthorTrailer):]))
+				}
+			}
+		}
+	}
+	return contributors
+}
+
+// parseContributor parses "Name <email>", a bare name, or a bare email.
+func parseContributor(s string) Contributor {
+	s = strings.TrimSpace(s)
+	if name, rest, ok := strings.Cut(s, "<"); ok {
+		email, _, _ := strings.Cut(rest, ">")
+		return Contributor{Name: strings.TrimSpace(name), Email: strings.TrimSpace(email)}
+	}
+	if strings.Contains(s, "@") && !strings.ContainsAny(s, " \t") {
+		return Contributor{Email: s}
+	}
+	return Contributor{Name: s}
+}
+
+// contributorHandle looks up the Telegram username of c by email, then by
+// name. Keys are matched case-insensitively and a leading @ is dropped.
+func contributorHandle(c Contributor, handles map[string]string) string {
+	for _, id := range []string{c.Email, c.Name} {
+		if id == "" {
+			continue
+		}
+		for key, handle := range handles {
+			if strings.EqualFold(key, id) {
+				return strings.TrimPrefix(strings.TrimSpace(handle), "@")
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseContributor(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Contributor
+	}{
+		{"Alice Smith <alice@example.com>", Contributor{Name: "Alice Smith", Email: "alice@example.com"}},
+		{" Bob ", Contributor{Name: "Bob"}},
+		{"carol@example.com", Contributor{Email: "carol@example.com"}},
+		{"<dave@example.com>", Contributor{Email: "dave@example.com"}},
+		{"", Contributor{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := parseContributor(tt.input); got != tt.expected {
+				t.Errorf("parseContributor(%q) = %+v, want %+v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestContributors(t *testing.T) {
+	changes := &plugin.CategorizedChanges{
+		Features: []plugin.ConventionalCommit{
+			{Author: "Alice Smith <alice@example.com>", Body: "Details.\n\nCo-authored-by: Bob <bob@example.com>"},
+			{Author: "dependabot[bot] <bot@example.com>"},
+		},
+		Fixes: []plugin.ConventionalCommit{
+			{Author: "alice smith <ALICE@example.com>", Body: "co-authored-by: Carol"},
+			{Author: "Bob <bob@example.com>"},
+		},
+	}
+	handles := map[string]string{
+		"alice@example.com": "@alice",
+		"Carol":             "carol_t",
+	}
+
+	got := Contributors(changes, handles)
+	want := []Contributor{
+		{Name: "Alice Smith", Email: "alice@example.com", Handle: "alice"},
+		{Name: "Bob", Email: "bob@example.com"},
+		{Name: "Carol", Handle: "carol_t"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Contributors() = %+v, want %+v", got, want)
+	}
+
+	if got := Contributors(nil, nil); got != nil {
+		t.Errorf("Contributors(nil) = %+v, want nil", got)
+	}
+}
+
+func TestContributorsSection(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version: "1.0.0",
+		Changes: &plugin.CategorizedChanges{
+			Features: []plugin.ConventionalCommit{
+				{Author: "Alice <alice@example.com>", Body: "Co-authored-by: Bob <bob@example.com>"},
+			},
+		},
+	}
+	handles := map[string]string{"alice@example.com": "alice_dev"}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "show contributors",
+			opts:     Options{ParseMode: "MarkdownV2", ShowContributors: true, ContributorHandles: handles, Sections: []Section{{Name: SectionHeader}}},
+			expected: "🚀 *Release 1\\.0\\.0 Published\\!*\n\n\n🙏 Thanks to @alice\\_dev, Bob\n",
+		},
+		{
+			name:     "listed section",
+			opts:     Options{Sections: []Section{{Name: SectionContributors}}},
+			expected: "\n🙏 Thanks to Alice, Bob\n",
+		},
+		{
+			name:     "hidden by default",
+			opts:     Options{Sections: []Section{{Name: SectionHeader}}},
+			expected: "🚀 Release 1.0.0 Published!\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.opts).Success(releaseCtx); got != tt.expected {
+				t.Errorf("Success() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Digest schedules.
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// DigestRelease summarizes a release collected into a digest.
+type DigestRelease struct {
+	Component   string
+	Version     string
+	ReleaseType string
+	Features    int
+	Fixes       int
+	Breaking    int
+}
+
+// Digest renders the combined summary of the releases collected during the
+// digest period starting at start: one line per release with its change
+// counts.
+func (r *Renderer) Digest(schedule string, start time.Time, releases []DigestRelease) string {
+	f := newFormatter(&r.opts)
+
+	headline := f.t(msgDailyDigest, start.Format("2006-01-02"))
+	if schedule == DigestWeekly {
+		headline = f.t(msgWeeklyDigest, start.Format("2006-01-02"))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🗓 %s\n\n", f.bold(f.escape(headline))))
+	for _, release := range releases {
+		line := "📦 " + f.code(release.Version)
+		if release.Component != "" {
+			line = "📦 " + f.escape(release.Component) + " " + f.code(release.Version)
+		}
+		if release.ReleaseType != "" {
+			line += " " + f.escape(fmt.Sprintf("(%s)", release.ReleaseType))
+		}
+
+		var counts []string
+		if release.Features > 0 {
+			counts = append(counts, f.t(msgFeatures, release.Features))
+		}
+		if release.Fixes > 0 {
+			counts = append(counts, f.t(msgBugFixes, release.Fixes))
+		}
+		if release.Breaking > 0 {
+			counts = append(counts, f.t(msgBreakingCount, release.Breaking))
+		}
+		if len(counts) > 0 {
+			line += f.escape(": " + strings.Join(counts, ", "))
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,55 @@
+package render
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDigest(t *testing.T) {
+	start := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	releases := []DigestRelease{
+		{Version: "1.2.0", ReleaseType: "minor", Features: 3, Fixes: 1},
+		{Component: "payments", Version: "1.2.1", ReleaseType: "patch"},
+	}
+
+	tests := []struct {
+		name      string
+		parseMode string
+		language  []string
+		schedule  string
+		expected  string
+	}{
+		{
+			name:     "daily plain",
+			schedule: DigestDaily,
+			expected: "🗓 Release digest for 2024-03-11\n\n" +
+				"📦 1.2.0 (minor): 3 features, 1 bug fixes\n" +
+				"📦 payments 1.2.1 (patch)\n",
+		},
+		{
+			name:      "weekly MarkdownV2",
+			parseMode: "MarkdownV2",
+			schedule:  DigestWeekly,
+			expected: "🗓 *Release digest for the week of 2024\\-03\\-11*\n\n" +
+				"📦 `1\\.2\\.0` \\(minor\\): 3 features, 1 bug fixes\n" +
+				"📦 payments `1\\.2\\.1` \\(patch\\)\n",
+		},
+		{
+			name:     "translated",
+			language: []string{"de"},
+			schedule: DigestDaily,
+			expected: "🗓 Release-Übersicht vom 2024-03-11\n\n" +
+				"📦 1.2.0 (minor): 3 Features, 1 Fehlerbehebungen\n" +
+				"📦 payments 1.2.1 (patch)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(Options{ParseMode: tt.parseMode, Language: tt.language})
+			if got := r.Digest(tt.schedule, start, releases); got != tt.expected {
+				t.Errorf("Digest() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// MaxFormattingDepth is the deepest entity nesting left in place. Telegram
+// does not document a limit, but deeply stacked entities are likely to be
+// rejected, so anything deeper is reported and flattened.
+const MaxFormattingDepth = 3
+
+// htmlEntityTags lists the HTML tags Telegram accepts.
+var htmlEntityTags = map[string]bool{
+	"b": true, "strong": true, "i": true, "em": true, "u": true, "ins": true,
+	"s": true, "strike": true, "del": true, "span": true, "tg-spoiler": true,
+	"a": true, "code": true, "pre": true, "blockquote": true, "tg-emoji": true,
+}
+
+// htmlTagPattern matches an opening or closing HTML tag at the start of
+// the input.
+var htmlTagPattern = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9-]*)(?:\s[^<>]*)?>`)
+
+// markdownMarkers lists the MarkdownV2 entity markers, longest first so
+// "__" is matched before "_".
+var markdownMarkers = []string{"```", "`", "||", "__", "_", "*", "~"}
+
+type formattingTokenKind int
+
+const (
+	formattingText formattingTokenKind = iota
+	formattingOpen
+	formattingClose
+	// formattingToggle is a MarkdownV2 marker that opens or closes depending
+	// on whether the entity is already open.
+	formattingToggle
+)
+
+// formattingToken is a run of text or an entity marker.
+type formattingToken struct {
+	kind formattingTokenKind
+	name string
+	text string
+}
+
+// openEntity is an entity on the analyzer's stack.
+type openEntity struct {
+	name string
+	open string
+	// dropped entities were removed from the repaired text; their closing
+	// markers are removed too.
+	dropped bool
+}
+
+// AnalyzeFormatting checks text rendered from a custom template for
+// formatting Telegram is likely to reject: unterminated or unexpected
+// entities, crossing entities, entities inside code, and nesting deeper
+// than MaxFormattingDepth. It returns the issues found and a repaired copy
+// of text that closes unterminated entities and flattens the nesting. Plain
+// text is returned unchanged.
+func AnalyzeFormatting(text, parseMode string) ([]string, string) {
+	var tokens []formattingToken
+	switch parseMode {
+	case "HTML":
+		tokens = tokenizeHTML(text)
+	case "MarkdownV2":
+		tokens = tokenizeMarkdownV2(text)
+	default:
+		return nil, text
+	}
+
+	a := formattingAnalyzer{html: parseMode == "HTML"}
+	for _, tok := range tokens {
+		switch tok.kind {
+		case formattingText:
+			a.out.WriteString(tok.text)
+		case formattingToggle:
+			if a.find(tok.name) >= 0 {
+				a.close(tok)
+			} else {
+				a.open(tok)
+			}
+		case formattingOpen:
+			a.open(tok)
+		case formattingClose:
+			a.close(tok)
+		}
+	}
+	a.finish()
+
+	if len(a.issues) == 0 {
+		return nil, text
+	}
+	return a.issues, a.out.String()
+}
+
+// StripFormatting returns text rendered for parseMode as plain text: entity
+// markers and tags are removed, and MarkdownV2 escapes and HTML entities
+// are resolved. Plain text is returned unchanged.
+func StripFormatting(text, parseMode string) string {
+	var tokens []formattingToken
+	switch parseMode {
+	case "HTML":
+		tokens = tokenizeHTML(text)
+	case "MarkdownV2":
+		tokens = tokenizeMarkdownV2(text)
+	default:
+		return text
+	}
+
+	var sb strings.Builder
+	for _, tok := range tokens {
+		if tok.kind != formattingText {
+			continue
+		}
+		if parseMode == "HTML" {
+			sb.WriteString(html.UnescapeString(tok.text))
+		} else {
+			sb.WriteString(markdownV2Unescape.Replace(tok.text))
+		}
+	}
+	return sb.String()
+}
+
+// markdownV2Unescape resolves the escapes EscapeMarkdownV2 adds.
+var markdownV2Unescape = func() *strings.Replacer {
+	var pairs []string
+	for _, c := range "_*[]()~`>#+-=|{}.!\\" {
+		pairs = append(pairs, "\\"+string(c), string(c))
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+// formattingAnalyzer tracks open entities while rebuilding the text.
+type formattingAnalyzer struct {
+	html   bool
+	stack  []openEntity
+	out    strings.Builder
+	issues []string
+}
+
+// open handles an opening marker.
+func (a *formattingAnalyzer) open(tok formattingToken) {
+	entity := openEntity{name: tok.name, open: tok.text}
+	if verbatim := a.verbatim(); verbatim != "" && !(a.html && tok.name == "code" && verbatim == "pre") {
+		a.issue("%s inside %s", a.display(tok.name), a.display(verbatim))
+		entity.dropped = true
+	} else if a.depth() >= MaxFormattingDepth {
+		a.issue("%s nested deeper than %d entities", a.display(tok.name), MaxFormattingDepth)
+		entity.dropped = true
+	} else {
+		a.out.WriteString(tok.text)
+	}
+	a.stack = append(a.stack, entity)
+}
+
+// close handles a closing marker. Entities opened after the one being
+// closed are closed first and reopened afterwards, so entities never cross.
+func (a *formattingAnalyzer) close(tok formattingToken) {
+	i := a.find(tok.name)
+	if i < 0 {
+		a.issue("unexpected closing %s", a.display(tok.name))
+		return
+	}
+
+	above := append([]openEntity(nil), a.stack[i+1:]...)
+	for j := len(above) - 1; j >= 0; j-- {
+		if !above[j].dropped {
+			a.issue("%s crosses %s", a.display(tok.name), a.display(above[j].name))
+			a.out.WriteString(a.closer(above[j].name))
+		}
+	}
+	if !a.stack[i].dropped {
+		a.out.WriteString(tok.text)
+	}
+	a.stack = a.stack[:i]
+	for _, entity := range above {
+		if !entity.dropped {
+			a.out.WriteString(entity.open)
+		}
+		a.stack = append(a.stack, entity)
+	}
+}
+
+// finish closes the entities still open at the end of the text. An entity
+// with no content is removed instead of closed.
+func (a *formattingAnalyzer) finish() {
+	for i := len(a.stack) - 1; i >= 0; i-- {
+		entity := a.stack[i]
+		if entity.dropped {
+			continue
+		}
+		a.issue("unterminated %s", a.display(entity.name))
+		if out := a.out.String(); strings.HasSuffix(out, entity.open) {
+			a.out.Reset()
+			a.out.WriteString(strings.TrimSuffix(out, entity.open))
+		} else {
+			a.out.WriteString(a.closer(entity.name))
+		}
+	}
+	a.stack = nil
+}
+
+// find returns the index of the innermost open entity named name, or -1.
+func (a *formattingAnalyzer) find(name string) int {
+	for i := len(a.stack) - 1; i >= 0; i-- {
+		if a.stack[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// depth returns the number of open entities kept in the repaired text.
+func (a *formattingAnalyzer) depth() int {
+	n := 0
+	for _, entity := range a.stack {
+		if !entity.dropped {
+			n++
+		}
+	}
+	return n
+}
+
+// verbatim returns the open code or pre entity, if any. Telegram does not
+// allow other entities inside code.
+func (a *formattingAnalyzer) verbatim() string {
+	for _, entity := range a.stack {
+		if entity.dropped {
+			continue
+		}
+		switch entity.name {
+		case "code", "pre", "`", "```":
+			return entity.name
+		}
+	}
+	return ""
+}
+
+// closer returns the closing marker for an entity.
+func (a *formattingAnalyzer) closer(name string) string {
+	if a.html {
+		return "</" + name + ">"
+	}
+	return name
+}
+
+// display returns how an entity is named in issues.
+func (a *formattingAnalyzer) display(name string) string {
+	if a.html {
+		return "<" + name + ">"
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// issue records a formatting issue.
+func (a *formattingAnalyzer) issue(format string, args ...any) {
+	a.issues = append(a.issues, fmt.Sprintf(format, args...))
+}
+
+// tokenizeHTML splits text into Telegram HTML tags and text. Unknown tags
+// are left as text.
+func tokenizeHTML(text string) []formattingToken {
+	var tokens []formattingToken
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '<' {
+			continue
+		}
+		m := htmlTagPattern.FindStringSubmatch(text[i:])
+		if m == nil || !htmlEntityTags[strings.ToLower(m[2])] {
+			continue
+		}
+		if start < i {
+			tokens = append(tokens, formattingToken{kind: formattingText, text: text[start:i]})
+		}
+		kind := formattingOpen
+		if m[1] == "/" {
+			kind = formattingClose
+		}
+		tokens = append(tokens, formattingToken{kind: kind, name: strings.ToLower(m[2]), text: m[0]})
+		i += len(m[0]) - 1
+		start = i + 1
+	}
+	if start < len(text) {
+		tokens = append(tokens, formattingToken{kind: formattingText, text: text[start:]})
+	}
+	return tokens
+}
+
+// tokenizeMarkdownV2 splits text into MarkdownV2 entity markers and text.
+// Escaped characters are text, and code spans are kept whole since no
+// entities apply inside them.
+func tokenizeMarkdownV2(text string) []formattingToken {
+	var tokens []formattingToken
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			tokens = append(tokens, formattingToken{kind: formattingText, text: buf.String()})
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		if text[i] == '\\' && i+1 < len(text) {
+			buf.WriteString(text[i : i+2])
+			i += 2
+			continue
+		}
+		marker := markdownMarker(text[i:])
+		if marker == "" {
+			buf.WriteByte(text[i])
+			i++
+			continue
+		}
+		flush()
+		i += len(marker)
+
+		if marker != "`" && marker != "```" {
+			tokens = append(tokens, formattingToken{kind: formattingToggle, name: marker, text: marker})
+			continue
+		}
+		tokens = append(tokens, formattingToken{kind: formattingOpen, name: marker, text: marker})
+		end := markdownCodeEnd(text[i:], marker)
+		if end < 0 {
+			buf.WriteString(text[i:])
+			break
+		}
+		buf.WriteString(text[i : i+end])
+		flush()
+		tokens = append(tokens, formattingToken{kind: formattingClose, name: marker, text: marker})
+		i += end + len(marker)
+	}
+	flush()
+	return tokens
+}
+
+// markdownMarker returns the entity marker at the start of s, or "".
+func markdownMarker(s string) string {
+	for _, marker := range markdownMarkers {
+		if strings.HasPrefix(s, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// markdownCodeEnd returns the offset of the unescaped marker closing a code
+// span in s, or -1.
+func markdownCodeEnd(s, marker string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], marker) {
+			return i
+		}
+	}
+	return -1
+}
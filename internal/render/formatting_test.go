@@ -0,0 +1,151 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeFormatting(t *testing.T) {
+	tests := []struct {
+		name       string
+		parseMode  string
+		text       string
+		wantIssues []string
+		wantText   string
+	}{
+		{
+			name:      "valid html",
+			parseMode: "HTML",
+			text:      `<b>Release</b> <a href="https://x">notes</a> <pre><code class="language-go">x</code></pre>`,
+			wantText:  `<b>Release</b> <a href="https://x">notes</a> <pre><code class="language-go">x</code></pre>`,
+		},
+		{
+			name:       "unterminated html",
+			parseMode:  "HTML",
+			text:       "<b>Release <i>1.0.0",
+			wantIssues: []string{"unterminated <i>", "unterminated <b>"},
+			wantText:   "<b>Release <i>1.0.0</i></b>",
+		},
+		{
+			name:       "unexpected closing html",
+			parseMode:  "HTML",
+			text:       "Release</b> 1.0.0",
+			wantIssues: []string{"unexpected closing <b>"},
+			wantText:   "Release 1.0.0",
+		},
+		{
+			name:       "crossing html",
+			parseMode:  "HTML",
+			text:       "<b>a<i>b</b>c</i>",
+			wantIssues: []string{"<b> crosses <i>"},
+			wantText:   "<b>a<i>b</i></b><i>c</i>",
+		},
+		{
+			name:       "crossing html at the end",
+			parseMode:  "HTML",
+			text:       "<b>a<i>b</b>",
+			wantIssues: []string{"<b> crosses <i>", "unterminated <i>"},
+			wantText:   "<b>a<i>b</i></b>",
+		},
+		{
+			name:       "html nested too deep",
+			parseMode:  "HTML",
+			text:       "<b><i><u><s>x</s></u></i></b>",
+			wantIssues: []string{"<s> nested deeper than 3 entities"},
+			wantText:   "<b><i><u>x</u></i></b>",
+		},
+		{
+			name:       "html inside code",
+			parseMode:  "HTML",
+			text:       "<code><b>x</b></code>",
+			wantIssues: []string{"<b> inside <code>"},
+			wantText:   "<code>x</code>",
+		},
+		{
+			name:      "unknown html tags are text",
+			parseMode: "HTML",
+			text:      "a <foo> b",
+			wantText:  "a <foo> b",
+		},
+		{
+			name:      "valid markdown",
+			parseMode: "MarkdownV2",
+			text:      "*Release* _1\\.0\\.0_ `a*b` \\*",
+			wantText:  "*Release* _1\\.0\\.0_ `a*b` \\*",
+		},
+		{
+			name:       "unterminated markdown",
+			parseMode:  "MarkdownV2",
+			text:       "*Release _1\\.0\\.0",
+			wantIssues: []string{`unterminated "_"`, `unterminated "*"`},
+			wantText:   "*Release _1\\.0\\.0_*",
+		},
+		{
+			name:       "unterminated markdown code",
+			parseMode:  "MarkdownV2",
+			text:       "```go\nx := 1",
+			wantIssues: []string{"unterminated \"```\""},
+			wantText:   "```go\nx := 1```",
+		},
+		{
+			name:       "markdown nested too deep",
+			parseMode:  "MarkdownV2",
+			text:       "*_~||x||~_*",
+			wantIssues: []string{`"||" nested deeper than 3 entities`},
+			wantText:   "*_~x~_*",
+		},
+		{
+			name:      "plain text",
+			parseMode: "",
+			text:      "<b>*x",
+			wantText:  "<b>*x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, text := AnalyzeFormatting(tt.text, tt.parseMode)
+			if !reflect.DeepEqual(issues, tt.wantIssues) {
+				t.Errorf("issues = %q, want %q", issues, tt.wantIssues)
+			}
+			if text != tt.wantText {
+				t.Errorf("repaired = %q, want %q", text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestAnalyzeFormattingRepairedIsClean(t *testing.T) {
+	for _, tc := range []struct{ parseMode, text string }{
+		{"HTML", "<b>a<i>b</b>c</i></u><code><b>x"},
+		{"MarkdownV2", "*a _b* c_ ~d"},
+	} {
+		_, repaired := AnalyzeFormatting(tc.text, tc.parseMode)
+		if issues, _ := AnalyzeFormatting(repaired, tc.parseMode); issues != nil {
+			t.Errorf("AnalyzeFormatting(%q) still reports %q", repaired, issues)
+		}
+	}
+}
+
+func TestStripFormatting(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		parseMode string
+		expected  string
+	}{
+		{"plain text", "*kept* as is", "", "*kept* as is"},
+		{"markdown", "*Release 1\\.2\\.3* ||spoiler|| `a_b`", "MarkdownV2", "Release 1.2.3 spoiler a_b"},
+		{"markdown escaped backslash", "C:\\\\dir", "MarkdownV2", "C:\\dir"},
+		{"html", "<b>Fish &amp; Chips</b> <a href=\"https://example.com\">site</a>", "HTML", "Fish & Chips site"},
+		{"html unknown tag kept", "a <x> b", "HTML", "a <x> b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripFormatting(tt.text, tt.parseMode); got != tt.expected {
+				t.Errorf("StripFormatting() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
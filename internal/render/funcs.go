@@ -0,0 +1,126 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs returns the helpers callable from templates: the humanize
+// helpers, the escapers, and a curated subset of the Sprig string, list, default,
+// and date functions. Arguments follow Sprig's order, with the value being
+// operated on last, so the helpers can be used in pipelines such as
+// {{.TagName | trimPrefix "v"}}.
+func templateFuncs(opts *Options) template.FuncMap {
+	f := newFormatter(opts)
+	return template.FuncMap{
+		// The humanize helpers accept any argument so both
+		// {{humanizeBytes 2048}} and {{humanizeBytes .Variables.size}} work.
+		"humanizeDuration": func(v any) (string, error) { return HumanizeDuration(fmt.Sprint(v)) },
+		"humanizeBytes":    func(v any) (string, error) { return HumanizeBytes(fmt.Sprint(v)) },
+		"escape":           func(v any) string { return f.escape(fmt.Sprint(v)) },
+		// The explicit escapers ignore parse_mode, for templates that build
+		// text for another mode, such as a raw payload.
+		"escapeMD2":  func(v any) string { return EscapeMarkdownV2(fmt.Sprint(v)) },
+		"escapeHTML": func(v any) string { return html.EscapeString(fmt.Sprint(v)) },
+
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      f.title,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, repl, s string) string { return strings.ReplaceAll(s, old, repl) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"trunc":      truncRunes,
+		"truncate":   truncateNotes,
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       joinList,
+		"toJson":     toJSON,
+		"default":    defaultValue,
+		"now":        func() time.Time { return opts.Now },
+		"date":       formatDate,
+	}
+}
+
+// truncRunes returns the first n runes of s.
+func truncRunes(n int, s string) string {
+	if runes := []rune(s); n >= 0 && len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
+
+// joinList joins the elements of a slice or array with sep. Other values
+// are formatted as a single element.
+func joinList(sep string, list any) string {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		if list == nil {
+			return ""
+		}
+		return fmt.Sprint(list)
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+// toJSON encodes v as JSON, for building JSON documents such as raw
+// payloads from templates. HTML characters are not escaped.
+func toJSON(v any) (string, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// defaultValue returns def when v is empty: nil, false, zero, or a string,
+// slice, or map of length 0.
+func defaultValue(def, v any) any {
+	if v == nil {
+		return def
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		if rv.Len() == 0 {
+			return def
+		}
+	default:
+		if rv.IsZero() {
+			return def
+		}
+	}
+	return v
+}
+
+// formatDate formats t with a Go time layout. Besides time.Time values it
+// accepts the YYYY-MM-DD and RFC 3339 strings templates see, such as
+// {{.Date}}.
+func formatDate(layout string, t any) (string, error) {
+	switch val := t.(type) {
+	case time.Time:
+		return val.Format(layout), nil
+	case string:
+		for _, parse := range []string{"2006-01-02", time.RFC3339} {
+			if parsed, err := time.Parse(parse, val); err == nil {
+				return parsed.Format(layout), nil
+			}
+		}
+		return "", fmt.Errorf("%q is not a date", val)
+	default:
+		return "", fmt.Errorf("cannot format %T as a date", t)
+	}
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	r := New(Options{
+		Now:       time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC),
+		Variables: map[string]string{"owner": "", "tags": "api,web"},
+	})
+	releaseCtx := plugin.ReleaseContext{
+		Version: "1.2.3",
+		TagName: "v1.2.3",
+		Branch:  "release/1.2",
+		Changes: &plugin.CategorizedChanges{
+			Features: []plugin.ConventionalCommit{{Scope: "api"}, {Scope: "web"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		wantErr  bool
+	}{
+		{name: "escapeMD2", template: `{{escapeMD2 .Branch}} {{escapeMD2 "a-b_c!"}}`, expected: `release/1\.2 a\-b\_c\!`},
+		{name: "escapeHTML", template: `{{escapeHTML "<b>a & b</b>"}}`, expected: "&lt;b&gt;a &amp; b&lt;/b&gt;"},
+		{name: "escape follows parse mode", template: `{{escape "a.b"}}`, expected: "a.b"},
+		{name: "upper", template: "{{upper .Branch}}", expected: "RELEASE/1.2"},
+		{name: "lower", template: `{{lower "MAIN"}}`, expected: "main"},
+		{name: "title", template: `{{title "new release"}}`, expected: "New Release"},
+		{name: "trim", template: `{{trim "  x  "}}`, expected: "x"},
+		{name: "trimPrefix", template: `{{.TagName | trimPrefix "v"}}`, expected: "1.2.3"},
+		{name: "trimSuffix", template: `{{trimSuffix ".3" .Version}}`, expected: "1.2"},
+		{name: "replace", template: `{{replace "/" "-" .Branch}}`, expected: "release-1.2"},
+		{name: "contains", template: `{{if contains "release" .Branch}}yes{{end}}`, expected: "yes"},
+		{name: "hasPrefix", template: `{{if hasPrefix "release/" .Branch}}yes{{end}}`, expected: "yes"},
+		{name: "hasSuffix", template: `{{if hasSuffix ".9" .Version}}yes{{else}}no{{end}}`, expected: "no"},
+		{name: "trunc", template: `{{trunc 3 "abcdef"}}`, expected: "abc"},
+		{name: "truncate", template: `{{truncate 3 "abcdef"}} {{"abc" | truncate 3}}`, expected: "abc... abc"},
+		{name: "join split list", template: `{{splitList "," .Variables.tags | join " + "}}`, expected: "api + web"},
+		{name: "default on empty", template: `{{.Variables.owner | default "nobody"}}`, expected: "nobody"},
+		{name: "default on value", template: `{{.Version | default "dev"}}`, expected: "1.2.3"},
+		{name: "date from now", template: `{{now | date "Jan 2, 2006"}}`, expected: "Mar 9, 2024"},
+		{name: "date from string", template: `{{.Date | date "02.01.2006"}}`, expected: "09.03.2024"},
+		{name: "invalid date", template: `{{date "2006" .Version}}`, wantErr: true},
+		{name: "toJson", template: `{{printf "%s <b>\"%s\"</b>" .Version .Branch | toJson}}`, expected: `"1.2.3 <b>\"release/1.2\"</b>"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Template(tt.template, releaseCtx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Template() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("Template() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJoinList(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     any
+		expected string
+	}{
+		{"strings", []string{"a", "b"}, "a, b"},
+		{"mixed", []any{"a", 1, true}, "a, 1, true"},
+		{"scalar", "a", "a"},
+		{"nil", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinList(", ", tt.list); got != tt.expected {
+				t.Errorf("joinList() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
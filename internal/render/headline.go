@@ -0,0 +1,54 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// defaultHeadlineEmoji is the success headline emoji when no rule matches.
+const defaultHeadlineEmoji = "🚀"
+
+// headlineMetrics maps rule metric names to how they are counted.
+var headlineMetrics = map[string]func(*plugin.CategorizedChanges) int{
+	"breaking": func(c *plugin.CategorizedChanges) int { return len(c.Breaking) },
+	"features": func(c *plugin.CategorizedChanges) int { return len(c.Features) },
+	"fixes":    func(c *plugin.CategorizedChanges) int { return len(c.Fixes) },
+}
+
+// HeadlineRule escalates the success headline emoji when a change count
+// reaches a threshold.
+type HeadlineRule struct {
+	// Metric is the change count to test: breaking, features, or fixes.
+	Metric string `json:"metric" enum:"breaking,features,fixes" required:"true"`
+	// Min is the inclusive threshold.
+	Min int `json:"min" default:"1"`
+	// Emoji replaces the default headline emoji when the rule matches.
+	Emoji string `json:"emoji" required:"true"`
+}
+
+// Validate reports whether the rule can be applied.
+func (r HeadlineRule) Validate() error {
+	if _, ok := headlineMetrics[r.Metric]; !ok {
+		return fmt.Errorf("unknown metric %q (expected breaking, features, or fixes)", r.Metric)
+	}
+	if r.Emoji == "" {
+		return fmt.Errorf("emoji is required")
+	}
+	return nil
+}
+
+// headlineEmoji returns the emoji of the first rule whose threshold the
+// release reaches, or the default emoji.
+func headlineEmoji(rules []HeadlineRule, changes *plugin.CategorizedChanges) string {
+	if changes == nil {
+		return defaultHeadlineEmoji
+	}
+	for _, rule := range rules {
+		count, ok := headlineMetrics[rule.Metric]
+		if ok && count(changes) >= rule.Min {
+			return rule.Emoji
+		}
+	}
+	return defaultHeadlineEmoji
+}
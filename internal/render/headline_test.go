@@ -0,0 +1,56 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestHeadlineEmoji(t *testing.T) {
+	rules := []HeadlineRule{
+		{Metric: "breaking", Min: 1, Emoji: "⚠️🚨"},
+		{Metric: "features", Min: 5, Emoji: "🎉"},
+	}
+	commits := func(n int) []plugin.ConventionalCommit {
+		return make([]plugin.ConventionalCommit, n)
+	}
+
+	tests := []struct {
+		name     string
+		changes  *plugin.CategorizedChanges
+		expected string
+	}{
+		{"no changes", nil, "🚀"},
+		{"below thresholds", &plugin.CategorizedChanges{Features: commits(4)}, "🚀"},
+		{"feature threshold", &plugin.CategorizedChanges{Features: commits(5)}, "🎉"},
+		{"first matching rule wins", &plugin.CategorizedChanges{Features: commits(9), Breaking: commits(1)}, "⚠️🚨"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := headlineEmoji(rules, tt.changes); got != tt.expected {
+				t.Errorf("headlineEmoji() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHeadlineRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    HeadlineRule
+		wantErr bool
+	}{
+		{"valid", HeadlineRule{Metric: "fixes", Min: 3, Emoji: "🐛"}, false},
+		{"unknown metric", HeadlineRule{Metric: "commits", Emoji: "🎉"}, true},
+		{"missing emoji", HeadlineRule{Metric: "breaking", Min: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HumanizeDuration formats a duration given in seconds ("151", "2.5") or Go
+// duration syntax ("2m31s") as "2m 31s". Sub-second durations are shown in
+// milliseconds and zero units are omitted.
+func HumanizeDuration(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, ferr := strconv.ParseFloat(value, 64)
+		if ferr != nil {
+			return "", fmt.Errorf("invalid duration %q", value)
+		}
+		d = time.Duration(seconds * float64(time.Second))
+	}
+
+	if d < 0 {
+		return "", fmt.Errorf("negative duration %q", value)
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds()), nil
+	}
+
+	d = d.Round(time.Second)
+	units := []struct {
+		size   time.Duration
+		suffix string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+
+	var parts []string
+	for _, unit := range units {
+		if n := d / unit.size; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, unit.suffix))
+			d -= n * unit.size
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// humanizeReleaseDuration formats a release duration like HumanizeDuration,
+// or returns "" when it is not positive.
+func humanizeReleaseDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	text, _ := HumanizeDuration(d.String())
+	return text
+}
+
+// HumanizeBytes formats a byte count using decimal units, e.g. "14.2 MB".
+func HumanizeBytes(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("invalid byte count %q", value)
+	}
+
+	if n < 1000 {
+		return fmt.Sprintf("%d B", int64(n)), nil
+	}
+	units := []string{"kB", "MB", "GB", "TB", "PB"}
+	unit := -1
+	for n >= 1000 && unit < len(units)-1 {
+		n /= 1000
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", n, units[unit]), nil
+}
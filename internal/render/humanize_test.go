@@ -0,0 +1,62 @@
+package render
+
+import "testing"
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "151", expected: "2m 31s"},
+		{input: "2m31s", expected: "2m 31s"},
+		{input: "3600", expected: "1h"},
+		{input: "93784", expected: "1d 2h 3m 4s"},
+		{input: "2.6", expected: "3s"},
+		{input: "0.35", expected: "350ms"},
+		{input: "0", expected: "0ms"},
+		{input: " 45 ", expected: "45s"},
+		{input: "-5", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := HumanizeDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HumanizeDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("HumanizeDuration() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHumanizeBytes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "0", expected: "0 B"},
+		{input: "999", expected: "999 B"},
+		{input: "1000", expected: "1.0 kB"},
+		{input: "14200000", expected: "14.2 MB"},
+		{input: "3500000000", expected: "3.5 GB"},
+		{input: "-1", wantErr: true},
+		{input: "big", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := HumanizeBytes(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HumanizeBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("HumanizeBytes() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
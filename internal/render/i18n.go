@@ -0,0 +1,258 @@
+package render
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Message catalog keys.
+const (
+	msgReleasePublished = "release_published"
+	msgReleaseFailed    = "release_failed"
+	msgNextRelease      = "next_release"
+	msgVersion          = "version"
+	msgType             = "type"
+	msgBranch           = "branch"
+	msgTag              = "tag"
+	msgChanges          = "changes"
+	msgFeatures         = "features"
+	msgBugFixes         = "bug_fixes"
+	msgBreakingCount    = "breaking_count"
+	msgBreakingChanges  = "breaking_changes"
+	msgReleaseNotes     = "release_notes"
+	msgReleasePage      = "release_page"
+	msgCheckLogs        = "check_logs"
+	msgBreakingAlert    = "breaking_alert"
+	msgMore             = "more"
+	msgDailyDigest      = "daily_digest"
+	msgWeeklyDigest     = "weekly_digest"
+	msgThanksTo         = "thanks_to"
+	msgPermalinks       = "permalinks"
+	msgDuration         = "duration"
+	msgChangedFiles     = "changed_files"
+)
+
+// defaultLanguage is the language every chain falls back to.
+const defaultLanguage = "en"
+
+// catalogs holds the built-in translations. Regional catalogs such as pt-BR
+// only list the strings that differ from their base language.
+var catalogs = map[string]map[string]string{
+	"en": {
+		msgReleasePublished: "Release %s Published!",
+		msgReleaseFailed:    "Release %s Failed",
+		msgNextRelease:      "Next release will be %s",
+		msgVersion:          "Version",
+		msgType:             "Type",
+		msgBranch:           "Branch",
+		msgTag:              "Tag",
+		msgChanges:          "Changes",
+		msgFeatures:         "%d features",
+		msgBugFixes:         "%d bug fixes",
+		msgBreakingCount:    "%d breaking changes",
+		msgBreakingChanges:  "Breaking Changes",
+		msgReleaseNotes:     "Release Notes",
+		msgReleasePage:      "Release page",
+		msgCheckLogs:        "Please check the CI logs for details.",
+		msgBreakingAlert:    "Breaking changes in %s",
+		msgMore:             "…and %d more",
+		msgDailyDigest:      "Release digest for %s",
+		msgWeeklyDigest:     "Release digest for the week of %s",
+		msgThanksTo:         "Thanks to %s",
+		msgPermalinks:       "Announcement links for %s",
+		msgDuration:         "Released in %s",
+		msgChangedFiles:     "Changed files (%d)",
+	},
+	"de": {
+		msgReleasePublished: "Release %s veröffentlicht!",
+		msgReleaseFailed:    "Release %s fehlgeschlagen",
+		msgNextRelease:      "Nächstes Release wird %s",
+		msgVersion:          "Version",
+		msgType:             "Typ",
+		msgBranch:           "Branch",
+		msgTag:              "Tag",
+		msgChanges:          "Änderungen",
+		msgFeatures:         "%d Features",
+		msgBugFixes:         "%d Fehlerbehebungen",
+		msgBreakingCount:    "%d Breaking Changes",
+		msgBreakingChanges:  "Breaking Changes",
+		msgReleaseNotes:     "Release Notes",
+		msgReleasePage:      "Release-Seite",
+		msgCheckLogs:        "Details stehen in den CI-Logs.",
+		msgBreakingAlert:    "Breaking Changes in %s",
+		msgMore:             "…und %d weitere",
+		msgDailyDigest:      "Release-Übersicht vom %s",
+		msgWeeklyDigest:     "Release-Übersicht der Woche vom %s",
+		msgThanksTo:         "Danke an %s",
+		msgPermalinks:       "Links zu den Ankündigungen von %s",
+		msgDuration:         "Veröffentlicht in %s",
+		msgChangedFiles:     "Geänderte Dateien (%d)",
+	},
+	"es": {
+		msgReleasePublished: "¡Versión %s publicada!",
+		msgReleaseFailed:    "Falló la versión %s",
+		msgNextRelease:      "La próxima versión será %s",
+		msgVersion:          "Versión",
+		msgType:             "Tipo",
+		msgBranch:           "Rama",
+		msgTag:              "Etiqueta",
+		msgChanges:          "Cambios",
+		msgFeatures:         "%d funcionalidades",
+		msgBugFixes:         "%d correcciones",
+		msgBreakingCount:    "%d cambios incompatibles",
+		msgBreakingChanges:  "Cambios incompatibles",
+		msgReleaseNotes:     "Notas de la versión",
+		msgReleasePage:      "Página de la versión",
+		msgCheckLogs:        "Revisa los logs de CI para más detalles.",
+		msgBreakingAlert:    "Cambios incompatibles en %s",
+		msgMore:             "…y %d más",
+		msgDailyDigest:      "Resumen de versiones del %s",
+		msgWeeklyDigest:     "Resumen de versiones de la semana del %s",
+		msgThanksTo:         "Gracias a %s",
+		msgPermalinks:       "Enlaces a los anuncios de %s",
+		msgDuration:         "Publicado en %s",
+		msgChangedFiles:     "Archivos modificados (%d)",
+	},
+	"fr": {
+		msgReleasePublished: "Version %s publiée !",
+		msgReleaseFailed:    "Échec de la version %s",
+		msgNextRelease:      "La prochaine version sera %s",
+		msgVersion:          "Version",
+		msgType:             "Type",
+		msgBranch:           "Branche",
+		msgTag:              "Tag",
+		msgChanges:          "Changements",
+		msgFeatures:         "%d fonctionnalités",
+		msgBugFixes:         "%d corrections",
+		msgBreakingCount:    "%d changements incompatibles",
+		msgBreakingChanges:  "Changements incompatibles",
+		msgReleaseNotes:     "Notes de version",
+		msgReleasePage:      "Page de la version",
+		msgCheckLogs:        "Consultez les logs de la CI pour plus de détails.",
+		msgBreakingAlert:    "Changements incompatibles dans %s",
+		msgMore:             "…et %d de plus",
+		msgDailyDigest:      "Résumé des versions du %s",
+		msgWeeklyDigest:     "Résumé des versions de la semaine du %s",
+		msgThanksTo:         "Merci à %s",
+		msgPermalinks:       "Liens vers les annonces de %s",
+		msgDuration:         "Publié en %s",
+		msgChangedFiles:     "Fichiers modifiés (%d)",
+	},
+	"pt": {
+		msgReleasePublished: "Versão %s publicada!",
+		msgReleaseFailed:    "Falha na versão %s",
+		msgNextRelease:      "A próxima versão será %s",
+		msgVersion:          "Versão",
+		msgType:             "Tipo",
+		msgBranch:           "Ramo",
+		msgTag:              "Etiqueta",
+		msgChanges:          "Alterações",
+		msgFeatures:         "%d funcionalidades",
+		msgBugFixes:         "%d correções",
+		msgBreakingCount:    "%d alterações incompatíveis",
+		msgBreakingChanges:  "Alterações incompatíveis",
+		msgReleaseNotes:     "Notas da versão",
+		msgReleasePage:      "Página da versão",
+		msgCheckLogs:        "Consulte os logs de CI para mais detalhes.",
+		msgBreakingAlert:    "Alterações incompatíveis na versão %s",
+		msgMore:             "…e mais %d",
+		msgDailyDigest:      "Resumo de versões de %s",
+		msgWeeklyDigest:     "Resumo de versões da semana de %s",
+		msgThanksTo:         "Obrigado a %s",
+		msgPermalinks:       "Links dos anúncios da versão %s",
+		msgDuration:         "Publicado em %s",
+		msgChangedFiles:     "Ficheiros alterados (%d)",
+	},
+	"pt-BR": {
+		msgBranch:          "Branch",
+		msgTag:             "Tag",
+		msgChanges:         "Mudanças",
+		msgBreakingCount:   "%d mudanças incompatíveis",
+		msgBreakingChanges: "Mudanças incompatíveis",
+		msgBreakingAlert:   "Mudanças incompatíveis na versão %s",
+		msgChangedFiles:    "Arquivos alterados (%d)",
+	},
+}
+
+// resolveCatalog returns the catalog for the first language in chain that
+// can render every message, so a message is never mixed from several
+// languages. A regional language is completed from its base language (pt-BR
+// from pt); English is the final fallback. The returned tag names the
+// language the catalog was resolved for.
+func resolveCatalog(chain []string) (language.Tag, map[string]string) {
+	for _, lang := range chain {
+		if catalog := completeCatalog(lang); catalog != nil {
+			return language.Make(lang), catalog
+		}
+	}
+	return language.Make(defaultLanguage), catalogs[defaultLanguage]
+}
+
+// completeCatalog returns the catalog for lang merged over its base
+// language, or nil if lang is unknown or the result is missing strings.
+func completeCatalog(lang string) map[string]string {
+	regional, hasRegional := catalogs[lang]
+	base, hasBase := catalogs[baseLanguage(lang)]
+	if !hasRegional && !hasBase {
+		return nil
+	}
+
+	merged := make(map[string]string, len(catalogs[defaultLanguage]))
+	for key, text := range base {
+		merged[key] = text
+	}
+	for key, text := range regional {
+		merged[key] = text
+	}
+	for key := range catalogs[defaultLanguage] {
+		if _, ok := merged[key]; !ok {
+			return nil
+		}
+	}
+	return merged
+}
+
+// baseLanguage returns the language subtag of a BCP 47 tag ("pt" for "pt-BR").
+func baseLanguage(lang string) string {
+	base, _, _ := strings.Cut(lang, "-")
+	return base
+}
+
+// IsSupportedLanguage reports whether lang, or its base language, has a
+// built-in catalog.
+func IsSupportedLanguage(lang string) bool {
+	_, ok := catalogs[lang]
+	if !ok {
+		_, ok = catalogs[baseLanguage(lang)]
+	}
+	return ok
+}
+
+// tag returns the formatter's language, defaulting to English.
+func (f formatter) tag() language.Tag {
+	if f.lang == language.Und {
+		return language.Make(defaultLanguage)
+	}
+	return f.lang
+}
+
+// t formats the catalog message for key. Numbers are formatted for the
+// formatter's language, so counts get locale thousand separators.
+func (f formatter) t(key string, args ...any) string {
+	catalog := f.catalog
+	if catalog == nil {
+		catalog = catalogs[defaultLanguage]
+	}
+	if len(args) == 0 {
+		return catalog[key]
+	}
+	return message.NewPrinter(f.tag()).Sprintf(catalog[key], args...)
+}
+
+// title capitalizes s using the formatter's language rules.
+func (f formatter) title(s string) string {
+	return cases.Title(f.tag()).String(s)
+}
@@ -0,0 +1,115 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestCatalogsComplete(t *testing.T) {
+	for lang := range catalogs {
+		if completeCatalog(lang) == nil {
+			t.Errorf("catalog %q is missing strings", lang)
+		}
+	}
+}
+
+func TestResolveCatalog(t *testing.T) {
+	tests := []struct {
+		name     string
+		chain    []string
+		expected string // rendered msgChanges
+	}{
+		{"default", nil, "Changes"},
+		{"single", []string{"de"}, "Änderungen"},
+		{"regional overrides base", []string{"pt-BR", "pt", "en"}, "Mudanças"},
+		{"regional without catalog uses base", []string{"pt-PT", "en"}, "Alterações"},
+		{"unknown skipped", []string{"xx", "fr"}, "Changements"},
+		{"all unknown", []string{"xx", "yy"}, "Changes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, catalog := resolveCatalog(tt.chain)
+			f := formatter{catalog: catalog}
+			if got := f.t(msgChanges); got != tt.expected {
+				t.Errorf("t(changes) = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveCatalogNeverMixes(t *testing.T) {
+	catalogs["zz"] = map[string]string{msgChanges: "Zmiany"}
+	defer delete(catalogs, "zz")
+
+	_, catalog := resolveCatalog([]string{"zz", "de"})
+	f := formatter{catalog: catalog}
+	if got := f.t(msgChanges); got != "Änderungen" {
+		t.Errorf("t(changes) = %q, want the complete de catalog", got)
+	}
+}
+
+func TestRendererLanguage(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version: "1.0.0",
+		Branch:  "main",
+		Changes: &plugin.CategorizedChanges{
+			Features: []plugin.ConventionalCommit{{Description: "x"}},
+		},
+	}
+
+	r := New(Options{
+		Language: []string{"pt-BR", "pt", "en"},
+		Sections: []Section{{Name: SectionHeader}, {Name: SectionChanges}},
+	})
+	want := "🚀 Versão 1.0.0 publicada!\n\n\nMudanças:\n• 1 funcionalidades\n• 0 correções\n"
+	if got := r.Success(releaseCtx); got != want {
+		t.Errorf("Success() = %q, want %q", got, want)
+	}
+
+	r = New(Options{Language: []string{"de"}})
+	want = "❌ Release 1.0.0 fehlgeschlagen\n\n📦 Version: 1.0.0\n🌿 Branch: main\n\nDetails stehen in den CI-Logs."
+	if got := r.Error(releaseCtx); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestIsSupportedLanguage(t *testing.T) {
+	for lang, want := range map[string]bool{"en": true, "pt-BR": true, "de-AT": true, "xx": false, "": false} {
+		if got := IsSupportedLanguage(lang); got != want {
+			t.Errorf("IsSupportedLanguage(%q) = %v, want %v", lang, got, want)
+		}
+	}
+}
+
+func TestLocalizedNumbers(t *testing.T) {
+	tests := []struct {
+		lang     string
+		expected string
+	}{
+		{"en", "1,234 features"},
+		{"de", "1.234 Features"},
+		{"fr", "1 234 fonctionnalités"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			f := newFormatter(&Options{Language: []string{tt.lang}})
+			if got := f.t(msgFeatures, 1234); got != tt.expected {
+				t.Errorf("t(features) = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatterTitle(t *testing.T) {
+	f := newFormatter(&Options{Language: []string{"de"}})
+	if got := f.title("minor"); got != "Minor" {
+		t.Errorf("title() = %q, want %q", got, "Minor")
+	}
+	f = formatter{}
+	if got := f.title("major"); got != "Major" {
+		t.Errorf("title() = %q, want %q", got, "Major")
+	}
+}
@@ -0,0 +1,173 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// EscapeMarkdownV2 escapes special characters for Telegram MarkdownV2.
+func EscapeMarkdownV2(text string) string {
+	// Characters that need escaping in MarkdownV2
+	specialChars := []string{"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!"}
+
+	result := text
+	for _, char := range specialChars {
+		result = strings.ReplaceAll(result, char, "\\"+char)
+	}
+	return result
+}
+
+// sectionLink renders text as a link to the release page anchor for heading.
+// An empty heading links to the release page itself. Without a release URL,
+// or in plain text mode, the text is returned escaped for the parse mode.
+func sectionLink(opts *Options, text, heading string) string {
+	url := opts.ReleaseURL
+	if url != "" && heading != "" {
+		url += "#" + githubAnchor(heading)
+	}
+
+	switch opts.ParseMode {
+	case "MarkdownV2":
+		if url == "" {
+			return EscapeMarkdownV2(text)
+		}
+		return fmt.Sprintf("[%s](%s)", EscapeMarkdownV2(text), escapeMarkdownV2URL(url))
+	case "HTML":
+		if url == "" {
+			return html.EscapeString(text)
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), html.EscapeString(text))
+	default:
+		return text
+	}
+}
+
+// markdownHeadingPattern matches ATX-style markdown headings.
+var markdownHeadingPattern = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+
+// formatReleaseNotes escapes release notes for the parse mode. When a release
+// URL is configured, markdown headings are rendered as bold links to their
+// anchors on the release page.
+func formatReleaseNotes(opts *Options, notes string) string {
+	escape := func(s string) string { return s }
+	switch opts.ParseMode {
+	case "MarkdownV2":
+		escape = EscapeMarkdownV2
+	case "HTML":
+		escape = html.EscapeString
+	}
+
+	if opts.ReleaseURL == "" || opts.ParseMode == "" {
+		return escape(notes)
+	}
+
+	slugger := newAnchorSlugger()
+	lines := strings.Split(notes, "\n")
+	for i, line := range lines {
+		m := markdownHeadingPattern.FindStringSubmatch(line)
+		if m == nil {
+			lines[i] = escape(line)
+			continue
+		}
+		url := opts.ReleaseURL + "#" + slugger.slug(m[1])
+		if opts.ParseMode == "HTML" {
+			lines[i] = fmt.Sprintf(`<b><a href="%s">%s</a></b>`, html.EscapeString(url), html.EscapeString(m[1]))
+		} else {
+			lines[i] = fmt.Sprintf("*[%s](%s)*", EscapeMarkdownV2(m[1]), escapeMarkdownV2URL(url))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escapeMarkdownV2URL escapes a URL for use inside a MarkdownV2 inline link.
+func escapeMarkdownV2URL(url string) string {
+	url = strings.ReplaceAll(url, "\\", "\\\\")
+	return strings.ReplaceAll(url, ")", "\\)")
+}
+
+// anchorSlugger generates heading anchors the way GitHub does, including the
+// numeric suffixes added to repeated headings.
+type anchorSlugger struct {
+	seen map[string]int
+}
+
+// newAnchorSlugger creates an anchorSlugger with no headings seen.
+func newAnchorSlugger() *anchorSlugger {
+	return &anchorSlugger{seen: make(map[string]int)}
+}
+
+// slug returns the unique anchor for heading.
+func (s *anchorSlugger) slug(heading string) string {
+	base := githubAnchor(heading)
+	n := s.seen[base]
+	s.seen[base] = n + 1
+	if n == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s-%d", base, n)
+}
+
+// githubAnchor slugifies a heading using GitHub's anchor algorithm: the text
+// is lowercased, punctuation is dropped, and spaces become hyphens.
+func githubAnchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// ExcludeLines removes the lines of notes that match any of patterns.
+func ExcludeLines(notes string, patterns []*regexp.Regexp) string {
+	if len(patterns) == 0 || notes == "" {
+		return notes
+	}
+
+	lines := strings.Split(notes, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !matchesAny(line, patterns) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// NormalizeWhitespace removes trailing spaces and tabs from each line of s,
+// collapses runs of blank lines into one, and drops leading and trailing
+// blank lines.
+func NormalizeWhitespace(s string) string {
+	var kept []string
+	blank := false
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			blank = len(kept) > 0
+			continue
+		}
+		if blank {
+			kept = append(kept, "")
+			blank = false
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// matchesAny reports whether line matches any of patterns.
+func matchesAny(line string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
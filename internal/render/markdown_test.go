@@ -0,0 +1,147 @@
+package render
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestEscapeMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "simple text",
+			input:    "hello world",
+			expected: "hello world",
+		},
+		{
+			name:     "version with dots",
+			input:    "v1.2.3",
+			expected: "v1\\.2\\.3",
+		},
+		{
+			name:     "text with underscores",
+			input:    "my_variable_name",
+			expected: "my\\_variable\\_name",
+		},
+		{
+			name:     "text with asterisks",
+			input:    "*bold*",
+			expected: "\\*bold\\*",
+		},
+		{
+			name:     "text with brackets",
+			input:    "[link](url)",
+			expected: "\\[link\\]\\(url\\)",
+		},
+		{
+			name:     "complex text",
+			input:    "Release v1.0.0 - feat: add *new* feature!",
+			expected: "Release v1\\.0\\.0 \\- feat: add \\*new\\* feature\\!",
+		},
+		{
+			name:     "empty string",
+			input:    "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EscapeMarkdownV2(tt.input)
+			if result != tt.expected {
+				t.Errorf("EscapeMarkdownV2(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGithubAnchor(t *testing.T) {
+	tests := []struct {
+		heading  string
+		expected string
+	}{
+		{"Breaking Changes", "breaking-changes"},
+		{"Bug Fixes", "bug-fixes"},
+		{"v1.2.3 (2024-01-01)", "v123-2024-01-01"},
+		{"🚀 Features", "-features"},
+		{"snake_case & more!", "snake_case--more"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.heading, func(t *testing.T) {
+			if got := githubAnchor(tt.heading); got != tt.expected {
+				t.Errorf("githubAnchor(%q) = %q, want %q", tt.heading, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnchorSluggerDuplicates(t *testing.T) {
+	s := newAnchorSlugger()
+	got := []string{s.slug("Features"), s.slug("Features"), s.slug("Features")}
+	want := []string{"features", "features-1", "features-2"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("slug #%d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExcludeLines(t *testing.T) {
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`^- Revert "`),
+		regexp.MustCompile(`(?i)merge (pull request|branch)`),
+	}
+
+	tests := []struct {
+		name     string
+		notes    string
+		patterns []*regexp.Regexp
+		expected string
+	}{
+		{"no patterns", "- Revert \"x\"\n- add y", nil, "- Revert \"x\"\n- add y"},
+		{"empty notes", "", patterns, ""},
+		{
+			name:     "matching lines dropped",
+			notes:    "## Fixes\n- Revert \"add x\"\n- fix y\n- Merge pull request #12\n- fix z",
+			patterns: patterns,
+			expected: "## Fixes\n- fix y\n- fix z",
+		},
+		{"nothing matches", "- fix y", patterns, "- fix y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExcludeLines(tt.notes, tt.patterns); got != tt.expected {
+				t.Errorf("ExcludeLines() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"clean", "- fix x\n- fix y", "- fix x\n- fix y"},
+		{"trailing spaces", "- fix x  \n- fix y\t", "- fix x\n- fix y"},
+		{"blank line runs", "## Fixes\n\n\n\n- fix x\n \n\t\n- fix y", "## Fixes\n\n- fix x\n\n- fix y"},
+		{"leading and trailing blank lines", "\n\n- fix x\n\n\n", "- fix x"},
+		{"carriage returns", "- fix x \r\n\r\n\r\n- fix y\r\n", "- fix x\n\n- fix y"},
+		{"indentation kept", "- fix x\n  - detail", "- fix x\n  - detail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeWhitespace(tt.text); got != tt.expected {
+				t.Errorf("NormalizeWhitespace() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
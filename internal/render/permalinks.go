@@ -0,0 +1,43 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Permalink is a link to a delivered message.
+type Permalink struct {
+	// Kind names the message, e.g. "success" or "forward".
+	Kind string
+	// ChatID is the chat the message was delivered to.
+	ChatID string
+	// URL is the t.me link to the message.
+	URL string
+}
+
+// PermalinkGroup is the permalinks of the messages sent for one audience.
+type PermalinkGroup struct {
+	// Labels describes the audience, e.g. "audience=devs, team=core". It is
+	// empty for unlabeled deliveries.
+	Labels string
+	Links  []Permalink
+}
+
+// Permalinks renders the permalink report listing the delivered messages of
+// a release, grouped by audience.
+func (r *Renderer) Permalinks(version string, groups []PermalinkGroup) string {
+	f := newFormatter(&r.opts)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔗 %s\n", f.bold(f.escape(f.t(msgPermalinks, version)))))
+	for _, group := range groups {
+		sb.WriteString("\n")
+		if group.Labels != "" {
+			sb.WriteString(f.label(group.Labels) + "\n")
+		}
+		for _, link := range group.Links {
+			sb.WriteString(fmt.Sprintf("• %s %s\n", f.escape(link.Kind+" "+link.ChatID+":"), f.escape(link.URL)))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,38 @@
+package render
+
+import "testing"
+
+func TestPermalinks(t *testing.T) {
+	groups := []PermalinkGroup{
+		{Labels: "audience=devs", Links: []Permalink{{Kind: "success", ChatID: "@news", URL: "https://t.me/news/41"}}},
+		{Links: []Permalink{{Kind: "forward", ChatID: "@mirror", URL: "https://t.me/mirror/7"}}},
+	}
+
+	tests := []struct {
+		name     string
+		language []string
+		expected string
+	}{
+		{
+			name: "english",
+			expected: "🔗 Announcement links for 1.0.0\n\n" +
+				"audience=devs:\n• success @news: https://t.me/news/41\n\n" +
+				"• forward @mirror: https://t.me/mirror/7\n",
+		},
+		{
+			name:     "translated",
+			language: []string{"fr"},
+			expected: "🔗 Liens vers les annonces de 1.0.0\n\n" +
+				"audience=devs:\n• success @news: https://t.me/news/41\n\n" +
+				"• forward @mirror: https://t.me/mirror/7\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(Options{Language: tt.language}).Permalinks("1.0.0", groups); got != tt.expected {
+				t.Errorf("Permalinks() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,527 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+	"golang.org/x/text/language"
+)
+
+// Options holds the inputs that shape rendered messages. Rendering depends
+// only on Options and the release context, so the same inputs always
+// produce the same text.
+type Options struct {
+	// ParseMode is the Telegram parse mode: MarkdownV2, HTML, or "" for plain text.
+	ParseMode string
+	// IncludeChangelog includes the release notes in success messages.
+	IncludeChangelog bool
+	// MaxChangelogLength truncates the release notes; 0 means no limit.
+	MaxChangelogLength int
+	// ChangelogStyle is full or teaser.
+	ChangelogStyle string
+	// TeaserLines is the number of release note lines kept in teaser style.
+	TeaserLines int
+	// ChangesSummaryMode is counts, or sampled to also list the top
+	// SampleSize commits of each category under its count.
+	ChangesSummaryMode string
+	// SampleSize is the number of commits listed per category in sampled mode.
+	SampleSize int
+	// ScopePriority lists scopes sampled first, in order. Other commits are
+	// ranked by how many commits share their scope.
+	ScopePriority []string
+	// ReleaseURL links section headings to the release page.
+	ReleaseURL string
+	// BreakingFirst moves the breaking changes section to the top.
+	BreakingFirst bool
+	// Sections is the success message layout; empty uses the default layout.
+	Sections []Section
+	// HeadlineRules escalate the success headline emoji.
+	HeadlineRules []HeadlineRule
+	// Language is the fallback chain of message languages, e.g.
+	// ["pt-BR", "pt", "en"]. Empty means English.
+	Language []string
+	// CommitFormat is how commit lines are rendered: pretty (the default),
+	// raw, or template.
+	CommitFormat string
+	// CommitTemplate renders each commit line in the template commit format.
+	CommitTemplate string
+	// ShowContributors appends the contributors section to success messages
+	// that do not list it.
+	ShowContributors bool
+	// ContributorHandles maps contributor emails or names to Telegram
+	// usernames, so contributors are mentioned as @username.
+	ContributorHandles map[string]string
+	// Component names the released component or package in a monorepo. It
+	// prefixes the version in headlines.
+	Component string
+	// NormalizeWhitespace strips trailing whitespace and collapses blank
+	// line runs in the release notes and template output.
+	NormalizeWhitespace bool
+	// Duration is how long the release took, shown in the version info
+	// section when positive.
+	Duration time.Duration
+	// CompareStats are the files changed since the previous release. When
+	// set, success messages that do not list the compare stats section end
+	// with it.
+	CompareStats []FileStat
+	// Variables are the values available to templates as {{.Variables.name}}.
+	Variables map[string]string
+	// Now is the time substituted for {{.Date}} in templates.
+	Now time.Time
+}
+
+// Renderer renders notification messages without performing any I/O.
+type Renderer struct {
+	opts Options
+}
+
+// New creates a Renderer for opts.
+func New(opts Options) *Renderer {
+	return &Renderer{opts: opts}
+}
+
+// Built-in success message sections.
+const (
+	SectionHeader      = "header"
+	SectionVersionInfo = "version_info"
+	SectionChanges     = "changes"
+	SectionChangelog   = "changelog"
+	SectionFooter      = "footer"
+	SectionBreaking    = "breaking_changes"
+	// SectionContributors thanks the commit authors and co-authors.
+	SectionContributors = "contributors"
+	// SectionCompareStats lists the most changed files of CompareStats.
+	SectionCompareStats = "compare_stats"
+)
+
+// Changelog styles.
+const (
+	ChangelogStyleFull   = "full"
+	ChangelogStyleTeaser = "teaser"
+)
+
+// Changes summary modes.
+const (
+	ChangesSummaryCounts  = "counts"
+	ChangesSummarySampled = "sampled"
+)
+
+// defaultSections is the section order used when none is configured.
+var defaultSections = []Section{
+	{Name: SectionHeader},
+	{Name: SectionVersionInfo},
+	{Name: SectionChanges},
+	{Name: SectionChangelog},
+}
+
+// IsBuiltinSection reports whether name is a built-in section name.
+func IsBuiltinSection(name string) bool {
+	return builtinSections[name]
+}
+
+// builtinSections lists the section names accepted in the sections config.
+var builtinSections = map[string]bool{
+	SectionHeader:       true,
+	SectionVersionInfo:  true,
+	SectionChanges:      true,
+	SectionChangelog:    true,
+	SectionFooter:       true,
+	SectionBreaking:     true,
+	SectionContributors: true,
+	SectionCompareStats: true,
+}
+
+// Section is one entry of the success message layout: either a
+// built-in section referenced by name or a custom templated block.
+type Section struct {
+	// Name is the built-in section name.
+	Name string `json:"name,omitempty"`
+	// Template is a custom block rendered with the release context and
+	// inserted verbatim, so it must already be formatted for the parse mode.
+	Template string `json:"template,omitempty"`
+}
+
+// formatter applies parse-mode specific formatting and translations.
+type formatter struct {
+	parseMode string
+	lang      language.Tag
+	catalog   map[string]string
+}
+
+// newFormatter creates a formatter for opts.
+func newFormatter(opts *Options) formatter {
+	lang, catalog := resolveCatalog(opts.Language)
+	return formatter{parseMode: opts.ParseMode, lang: lang, catalog: catalog}
+}
+
+// escape escapes text for the parse mode.
+func (f formatter) escape(text string) string {
+	switch f.parseMode {
+	case "MarkdownV2":
+		return EscapeMarkdownV2(text)
+	case "HTML":
+		return html.EscapeString(text)
+	default:
+		return text
+	}
+}
+
+// bold wraps already formatted text in bold markup.
+func (f formatter) bold(formatted string) string {
+	switch f.parseMode {
+	case "MarkdownV2":
+		return "*" + formatted + "*"
+	case "HTML":
+		return "<b>" + formatted + "</b>"
+	default:
+		return formatted
+	}
+}
+
+// code escapes text and wraps it in inline code markup.
+func (f formatter) code(text string) string {
+	switch f.parseMode {
+	case "MarkdownV2":
+		return "`" + EscapeMarkdownV2(text) + "`"
+	case "HTML":
+		return "<code>" + html.EscapeString(text) + "</code>"
+	default:
+		return text
+	}
+}
+
+// label renders a bold "Name:" label.
+func (f formatter) label(name string) string {
+	return f.bold(f.escape(name + ":"))
+}
+
+// Success renders the success notification message.
+func (r *Renderer) Success(releaseCtx plugin.ReleaseContext) string {
+	opts := &r.opts
+	sections := opts.Sections
+	if len(sections) == 0 {
+		sections = defaultSections
+	}
+
+	if releaseCtx.Changes != nil && len(releaseCtx.Changes.Breaking) > 0 && !hasSection(sections, SectionBreaking) {
+		sections = insertBreakingSection(sections, opts.BreakingFirst)
+	}
+	if opts.ShowContributors && !hasSection(sections, SectionContributors) {
+		sections = append(slices.Clip(sections), Section{Name: SectionContributors})
+	}
+	if len(opts.CompareStats) > 0 && !hasSection(sections, SectionCompareStats) {
+		sections = append(slices.Clip(sections), Section{Name: SectionCompareStats})
+	}
+
+	var sb strings.Builder
+	for i, section := range sections {
+		text := renderSection(opts, section, releaseCtx)
+		if i == 0 && section.Name == SectionBreaking && text != "" {
+			// Leading breaking changes are separated from what follows instead.
+			text = strings.TrimPrefix(text, "\n") + "\n"
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// hasSection reports whether sections contains the named built-in section.
+func hasSection(sections []Section, name string) bool {
+	for _, section := range sections {
+		if section.Template == "" && section.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// insertBreakingSection adds the breaking changes section either at the top
+// of the message or right after the change counts.
+func insertBreakingSection(sections []Section, first bool) []Section {
+	breaking := Section{Name: SectionBreaking}
+	result := make([]Section, 0, len(sections)+1)
+	if first {
+		return append(append(result, breaking), sections...)
+	}
+
+	inserted := false
+	for _, section := range sections {
+		result = append(result, section)
+		if !inserted && section.Template == "" && section.Name == SectionChanges {
+			result = append(result, breaking)
+			inserted = true
+		}
+	}
+	if !inserted {
+		result = append(result, breaking)
+	}
+	return result
+}
+
+// renderSection renders a single success message section.
+func renderSection(opts *Options, section Section, releaseCtx plugin.ReleaseContext) string {
+	f := newFormatter(opts)
+
+	if section.Template != "" {
+		text, err := renderTemplate(opts, section.Template, releaseCtx)
+		if err != nil {
+			return ""
+		}
+		return "\n" + text + "\n"
+	}
+
+	var sb strings.Builder
+	switch section.Name {
+	case SectionHeader:
+		emoji := headlineEmoji(opts.HeadlineRules, releaseCtx.Changes)
+		sb.WriteString(fmt.Sprintf("%s %s\n\n", emoji, f.bold(f.escape(f.t(msgReleasePublished, releaseName(opts, releaseCtx.Version))))))
+
+	case SectionVersionInfo:
+		sb.WriteString(fmt.Sprintf("📦 %s %s\n", f.label(f.t(msgVersion)), f.code(releaseCtx.Version)))
+		sb.WriteString(fmt.Sprintf("📋 %s %s\n", f.label(f.t(msgType)), f.escape(f.title(releaseCtx.ReleaseType))))
+		sb.WriteString(fmt.Sprintf("🌿 %s %s\n", f.label(f.t(msgBranch)), f.code(releaseCtx.Branch)))
+		sb.WriteString(fmt.Sprintf("🏷️ %s %s\n", f.label(f.t(msgTag)), f.code(releaseCtx.TagName)))
+		if duration := humanizeReleaseDuration(opts.Duration); duration != "" {
+			sb.WriteString(fmt.Sprintf("⏱️ %s\n", f.escape(f.t(msgDuration, duration))))
+		}
+
+	case SectionChanges:
+		if releaseCtx.Changes == nil {
+			break
+		}
+		features := len(releaseCtx.Changes.Features)
+		fixes := len(releaseCtx.Changes.Fixes)
+		breaking := len(releaseCtx.Changes.Breaking)
+
+		sb.WriteString(fmt.Sprintf("\n%s\n", f.label(f.t(msgChanges))))
+		sb.WriteString(fmt.Sprintf("• %s\n", sectionLink(opts, f.t(msgFeatures, features), "Features")))
+		sb.WriteString(sampleLines(opts, f, releaseCtx.Changes.Features))
+		sb.WriteString(fmt.Sprintf("• %s\n", sectionLink(opts, f.t(msgBugFixes, fixes), "Bug Fixes")))
+		sb.WriteString(sampleLines(opts, f, releaseCtx.Changes.Fixes))
+		if breaking > 0 {
+			sb.WriteString(fmt.Sprintf("• %s\n", sectionLink(opts, f.t(msgBreakingCount, breaking), "Breaking Changes")))
+		}
+
+	case SectionBreaking:
+		if releaseCtx.Changes == nil || len(releaseCtx.Changes.Breaking) == 0 {
+			break
+		}
+		sb.WriteString(fmt.Sprintf("\n⚠️ %s\n", f.bold(sectionLink(opts, f.t(msgBreakingChanges), "Breaking Changes")+f.escape(":"))))
+		for _, commit := range releaseCtx.Changes.Breaking {
+			sb.WriteString(fmt.Sprintf("• %s\n", commitLine(opts, f, commit)))
+		}
+
+	case SectionChangelog:
+		notes, ok := changelogNotes(opts, releaseCtx)
+		if !ok {
+			break
+		}
+		notes = truncateNotes(opts.MaxChangelogLength, notes)
+		sb.WriteString(fmt.Sprintf("\n%s\n", f.bold(sectionLink(opts, f.t(msgReleaseNotes), "")+f.escape(":"))))
+		sb.WriteString(formatReleaseNotes(opts, notes))
+
+	case SectionContributors:
+		contributors := Contributors(releaseCtx.Changes, opts.ContributorHandles)
+		if len(contributors) == 0 {
+			break
+		}
+		names := make([]string, len(contributors))
+		for i, c := range contributors {
+			names[i] = c.String()
+		}
+		sb.WriteString(fmt.Sprintf("\n🙏 %s\n", f.escape(f.t(msgThanksTo, strings.Join(names, ", ")))))
+
+	case SectionCompareStats:
+		if len(opts.CompareStats) == 0 {
+			break
+		}
+		sb.WriteString(compareStatsSection(f, opts.CompareStats))
+
+	case SectionFooter:
+		if opts.ReleaseURL == "" {
+			break
+		}
+		if opts.ParseMode == "" {
+			sb.WriteString(fmt.Sprintf("\n🔗 %s: %s\n", f.t(msgReleasePage), opts.ReleaseURL))
+		} else {
+			sb.WriteString(fmt.Sprintf("\n🔗 %s\n", sectionLink(opts, f.t(msgReleasePage), "")))
+		}
+	}
+
+	return sb.String()
+}
+
+// changelogNotes returns the release notes shown by the changelog section
+// before truncation, and whether the section is shown at all.
+func changelogNotes(opts *Options, releaseCtx plugin.ReleaseContext) (string, bool) {
+	teaser := opts.ChangelogStyle == ChangelogStyleTeaser
+	if (!opts.IncludeChangelog && !teaser) || releaseCtx.ReleaseNotes == "" {
+		return "", false
+	}
+	notes := releaseCtx.ReleaseNotes
+	if opts.NormalizeWhitespace {
+		notes = NormalizeWhitespace(notes)
+	}
+	if teaser {
+		return teaserLines(notes, opts.TeaserLines), true
+	}
+	return notes, true
+}
+
+// truncateNotes shortens s to at most n UTF-16 code units, the unit
+// Telegram measures message length in, followed by "...". The cut falls on
+// a rune boundary, so the result is always valid text, and backs off to the
+// last line break when that keeps at least half of the text. A non-positive
+// n means no limit.
+func truncateNotes(n int, s string) string {
+	if n <= 0 || utf16Len(s) <= n {
+		return s
+	}
+	cut, units := 0, 0
+	for i, r := range s {
+		if units += utf16.RuneLen(r); units > n {
+			cut = i
+			break
+		}
+	}
+	kept := s[:cut]
+	if i := strings.LastIndexByte(kept, '\n'); i >= 0 && i+1 >= len(kept)/2 {
+		kept = kept[:i+1]
+	}
+	return kept + "..."
+}
+
+// utf16Len returns the length of s in UTF-16 code units. Invalid bytes count
+// as one unit, like the replacement character they decode to.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// Truncated reports whether the success message cuts the release notes
+// short at MaxChangelogLength. Teaser style shortening is not truncation.
+func (r *Renderer) Truncated(releaseCtx plugin.ReleaseContext) bool {
+	opts := &r.opts
+	sections := opts.Sections
+	if len(sections) == 0 {
+		sections = defaultSections
+	}
+	if !hasSection(sections, SectionChangelog) {
+		return false
+	}
+	notes, ok := changelogNotes(opts, releaseCtx)
+	return ok && opts.MaxChangelogLength > 0 && utf16Len(notes) > opts.MaxChangelogLength
+}
+
+// teaserLines returns the first n non-blank lines of notes, followed by an
+// ellipsis line when lines were dropped.
+func teaserLines(notes string, n int) string {
+	if n <= 0 {
+		n = 5
+	}
+
+	var kept []string
+	for _, line := range strings.Split(notes, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(kept) == n {
+			kept = append(kept, "…")
+			break
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// releaseName names a release in headlines: the version, prefixed with the
+// component in monorepos.
+func releaseName(opts *Options, version string) string {
+	if opts.Component == "" {
+		return version
+	}
+	return opts.Component + " " + version
+}
+
+// commitSubject renders the one-line subject of a commit, prefixed with its
+// scope when present.
+func commitSubject(f formatter, commit plugin.ConventionalCommit) string {
+	subject := strings.TrimSpace(strings.SplitN(commit.Description, "\n", 2)[0])
+	if commit.Scope == "" {
+		return f.escape(subject)
+	}
+	return f.bold(f.escape(commit.Scope+":")) + " " + f.escape(subject)
+}
+
+// BreakingAlert renders a message listing only the breaking changes of the
+// release, each followed by its migration notes from the commit body.
+func (r *Renderer) BreakingAlert(releaseCtx plugin.ReleaseContext) string {
+	opts := &r.opts
+	f := newFormatter(opts)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🚨 %s\n", f.bold(f.escape(f.t(msgBreakingAlert, releaseName(opts, releaseCtx.Version))))))
+	if releaseCtx.Changes != nil {
+		for _, commit := range releaseCtx.Changes.Breaking {
+			sb.WriteString(fmt.Sprintf("\n• %s\n", commitLine(opts, f, commit)))
+			if notes := migrationNotes(commit); notes != "" {
+				sb.WriteString(f.escape(notes) + "\n")
+			}
+		}
+	}
+	if opts.ReleaseURL != "" {
+		if opts.ParseMode == "" {
+			sb.WriteString(fmt.Sprintf("\n🔗 %s: %s\n", f.t(msgReleasePage), opts.ReleaseURL))
+		} else {
+			sb.WriteString(fmt.Sprintf("\n🔗 %s\n", sectionLink(opts, f.t(msgReleasePage), "Breaking Changes")))
+		}
+	}
+	return sb.String()
+}
+
+// migrationNotes returns the commit description after its subject line.
+func migrationNotes(commit plugin.ConventionalCommit) string {
+	_, body, _ := strings.Cut(commit.Description, "\n")
+	return strings.TrimSpace(body)
+}
+
+// Version renders the notification announcing the next version.
+func (r *Renderer) Version(releaseCtx plugin.ReleaseContext) string {
+	opts := &r.opts
+	f := newFormatter(opts)
+
+	headline := f.t(msgNextRelease, releaseName(opts, releaseCtx.Version))
+	if releaseCtx.ReleaseType != "" {
+		headline += fmt.Sprintf(" (%s)", releaseCtx.ReleaseType)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔖 %s\n\n", f.bold(f.escape(headline))))
+	sb.WriteString(fmt.Sprintf("🌿 %s %s\n", f.label(f.t(msgBranch)), f.code(releaseCtx.Branch)))
+	if releaseCtx.TagName != "" {
+		sb.WriteString(fmt.Sprintf("🏷️ %s %s\n", f.label(f.t(msgTag)), f.code(releaseCtx.TagName)))
+	}
+	return sb.String()
+}
+
+// Error renders the error notification message.
+func (r *Renderer) Error(releaseCtx plugin.ReleaseContext) string {
+	opts := &r.opts
+	f := newFormatter(opts)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("❌ %s\n\n", f.bold(f.escape(f.t(msgReleaseFailed, releaseName(opts, releaseCtx.Version))))))
+	sb.WriteString(fmt.Sprintf("📦 %s %s\n", f.label(f.t(msgVersion)), f.code(releaseCtx.Version)))
+	sb.WriteString(fmt.Sprintf("🌿 %s %s\n", f.label(f.t(msgBranch)), f.code(releaseCtx.Branch)))
+	sb.WriteString("\n" + f.escape(f.t(msgCheckLogs)))
+
+	return sb.String()
+}
@@ -0,0 +1,415 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestBuildSuccessSections(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version:     "1.0.0",
+		TagName:     "v1.0.0",
+		Branch:      "main",
+		ReleaseType: "minor",
+		Changes: &plugin.CategorizedChanges{
+			Features: []plugin.ConventionalCommit{{Hash: "abc123", Description: "new feature"}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		sections    []Section
+		releaseURL  string
+		expected    string
+		notContains []string
+	}{
+		{
+			name:     "reordered and dropped",
+			sections: []Section{{Name: SectionChanges}, {Name: SectionHeader}},
+			expected: "\nChanges:\n• 1 features\n• 0 bug fixes\n🚀 Release 1.0.0 Published!\n\n",
+		},
+		{
+			name:     "custom block",
+			sections: []Section{{Name: SectionHeader}, {Template: "Docs for {{.Version}}: https://docs.example.com"}},
+			expected: "🚀 Release 1.0.0 Published!\n\n\nDocs for 1.0.0: https://docs.example.com\n",
+		},
+		{
+			name:       "footer with release URL",
+			sections:   []Section{{Name: SectionFooter}},
+			releaseURL: "https://example.com/r",
+			expected:   "\n🔗 Release page: https://example.com/r\n",
+		},
+		{
+			name:     "footer without release URL",
+			sections: []Section{{Name: SectionFooter}},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(Options{Sections: tt.sections, ReleaseURL: tt.releaseURL})
+			if got := r.Success(releaseCtx); got != tt.expected {
+				t.Errorf("Success() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRendererSuccessBreakingChanges(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version: "2.0.0",
+		Changes: &plugin.CategorizedChanges{
+			Features: []plugin.ConventionalCommit{{Description: "new thing"}},
+			Breaking: []plugin.ConventionalCommit{
+				{Scope: "api", Description: "drop v1 endpoints\n\nlong body"},
+				{Description: "require Go 1.22"},
+			},
+		},
+	}
+	sections := []Section{{Name: SectionHeader}, {Name: SectionChanges}}
+
+	tests := []struct {
+		name          string
+		breakingFirst bool
+		sections      []Section
+		expected      string
+	}{
+		{
+			name:          "breaking first",
+			breakingFirst: true,
+			sections:      sections,
+			expected: "⚠️ Breaking Changes:\n• api: drop v1 endpoints\n• require Go 1.22\n\n" +
+				"🚀 Release 2.0.0 Published!\n\n" +
+				"\nChanges:\n• 1 features\n• 0 bug fixes\n• 2 breaking changes\n",
+		},
+		{
+			name:     "after changes",
+			sections: sections,
+			expected: "🚀 Release 2.0.0 Published!\n\n" +
+				"\nChanges:\n• 1 features\n• 0 bug fixes\n• 2 breaking changes\n" +
+				"\n⚠️ Breaking Changes:\n• api: drop v1 endpoints\n• require Go 1.22\n",
+		},
+		{
+			name:          "explicit position wins",
+			breakingFirst: true,
+			sections:      []Section{{Name: SectionHeader}, {Name: SectionBreaking}},
+			expected: "🚀 Release 2.0.0 Published!\n\n" +
+				"\n⚠️ Breaking Changes:\n• api: drop v1 endpoints\n• require Go 1.22\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(Options{Sections: tt.sections, BreakingFirst: tt.breakingFirst})
+			if got := r.Success(releaseCtx); got != tt.expected {
+				t.Errorf("Success() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRendererVersion(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{Version: "1.3.0", TagName: "v1.3.0", Branch: "main", ReleaseType: "minor"}
+
+	tests := []struct {
+		parseMode string
+		expected  string
+	}{
+		{"MarkdownV2", "🔖 *Next release will be 1\\.3\\.0 \\(minor\\)*\n\n🌿 *Branch:* `main`\n🏷️ *Tag:* `v1\\.3\\.0`\n"},
+		{"HTML", "🔖 <b>Next release will be 1.3.0 (minor)</b>\n\n🌿 <b>Branch:</b> <code>main</code>\n🏷️ <b>Tag:</b> <code>v1.3.0</code>\n"},
+		{"", "🔖 Next release will be 1.3.0 (minor)\n\n🌿 Branch: main\n🏷️ Tag: v1.3.0\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.parseMode, func(t *testing.T) {
+			got := New(Options{ParseMode: tt.parseMode}).Version(releaseCtx)
+			if got != tt.expected {
+				t.Errorf("Version() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRendererComponent(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{Version: "2.1.0", Branch: "main"}
+	r := New(Options{Component: "payments-service", Sections: []Section{{Name: SectionHeader}}})
+
+	tests := []struct {
+		name     string
+		render   func() string
+		expected string
+	}{
+		{"success", func() string { return r.Success(releaseCtx) }, "🚀 Release payments-service 2.1.0 Published!"},
+		{"version", func() string { return r.Version(releaseCtx) }, "🔖 Next release will be payments-service 2.1.0"},
+		{"error", func() string { return r.Error(releaseCtx) }, "❌ Release payments-service 2.1.0 Failed"},
+		{"breaking alert", func() string { return r.BreakingAlert(releaseCtx) }, "🚨 Breaking changes in payments-service 2.1.0"},
+		{"template", func() string {
+			text, err := r.Template("🚀 {{.Component}} v{{.Version}}", releaseCtx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return text
+		}, "🚀 payments-service v2.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.render(); !strings.HasPrefix(got, tt.expected) {
+				t.Errorf("got %q, want prefix %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRendererDuration(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.3", Branch: "main", TagName: "v1.2.3"}
+	sections := []Section{{Name: SectionVersionInfo}}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{"unknown", Options{Sections: sections}, ""},
+		{"english", Options{Sections: sections, Duration: 4*time.Minute + 12*time.Second}, "⏱️ Released in 4m 12s\n"},
+		{"german", Options{Sections: sections, Duration: 90 * time.Second, Language: []string{"de"}}, "⏱️ Veröffentlicht in 1m 30s\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.opts).Success(releaseCtx)
+			if tt.expected == "" && strings.Contains(got, "⏱️") {
+				t.Errorf("Success() = %q, want no duration line", got)
+			}
+			if !strings.Contains(got, tt.expected) {
+				t.Errorf("Success() = %q, want duration line %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRendererParseModes(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version:      "1.2.0",
+		TagName:      "v1.2.0",
+		Branch:       "main",
+		ReleaseType:  "minor",
+		ReleaseNotes: "## Features\n- add x_y",
+		Changes: &plugin.CategorizedChanges{
+			Features: []plugin.ConventionalCommit{{Description: "add x_y"}},
+		},
+	}
+
+	tests := []struct {
+		parseMode string
+		success   string
+		error     string
+	}{
+		{
+			parseMode: "MarkdownV2",
+			success: "🚀 *Release 1\\.2\\.0 Published\\!*\n\n" +
+				"📦 *Version:* `1\\.2\\.0`\n📋 *Type:* Minor\n🌿 *Branch:* `main`\n🏷️ *Tag:* `v1\\.2\\.0`\n" +
+				"\n*Changes:*\n• 1 features\n• 0 bug fixes\n" +
+				"\n*Release Notes:*\n\\#\\# Features\n\\- add x\\_y",
+			error: "❌ *Release 1\\.2\\.0 Failed*\n\n📦 *Version:* `1\\.2\\.0`\n🌿 *Branch:* `main`\n\n" +
+				"Please check the CI logs for details\\.",
+		},
+		{
+			parseMode: "HTML",
+			success: "🚀 <b>Release 1.2.0 Published!</b>\n\n" +
+				"📦 <b>Version:</b> <code>1.2.0</code>\n📋 <b>Type:</b> Minor\n🌿 <b>Branch:</b> <code>main</code>\n🏷️ <b>Tag:</b> <code>v1.2.0</code>\n" +
+				"\n<b>Changes:</b>\n• 1 features\n• 0 bug fixes\n" +
+				"\n<b>Release Notes:</b>\n## Features\n- add x_y",
+			error: "❌ <b>Release 1.2.0 Failed</b>\n\n📦 <b>Version:</b> <code>1.2.0</code>\n🌿 <b>Branch:</b> <code>main</code>\n\n" +
+				"Please check the CI logs for details.",
+		},
+		{
+			parseMode: "",
+			success: "🚀 Release 1.2.0 Published!\n\n" +
+				"📦 Version: 1.2.0\n📋 Type: Minor\n🌿 Branch: main\n🏷️ Tag: v1.2.0\n" +
+				"\nChanges:\n• 1 features\n• 0 bug fixes\n" +
+				"\nRelease Notes:\n## Features\n- add x_y",
+			error: "❌ Release 1.2.0 Failed\n\n📦 Version: 1.2.0\n🌿 Branch: main\n\n" +
+				"Please check the CI logs for details.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.parseMode, func(t *testing.T) {
+			r := New(Options{ParseMode: tt.parseMode, IncludeChangelog: true})
+			if got := r.Success(releaseCtx); got != tt.success {
+				t.Errorf("Success() = %q, want %q", got, tt.success)
+			}
+			if got := r.Error(releaseCtx); got != tt.error {
+				t.Errorf("Error() = %q, want %q", got, tt.error)
+			}
+		})
+	}
+}
+
+func TestRendererDeterministicTemplateSection(t *testing.T) {
+	opts := Options{
+		Sections: []Section{{Template: "Released {{.Version}} on {{.Date}}"}},
+		Now:      time.Date(2024, 3, 9, 23, 59, 0, 0, time.UTC),
+	}
+	releaseCtx := plugin.ReleaseContext{Version: "1.0.0"}
+
+	want := "\nReleased 1.0.0 on 2024-03-09\n"
+	for i := 0; i < 2; i++ {
+		if got := New(opts).Success(releaseCtx); got != want {
+			t.Errorf("Success() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	tests := []struct {
+		parseMode string
+		label     string
+		code      string
+	}{
+		{"MarkdownV2", "*Tag:*", "`v1\\.0`"},
+		{"HTML", "<b>Tag:</b>", "<code>v1.0</code>"},
+		{"", "Tag:", "v1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.parseMode, func(t *testing.T) {
+			f := formatter{parseMode: tt.parseMode}
+			if got := f.label("Tag"); got != tt.label {
+				t.Errorf("label() = %q, want %q", got, tt.label)
+			}
+			if got := f.code("v1.0"); got != tt.code {
+				t.Errorf("code() = %q, want %q", got, tt.code)
+			}
+		})
+	}
+}
+
+func TestCommitSubjectMarkdownV2(t *testing.T) {
+	f := formatter{parseMode: "MarkdownV2"}
+	got := commitSubject(f, plugin.ConventionalCommit{Scope: "core", Description: "rename foo.bar"})
+	want := "*core:* rename foo\\.bar"
+	if got != want {
+		t.Errorf("commitSubject() = %q, want %q", got, want)
+	}
+}
+
+func TestTeaserLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		notes    string
+		n        int
+		expected string
+	}{
+		{"fewer lines", "a\n\nb", 5, "a\nb"},
+		{"truncated", "a\nb\n\nc\nd", 2, "a\nb\n…"},
+		{"exact", "a\nb\n\n", 2, "a\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := teaserLines(tt.notes, tt.n); got != tt.expected {
+				t.Errorf("teaserLines() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRendererBreakingAlert(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version: "2.0.0",
+		Changes: &plugin.CategorizedChanges{
+			Breaking: []plugin.ConventionalCommit{
+				{Scope: "api", Description: "drop v1 endpoints\n\nMigrate to /v2."},
+				{Description: "require Go 1.22"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{
+			name: "plain",
+			expected: "🚨 Breaking changes in 2.0.0\n" +
+				"\n• api: drop v1 endpoints\nMigrate to /v2.\n" +
+				"\n• require Go 1.22\n",
+		},
+		{
+			name: "MarkdownV2 with release URL",
+			opts: Options{ParseMode: "MarkdownV2", ReleaseURL: "https://example.com/r"},
+			expected: "🚨 *Breaking changes in 2\\.0\\.0*\n" +
+				"\n• *api:* drop v1 endpoints\nMigrate to /v2\\.\n" +
+				"\n• require Go 1\\.22\n" +
+				"\n🔗 [Release page](https://example.com/r#breaking-changes)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.opts).BreakingAlert(releaseCtx); got != tt.expected {
+				t.Errorf("BreakingAlert() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTruncateNotes(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		notes    string
+		expected string
+	}{
+		{"unlimited", 0, "abcdef", "abcdef"},
+		{"fits", 6, "abcdef", "abcdef"},
+		{"cut", 3, "abcdef", "abc..."},
+		{"multi-byte runes count once", 6, "café ☕", "café ☕"},
+		{"multi-byte rune", 4, "café ☕", "café..."},
+		{"surrogate pair", 3, "😀😀", "😀..."},
+		{"surrogate pair boundary", 2, "a😀", "a..."},
+		{"line boundary", 12, "- one\n- two\n- three", "- one\n- two\n..."},
+		{"line break too early", 12, "a\n- two is longer", "a\n- two is l..."},
+		{"invalid UTF-8", 2, "ab\xffcd", "ab..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateNotes(tt.n, tt.notes); got != tt.expected {
+				t.Errorf("truncateNotes(%d) = %q, want %q", tt.n, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRendererTruncated(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{ReleaseNotes: "a\nb\nc\nd"}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected bool
+	}{
+		{"changelog excluded", Options{MaxChangelogLength: 3}, false},
+		{"fits", Options{IncludeChangelog: true, MaxChangelogLength: 100}, false},
+		{"unlimited", Options{IncludeChangelog: true}, false},
+		{"truncated", Options{IncludeChangelog: true, MaxChangelogLength: 3}, true},
+		{"section not shown", Options{IncludeChangelog: true, MaxChangelogLength: 3, Sections: []Section{{Name: SectionHeader}}}, false},
+		{"teaser fits", Options{ChangelogStyle: ChangelogStyleTeaser, TeaserLines: 1, MaxChangelogLength: 10}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.opts).Truncated(releaseCtx); got != tt.expected {
+				t.Errorf("Truncated() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// defaultSampleSize is the number of commits listed per category when
+// SampleSize is not set.
+const defaultSampleSize = 5
+
+// sampleLines renders the sampled commits of a category in sampled mode,
+// followed by a count of the commits left out. It renders nothing in counts
+// mode.
+func sampleLines(opts *Options, f formatter, commits []plugin.ConventionalCommit) string {
+	if opts.ChangesSummaryMode != ChangesSummarySampled || len(commits) == 0 {
+		return ""
+	}
+	n := opts.SampleSize
+	if n <= 0 {
+		n = defaultSampleSize
+	}
+
+	var sb strings.Builder
+	sample := sampleCommits(commits, n, opts.ScopePriority)
+	for _, commit := range sample {
+		sb.WriteString(fmt.Sprintf("  ◦ %s\n", commitLine(opts, f, commit)))
+	}
+	if rest := len(commits) - len(sample); rest > 0 {
+		sb.WriteString(fmt.Sprintf("  ◦ %s\n", f.escape(f.t(msgMore, rest))))
+	}
+	return sb.String()
+}
+
+// sampleCommits returns up to n commits, preferring the scopes in priority
+// in order, then the scopes shared by the most commits. Unscoped commits
+// come last, and commits of equal rank keep their original order.
+func sampleCommits(commits []plugin.ConventionalCommit, n int, priority []string) []plugin.ConventionalCommit {
+	frequency := make(map[string]int)
+	for _, commit := range commits {
+		frequency[commit.Scope]++
+	}
+
+	rank := func(commit plugin.ConventionalCommit) (int, int) {
+		if i := slices.Index(priority, commit.Scope); i >= 0 && commit.Scope != "" {
+			return 0, i
+		}
+		if commit.Scope == "" {
+			return 2, 0
+		}
+		return 1, -frequency[commit.Scope]
+	}
+
+	sorted := slices.Clone(commits)
+	slices.SortStableFunc(sorted, func(a, b plugin.ConventionalCommit) int {
+		ag, ak := rank(a)
+		bg, bk := rank(b)
+		if ag != bg {
+			return ag - bg
+		}
+		if ak != bk {
+			return ak - bk
+		}
+		return strings.Compare(a.Scope, b.Scope)
+	})
+	return sorted[:min(n, len(sorted))]
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestSampleCommits(t *testing.T) {
+	commits := []plugin.ConventionalCommit{
+		{Scope: "", Description: "unscoped"},
+		{Scope: "cli", Description: "cli 1"},
+		{Scope: "api", Description: "api 1"},
+		{Scope: "docs", Description: "docs 1"},
+		{Scope: "api", Description: "api 2"},
+		{Scope: "cli", Description: "cli 2"},
+		{Scope: "api", Description: "api 3"},
+	}
+
+	tests := []struct {
+		name     string
+		n        int
+		priority []string
+		expected []string
+	}{
+		{"by scope frequency", 4, nil, []string{"api 1", "api 2", "api 3", "cli 1"}},
+		{"priority first", 3, []string{"docs", "cli"}, []string{"docs 1", "cli 1", "cli 2"}},
+		{"unscoped last", 7, nil, []string{"api 1", "api 2", "api 3", "cli 1", "cli 2", "docs 1", "unscoped"}},
+		{"fewer than n", 10, nil, []string{"api 1", "api 2", "api 3", "cli 1", "cli 2", "docs 1", "unscoped"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, commit := range sampleCommits(commits, tt.n, tt.priority) {
+				got = append(got, commit.Description)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("sampleCommits() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSuccessSampledChanges(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version: "2.0.0",
+		Changes: &plugin.CategorizedChanges{
+			Features: []plugin.ConventionalCommit{
+				{Scope: "api", Description: "add search"},
+				{Scope: "cli", Description: "add init"},
+				{Scope: "api", Description: "add filters"},
+			},
+			Fixes: []plugin.ConventionalCommit{{Description: "fix crash"}},
+		},
+	}
+
+	r := New(Options{
+		ChangesSummaryMode: ChangesSummarySampled,
+		SampleSize:         2,
+		Sections:           []Section{{Name: SectionChanges}},
+	})
+	want := "\nChanges:\n" +
+		"• 3 features\n" +
+		"  ◦ api: add search\n" +
+		"  ◦ api: add filters\n" +
+		"  ◦ …and 1 more\n" +
+		"• 1 bug fixes\n" +
+		"  ◦ fix crash\n"
+	if got := r.Success(releaseCtx); got != want {
+		t.Errorf("Success() = %q, want %q", got, want)
+	}
+
+	r = New(Options{Sections: []Section{{Name: SectionChanges}}})
+	if got := r.Success(releaseCtx); got != "\nChanges:\n• 3 features\n• 1 bug fixes\n" {
+		t.Errorf("Success() in counts mode = %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// TemplateError is a template syntax error located in the template text.
+type TemplateError struct {
+	// Line is the 1-based line of the error.
+	Line int
+	// Column is the 1-based column of the error, or 0 when only the line is
+	// known, e.g. for a template that ends inside an action.
+	Column int
+	// Message describes the error.
+	Message string
+}
+
+// Error implements error.
+func (e *TemplateError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+var (
+	// parseErrorPattern matches the errors of text/template's parser, e.g.
+	// `template: message:2: function "foo" not defined`.
+	parseErrorPattern = regexp.MustCompile(`^template: [^:]*:(\d+): (.*)$`)
+	// offendingTokenPattern extracts the token a parse error names:
+	// "quoted", <operand>, {{keyword}}, or "for keyword".
+	offendingTokenPattern = regexp.MustCompile(`"([^"]+)"|<([^>]+)>|(\{\{[^}]+\}\})|for (\w+)$`)
+)
+
+// ParseTemplate checks a template for syntax errors and unknown functions
+// without rendering it. Errors are returned as *TemplateError.
+func ParseTemplate(templateStr string) error {
+	_, err := template.New("message").
+		Funcs(templateFuncs(&Options{})).
+		Parse(templateStr)
+	if err == nil {
+		return nil
+	}
+
+	m := parseErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	return &TemplateError{
+		Line:    line,
+		Column:  errorColumn(templateStr, line, m[2]),
+		Message: m[2],
+	}
+}
+
+// errorColumn locates a parse error within its line: at the token the
+// message names, or at the last action opened on the line for unclosed
+// actions. It returns 0 when the position cannot be determined.
+func errorColumn(templateStr string, line int, message string) int {
+	lines := strings.Split(templateStr, "\n")
+	if line < 1 || line > len(lines) {
+		return 0
+	}
+	text := lines[line-1]
+
+	index := -1
+	if strings.HasPrefix(message, "unclosed action") {
+		index = strings.LastIndex(text, "{{")
+	} else if m := offendingTokenPattern.FindStringSubmatch(message); m != nil {
+		for _, token := range m[1:] {
+			if token != "" {
+				index = strings.Index(text, token)
+				break
+			}
+		}
+	}
+	if index < 0 {
+		return 0
+	}
+	return utf8.RuneCountInString(text[:index]) + 1
+}
@@ -0,0 +1,42 @@
+package render
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string // error message, empty for a valid template
+	}{
+		{name: "valid", template: "🚀 {{escape .Version}}\n{{range .Changes.Features}}• {{.Description}}\n{{end}}"},
+		{name: "unknown field parses", template: "{{.Missing}}"},
+		{name: "unknown function", template: "Release\n🚀 {{versoin .Version}}", expected: `line 2, column 5: function "versoin" not defined`},
+		{name: "unclosed action", template: "a\nb {{.Version", expected: "line 2, column 3: unclosed action"},
+		{name: "unexpected end", template: "{{.Version}}\n{{end}}", expected: "line 2, column 1: unexpected {{end}}"},
+		{name: "bad operand", template: `{{printf "%s" }`, expected: `line 1, column 15: unexpected "}" in operand`},
+		{name: "missing range value", template: "x\n  {{range}}{{end}}", expected: "line 2, column 5: missing value for range"},
+		{name: "missing end", template: "{{if .Version}}\nyes", expected: "line 2: unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseTemplate(tt.template)
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("ParseTemplate() error = %v, want nil", err)
+				}
+				return
+			}
+			var templateErr *TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("ParseTemplate() error = %v, want a *TemplateError", err)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("ParseTemplate() error = %q, want %q", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package render
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// templateData is the value templates are executed against. The release
+// context is embedded so its fields, including the categorized changes, are
+// available as {{.Version}}, {{.Changes.Features}}, and so on.
+type templateData struct {
+	plugin.ReleaseContext
+	// Date is the Now option formatted as 2006-01-02.
+	Date string
+	// Variables are the configured template variables.
+	Variables map[string]string
+	// Contributors are the authors and co-authors of the release's commits.
+	Contributors []Contributor
+	// Component is the Component option.
+	Component string
+	// ReleaseNotesTruncated is ReleaseNotes cut at MaxChangelogLength like
+	// the changelog section of the default message.
+	ReleaseNotesTruncated string
+	// Duration is the Duration option humanized, such as "4m 12s", or ""
+	// when it is unknown.
+	Duration string
+}
+
+// Template renders a message template with the release context and the
+// configured variables. The {{.Date}} placeholder is replaced with the
+// Now option.
+func (r *Renderer) Template(templateStr string, releaseCtx plugin.ReleaseContext) (string, error) {
+	return renderTemplate(&r.opts, templateStr, releaseCtx)
+}
+
+// renderTemplate renders templateStr for opts with text/template. Unknown
+// variables and functions are errors rather than empty output.
+func renderTemplate(opts *Options, templateStr string, releaseCtx plugin.ReleaseContext) (string, error) {
+	tmpl, err := template.New("message").
+		Option("missingkey=error").
+		Funcs(templateFuncs(opts)).
+		Parse(templateStr)
+	if err != nil {
+		return "", err
+	}
+
+	// Templates ranging over changes should not fail on releases without
+	// categorized changes.
+	if releaseCtx.Changes == nil {
+		releaseCtx.Changes = &plugin.CategorizedChanges{}
+	}
+	if opts.NormalizeWhitespace {
+		releaseCtx.ReleaseNotes = NormalizeWhitespace(releaseCtx.ReleaseNotes)
+	}
+	data := templateData{
+		ReleaseContext:        releaseCtx,
+		Date:                  opts.Now.Format("2006-01-02"),
+		Variables:             opts.Variables,
+		Contributors:          Contributors(releaseCtx.Changes, opts.ContributorHandles),
+		Component:             opts.Component,
+		ReleaseNotesTruncated: truncateNotes(opts.MaxChangelogLength, releaseCtx.ReleaseNotes),
+		Duration:              humanizeReleaseDuration(opts.Duration),
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	if opts.NormalizeWhitespace {
+		return NormalizeWhitespace(b.String()), nil
+	}
+	return b.String(), nil
+}
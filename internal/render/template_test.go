@@ -0,0 +1,287 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestTemplate(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version:      "1.2.3",
+		TagName:      "v1.2.3",
+		Branch:       "main",
+		ReleaseType:  "minor",
+		ReleaseNotes: "Bug fixes and improvements",
+	}
+
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		template string
+		contains []string
+	}{
+		{
+			name:     "version placeholder",
+			template: "Release {{.Version}}",
+			contains: []string{"Release 1.2.3"},
+		},
+		{
+			name:     "multiple placeholders",
+			template: "{{.Version}} on {{.Branch}}",
+			contains: []string{"1.2.3 on main"},
+		},
+		{
+			name:     "tag name",
+			template: "Tag: {{.TagName}}",
+			contains: []string{"Tag: v1.2.3"},
+		},
+		{
+			name:     "date",
+			template: "Released {{.Date}}",
+			contains: []string{"Released 2024-03-09"},
+		},
+		{
+			name:     "release notes",
+			template: "Notes: {{.ReleaseNotes}}",
+			contains: []string{"Notes: Bug fixes and improvements"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := New(Options{Now: now}).Template(tt.template, releaseCtx)
+			if err != nil {
+				t.Fatalf("Template() error = %v", err)
+			}
+			for _, c := range tt.contains {
+				if !strings.Contains(result, c) {
+					t.Errorf("Template() = %q, want to contain %q", result, c)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateReleaseNotesTruncated(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{ReleaseNotes: "- Fixed the crash on startup"}
+	template := "{{.ReleaseNotesTruncated}}|{{.ReleaseNotes}}"
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{"no limit", Options{}, "- Fixed the crash on startup|- Fixed the crash on startup"},
+		{"fits", Options{MaxChangelogLength: 100}, "- Fixed the crash on startup|- Fixed the crash on startup"},
+		{"truncated", Options{MaxChangelogLength: 9}, "- Fixed t...|- Fixed the crash on startup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.opts).Template(template, releaseCtx)
+			if err != nil {
+				t.Fatalf("Template() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Template() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplateDuration(t *testing.T) {
+	template := "{{with .Duration}}from tag to publish in {{.}}{{else}}-{{end}}"
+	for _, tt := range []struct {
+		duration time.Duration
+		expected string
+	}{
+		{0, "-"},
+		{4*time.Minute + 12*time.Second, "from tag to publish in 4m 12s"},
+	} {
+		got, err := New(Options{Duration: tt.duration}).Template(template, plugin.ReleaseContext{})
+		if err != nil {
+			t.Fatalf("Template() error = %v", err)
+		}
+		if got != tt.expected {
+			t.Errorf("Template() with %v = %q, want %q", tt.duration, got, tt.expected)
+		}
+	}
+}
+
+func TestTemplateNormalizeWhitespace(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.3", ReleaseNotes: "- fix x  \n\n\n- fix y\n\n"}
+	template := "🚀 {{.Version}}   \n\n\n{{.ReleaseNotes}}\n\n{{if .Changes.Breaking}}breaking{{end}}\n"
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{"off", Options{}, "🚀 1.2.3   \n\n\n- fix x  \n\n\n- fix y\n\n\n\n\n"},
+		{"on", Options{NormalizeWhitespace: true}, "🚀 1.2.3\n\n- fix x\n\n- fix y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.opts).Template(template, releaseCtx)
+			if err != nil {
+				t.Fatalf("Template() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Template() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplateVariablesAndFunctions(t *testing.T) {
+	r := New(Options{Variables: map[string]string{
+		"build_seconds": "151",
+		"artifact_size": "14200000",
+		"runner":        "ubuntu-latest",
+	}})
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.3"}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "variable",
+			template: "{{.Version}} built on {{.Variables.runner}}",
+			expected: "1.2.3 built on ubuntu-latest",
+		},
+		{
+			name:     "helpers on variables",
+			template: "Built in {{humanizeDuration .Variables.build_seconds}}, {{ humanizeBytes .Variables.artifact_size }}",
+			expected: "Built in 2m 31s, 14.2 MB",
+		},
+		{
+			name:     "helpers on literals",
+			template: `{{humanizeDuration "90s"}} / {{humanizeBytes 2048}}`,
+			expected: "1m 30s / 2.0 kB",
+		},
+		{name: "unknown variable", template: "{{.Variables.missing}}", wantErr: true},
+		{name: "unknown function", template: "{{shout .Variables.runner}}", wantErr: true},
+		{name: "invalid argument", template: "{{humanizeBytes .Variables.runner}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Template(tt.template, releaseCtx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Template() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("Template() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTemplateControlFlow(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{
+		Version: "2.0.0",
+		Changes: &plugin.CategorizedChanges{
+			Features: []plugin.ConventionalCommit{
+				{Scope: "api", Description: "add search"},
+				{Description: "faster builds"},
+			},
+			Breaking: []plugin.ConventionalCommit{{Description: "drop v1"}},
+		},
+	}
+	authorsCtx := plugin.ReleaseContext{
+		Changes: &plugin.CategorizedChanges{
+			Fixes: []plugin.ConventionalCommit{
+				{Author: "Alice <alice@example.com>", Body: "Co-authored-by: Bob <bob@example.com>"},
+			},
+		},
+	}
+	fixesCtx := plugin.ReleaseContext{
+		Changes: &plugin.CategorizedChanges{
+			Fixes: []plugin.ConventionalCommit{
+				{Hash: "a1b2c3d4e5f6", Type: "fix", Description: "handle empty notes"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		parseMode  string
+		template   string
+		releaseCtx plugin.ReleaseContext
+		expected   string
+		wantErr    bool
+	}{
+		{
+			name:       "conditional",
+			template:   "{{.Version}}{{if .Changes.Breaking}} (breaking){{end}}",
+			releaseCtx: releaseCtx,
+			expected:   "2.0.0 (breaking)",
+		},
+		{
+			name:       "range over commits",
+			template:   "{{range .Changes.Features}}- {{with .Scope}}{{.}}: {{end}}{{.Description}}\n{{end}}",
+			releaseCtx: releaseCtx,
+			expected:   "- api: add search\n- faster builds\n",
+		},
+		{
+			name:       "commit hash and type",
+			template:   "{{range .Changes.Fixes}}{{trunc 7 .Hash}} {{.Type}}: {{.Description}}{{end}}",
+			releaseCtx: fixesCtx,
+			expected:   "a1b2c3d fix: handle empty notes",
+		},
+		{
+			name: "grouped changelog",
+			template: "{{with .Changes.Features}}Features:{{range .}} {{.Description}};{{end}}{{end}}" +
+				"{{with .Changes.Fixes}} Fixes:{{range .}} {{.Description}};{{end}}{{end}}",
+			releaseCtx: fixesCtx,
+			expected:   " Fixes: handle empty notes;",
+		},
+		{
+			name:       "contributors",
+			template:   "Thanks to {{join \", \" .Contributors}}{{range .Contributors}} {{.Email}}{{end}}",
+			releaseCtx: authorsCtx,
+			expected:   "Thanks to Alice, Bob alice@example.com bob@example.com",
+		},
+		{
+			name:       "nested fields",
+			template:   "{{(index .Changes.Features 0).Scope}} {{len .Changes.Features}}",
+			releaseCtx: releaseCtx,
+			expected:   "api 2",
+		},
+		{
+			name:       "range without changes",
+			template:   "{{.Version}}{{range .Changes.Features}} {{.Description}}{{end}}",
+			releaseCtx: plugin.ReleaseContext{Version: "1.0.0"},
+			expected:   "1.0.0",
+		},
+		{
+			name:       "escape",
+			parseMode:  "MarkdownV2",
+			template:   "*{{escape .Version}}*",
+			releaseCtx: releaseCtx,
+			expected:   "*2\\.0\\.0*",
+		},
+		{name: "parse error", template: "{{if .Version}}", wantErr: true},
+		{name: "unknown field", template: "{{.Missing}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(Options{ParseMode: tt.parseMode}).Template(tt.template, tt.releaseCtx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Template() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("Template() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// envReferencePattern matches ${NAME} references and the $${ escape, which
+// stands for a literal ${.
+var envReferencePattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// missingEnv is an unset environment variable referenced by a config value.
+type missingEnv struct {
+	// Key is the top-level config key holding the reference.
+	Key string
+	// Name is the environment variable name.
+	Name string
+}
+
+// interpolateEnv returns a copy of config with ${NAME} references in string
+// values, including values nested in lists and objects, replaced by the
+// environment variable. Unset variables expand to "" and are returned so
+// strict_env can reject them. The profiles map is left as is: the selected
+// profile is applied first, and the others are not part of the config.
+func interpolateEnv(config map[string]any) (map[string]any, []missingEnv) {
+	if config == nil {
+		return nil, nil
+	}
+
+	var missing []missingEnv
+	interpolated := make(map[string]any, len(config))
+	for key, value := range config {
+		if key == "profiles" {
+			interpolated[key] = value
+			continue
+		}
+		interpolated[key] = interpolateValue(value, func(name string) {
+			ref := missingEnv{Key: key, Name: name}
+			if !slices.Contains(missing, ref) {
+				missing = append(missing, ref)
+			}
+		})
+	}
+	slices.SortFunc(missing, func(a, b missingEnv) int {
+		return strings.Compare(a.Key+"\x00"+a.Name, b.Key+"\x00"+b.Name)
+	})
+	return interpolated, missing
+}
+
+// interpolateValue expands the references in v, calling unset for each
+// referenced variable that is not set.
+func interpolateValue(v any, unset func(name string)) any {
+	switch val := v.(type) {
+	case string:
+		return envReferencePattern.ReplaceAllStringFunc(val, func(ref string) string {
+			if ref == "$${" {
+				return "${"
+			}
+			name := ref[2 : len(ref)-1]
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				unset(name)
+			}
+			return value
+		})
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = interpolateValue(item, unset)
+		}
+		return out
+	case []string:
+		out := make([]string, len(val))
+		for i, item := range val {
+			out[i], _ = interpolateValue(item, unset).(string)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for key, item := range val {
+			out[key] = interpolateValue(item, unset)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// missingEnvError describes the unset variables referenced by the config.
+func missingEnvError(missing []missingEnv) error {
+	names := make([]string, 0, len(missing))
+	for _, ref := range missing {
+		names = append(names, fmt.Sprintf("%s (in %s)", ref.Name, ref.Key))
+	}
+	return fmt.Errorf("environment variables not set: %s", strings.Join(names, ", "))
+}
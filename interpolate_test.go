@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestInterpolateEnv(t *testing.T) {
+	t.Setenv("TEAM_CHAT", "@releases")
+	t.Setenv("TEAM", "core")
+
+	tests := []struct {
+		name        string
+		config      map[string]any
+		expected    map[string]any
+		wantMissing []missingEnv
+	}{
+		{
+			name:     "string values",
+			config:   map[string]any{"chat_id": "${TEAM_CHAT}", "template": "{{.Version}} for ${TEAM}"},
+			expected: map[string]any{"chat_id": "@releases", "template": "{{.Version}} for core"},
+		},
+		{
+			name:     "nested values",
+			config:   map[string]any{"labels": map[string]any{"team": "${TEAM}"}, "language": []any{"${TEAM}", 1}},
+			expected: map[string]any{"labels": map[string]any{"team": "core"}, "language": []any{"core", 1}},
+		},
+		{
+			name:     "escape and non-strings",
+			config:   map[string]any{"template": "$${TEAM} $TEAM", "teaser_lines": float64(3)},
+			expected: map[string]any{"template": "${TEAM} $TEAM", "teaser_lines": float64(3)},
+		},
+		{
+			name:     "unset",
+			config:   map[string]any{"chat_id": "${NO_SUCH_VAR}", "run_id": "${NO_SUCH_VAR}-${ALSO_UNSET}"},
+			expected: map[string]any{"chat_id": "", "run_id": "-"},
+			wantMissing: []missingEnv{
+				{Key: "chat_id", Name: "NO_SUCH_VAR"},
+				{Key: "run_id", Name: "ALSO_UNSET"},
+				{Key: "run_id", Name: "NO_SUCH_VAR"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, missing := interpolateEnv(tt.config)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("interpolateEnv() = %v, want %v", got, tt.expected)
+			}
+			if !reflect.DeepEqual(missing, tt.wantMissing) {
+				t.Errorf("missing = %v, want %v", missing, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestValidateStrictEnv(t *testing.T) {
+	t.Setenv("TEAM_CHAT", "@releases")
+	p := &TelegramPlugin{}
+
+	tests := []struct {
+		name      string
+		strict    bool
+		chatID    string
+		wantField string
+	}{
+		{"set", true, "${TEAM_CHAT}", ""},
+		{"unset lenient", false, "@mychannel${NO_SUCH_VAR}", ""},
+		{"unset strict", true, "@mychannel${NO_SUCH_VAR}", "chat_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := p.Validate(context.Background(), map[string]any{
+				"bot_token":  "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":    tt.chatID,
+				"strict_env": tt.strict,
+			})
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			var field string
+			if len(resp.Errors) > 0 {
+				field = resp.Errors[0].Field
+			}
+			if field != tt.wantField {
+				t.Errorf("Validate() errors = %v, want error on %q", resp.Errors, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestExecuteStrictEnv(t *testing.T) {
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":  "123:abc",
+			"chat_id":    "${NO_SUCH_VAR}",
+			"strict_env": true,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+		DryRun:  true,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "NO_SUCH_VAR (in chat_id)") {
+		t.Errorf("Execute() = %+v, want failure naming NO_SUCH_VAR", resp)
+	}
+}
+
+func TestStrictEnvProfiles(t *testing.T) {
+	t.Setenv("DEPLOY_ENV", "dev")
+	t.Setenv("DEV_CHAT", "@releases_dev")
+	p := &TelegramPlugin{}
+
+	tests := []struct {
+		name    string
+		profile string
+		wantErr string
+	}{
+		{"unselected profile", "${DEPLOY_ENV}", ""},
+		{"selected profile", "prod", "NO_SUCH_VAR (in chat_id)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]any{
+				"bot_token":  "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"strict_env": true,
+				"profile":    tt.profile,
+				"profiles": map[string]any{
+					"dev":  map[string]any{"chat_id": "${DEV_CHAT}"},
+					"prod": map[string]any{"chat_id": "${NO_SUCH_VAR}"},
+				},
+			}
+			vresp, err := p.Validate(context.Background(), config)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if (len(vresp.Errors) > 0) != (tt.wantErr != "") {
+				t.Errorf("Validate() errors = %v, want errors %v", vresp.Errors, tt.wantErr != "")
+			}
+
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+				DryRun:  true,
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if tt.wantErr == "" {
+				if !resp.Success || resp.Outputs["chat_id"] != "@releases_dev" {
+					t.Errorf("Execute() = %+v, want the dev chat", resp)
+				}
+			} else if resp.Success || !strings.Contains(resp.Error, tt.wantErr) {
+				t.Errorf("Execute() = %+v, want failure naming %s", resp, tt.wantErr)
+			}
+		})
+	}
+}
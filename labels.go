@@ -0,0 +1,16 @@
+package main
+
+import "maps"
+
+// addLabels copies labels into outputs so notification reports can be
+// grouped by audience, allocating outputs if needed.
+func addLabels(outputs map[string]any, labels map[string]string) map[string]any {
+	if len(labels) == 0 {
+		return outputs
+	}
+	if outputs == nil {
+		outputs = map[string]any{}
+	}
+	outputs["labels"] = maps.Clone(labels)
+	return outputs
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteLabelsInOutputs(t *testing.T) {
+	ok := true
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: chat not found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token": "123:abc",
+			"chat_id":   "@test",
+			"labels":    map[string]any{"team": "payments", "region": "eu"},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+	want := map[string]string{"team": "payments", "region": "eu"}
+
+	for _, tt := range []struct {
+		name   string
+		dryRun bool
+		ok     bool
+	}{
+		{"dry run", true, true},
+		{"sent", false, true},
+		{"failed", false, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ok = tt.ok
+			req.DryRun = tt.dryRun
+			resp, err := p.Execute(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if resp.Success != tt.ok {
+				t.Fatalf("Execute() success = %v, want %v", resp.Success, tt.ok)
+			}
+			if got := resp.Outputs["labels"]; !reflect.DeepEqual(got, want) {
+				t.Errorf("Outputs[labels] = %v, want %v", got, want)
+			}
+		})
+	}
+}
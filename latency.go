@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sendDurationExceededOutput records a missed send latency objective.
+var sendDurationExceededOutput = registerDegradation("send_duration_exceeded", func(v any) []string {
+	return []string{fmt.Sprint(v)}
+})
+
+// hookStartKey is the context key holding the time the hook started.
+type hookStartKey struct{}
+
+// withHookStart returns ctx carrying the hook start time.
+func withHookStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, hookStartKey{}, start)
+}
+
+// hookStart returns the hook start time carried by ctx, if any.
+func hookStart(ctx context.Context) (time.Time, bool) {
+	start, ok := ctx.Value(hookStartKey{}).(time.Time)
+	return start, ok
+}
+
+// sendTiming breaks down the time from hook start to the final successful
+// send of a notification.
+type sendTiming struct {
+	// render is the time from hook start until the message was ready.
+	render time.Duration
+	// queue is the time between the message being ready and the first send
+	// attempt, e.g. resolving the chat title.
+	queue time.Duration
+	// api is the duration of the first send attempt.
+	api time.Duration
+	// retries is the time spent resending with formatting fallbacks.
+	retries time.Duration
+}
+
+// total returns the time from hook start to the final send.
+func (t sendTiming) total() time.Duration {
+	return t.render + t.queue + t.api + t.retries
+}
+
+// outputs returns the breakdown in milliseconds.
+func (t sendTiming) outputs() map[string]any {
+	return map[string]any{
+		"render_ms":  t.render.Milliseconds(),
+		"queue_ms":   t.queue.Milliseconds(),
+		"api_ms":     t.api.Milliseconds(),
+		"retries_ms": t.retries.Milliseconds(),
+		"total_ms":   t.total().Milliseconds(),
+	}
+}
+
+// checkSendDuration records the timing breakdown in outputs and a warning
+// when the total exceeds max_send_duration.
+func checkSendDuration(outputs map[string]any, maxDuration time.Duration, timing sendTiming) {
+	outputs["send_timing"] = timing.outputs()
+	if total := timing.total(); total > maxDuration {
+		outputs[sendDurationExceededOutput] = fmt.Sprintf("notification took %s, exceeding max_send_duration %s",
+			total.Round(time.Millisecond), maxDuration)
+	}
+}
+
+// parseDuration parses a duration config value: a Go duration string such
+// as "30s", or a number of seconds.
+func parseDuration(v any) (time.Duration, error) {
+	switch val := v.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return time.Duration(val) * time.Second, nil
+	case int64:
+		return time.Duration(val) * time.Second, nil
+	case float64:
+		return time.Duration(val * float64(time.Second)), nil
+	case string:
+		if seconds, err := strconv.ParseFloat(val, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second)), nil
+		}
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a duration such as \"30s\" or a number of seconds", val)
+		}
+		return d, nil
+	default:
+		return 0, fmt.Errorf("must be a duration such as \"30s\" or a number of seconds")
+	}
+}
+
+// parseStartedAt parses started_at: an RFC 3339 timestamp or Unix seconds,
+// as CI systems expose pipeline start times.
+func parseStartedAt(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil && seconds > 0 {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid start time %q, expected an RFC 3339 timestamp or Unix seconds", s)
+}
+
+// releaseDuration returns the time from started_at to now, or 0 when
+// started_at is unset, invalid, or in the future.
+func (cfg *Config) releaseDuration(now time.Time) time.Duration {
+	if cfg.StartedAt == "" {
+		return 0
+	}
+	// Invalid start times are reported by Validate.
+	start, err := parseStartedAt(cfg.StartedAt)
+	if err != nil {
+		return 0
+	}
+	return max(now.Sub(start), 0)
+}
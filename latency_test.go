@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"unset", nil, 0, false},
+		{"go duration", "1m30s", 90 * time.Second, false},
+		{"seconds string", "2.5", 2500 * time.Millisecond, false},
+		{"seconds number", float64(10), 10 * time.Second, false},
+		{"seconds int", 3, 3 * time.Second, false},
+		{"invalid string", "soon", 0, true},
+		{"invalid type", true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDuration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("parseDuration() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReleaseDuration(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 4, 12, 0, time.UTC)
+	tests := []struct {
+		name      string
+		startedAt string
+		expected  time.Duration
+	}{
+		{"unset", "", 0},
+		{"rfc 3339", "2024-03-01T12:00:00Z", 4*time.Minute + 12*time.Second},
+		{"rfc 3339 with offset", "2024-03-01T13:02:00+01:00", 2*time.Minute + 12*time.Second},
+		{"unix seconds", "1709294400", 4*time.Minute + 12*time.Second},
+		{"in the future", "2024-03-01T13:00:00Z", 0},
+		{"invalid", "yesterday", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{StartedAt: tt.startedAt}
+			if got := cfg.releaseDuration(now); got != tt.expected {
+				t.Errorf("releaseDuration() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecuteMaxSendDuration(t *testing.T) {
+	clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		clk.Advance(3 * time.Second)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	tests := []struct {
+		name         string
+		maxDuration  string
+		strict       bool
+		wantSuccess  bool
+		wantExceeded bool
+	}{
+		{"within objective", "5s", false, true, false},
+		{"exceeded", "2s", false, true, true},
+		{"exceeded strict", "2s", true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &TelegramPlugin{clock: clk}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"bot_token":         "123:abc",
+					"chat_id":           "@test",
+					"max_send_duration": tt.maxDuration,
+					"strict":            tt.strict,
+				},
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v (%s)", resp.Success, tt.wantSuccess, resp.Error)
+			}
+			if _, exceeded := resp.Outputs["send_duration_exceeded"]; exceeded != tt.wantExceeded {
+				t.Errorf("send_duration_exceeded present = %v, want %v", exceeded, tt.wantExceeded)
+			}
+			timing, _ := resp.Outputs["send_timing"].(map[string]any)
+			if timing["api_ms"] != int64(3000) || timing["total_ms"] != int64(3000) {
+				t.Errorf("send_timing = %v, want 3000ms api and total", timing)
+			}
+		})
+	}
+}
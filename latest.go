@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// latestReleasePinErrorsOutput records the chats whose latest release pin
+// was not updated.
+var latestReleasePinErrorsOutput = registerDegradation("latest_release_pin_errors", describeByKey("latest release pin in %s failed: %s"))
+
+// defaultLatestReleaseTemplate is the default text of the pinned latest
+// release message.
+const defaultLatestReleaseTemplate = "📌 Latest release: {{escape .Version}}"
+
+// latestReleasePinKey identifies the pinned latest release message of a chat
+// thread within the state file.
+func latestReleasePinKey(chatID string, threadID int64) string {
+	return strings.Join([]string{"latest", chatID, strconv.FormatInt(threadID, 10)}, "|")
+}
+
+// updateLatestReleasePins edits the pinned latest release message of the
+// chat and of every target to announce releaseCtx, posting and pinning it on
+// first use or when it was deleted. The message IDs are reported in
+// latest_release_pins and the failures in latest_release_pin_errors; a
+// failure does not fail the hook, as the announcement was already sent.
+func (p *TelegramPlugin) updateLatestReleasePins(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, outputs map[string]any) {
+	text, err := p.renderTemplate(cfg, cfg.LatestReleaseTemplate, releaseCtx)
+	if err != nil {
+		outputs[latestReleasePinErrorsOutput] = map[string]string{cfg.ChatID: fmt.Sprintf("failed to render latest_release_template: %v", err)}
+		return
+	}
+
+	configs := []*Config{cfg}
+	for _, target := range cfg.componentTargets() {
+		if !target.AlwaysDryRun {
+			configs = append(configs, cfg.targetConfig(target))
+		}
+	}
+	pins := map[string]int64{}
+	failed := map[string]string{}
+	for _, chatCfg := range configs {
+		id, err := p.updateLatestReleasePin(ctx, chatCfg, text, dryRun)
+		if err != nil {
+			failed[chatCfg.ChatID] = errorText(err)
+		}
+		if id != 0 {
+			pins[chatCfg.ChatID] = id
+		}
+	}
+	if len(pins) > 0 {
+		outputs["latest_release_pins"] = pins
+	}
+	if len(failed) > 0 {
+		outputs[latestReleasePinErrorsOutput] = failed
+	}
+}
+
+// updateLatestReleasePin edits the pinned latest release message of cfg's
+// chat thread to text, or posts it when there is none, and pins it. It
+// returns the message ID. In dry-run mode nothing is changed and the ID of
+// an existing message is returned.
+func (p *TelegramPlugin) updateLatestReleasePin(ctx context.Context, cfg *Config, text string, dryRun bool) (int64, error) {
+	key := latestReleasePinKey(cfg.ChatID, cfg.MessageThreadID)
+	state, err := loadState(cfg.StateFile)
+	if err != nil {
+		return 0, err
+	}
+	id := state.LatestReleasePins[key]
+	if dryRun {
+		return id, nil
+	}
+
+	msg := newMessage(cfg, text)
+	msg.DisableNotification = true
+	if id != 0 {
+		switch err := p.editMessageText(ctx, cfg, msg, id); {
+		case err == nil, isMessageNotModifiedError(err):
+		case isMessageToEditNotFoundError(err):
+			// The message was deleted; post a new one.
+			id = 0
+		default:
+			return id, fmt.Errorf("failed to edit latest release message: %w", err)
+		}
+	}
+	if id == 0 {
+		sent, err := p.postMessage(ctx, cfg, msg)
+		if err != nil {
+			return 0, fmt.Errorf("failed to post latest release message: %w", err)
+		}
+		id = sent.MessageID
+		// The message exists now, so pin it even if it could not be
+		// remembered.
+		_ = p.updateState(cfg.StateFile, func(s *pluginState) {
+			if s.LatestReleasePins == nil {
+				s.LatestReleasePins = make(map[string]int64)
+			}
+			s.LatestReleasePins[key] = id
+		})
+	}
+
+	// Pinning again keeps the message pinned if someone unpinned it.
+	if err := p.pinChatMessage(ctx, cfg, cfg.ChatID, id); err != nil {
+		return id, fmt.Errorf("failed to pin latest release message: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteLatestReleasePin(t *testing.T) {
+	var calls []string
+	var pinText string
+	var editResponse TelegramResponse
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		method := path.Base(r.URL.Path)
+		calls = append(calls, method)
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch method {
+		case "sendMessage":
+			id := int64(1)
+			if text, _ := req["text"].(string); strings.HasPrefix(text, "📌") {
+				id = 100
+				pinText = text
+			}
+			result, _ := json.Marshal(TelegramSentMessage{MessageID: id})
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+		case "editMessageText":
+			if req["message_id"] != float64(100) {
+				t.Errorf("edited message %v, want 100", req["message_id"])
+			}
+			pinText, _ = req["text"].(string)
+			_ = json.NewEncoder(w).Encode(editResponse)
+		case "pinChatMessage":
+			if req["message_id"] != float64(100) {
+				t.Errorf("pinned message %v, want 100", req["message_id"])
+			}
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+		default:
+			t.Errorf("unexpected call to %s", r.URL.Path)
+		}
+	})
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	notFound := TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: message to edit not found"}
+	notModified := TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: message is not modified"}
+	tests := []struct {
+		name         string
+		version      string
+		dryRun       bool
+		editResponse TelegramResponse
+		calls        []string
+		pinText      string
+	}{
+		{"posted on first use", "1.0.0", false, TelegramResponse{OK: true}, []string{"sendMessage", "sendMessage", "pinChatMessage"}, `📌 Latest release: 1\.0\.0`},
+		{"edited in place", "1.1.0", false, TelegramResponse{OK: true}, []string{"sendMessage", "editMessageText", "pinChatMessage"}, `📌 Latest release: 1\.1\.0`},
+		{"rerun", "1.1.0", false, notModified, []string{"sendMessage", "editMessageText", "pinChatMessage"}, `📌 Latest release: 1\.1\.0`},
+		{"deleted message is posted again", "1.2.0", false, notFound, []string{"sendMessage", "editMessageText", "sendMessage", "pinChatMessage"}, `📌 Latest release: 1\.2\.0`},
+		{"dry run", "1.3.0", true, TelegramResponse{OK: true}, nil, ""},
+	}
+
+	p := &TelegramPlugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, pinText, editResponse = nil, "", tt.editResponse
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"bot_token":          "123:abc",
+					"chat_id":            "-1001234567890",
+					"latest_release_pin": true,
+					"state_file":         stateFile,
+				},
+				Context: plugin.ReleaseContext{Version: tt.version},
+				DryRun:  tt.dryRun,
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v", resp, err)
+			}
+			if !slices.Equal(calls, tt.calls) {
+				t.Errorf("calls = %v, want %v", calls, tt.calls)
+			}
+			if pinText != tt.pinText {
+				t.Errorf("latest release message = %q, want %q", pinText, tt.pinText)
+			}
+			pins, _ := resp.Outputs["latest_release_pins"].(map[string]int64)
+			if pins["-1001234567890"] != 100 || resp.Outputs["latest_release_pin_errors"] != nil {
+				t.Errorf("Outputs = %v, want the pinned message 100", resp.Outputs)
+			}
+		})
+	}
+}
@@ -2,9 +2,29 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
 )
 
 func main() {
+	if mode := selfTestMode(os.Args[1:], os.Getenv(selfTestEnv)); mode != "" {
+		if err := (&TelegramPlugin{}).selfTest(context.Background(), os.Stdout, mode); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if renderOnlyMode(os.Args[1:], os.Getenv(renderOnlyEnv)) {
+		if err := (&TelegramPlugin{}).renderOnly(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	plugin.Serve(&TelegramPlugin{})
 }
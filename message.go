@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// errorTemplateErrorOutput records why the error template could not be
+// rendered.
+var errorTemplateErrorOutput = registerDegradation("error_template_error", describeError("default error message sent"))
+
+// renderer returns the message renderer for cfg.
+func (p *TelegramPlugin) renderer(cfg *Config) *render.Renderer {
+	return render.New(render.Options{
+		ParseMode:           cfg.ParseMode,
+		IncludeChangelog:    cfg.IncludeChangelog,
+		MaxChangelogLength:  cfg.MaxChangelogLength,
+		ChangelogStyle:      cfg.ChangelogStyle,
+		TeaserLines:         cfg.TeaserLines,
+		ChangesSummaryMode:  cfg.ChangesSummaryMode,
+		SampleSize:          cfg.ChangesSampleSize,
+		ScopePriority:       cfg.ScopePriority,
+		CommitFormat:        cfg.CommitFormat,
+		CommitTemplate:      cfg.CommitTemplate,
+		ReleaseURL:          cfg.ReleaseURL,
+		BreakingFirst:       cfg.BreakingFirst,
+		Sections:            cfg.Sections,
+		HeadlineRules:       cfg.HeadlineRules,
+		Language:            cfg.Language,
+		ShowContributors:    cfg.ShowContributors,
+		ContributorHandles:  cfg.ContributorHandles,
+		Component:           cfg.Component,
+		NormalizeWhitespace: cfg.NormalizeWhitespace,
+		Duration:            cfg.releaseDuration(p.now()),
+		CompareStats:        cfg.compareStats,
+		Variables:           cfg.Variables,
+		Now:                 p.now(),
+	})
+}
+
+// buildSuccessMessage builds the success notification message.
+func (p *TelegramPlugin) buildSuccessMessage(cfg *Config, releaseCtx plugin.ReleaseContext) string {
+	return p.renderer(cfg).Success(releaseCtx)
+}
+
+// buildVersionMessage builds the notification announcing the next version.
+func (p *TelegramPlugin) buildVersionMessage(cfg *Config, releaseCtx plugin.ReleaseContext) string {
+	return p.renderer(cfg).Version(releaseCtx)
+}
+
+// buildBreakingAlertMessage builds the separate breaking changes alert.
+func (p *TelegramPlugin) buildBreakingAlertMessage(cfg *Config, releaseCtx plugin.ReleaseContext) string {
+	return p.renderer(cfg).BreakingAlert(releaseCtx)
+}
+
+// buildErrorMessage builds the error notification message.
+func (p *TelegramPlugin) buildErrorMessage(cfg *Config, releaseCtx plugin.ReleaseContext) string {
+	return p.renderer(cfg).Error(releaseCtx)
+}
+
+// renderErrorTemplate renders error_template or error_template_file in the
+// parse mode set by its front matter, which is returned with the text. It
+// returns "" when neither is configured.
+func (p *TelegramPlugin) renderErrorTemplate(cfg *Config, releaseCtx plugin.ReleaseContext) (string, frontMatter, error) {
+	tmpl, err := templateSource(cfg.ErrorTemplate, cfg.ErrorTemplateFile)
+	if err != nil || tmpl == "" {
+		return "", frontMatter{}, err
+	}
+	fm, tmpl, err := parseFrontMatter(tmpl)
+	if err != nil {
+		return "", frontMatter{}, fmt.Errorf("invalid error template front matter: %w", err)
+	}
+	text, err := p.renderTemplate(fm.apply(cfg), tmpl, releaseCtx)
+	if err != nil {
+		return "", frontMatter{}, fmt.Errorf("failed to render error template: %w", err)
+	}
+	return text, fm, nil
+}
+
+// renderTemplate renders a custom template with release context.
+func (p *TelegramPlugin) renderTemplate(cfg *Config, templateStr string, releaseCtx plugin.ReleaseContext) (string, error) {
+	return p.renderer(cfg).Template(templateStr, releaseCtx)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+)
+
+func TestParseSections(t *testing.T) {
+	sections := parseSections([]any{
+		"header",
+		map[string]any{"template": "Hi {{.Version}}"},
+		42,
+	})
+
+	if len(sections) != 3 {
+		t.Fatalf("parseSections() returned %d sections, want 3", len(sections))
+	}
+	if sections[0].Name != "header" {
+		t.Errorf("sections[0] = %+v, want header", sections[0])
+	}
+	if sections[1].Template != "Hi {{.Version}}" {
+		t.Errorf("sections[1] = %+v, want template block", sections[1])
+	}
+	if sections[2] != (render.Section{}) {
+		t.Errorf("sections[2] = %+v, want empty section for invalid entry", sections[2])
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+const (
+	// metricsHistorySize is how many sends are kept per chat in the metrics
+	// file.
+	metricsHistorySize = 50
+	// failingChatThreshold is how many consecutive failed sends to a chat
+	// Validate warns about.
+	failingChatThreshold = 3
+)
+
+// chatSend is a send to a chat recorded in the metrics file.
+type chatSend struct {
+	// At is when the send finished.
+	At time.Time `json:"at"`
+	// OK reports whether the send succeeded.
+	OK bool `json:"ok"`
+	// LatencyMS is the duration of a successful send.
+	LatencyMS int64 `json:"latency_ms,omitempty"`
+	// Error is why a failed send failed.
+	Error string `json:"error,omitempty"`
+}
+
+// chatMetrics is the send history of a chat.
+type chatMetrics struct {
+	// Sends is the number of sends recorded for the chat.
+	Sends int `json:"sends"`
+	// Failures is the number of those sends that failed.
+	Failures int `json:"failures"`
+	// AverageLatencyMS is the mean duration of the successful sends in
+	// History.
+	AverageLatencyMS int64 `json:"average_latency_ms"`
+	// LastSuccess is when the last successful send finished, even when it
+	// has rolled out of History.
+	LastSuccess time.Time `json:"last_success,omitzero"`
+	// History holds the most recent sends, oldest first.
+	History []chatSend `json:"history"`
+}
+
+// record adds a send, dropping the oldest beyond metricsHistorySize from
+// the history.
+func (m *chatMetrics) record(send chatSend) {
+	m.Sends++
+	if send.OK {
+		m.LastSuccess = send.At
+	} else {
+		m.Failures++
+	}
+	m.History = append(m.History, send)
+	if n := len(m.History) - metricsHistorySize; n > 0 {
+		m.History = slices.Delete(m.History, 0, n)
+	}
+
+	var total, n int64
+	for _, send := range m.History {
+		if send.OK {
+			total += send.LatencyMS
+			n++
+		}
+	}
+	m.AverageLatencyMS = 0
+	if n > 0 {
+		m.AverageLatencyMS = total / n
+	}
+}
+
+// consecutiveFailures returns the number of failed sends since the last
+// successful one.
+func (m *chatMetrics) consecutiveFailures() int {
+	n := 0
+	for i := len(m.History) - 1; i >= 0 && !m.History[i].OK; i-- {
+		n++
+	}
+	return n
+}
+
+// loadMetrics reads the metrics file at path, keyed by chat ID. A missing
+// file yields no metrics.
+func loadMetrics(path string) (map[string]*chatMetrics, error) {
+	metrics := map[string]*chatMetrics{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return metrics, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics file: %w", err)
+	}
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		return nil, fmt.Errorf("failed to decode metrics file: %w", err)
+	}
+	return metrics, nil
+}
+
+// recordMetrics adds the sends of a notification to the metrics file. The
+// plugin mutex serializes updates from concurrent hooks. Recording is best
+// effort: a metrics file that cannot be written does not fail the release.
+func (p *TelegramPlugin) recordMetrics(cfg *Config, targets []Target, sent []delivery, errs []error) {
+	if cfg.MetricsFile == "" || len(targets) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	metrics, err := loadMetrics(cfg.MetricsFile)
+	if err != nil {
+		return
+	}
+	now := p.now()
+	for i, target := range targets {
+		send := chatSend{At: now, OK: errs[i] == nil}
+		if send.OK {
+			send.LatencyMS = (sent[i].api + sent[i].retries).Milliseconds()
+		} else {
+			send.Error = failureReason(errs[i])
+		}
+		m, ok := metrics[target.ChatID]
+		if !ok {
+			m = &chatMetrics{}
+			metrics[target.ChatID] = m
+		}
+		m.record(send)
+	}
+
+	data, err := json.MarshalIndent(metrics, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = writeFileAtomic(cfg.MetricsFile, "metrics", data)
+}
+
+// failingChatWarnings returns a warning per chat of the metrics file whose
+// last failingChatThreshold or more sends failed, in chat order.
+func failingChatWarnings(path string) []plugin.ValidationError {
+	metrics, err := loadMetrics(path)
+	if err != nil {
+		return nil
+	}
+	var warnings []plugin.ValidationError
+	for _, chatID := range slices.Sorted(maps.Keys(metrics)) {
+		m := metrics[chatID]
+		n := m.consecutiveFailures()
+		if n < failingChatThreshold {
+			continue
+		}
+		msg := fmt.Sprintf("chat %s failed the last %d sends (%s)", chatID, n, m.History[len(m.History)-1].Error)
+		if m.LastSuccess.IsZero() {
+			msg += "; it has never received a notification"
+		} else {
+			msg += "; last success " + m.LastSuccess.UTC().Format(time.RFC3339)
+		}
+		warnings = append(warnings, plugin.ValidationError{Field: "metrics_file", Message: msg, Code: "failing_chat"})
+	}
+	return warnings
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestChatMetricsRecord(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := &chatMetrics{}
+	for i := range metricsHistorySize + 2 {
+		m.record(chatSend{At: start.Add(time.Duration(i) * time.Minute), OK: true, LatencyMS: int64(i)})
+	}
+	m.record(chatSend{At: start.Add(time.Hour), Error: "forbidden"})
+	m.record(chatSend{At: start.Add(2 * time.Hour), Error: "forbidden"})
+
+	if m.Sends != metricsHistorySize+4 || m.Failures != 2 {
+		t.Errorf("sends = %d with %d failures, want %d with 2", m.Sends, m.Failures, metricsHistorySize+4)
+	}
+	if len(m.History) != metricsHistorySize || m.History[0].LatencyMS != 4 {
+		t.Errorf("history holds %d sends from latency %d, want the last %d", len(m.History), m.History[0].LatencyMS, metricsHistorySize)
+	}
+	// The 48 successful sends left in the history took 4 to 51ms.
+	if m.AverageLatencyMS != 27 {
+		t.Errorf("average latency = %dms, want 27ms", m.AverageLatencyMS)
+	}
+	if want := start.Add(time.Duration(metricsHistorySize+1) * time.Minute); !m.LastSuccess.Equal(want) {
+		t.Errorf("last success = %v, want %v", m.LastSuccess, want)
+	}
+	if got := m.consecutiveFailures(); got != 2 {
+		t.Errorf("consecutiveFailures() = %d, want 2", got)
+	}
+}
+
+func TestExecuteMetricsFile(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg.ChatID == "@dead_chat" {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was kicked from the channel chat"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	metricsFile := filepath.Join(t.TempDir(), "metrics.json")
+	config := map[string]any{
+		"bot_token":    "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+		"chat_id":      "@live_chat",
+		"chat_ids":     []any{"@dead_chat"},
+		"metrics_file": metricsFile,
+	}
+	p := &TelegramPlugin{}
+	for range failingChatThreshold {
+		if _, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook:    plugin.HookPostPublish,
+			Config:  config,
+			Context: plugin.ReleaseContext{Version: "1.0.0"},
+		}); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+
+	metrics, err := loadMetrics(metricsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live, dead := metrics["@live_chat"], metrics["@dead_chat"]; live == nil || dead == nil ||
+		live.Sends != 3 || live.Failures != 0 || dead.Failures != 3 || !dead.LastSuccess.IsZero() {
+		t.Fatalf("metrics = %+v, want 3 sends to each chat with the dead one failing", metrics)
+	}
+
+	resp, err := p.Validate(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Valid || len(resp.Errors) != 1 {
+		t.Fatalf("Validate() = %+v, want a valid config with one warning", resp)
+	}
+	if warning := resp.Errors[0]; warning.Code != "failing_chat" || !strings.Contains(warning.Message, "@dead_chat failed the last 3 sends") {
+		t.Errorf("warning = %+v, want @dead_chat failing", warning)
+	}
+}
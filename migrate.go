@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// chatMigrationWarningsOutput records chats that moved to a supergroup.
+var chatMigrationWarningsOutput = registerDegradation("chat_migration_warnings", describeEach("chat migrated"))
+
+// migrateToChatID returns the supergroup err says the group was upgraded
+// to, if it is such an error.
+func migrateToChatID(err error) (string, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.MigrateToChatID == 0 {
+		return "", false
+	}
+	return strconv.FormatInt(apiErr.MigrateToChatID, 10), true
+}
+
+// sendMessageMigrating sends a sendMessage payload, following a group's
+// upgrade to a supergroup: when the Bot API reports the chat moved, the
+// message is sent again to the new chat and the migration is reported in
+// the outputs of the notification. With persist_chat_migrations, the
+// migration is remembered in the state file and later sends go to the new
+// chat right away.
+func (p *TelegramPlugin) sendMessageMigrating(ctx context.Context, cfg *Config, payload map[string]any) (*TelegramSentMessage, error) {
+	chatID := fmt.Sprint(payload["chat_id"])
+	if cfg.PersistChatMigrations {
+		if state, err := loadState(cfg.StateFile); err == nil && state.ChatMigrations[chatID] != "" {
+			p.recordChatMigration(chatID, state.ChatMigrations[chatID])
+			payload = maps.Clone(payload)
+			payload["chat_id"] = state.ChatMigrations[chatID]
+			return p.sendMessagePayload(ctx, cfg, payload)
+		}
+	}
+
+	sent, err := p.sendMessagePayload(ctx, cfg, payload)
+	to, migrated := migrateToChatID(err)
+	if !migrated {
+		return sent, err
+	}
+	p.recordChatMigration(chatID, to)
+	if cfg.PersistChatMigrations {
+		// The message goes to the new chat even if the migration could not
+		// be remembered.
+		_ = p.updateState(cfg.StateFile, func(s *pluginState) {
+			if s.ChatMigrations == nil {
+				s.ChatMigrations = make(map[string]string)
+			}
+			s.ChatMigrations[chatID] = to
+		})
+	}
+	payload = maps.Clone(payload)
+	payload["chat_id"] = to
+	return p.sendMessagePayload(ctx, cfg, payload)
+}
+
+// recordChatMigration remembers for the outputs of the run that from was
+// upgraded to the supergroup to.
+func (p *TelegramPlugin) recordChatMigration(from, to string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.chatMigrations == nil {
+		p.chatMigrations = make(map[string]string)
+	}
+	p.chatMigrations[from] = to
+}
+
+// reportChatMigrations adds a warning per chat migrated since the last
+// report to outputs["chat_migration_warnings"], suggesting the config
+// update.
+func (p *TelegramPlugin) reportChatMigrations(outputs map[string]any) {
+	p.mu.Lock()
+	migrations := p.chatMigrations
+	p.chatMigrations = nil
+	p.mu.Unlock()
+	if len(migrations) == 0 {
+		return
+	}
+
+	warnings := make([]string, 0, len(migrations))
+	for _, from := range slices.Sorted(maps.Keys(migrations)) {
+		warnings = append(warnings, fmt.Sprintf("chat %s was upgraded to the supergroup %s; replace it in the config", from, migrations[from]))
+	}
+	outputs[chatMigrationWarningsOutput] = warnings
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteChatMigration(t *testing.T) {
+	tests := []struct {
+		name    string
+		persist bool
+		want    []string
+	}{
+		{"followed each run", false, []string{"-12345", "-10012345", "-12345", "-10012345"}},
+		{"persisted", true, []string{"-12345", "-10012345", "-10012345"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				var msg TelegramMessage
+				_ = json.NewDecoder(r.Body).Decode(&msg)
+				sent = append(sent, msg.ChatID)
+				if msg.ChatID == "-12345" {
+					_ = json.NewEncoder(w).Encode(TelegramResponse{
+						OK:          false,
+						ErrorCode:   400,
+						Description: "Bad Request: group chat was upgraded to a supergroup chat",
+						Parameters:  &ResponseParameters{MigrateToChatID: -10012345},
+					})
+					return
+				}
+				_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+			})
+
+			req := plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"bot_token":               "123:abc",
+					"chat_id":                 "-12345",
+					"persist_chat_migrations": tt.persist,
+					"state_file":              filepath.Join(t.TempDir(), "state.json"),
+				},
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			}
+			p := &TelegramPlugin{}
+			for range 2 {
+				resp, err := p.Execute(context.Background(), req)
+				if err != nil || !resp.Success {
+					t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+				}
+				want := []string{"chat -12345 was upgraded to the supergroup -10012345; replace it in the config"}
+				if got, _ := resp.Outputs["chat_migration_warnings"].([]string); !slices.Equal(got, want) {
+					t.Errorf("chat_migration_warnings = %v, want %v", got, want)
+				}
+			}
+			if !slices.Equal(sent, tt.want) {
+				t.Errorf("sent to %v, want %v", sent, tt.want)
+			}
+		})
+	}
+}
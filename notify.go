@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// notifyHooks are the hooks the plugin sends notifications for.
+var notifyHooks = []plugin.Hook{
+	plugin.HookPostVersion,
+	plugin.HookPostPublish,
+	plugin.HookOnSuccess,
+	plugin.HookOnError,
+}
+
+// hookKey returns the key of hook in config maps such as notify_on: the
+// hook name with underscores, as in post_publish.
+func hookKey(hook plugin.Hook) string {
+	return strings.ReplaceAll(string(hook), "-", "_")
+}
+
+// parseHookKey returns the notified hook that a config map key names. Keys
+// are written with underscores, as in post_publish; the hyphenated hook
+// names Relicta uses, such as post-publish, are accepted too.
+func parseHookKey(key string) (plugin.Hook, bool) {
+	for _, hook := range notifyHooks {
+		if key == hookKey(hook) || key == string(hook) {
+			return hook, true
+		}
+	}
+	return "", false
+}
+
+// parseHookStringMap parses a map of strings keyed by hook, such as
+// templates, keyed by hookKey. Entries for other hooks are dropped;
+// Validate reports them.
+func parseHookStringMap(v any) map[string]string {
+	values := parseStringMap(v)
+	if values == nil {
+		return nil
+	}
+	byHook := make(map[string]string, len(values))
+	for key, value := range values {
+		if hook, ok := parseHookKey(key); ok {
+			byHook[hookKey(hook)] = value
+		}
+	}
+	return byHook
+}
+
+// notifyOnDefaults resolves which hooks notify before notify_on is applied.
+// The defaults come from the older notify_on_* booleans, which default to
+// notifying on success and error but not on post_version.
+func notifyOnDefaults(success, failure, version bool) map[string]bool {
+	return map[string]bool{
+		hookKey(plugin.HookPostVersion): version,
+		hookKey(plugin.HookPostPublish): success,
+		hookKey(plugin.HookOnSuccess):   success,
+		hookKey(plugin.HookOnError):     failure,
+	}
+}
+
+// resolveNotifyOn applies the notify_on map, keyed by hook name, over the
+// defaults. Entries in notify_on take precedence over the notify_on_*
+// booleans. Invalid entries are skipped; Validate reports them.
+func resolveNotifyOn(defaults map[string]bool, v any) map[string]bool {
+	notifyOn := maps.Clone(defaults)
+	entries, _ := parseNotifyOn(v)
+	maps.Copy(notifyOn, entries)
+	return notifyOn
+}
+
+// parseNotifyOn parses the notify_on map. Values are booleans or strings
+// such as "true", so entries can come from environment variables. The
+// returned map holds the valid entries; the error names the first invalid
+// one in key order.
+func parseNotifyOn(v any) (map[string]bool, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("must be a map of hook names to booleans")
+	}
+
+	var errs []error
+	notifyOn := make(map[string]bool, len(raw))
+	for _, key := range slices.Sorted(maps.Keys(raw)) {
+		hook, ok := parseHookKey(key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown hook %q; supported hooks are %s", key, hookNames(notifyHooks)))
+			continue
+		}
+		switch value := raw[key].(type) {
+		case bool:
+			notifyOn[hookKey(hook)] = value
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%q must be true or false, got %q", key, value))
+				continue
+			}
+			notifyOn[hookKey(hook)] = b
+		default:
+			errs = append(errs, fmt.Errorf("%q must be true or false", key))
+		}
+	}
+	if len(errs) > 0 {
+		return notifyOn, errs[0]
+	}
+	return notifyOn, nil
+}
+
+// hookNames joins the config keys of hooks for messages.
+func hookNames(hooks []plugin.Hook) string {
+	names := make([]string, len(hooks))
+	for i, hook := range hooks {
+		names[i] = hookKey(hook)
+	}
+	return strings.Join(names, ", ")
+}
+
+// notifies reports whether hook sends a notification.
+func (cfg *Config) notifies(hook plugin.Hook) bool {
+	return cfg.NotifyOn[hookKey(hook)]
+}
+
+// notifyOnRule describes the notify_on entry that turned off hook's
+// notification, for the skip_rule output.
+func notifyOnRule(hook plugin.Hook) string {
+	return fmt.Sprintf("notify_on.%s = false", hookKey(hook))
+}
+
+// applyHookTemplate makes the templates entry for hook, if any, the template
+// of the notification the hook sends. It takes precedence over template,
+// error_template, version_template, and their files.
+func (cfg *Config) applyHookTemplate(hook plugin.Hook) {
+	tmpl, ok := cfg.Templates[hookKey(hook)]
+	if !ok {
+		return
+	}
+	switch hook {
+	case plugin.HookPostPublish, plugin.HookOnSuccess:
+		cfg.Template, cfg.TemplateFile = tmpl, ""
+	case plugin.HookOnError:
+		cfg.ErrorTemplate, cfg.ErrorTemplateFile = tmpl, ""
+	case plugin.HookPostVersion:
+		cfg.VersionTemplate = tmpl
+	}
+}
+
+// applyHookChat sends the notification of hook to the chat configured for
+// it: its hook_chat_ids entry, or error_chat_id for on_error. The chat
+// replaces chat_id, chat_ids, and the release type route; targets are still
+// notified.
+func (cfg *Config) applyHookChat(hook plugin.Hook) {
+	chatID := cfg.HookChatIDs[hookKey(hook)]
+	if chatID == "" && hook == plugin.HookOnError {
+		chatID = cfg.ErrorChatID
+	}
+	if chatID == "" {
+		return
+	}
+	cfg.ChatID, cfg.MessageThreadID = resolveChatID(chatID)
+	cfg.chatTargets = nil
+	cfg.route = ""
+}
+
+// validateHookChatIDs reports whether the hook_chat_ids map only holds
+// valid chats for hooks the plugin notifies on.
+func validateHookChatIDs(v any) error {
+	if err := validateStringMap(v); err != nil {
+		return err
+	}
+	raw, _ := v.(map[string]any)
+	for _, hook := range slices.Sorted(maps.Keys(raw)) {
+		if _, ok := parseHookKey(hook); !ok {
+			return fmt.Errorf("unknown hook %q; supported hooks are %s", hook, hookNames(notifyHooks))
+		}
+		chatID, _ := raw[hook].(string)
+		resolved, _ := resolveChatID(chatID)
+		if err := validateChatID(resolved); err != nil {
+			return fmt.Errorf("%q: %w", hook, err)
+		}
+	}
+	return nil
+}
+
+// validateHookTemplates reports whether the templates map only holds
+// string templates for hooks the plugin notifies on.
+func validateHookTemplates(v any) error {
+	if err := validateStringMap(v); err != nil {
+		return err
+	}
+	raw, _ := v.(map[string]any)
+	for _, hook := range slices.Sorted(maps.Keys(raw)) {
+		if _, ok := parseHookKey(hook); !ok {
+			return fmt.Errorf("unknown hook %q; supported hooks are %s", hook, hookNames(notifyHooks))
+		}
+		if _, ok := raw[hook].(string); !ok {
+			return fmt.Errorf("%q must be a template string", hook)
+		}
+	}
+	return nil
+}
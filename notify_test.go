@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseNotifyOn(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    map[string]bool
+		wantErr bool
+	}{
+		{name: "unset"},
+		{
+			name:  "booleans and strings",
+			value: map[string]any{"post_version": true, "on_error": "false"},
+			want:  map[string]bool{"post_version": true, "on_error": false},
+		},
+		{
+			name:  "hyphenated hook names",
+			value: map[string]any{"post-publish": false, "on-error": true},
+			want:  map[string]bool{"post_publish": false, "on_error": true},
+		},
+		{
+			name:    "unknown hook",
+			value:   map[string]any{"pre_plan": true, "on_error": false},
+			want:    map[string]bool{"on_error": false},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			value:   map[string]any{"on_error": "sometimes"},
+			want:    map[string]bool{},
+			wantErr: true,
+		},
+		{name: "not a map", value: "on_error", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNotifyOn(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseNotifyOn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNotifyOn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyOnConfig(t *testing.T) {
+	p := &TelegramPlugin{}
+
+	tests := []struct {
+		name   string
+		config map[string]any
+		want   map[string]bool
+	}{
+		{
+			name:   "defaults",
+			config: map[string]any{},
+			want:   map[string]bool{"post_version": false, "post_publish": true, "on_success": true, "on_error": true},
+		},
+		{
+			name:   "legacy booleans",
+			config: map[string]any{"notify_on_success": false, "notify_on_version": true},
+			want:   map[string]bool{"post_version": true, "post_publish": false, "on_success": false, "on_error": true},
+		},
+		{
+			name: "notify_on wins over legacy booleans",
+			config: map[string]any{
+				"notify_on_success": false,
+				"notify_on":         map[string]any{"on_success": true, "on_error": false},
+			},
+			want: map[string]bool{"post_version": false, "post_publish": false, "on_success": true, "on_error": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.parseConfig(tt.config).NotifyOn; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NotifyOn = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteNotifyOn(t *testing.T) {
+	p := &TelegramPlugin{}
+	config := map[string]any{
+		"bot_token": "123:abc",
+		"chat_id":   "@test",
+		"notify_on": map[string]any{"post_publish": false, "post_version": true},
+	}
+
+	for hook, want := range map[plugin.Hook]string{
+		plugin.HookPostPublish: "Success notification disabled",
+		plugin.HookOnSuccess:   "Would send Telegram success notification",
+		plugin.HookPostVersion: "Would send Telegram version notification",
+	} {
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook:    hook,
+			DryRun:  true,
+			Config:  config,
+			Context: plugin.ReleaseContext{Version: "1.0.0"},
+		})
+		if err != nil {
+			t.Fatalf("Execute(%s) error = %v", hook, err)
+		}
+		if resp.Message != want {
+			t.Errorf("Execute(%s) message = %q, want %q", hook, resp.Message, want)
+		}
+	}
+}
+
+func TestExecuteHookTemplates(t *testing.T) {
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	config := map[string]any{
+		"bot_token":         "123:abc",
+		"chat_id":           "@test",
+		"parse_mode":        "HTML",
+		"notify_on_version": true,
+		"template":          "generic {{.Version}}",
+		"templates": map[string]any{
+			"on_success":   "shipped {{.Version}}",
+			"on_error":     "broke {{.Version}}",
+			"post_version": "next {{.Version}}",
+		},
+	}
+
+	tests := []struct {
+		hook plugin.Hook
+		want string
+	}{
+		{plugin.HookOnSuccess, "shipped 1.0.0"},
+		{plugin.HookPostPublish, "generic 1.0.0"},
+		{plugin.HookOnError, "broke 1.0.0"},
+		{plugin.HookPostVersion, "next 1.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.hook), func(t *testing.T) {
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    tt.hook,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+			}
+			if got.Text != tt.want {
+				t.Errorf("text = %q, want %q", got.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateHookTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr bool
+	}{
+		{"unset", nil, false},
+		{"known hooks", map[string]any{"on_success": "a", "on_error": "b"}, false},
+		{"hyphenated hooks", map[string]any{"on-success": "a", "post-version": "b"}, false},
+		{"unknown hook", map[string]any{"pre_publish": "a"}, true},
+		{"not a string", map[string]any{"on_success": 42}, true},
+		{"not a map", "a", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateHookTemplates(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("validateHookTemplates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyHookChat(t *testing.T) {
+	tests := []struct {
+		name        string
+		hook        plugin.Hook
+		wantChatID  string
+		wantThread  int64
+		wantTargets []string
+	}{
+		{"error chat", plugin.HookOnError, "-1001234567890", 5, []string{"@mirror"}},
+		{"hook chat", plugin.HookPostVersion, "@dev_team", 0, []string{"@mirror"}},
+		{"no override", plugin.HookPostPublish, "@news", 0, []string{"@news_archive", "@mirror"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ChatID:      "@news",
+				chatTargets: []Target{{ChatID: "@news_archive"}},
+				Targets:     []Target{{ChatID: "@mirror"}},
+				ErrorChatID: "-1001234567890@5",
+				HookChatIDs: map[string]string{"post_version": "@dev_team"},
+			}
+			cfg.applyHookChat(tt.hook)
+			if cfg.ChatID != tt.wantChatID || cfg.MessageThreadID != tt.wantThread {
+				t.Errorf("chat = %q@%d, want %q@%d", cfg.ChatID, cfg.MessageThreadID, tt.wantChatID, tt.wantThread)
+			}
+			if got := targetChatIDs(cfg.componentTargets()); !reflect.DeepEqual(got, tt.wantTargets) {
+				t.Errorf("targets = %v, want %v", got, tt.wantTargets)
+			}
+		})
+	}
+
+	cfg := &Config{ChatID: "@news", ErrorChatID: "@ops_team", HookChatIDs: map[string]string{"on_error": "@oncall"}}
+	cfg.applyHookChat(plugin.HookOnError)
+	if cfg.ChatID != "@oncall" {
+		t.Errorf("chat = %q, want the hook_chat_ids entry over error_chat_id", cfg.ChatID)
+	}
+}
+
+func TestExecuteErrorChatID(t *testing.T) {
+	var sent []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		sent = append(sent, msg.ChatID)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	config := map[string]any{
+		"bot_token":     "123:abc",
+		"chat_id":       "@news",
+		"error_chat_id": "@ops_team",
+	}
+	for _, hook := range []plugin.Hook{plugin.HookPostPublish, plugin.HookOnError} {
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook:    hook,
+			Config:  config,
+			Context: plugin.ReleaseContext{Version: "1.0.0"},
+		})
+		if err != nil || !resp.Success {
+			t.Fatalf("Execute(%s) = %+v, %v; want success", hook, resp, err)
+		}
+	}
+	if want := []string{"@news", "@ops_team"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+}
+
+func TestValidateHookChatIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr bool
+	}{
+		{"unset", nil, false},
+		{"known hooks", map[string]any{"on_error": "@ops_team", "post_version": "-1001234567890@7"}, false},
+		{"hyphenated hooks", map[string]any{"on-error": "@ops_team"}, false},
+		{"unknown hook", map[string]any{"pre_publish": "@ops_team"}, true},
+		{"invalid chat", map[string]any{"on_error": "not a chat"}, true},
+		{"not a string", map[string]any{"on_error": 42}, true},
+		{"not a map", "@ops_team", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateHookChatIDs(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("validateHookChatIDs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// permalinkReportErrorOutput records why the permalink report was not sent.
+var permalinkReportErrorOutput = registerDegradation("permalink_report_error", describeError("permalink report failed"))
+
+// permalink returns the t.me link to a message, or "" when the chat has no
+// links: only public chats (@username) and supergroups or channels
+// (-100...) do. A thread ID links into a forum topic; messages in the
+// General topic link like messages of a regular group.
+func permalink(chatID string, threadID, messageID int64) string {
+	if messageID == 0 {
+		return ""
+	}
+	var base string
+	switch {
+	case strings.HasPrefix(chatID, "@"):
+		base = "https://t.me/" + chatID[1:]
+	case strings.HasPrefix(chatID, "-100"):
+		base = "https://t.me/c/" + chatID[4:]
+	default:
+		return ""
+	}
+	if wireThreadID(threadID) != 0 {
+		base += "/" + strconv.FormatInt(threadID, 10)
+	}
+	return base + "/" + strconv.FormatInt(messageID, 10)
+}
+
+// recordPermalink appends the link to a delivered message to
+// outputs["permalinks"] and returns the recorded link. Messages in chats
+// without links are skipped, returning nil.
+func recordPermalink(outputs map[string]any, kind, chatID string, threadID, messageID int64) map[string]any {
+	url := permalink(chatID, threadID, messageID)
+	if url == "" {
+		return nil
+	}
+	link := map[string]any{
+		"kind":    kind,
+		"chat_id": chatID,
+		"url":     url,
+	}
+	links, _ := outputs["permalinks"].([]map[string]any)
+	outputs["permalinks"] = append(links, link)
+	return link
+}
+
+// labelGroup formats labels as the audience a permalink report is grouped
+// by, e.g. "audience=devs, team=core".
+func labelGroup(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// permalinkGroups groups links by audience in order of first appearance:
+// the labels of the target a link was sent to, or the configured labels.
+func permalinkGroups(links []map[string]any, labels map[string]string) []render.PermalinkGroup {
+	var groups []render.PermalinkGroup
+	index := map[string]int{}
+	for _, link := range links {
+		linkLabels, ok := link["labels"].(map[string]string)
+		if !ok {
+			linkLabels = labels
+		}
+		name := labelGroup(linkLabels)
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, render.PermalinkGroup{Labels: name})
+		}
+		groups[i].Links = append(groups[i].Links, render.Permalink{
+			Kind:   fmt.Sprint(link["kind"]),
+			ChatID: fmt.Sprint(link["chat_id"]),
+			URL:    fmt.Sprint(link["url"]),
+		})
+	}
+	return groups
+}
+
+// sendPermalinkReport sets permalink_group to the configured labels, and
+// when permalink_report is enabled sends the recorded permalinks to the
+// summary chat, grouped by the labels of each target. Nothing is sent in
+// dry-run mode or when no link was recorded.
+func (p *TelegramPlugin) sendPermalinkReport(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, resp *plugin.ExecuteResponse) {
+	links, _ := resp.Outputs["permalinks"].([]map[string]any)
+	if len(links) == 0 {
+		return
+	}
+	if group := labelGroup(cfg.Labels); group != "" {
+		resp.Outputs["permalink_group"] = group
+	}
+	if !cfg.PermalinkReport || cfg.SummaryChatID == "" || dryRun {
+		return
+	}
+
+	reportCfg := *cfg
+	reportCfg.ChatID = cfg.SummaryChatID
+	reportCfg.MessageThreadID = cfg.SummaryThreadID
+	reportCfg.ParseMode = ""
+	text := p.renderer(&reportCfg).Permalinks(releaseCtx.Version, permalinkGroups(links, cfg.Labels))
+	msg := newMessage(&reportCfg, text)
+	msg.DisableWebPagePreview = true
+	if _, err := p.deliver(ctx, &reportCfg, msg); err != nil {
+		resp.Outputs[permalinkReportErrorOutput] = errorText(err)
+		return
+	}
+	resp.Outputs["permalink_report_sent"] = true
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestPermalink(t *testing.T) {
+	tests := []struct {
+		name      string
+		chatID    string
+		threadID  int64
+		messageID int64
+		expected  string
+	}{
+		{"public chat", "@news", 0, 42, "https://t.me/news/42"},
+		{"supergroup", "-1001234567890", 0, 42, "https://t.me/c/1234567890/42"},
+		{"forum topic", "-1001234567890", 7, 42, "https://t.me/c/1234567890/7/42"},
+		{"general topic", "-1001234567890", 1, 42, "https://t.me/c/1234567890/42"},
+		{"private chat", "123456789", 0, 42, ""},
+		{"basic group", "-123456", 0, 42, ""},
+		{"unknown message", "@news", 0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := permalink(tt.chatID, tt.threadID, tt.messageID); got != tt.expected {
+				t.Errorf("permalink() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLabelGroup(t *testing.T) {
+	if got := labelGroup(map[string]string{"team": "core", "audience": "devs"}); got != "audience=devs, team=core" {
+		t.Errorf("labelGroup() = %q", got)
+	}
+	if got := labelGroup(nil); got != "" {
+		t.Errorf("labelGroup(nil) = %q, want empty", got)
+	}
+}
+
+func TestExecutePermalinkReport(t *testing.T) {
+	var sent []TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		sent = append(sent, msg)
+		result, _ := json.Marshal(TelegramSentMessage{MessageID: int64(40 + len(sent))})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":           "123:abc",
+			"chat_id":             "@news",
+			"forward_to_chat_ids": []any{"-1001234567890@7"},
+			"summary_chat_id":     "-1009876543210",
+			"permalink_report":    true,
+			"labels":              map[string]any{"audience": "devs", "team": "core"},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+
+	wantLinks := []map[string]any{
+		{"kind": "success", "chat_id": "@news", "url": "https://t.me/news/41"},
+		{"kind": "forward", "chat_id": "-1001234567890", "url": "https://t.me/c/1234567890/7/42"},
+	}
+	if got := resp.Outputs["permalinks"]; !reflect.DeepEqual(got, wantLinks) {
+		t.Errorf("permalinks = %v, want %v", got, wantLinks)
+	}
+	if got := resp.Outputs["permalink_group"]; got != "audience=devs, team=core" {
+		t.Errorf("permalink_group = %v", got)
+	}
+	if resp.Outputs["permalink_report_sent"] != true {
+		t.Fatalf("outputs = %v, want the report sent", resp.Outputs)
+	}
+
+	report := sent[len(sent)-1]
+	want := "🔗 Announcement links for 1.0.0\n\n" +
+		"audience=devs, team=core:\n" +
+		"• success @news: https://t.me/news/41\n" +
+		"• forward -1001234567890: https://t.me/c/1234567890/7/42\n"
+	if report.ChatID != "-1009876543210" || report.Text != want {
+		t.Errorf("report to %s = %q, want %q", report.ChatID, report.Text, want)
+	}
+}
+
+func TestExecutePermalinkReportGroupsTargets(t *testing.T) {
+	var sent []TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		sent = append(sent, msg)
+		result, _ := json.Marshal(TelegramSentMessage{MessageID: int64(40 + len(sent))})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token": "123:abc",
+			"chat_id":   "@news",
+			"targets": []any{
+				map[string]any{"chat_id": "@news_eu", "labels": map[string]any{"region": "eu"}},
+				map[string]any{"chat_id": "@news_mirror"},
+				map[string]any{"chat_id": "@news_de", "labels": map[string]any{"region": "eu"}},
+			},
+			"summary_chat_id":  "-1009876543210",
+			"permalink_report": true,
+			"labels":           map[string]any{"audience": "devs"},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success || resp.Outputs["permalink_report_sent"] != true {
+		t.Fatalf("Execute() = %+v, %v; want the report sent", resp, err)
+	}
+
+	report := sent[len(sent)-1]
+	want := "🔗 Announcement links for 1.0.0\n\n" +
+		"audience=devs:\n" +
+		"• success @news: https://t.me/news/41\n" +
+		"• success @news_mirror: https://t.me/news_mirror/43\n" +
+		"\n" +
+		"region=eu:\n" +
+		"• success @news_eu: https://t.me/news_eu/42\n" +
+		"• success @news_de: https://t.me/news_de/44\n"
+	if report.Text != want {
+		t.Errorf("report = %q, want %q", report.Text, want)
+	}
+}
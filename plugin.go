@@ -2,166 +2,634 @@
 package main
 
 import (
-	"bytes"
+	"cmp"
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"html"
+	"maps"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/relicta-tech/plugin-telegram/internal/render"
 	"github.com/relicta-tech/relicta-plugin-sdk/helpers"
 	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
-// Shared HTTP client for connection reuse across requests.
-var defaultHTTPClient = &http.Client{
-	Timeout: 30 * time.Second,
-	Transport: &http.Transport{
-		MaxIdleConns:        10,
-		MaxIdleConnsPerHost: 5,
-		IdleConnTimeout:     90 * time.Second,
-		TLSClientConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		},
-	},
-}
+// truncatedOutput records that the release notes were cut at
+// max_changelog_length.
+var truncatedOutput = registerDegradation("truncated", describeFlag("release notes truncated"))
 
 // TelegramPlugin implements the Telegram notification plugin.
-type TelegramPlugin struct{}
+type TelegramPlugin struct {
+	mu         sync.Mutex
+	chatTitles map[string]string
+	clock      clock
+	clients    map[clientKey]*http.Client
+	// breakers are the circuit breakers of each bot, keyed by bot and
+	// breaker settings.
+	breakers map[breakerKey]*circuitBreaker
+	// apiVersions are the Bot API versions detected from rejected requests,
+	// keyed by API base URL.
+	apiVersions map[string]apiVersion
+	// limiters pace the sends of each bot, keyed by bot and rates.
+	limiters map[limiterKey]*sendLimiter
+	// chatMigrations maps group chat IDs to the supergroups messages were
+	// sent to instead, until they are reported.
+	chatMigrations map[string]string
+}
 
-// Config represents the Telegram plugin configuration.
+// Config represents the Telegram plugin configuration. The config schema
+// reported by GetInfo is generated from its tags; see configSchema.
 type Config struct {
 	// BotToken is the Telegram bot token from @BotFather.
-	BotToken string `json:"bot_token,omitempty"`
+	BotToken string `json:"bot_token,omitempty" description:"Telegram bot token (or use TELEGRAM_BOT_TOKEN env)"`
+	// BotTokenSource fetches the bot token at run time instead.
+	BotTokenSource SecretSource `json:"bot_token_source" description:"Secret provider the bot token is fetched from at run time; takes precedence over bot_token"`
+	// APIURL is the Bot API server, e.g. a local server. Empty uses the
+	// public endpoint.
+	APIURL string `json:"api_url,omitempty" description:"Bot API server URL, e.g. a local server at http://localhost:8081; defaults to https://api.telegram.org"`
+	// BotAPIVersion is the Bot API version of a self-hosted server that
+	// predates fields the plugin sends. Empty assumes a current server.
+	BotAPIVersion string `json:"bot_api_version,omitempty" description:"Bot API version of a self-hosted server, e.g. 6.9; newer message fields are downgraded"`
 	// ChatID is the target chat ID (channel, group, or user).
-	ChatID string `json:"chat_id,omitempty"`
+	ChatID string `json:"chat_id,omitempty" description:"Chat ID or @channel_username; required unless chat_ids is set (or use TELEGRAM_CHAT_ID env)"`
 	// MessageThreadID is the thread ID for topic-based groups.
-	MessageThreadID int64 `json:"message_thread_id,omitempty"`
+	MessageThreadID int64 `json:"message_thread_id,omitempty" description:"Thread ID for topic-based groups"`
+	// Chats maps aliases to chats, so options holding chats can reference
+	// a chat by name.
+	Chats map[string]string `json:"chats,omitempty" description:"Chat aliases, e.g. ops: \"@myops\", usable wherever a chat is configured"`
+	// ChatIDs are more chats the notification is sent to through the primary
+	// bot. Without chat_id, the first one is the primary chat.
+	ChatIDs []string `json:"chat_ids,omitempty" description:"Chat IDs to send each notification to; the first is the primary chat when chat_id is not set"`
+	// ErrorChatID is the chat for error notifications; empty uses chat_id.
+	ErrorChatID string `json:"error_chat_id,omitempty" description:"Chat for error notifications, e.g. an ops chat; defaults to chat_id"`
+	// HookChatIDs maps hook names to the chat their notification is sent to
+	// instead of chat_id.
+	HookChatIDs map[string]string `json:"hook_chat_ids,omitempty" description:"Chat per hook, replacing chat_id for that hook's notification"`
+	// RouteByReleaseType sends releases of a type (major, minor, patch, or
+	// prerelease) to other chats than chat_id and chat_ids.
+	RouteByReleaseType map[string][]string `json:"route_by_release_type,omitempty" description:"Chat ID or list of chat IDs per release type (major, minor, patch, prerelease), replacing chat_id and chat_ids for those releases"`
+	// Targets are additional chats notifications are sent to, each
+	// optionally through its own bot.
+	Targets []Target `json:"targets,omitempty" description:"Additional chats to notify, each optionally through its own bot"`
 	// ParseMode is the message parse mode (MarkdownV2 or HTML).
-	ParseMode string `json:"parse_mode,omitempty"`
+	ParseMode string `json:"parse_mode,omitempty" description:"Message parse mode" enum:"MarkdownV2,HTML," default:"MarkdownV2"`
 	// DisableWebPagePreview disables link previews.
-	DisableWebPagePreview bool `json:"disable_web_page_preview"`
+	DisableWebPagePreview bool `json:"disable_web_page_preview" description:"Disable link previews" default:"true"`
+	// PreviewURLTemplate renders the URL whose preview card is shown on the
+	// success message, e.g. the release page. Setting it enables the preview.
+	PreviewURLTemplate string `json:"preview_url_template,omitempty" description:"Template for the URL shown as the success message link preview"`
+	// ShowAboveText places the link preview card above the message text.
+	ShowAboveText bool `json:"show_above_text" description:"Show the link preview above the message text" default:"false"`
 	// DisableNotification sends the message silently.
-	DisableNotification bool `json:"disable_notification"`
-	// NotifyOnSuccess sends notification on successful release.
-	NotifyOnSuccess bool `json:"notify_on_success"`
-	// NotifyOnError sends notification on failed release.
-	NotifyOnError bool `json:"notify_on_error"`
+	DisableNotification bool `json:"disable_notification" description:"Send silently" default:"false"`
+	// NotifyOn maps hook names to whether they send a notification. It is
+	// resolved from notify_on over the notify_on_* booleans and holds every
+	// hook in notifyHooks.
+	NotifyOn map[string]bool `json:"notify_on,omitempty" description:"Hooks that send notifications, keyed by hook name; overrides the notify_on_* options"`
+	// NotifyOnSuccess sends notification on successful release. Deprecated:
+	// use NotifyOn.
+	NotifyOnSuccess bool `json:"notify_on_success" description:"Notify on success (deprecated: use notify_on)" default:"true"`
+	// NotifyOnError sends notification on failed release. Deprecated: use
+	// NotifyOn.
+	NotifyOnError bool `json:"notify_on_error" description:"Notify on error (deprecated: use notify_on)" default:"true"`
+	// ErrorMessageThreadID is the forum topic for error notifications. It
+	// overrides MessageThreadID for errors only.
+	ErrorMessageThreadID int64 `json:"error_message_thread_id,omitempty" description:"Forum topic thread ID for error notifications"`
+	// TopicName is the name of a forum topic for notifications, created on
+	// first use, when message_thread_id is not set.
+	TopicName string `json:"topic_name,omitempty" description:"Forum topic for notifications, created on first use; message_thread_id takes precedence"`
+	// SeriesTopicName is the name of the forum topic of a release series,
+	// such as "Releases v{major}.x", created on first use.
+	SeriesTopicName string `json:"series_topic_name,omitempty" description:"Forum topic per release series, e.g. \"Releases v{major}.x\"; {major} and {minor} are replaced, and the topic is created on first use"`
+	// ErrorTopicName is the name of a forum topic for error notifications,
+	// created on first use and remembered in the state file.
+	ErrorTopicName string `json:"error_topic_name,omitempty" description:"Forum topic for error notifications, created on first use"`
+	// ErrorAck adds an Acknowledge button to error notifications and waits
+	// for someone to press it.
+	ErrorAck bool `json:"error_ack" description:"Add an Acknowledge button to error notifications and wait for a press" default:"false"`
+	// ErrorAckTimeout bounds the wait for an acknowledgment; zero means
+	// defaultAckTimeout.
+	ErrorAckTimeout time.Duration `json:"error_ack_timeout,omitempty" description:"How long to wait for an acknowledgment, e.g. \"5m\"; defaults to 2m"`
+	// NotifyOnVersion sends a notification once the next version is computed.
+	// Deprecated: use NotifyOn.
+	NotifyOnVersion bool `json:"notify_on_version" description:"Notify when the next version is computed (deprecated: use notify_on)" default:"false"`
+	// VersionTemplate is a custom template for the version notification.
+	VersionTemplate string `json:"version_template,omitempty" description:"Custom template for the version notification"`
 	// IncludeChangelog includes changelog in the notification.
-	IncludeChangelog bool `json:"include_changelog"`
+	IncludeChangelog bool `json:"include_changelog" description:"Include changelog" default:"false"`
 	// MaxChangelogLength is the maximum changelog length before truncation.
-	MaxChangelogLength int `json:"max_changelog_length"`
+	MaxChangelogLength int `json:"max_changelog_length" description:"Max changelog length" default:"3000"`
+	// ChangelogExcludePatterns are regular expressions; release note lines
+	// matching any of them are removed before rendering.
+	ChangelogExcludePatterns []string `json:"changelog_exclude_patterns,omitempty" description:"Regular expressions; matching release note lines are removed"`
+	// NormalizeWhitespace strips trailing whitespace and collapses runs of
+	// blank lines in the release notes and template output.
+	NormalizeWhitespace bool `json:"normalize_whitespace" description:"Strip trailing whitespace and collapse runs of blank lines in release notes and template output" default:"false"`
+	// ChangelogStyle is "full" (default) or "teaser", which shows only the
+	// first TeaserLines lines followed by a button linking to ReleaseURL.
+	ChangelogStyle string `json:"changelog_style,omitempty" description:"Full release notes, or a teaser with a button linking to release_url" enum:"full,teaser" default:"full"`
+	// TeaserLines is the number of release note lines shown in teaser style.
+	TeaserLines int `json:"teaser_lines" description:"Release note lines shown in teaser style" default:"5"`
+	// TeaserButtonText is the label of the teaser style button.
+	TeaserButtonText string `json:"teaser_button_text,omitempty" description:"Teaser style button label" default:"Read full changelog"`
+	// ChangesSummaryMode is "counts" (default) or "sampled", which also lists
+	// the top ChangesSampleSize commits of each category under its count.
+	ChangesSummaryMode string `json:"changes_summary_mode,omitempty" description:"Change counts only, or sampled to also list the top commits per category" enum:"counts,sampled" default:"counts"`
+	// ChangesSampleSize is the number of commits listed per category in
+	// sampled mode.
+	ChangesSampleSize int `json:"changes_sample_size" description:"Commits listed per category in sampled mode" default:"5"`
+	// ScopePriority lists the scopes sampled first, in order; other commits
+	// are ranked by how many commits share their scope.
+	ScopePriority []string `json:"scope_priority,omitempty" description:"Scopes sampled first, in order; others are ranked by scope frequency"`
+	// CommitFormat is how commit lines are rendered in sampled changes and
+	// breaking change lists.
+	CommitFormat string `json:"commit_format,omitempty" description:"Commit line format: pretty, raw conventional commit subjects, or a custom commit_template" enum:"pretty,raw,template" default:"pretty"`
+	// CommitTemplate renders each commit line when CommitFormat is template.
+	CommitTemplate string `json:"commit_template,omitempty" description:"Template rendering each commit line when commit_format is template"`
 	// Template is a custom message template.
-	Template string `json:"template,omitempty"`
+	Template string `json:"template,omitempty" description:"Custom message template"`
+	// TemplateFile is the path of a file holding the message template, read
+	// when the success notification is sent. It replaces Template.
+	TemplateFile string `json:"template_file,omitempty" description:"Path of a file holding the message template, relative to the repository root"`
+	// RawPayloadTemplate renders the complete sendMessage JSON body of the
+	// success notification, bypassing the message builders.
+	RawPayloadTemplate string `json:"raw_payload_template,omitempty" description:"Template producing the complete sendMessage JSON body of the success notification"`
+	// Templates maps hook names to the template of the notification the hook
+	// sends, overriding the other template options.
+	Templates map[string]string `json:"templates,omitempty" description:"Templates keyed by hook name, overriding template, error_template, and version_template"`
+	// ErrorTemplate is a custom template for the error notification.
+	ErrorTemplate string `json:"error_template,omitempty" description:"Custom template for the error notification"`
+	// ErrorTemplateFile is the path of a file holding the error template,
+	// read when the error notification is sent. It replaces ErrorTemplate.
+	ErrorTemplateFile string `json:"error_template_file,omitempty" description:"Path of a file holding the error notification template, relative to the repository root"`
+	// AutoRepairFormatting closes unterminated entities and flattens deep
+	// nesting in messages rendered from custom templates before sending.
+	AutoRepairFormatting bool `json:"auto_repair_formatting" description:"Repair unterminated or deeply nested formatting in custom template output" default:"false"`
+	// Language is the fallback chain of message languages; the first
+	// language that can render the whole message is used.
+	Language []string `json:"language,omitempty" description:"Message language or fallback chain, e.g. [\"pt-BR\", \"pt\", \"en\"]" default:"en"`
+	// Variables are extra values available to templates as {{.Variables.name}}.
+	Variables map[string]string `json:"variables,omitempty" description:"Extra values available to templates as {{.Variables.name}}"`
+	// Component names the released component or package in monorepo
+	// pipelines where several packages notify the same chat. It prefixes the
+	// version in headlines and separates the dedup and digest entries.
+	Component string `json:"component,omitempty" description:"Monorepo component or package name shown before the version, e.g. payments-service (or use TELEGRAM_COMPONENT env)"`
+	// ChangelogThread posts every success announcement as a reply to a pinned
+	// root message per chat, created on first use and remembered in the
+	// state file.
+	ChangelogThread bool `json:"changelog_thread" description:"Post announcements as replies to a pinned changelog root message" default:"false"`
+	// ChangelogThreadTitle is the text of the changelog thread root message.
+	ChangelogThreadTitle string `json:"changelog_thread_title,omitempty" description:"Text of the pinned changelog root message" default:"📜 Changelog"`
+	// LatestReleasePin keeps a pinned message per chat that is edited to
+	// show the latest release, besides the regular announcement.
+	LatestReleasePin bool `json:"latest_release_pin" description:"Keep a pinned latest release message per chat, edited in place with each release" default:"false"`
+	// LatestReleaseTemplate is the template of the pinned latest release
+	// message.
+	LatestReleaseTemplate string `json:"latest_release_template,omitempty" description:"Template of the pinned latest release message" default:"📌 Latest release: {{escape .Version}}"`
+	// ChangelogDocument posts the release notes as a Markdown document
+	// replying to the success notification.
+	ChangelogDocument bool `json:"changelog_document" description:"Post the release notes as a Markdown document replying to the success notification" default:"false"`
+	// ChangelogDocumentMaxBytes is the largest document part; longer release
+	// notes are split. Zero uses the Bot API server's limit.
+	ChangelogDocumentMaxBytes int `json:"changelog_document_max_bytes,omitempty" description:"Largest changelog document part in bytes; defaults to the server limit (50 MB, or 2000 MB with api_url)"`
+	// CompareStatsFile is a file of per-file insertions and deletions in the
+	// format of git diff --numstat, summarized in the success message.
+	CompareStatsFile string `json:"compare_stats_file,omitempty" description:"Path of per-file change stats in git diff --numstat format, listed as the most changed files in the success message"`
+	// CompareStatsDocument attaches the compare stats file as a document
+	// instead of listing the most changed files.
+	CompareStatsDocument bool `json:"compare_stats_document" description:"Attach compare_stats_file as a document replying to the success notification instead of listing the most changed files" default:"false"`
+	// QRCodeURLTemplate renders the download URL posted as a QR code photo
+	// replying to the success notification.
+	QRCodeURLTemplate string `json:"qr_code_url_template,omitempty" description:"Template for a download URL posted as a QR code photo replying to the success notification"`
+	// ReleaseURL is the release page URL used to deep link message sections.
+	ReleaseURL string `json:"release_url,omitempty" description:"Release page URL used to link message sections to their anchors"`
+	// StartedAt is when the release pipeline started, for showing how long
+	// the release took.
+	StartedAt string `json:"started_at,omitempty" description:"Pipeline start time as an RFC 3339 timestamp or Unix seconds; adds the release duration to success messages"`
+	// CircuitBreakerThreshold is the number of API errors within the window
+	// after which remaining sends are skipped. Zero disables the breaker.
+	CircuitBreakerThreshold int `json:"circuit_breaker_threshold" description:"API errors within the window before remaining sends are skipped (0 disables)" default:"0"`
+	// CircuitBreakerWindowSeconds is the window in which API errors are counted.
+	CircuitBreakerWindowSeconds int `json:"circuit_breaker_window_seconds" description:"Window in seconds for counting API errors" default:"60"`
+	// MaxRetries is how often a failed send is retried. The backoff between
+	// attempts is persisted in the state file, so a rerun of the hook
+	// continues it.
+	MaxRetries int `json:"max_retries" description:"Retries of a send that hit a rate limit, server error, or network failure; the backoff carries over to reruns of the hook" default:"0"`
+	// RetryBackoffSeconds is the wait before the first retry; it doubles
+	// per consecutive failure.
+	RetryBackoffSeconds int `json:"retry_backoff_seconds" description:"Wait before the first retry in seconds; doubles per consecutive failure" default:"2"`
+	// RetryMaxBackoffSeconds caps the wait between retries.
+	RetryMaxBackoffSeconds int `json:"retry_max_backoff_seconds" description:"Cap of the wait between retries in seconds" default:"300"`
+	// RetryJitter randomizes the waits between retries.
+	RetryJitter bool `json:"retry_jitter" description:"Randomize each wait between retries between half and all of it" default:"false"`
+	// MaxConcurrency is how many targets are sent to at once.
+	MaxConcurrency int `json:"max_concurrency" description:"Number of chats of chat_ids and targets sent to at once" default:"1"`
+	// MaxMessagesPerSecond paces the messages of a bot across all chats.
+	MaxMessagesPerSecond int `json:"max_messages_per_second" description:"Messages a bot sends per second across all chats (0 disables)" default:"30"`
+	// MaxGroupMessagesPerMinute paces the messages to each group or channel.
+	MaxGroupMessagesPerMinute int `json:"max_group_messages_per_minute" description:"Messages a bot sends per minute to each group or channel (0 disables)" default:"20"`
+	// BreakingFirst places breaking change subjects at the top of the message
+	// instead of after the change counts.
+	BreakingFirst bool `json:"breaking_first" description:"Show breaking change subjects at the top of the message" default:"true"`
+	// ForwardToChatIDs are mirror chats the success announcement is
+	// forwarded to after it was sent, keeping the "forwarded from" header.
+	// Entries accept the same forms as ChatID.
+	ForwardToChatIDs []string `json:"forward_to_chat_ids,omitempty" description:"Mirror chats the success announcement is forwarded to"`
+	// BreakingAlert sends a separate, always loud message listing only the
+	// breaking changes when a release has any.
+	BreakingAlert bool `json:"breaking_alert" description:"Send a separate loud message listing only the breaking changes" default:"false"`
+	// BreakingAlertChatID is the chat for the breaking changes alert. Empty
+	// uses ChatID.
+	BreakingAlertChatID string `json:"breaking_alert_chat_id,omitempty" description:"Chat for the breaking changes alert (defaults to chat_id)"`
+	// BreakingAlertThreadID is the thread for the alert in BreakingAlertChatID.
+	BreakingAlertThreadID int64 `json:"breaking_alert_thread_id,omitempty" description:"Thread for the breaking changes alert"`
+	// HeadlineRules escalate the success headline emoji by change counts.
+	HeadlineRules []render.HeadlineRule `json:"headline_rules,omitempty" description:"Headline emoji escalation rules; the first matching rule wins"`
+	// Sections is the ordered success message layout. Empty uses the default.
+	Sections []render.Section `json:"sections,omitempty" description:"Ordered success message sections: header, version_info, changes, breaking_changes, changelog, contributors, footer, or {\"template\": \"...\"} blocks"`
+	// ShowContributors thanks the commit authors and co-authors at the end of
+	// the default success message.
+	ShowContributors bool `json:"show_contributors" description:"Thank the commit authors and co-authors in the success message" default:"false"`
+	// ContributorHandles maps contributor emails or names to Telegram
+	// usernames used to mention them.
+	ContributorHandles map[string]string `json:"contributor_handles,omitempty" description:"Telegram usernames of contributors, keyed by commit author email or name"`
+	// HTTP tunes the transport used for Bot API requests.
+	HTTP HTTPConfig `json:"http" description:"HTTP transport tuning"`
+	// FaultInjection simulates Bot API outages in staging pipelines.
+	FaultInjection FaultInjection `json:"fault_injection" description:"Simulated Bot API outages for resilience testing; requires TELEGRAM_ALLOW_FAULT_INJECTION=true"`
+	// RunID identifies the external CI run; when set, repeated deliveries for
+	// the same run, hook, version, and chat are skipped.
+	RunID string `json:"run_id,omitempty" description:"External CI run ID used to skip duplicate deliveries (or use TELEGRAM_RUN_ID env)"`
+	// Idempotent skips notifications already delivered for the same hook,
+	// version, and chat, whichever run sent them.
+	Idempotent bool `json:"idempotent" description:"Skip notifications already delivered for the same hook, version, and chat within dedup_ttl_seconds, even without run_id" default:"false"`
+	// CorrelationID ties the notifications of a run to the pipeline run that
+	// produced them. Empty uses RunID, or a generated ID.
+	CorrelationID string `json:"correlation_id,omitempty" description:"ID tracing notifications back to the pipeline run, reported in Outputs and receipts (or use TELEGRAM_CORRELATION_ID env); defaults to run_id or a generated ID"`
+	// CorrelationFooter appends a hidden link carrying CorrelationID to
+	// MarkdownV2 and HTML notifications.
+	CorrelationFooter bool `json:"correlation_footer" description:"Append a hidden link carrying correlation_id to MarkdownV2 and HTML notifications" default:"false"`
+	// DedupTTLSeconds is how long delivery records are kept for deduplication.
+	DedupTTLSeconds int `json:"dedup_ttl_seconds" description:"How long delivery records are kept for deduplication" default:"86400"`
+	// StateFile is the path of the persisted plugin state.
+	StateFile string `json:"state_file,omitempty" description:"Path of the persisted plugin state" default:".relicta/telegram-state.json"`
+	// ReceiptsFile is the path of the append-only JSON Lines file that a
+	// receipt of every sent notification is written to. Empty disables it.
+	ReceiptsFile string `json:"receipts_file,omitempty" description:"Path of a JSON Lines file receiving a receipt per sent notification, e.g. .relicta/receipts.jsonl"`
+	// PersistChatMigrations remembers group to supergroup migrations in the
+	// state file.
+	PersistChatMigrations bool `json:"persist_chat_migrations" description:"Remember groups upgraded to supergroups in the state file and send to the supergroup right away" default:"false"`
+	// MetricsFile is the path of the per-chat send metrics, kept for trend
+	// reporting. Empty disables it.
+	MetricsFile string `json:"metrics_file,omitempty" description:"Path of a JSON file with per-chat send metrics, e.g. .relicta/telegram-metrics.json; Validate warns about chats that keep failing"`
+	// RememberAnnouncements keeps the success announcement of each version
+	// in the state file, so it can be marked as yanked later.
+	RememberAnnouncements bool `json:"remember_announcements" description:"Remember success announcements in the state file so yank_version can mark them as yanked" default:"false"`
+	// YankVersion marks the remembered announcement of a version as yanked
+	// instead of sending the hook's notification.
+	YankVersion string `json:"yank_version,omitempty" description:"Version whose remembered announcement is marked as yanked instead of sending a notification"`
+	// YankReason is posted as a reply to the yanked announcement.
+	YankReason string `json:"yank_reason,omitempty" description:"Reason posted as a reply to the yanked announcement"`
+	// SpoolDir keeps notifications that could not be delivered because
+	// Telegram was unreachable until a later run delivers them. Empty
+	// disables spooling.
+	SpoolDir string `json:"spool_dir,omitempty" description:"Directory where notifications are kept while Telegram is unreachable and delivered on the next run, e.g. .relicta/telegram-spool"`
+	// DigestSchedule collects success announcements into a daily or weekly
+	// digest sent on the first hook execution after the period ends.
+	DigestSchedule string `json:"digest_schedule,omitempty" description:"Collect releases into one daily or weekly digest instead of announcing each release" enum:"daily,weekly,"`
+	// ResolveChatTitle looks up the chat title via getChat for dry-run output and Outputs.
+	ResolveChatTitle bool `json:"resolve_chat_title" description:"Resolve the chat title via getChat for dry-run output" default:"false"`
+	// SummaryChatID is an admin chat that receives a summary of which chats
+	// were notified after each success or error notification.
+	SummaryChatID string `json:"summary_chat_id,omitempty" description:"Admin chat that receives a summary of the notified chats"`
+	// SummaryThreadID is the thread for the summary in SummaryChatID.
+	SummaryThreadID int64 `json:"summary_thread_id,omitempty" description:"Thread for the summary"`
+	// PermalinkReport sends the links to the delivered messages to
+	// SummaryChatID, headed by the configured labels.
+	PermalinkReport bool `json:"permalink_report" description:"Send links to the delivered messages, grouped by labels, to summary_chat_id" default:"false"`
+	// MaxSendDuration is the notification latency objective: the longest
+	// acceptable time from hook start to the final successful send. Zero
+	// disables the check.
+	MaxSendDuration time.Duration `json:"max_send_duration,omitempty" description:"Longest acceptable time from hook start to the final send, e.g. \"10s\"; exceeding it adds a warning"`
+	// Strict fails the hook when a notification was degraded: truncated,
+	// sent with a formatting fallback, or only partially delivered.
+	Strict bool `json:"strict" description:"Fail the hook when a notification was degraded" default:"false"`
+	// StrictEnv rejects configs that reference unset environment variables
+	// with ${NAME}; otherwise they expand to an empty string.
+	StrictEnv bool `json:"strict_env" description:"Fail when a ${NAME} reference in a config value names an unset environment variable" default:"false"`
+	// Labels are free-form metadata (team, region, audience) copied into
+	// Outputs for reporting.
+	Labels map[string]string `json:"labels,omitempty" description:"Free-form labels (team, region, audience) copied into Outputs"`
+	// Profile names the profile of Profiles to apply; empty falls back to
+	// the TELEGRAM_PROFILE environment variable.
+	Profile string `json:"profile,omitempty" description:"Profile to apply, e.g. prod; defaults to the TELEGRAM_PROFILE environment variable"`
+	// Profiles maps profile names to config values that override the rest of
+	// the config when the profile is selected.
+	Profiles map[string]map[string]any `json:"profiles,omitempty" description:"Config overrides per environment, such as dev, staging, and prod"`
+	// Debug echoes the request the plugin received in the outputs.
+	Debug bool `json:"debug" description:"Echo the hook, config (secrets masked), and release context received from Relicta in the outputs" default:"false"`
+
+	// unsetEnv lists the unset environment variables referenced by the config.
+	unsetEnv []missingEnv
+	// chatTargets are the chats of chat_ids other than the primary chat.
+	chatTargets []Target
+	// delivered are the recipients an earlier run already delivered the
+	// notification to, which are not sent to again.
+	delivered map[recipient]bool
+	// handled collects the recipients the notification was delivered or
+	// spooled for; nil when deliveries are not deduplicated.
+	handled map[recipient]bool
+	// route is the route_by_release_type key the chats were routed by.
+	route string
+	// topicError is why the topic_name topic could not be resolved.
+	topicError string
+	// compareStats are the parsed compare stats listed in the success
+	// message.
+	compareStats []render.FileStat
+	// botPool is the bot token of a target with its own bot. Such targets
+	// get their own connection pool and circuit breaker; empty shares the
+	// primary bot's.
+	botPool string
 }
 
 // TelegramMessage represents a sendMessage request.
 type TelegramMessage struct {
-	ChatID                string `json:"chat_id"`
-	Text                  string `json:"text"`
-	ParseMode             string `json:"parse_mode,omitempty"`
-	MessageThreadID       int64  `json:"message_thread_id,omitempty"`
-	DisableWebPagePreview bool   `json:"disable_web_page_preview,omitempty"`
-	DisableNotification   bool   `json:"disable_notification,omitempty"`
+	ChatID                string                `json:"chat_id"`
+	Text                  string                `json:"text"`
+	ParseMode             string                `json:"parse_mode,omitempty"`
+	MessageThreadID       int64                 `json:"message_thread_id,omitempty"`
+	DisableWebPagePreview bool                  `json:"disable_web_page_preview,omitempty"`
+	DisableNotification   bool                  `json:"disable_notification,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	LinkPreviewOptions    *LinkPreviewOptions   `json:"link_preview_options,omitempty"`
+	ReplyParameters       *ReplyParameters      `json:"reply_parameters,omitempty"`
+	// ReplyToMessageID and AllowSendingWithoutReply are the reply fields of
+	// servers older than Bot API 7.0; see downgradePayload.
+	ReplyToMessageID         int64 `json:"reply_to_message_id,omitempty"`
+	AllowSendingWithoutReply bool  `json:"allow_sending_without_reply,omitempty"`
+}
+
+// TelegramForward represents a forwardMessage request.
+type TelegramForward struct {
+	ChatID              string `json:"chat_id"`
+	FromChatID          string `json:"from_chat_id"`
+	MessageID           int64  `json:"message_id"`
+	MessageThreadID     int64  `json:"message_thread_id,omitempty"`
+	DisableNotification bool   `json:"disable_notification,omitempty"`
+}
+
+// TelegramDocument represents the fields of a sendDocument or sendPhoto
+// request besides the uploaded file.
+type TelegramDocument struct {
+	ChatID              string
+	MessageThreadID     int64
+	Caption             string
+	DisableNotification bool
+	ReplyParameters     *ReplyParameters
+}
+
+// ReplyParameters makes a message a reply to another message.
+type ReplyParameters struct {
+	MessageID                int64 `json:"message_id"`
+	AllowSendingWithoutReply bool  `json:"allow_sending_without_reply,omitempty"`
+}
+
+// LinkPreviewOptions controls the link preview card of a message.
+type LinkPreviewOptions struct {
+	IsDisabled    bool   `json:"is_disabled,omitempty"`
+	URL           string `json:"url,omitempty"`
+	ShowAboveText bool   `json:"show_above_text,omitempty"`
+}
+
+// InlineKeyboardMarkup represents an inline keyboard attached to a message.
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton represents a single inline keyboard button.
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
 }
 
 // TelegramResponse represents a Telegram API response.
 type TelegramResponse struct {
-	OK          bool   `json:"ok"`
-	Description string `json:"description,omitempty"`
-	ErrorCode   int    `json:"error_code,omitempty"`
+	OK          bool                `json:"ok"`
+	Description string              `json:"description,omitempty"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Result      json.RawMessage     `json:"result,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+// ResponseParameters describes why a request failed and how to recover.
+type ResponseParameters struct {
+	RetryAfter      int   `json:"retry_after,omitempty"`
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+}
+
+// TelegramChat represents the subset of a getChat result used by the plugin.
+type TelegramChat struct {
+	ID       int64  `json:"id"`
+	Type     string `json:"type"`
+	Title    string `json:"title,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// TelegramUser represents the subset of a getMe result used by the plugin.
+type TelegramUser struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+}
+
+// TelegramUpdate is an incoming update from getUpdates. Only the update
+// kinds the plugin polls for are decoded; their payloads are left raw for
+// the feature handling them.
+type TelegramUpdate struct {
+	UpdateID        int64           `json:"update_id"`
+	Message         json.RawMessage `json:"message,omitempty"`
+	CallbackQuery   json.RawMessage `json:"callback_query,omitempty"`
+	MessageReaction json.RawMessage `json:"message_reaction,omitempty"`
+}
+
+// TelegramCallbackQuery represents the subset of a callback query, sent
+// when an inline keyboard button is pressed, used by the plugin.
+type TelegramCallbackQuery struct {
+	ID      string               `json:"id"`
+	From    TelegramUser         `json:"from"`
+	Message *TelegramSentMessage `json:"message,omitempty"`
+	Data    string               `json:"data,omitempty"`
+}
+
+// TelegramSentMessage represents the subset of a sent message used by the
+// plugin.
+type TelegramSentMessage struct {
+	MessageID int64 `json:"message_id"`
+}
+
+// TelegramForumTopic represents the subset of a createForumTopic result used
+// by the plugin.
+type TelegramForumTopic struct {
+	MessageThreadID int64  `json:"message_thread_id"`
+	Name            string `json:"name"`
 }
 
 // GetInfo returns plugin metadata.
 func (p *TelegramPlugin) GetInfo() plugin.Info {
 	return plugin.Info{
-		Name:        "telegram",
-		Version:     "1.0.0",
-		Description: "Send Telegram notifications for releases",
-		Author:      "Relicta Team",
-		Hooks: []plugin.Hook{
-			plugin.HookPostPublish,
-			plugin.HookOnSuccess,
-			plugin.HookOnError,
-		},
-		ConfigSchema: `{
-			"type": "object",
-			"properties": {
-				"bot_token": {"type": "string", "description": "Telegram bot token (or use TELEGRAM_BOT_TOKEN env)"},
-				"chat_id": {"type": "string", "description": "Chat ID or @channel_username"},
-				"message_thread_id": {"type": "integer", "description": "Thread ID for topic-based groups"},
-				"parse_mode": {"type": "string", "enum": ["MarkdownV2", "HTML", ""], "description": "Message parse mode", "default": "MarkdownV2"},
-				"disable_web_page_preview": {"type": "boolean", "description": "Disable link previews", "default": true},
-				"disable_notification": {"type": "boolean", "description": "Send silently", "default": false},
-				"notify_on_success": {"type": "boolean", "description": "Notify on success", "default": true},
-				"notify_on_error": {"type": "boolean", "description": "Notify on error", "default": true},
-				"include_changelog": {"type": "boolean", "description": "Include changelog", "default": false},
-				"max_changelog_length": {"type": "integer", "description": "Max changelog length", "default": 3000},
-				"template": {"type": "string", "description": "Custom message template"}
-			},
-			"required": ["chat_id"]
-		}`,
+		Name:         "telegram",
+		Version:      "1.0.0",
+		Description:  "Send Telegram notifications for releases",
+		Author:       "Relicta Team",
+		Hooks:        notifyHooks,
+		ConfigSchema: configSchema,
 	}
 }
 
 // Execute runs the plugin for a given hook.
 func (p *TelegramPlugin) Execute(ctx context.Context, req plugin.ExecuteRequest) (*plugin.ExecuteResponse, error) {
+	ctx = withHookStart(ctx, p.now())
 	cfg := p.parseConfig(req.Config)
-
-	switch req.Hook {
-	case plugin.HookPostPublish, plugin.HookOnSuccess:
-		if !cfg.NotifyOnSuccess {
-			return &plugin.ExecuteResponse{
-				Success: true,
-				Message: "Success notification disabled",
-			}, nil
+	if cfg.CorrelationID == "" {
+		cfg.CorrelationID = cmp.Or(cfg.RunID, newCorrelationID())
+	}
+	resp, err := p.execute(ctx, cfg, req)
+	if resp != nil {
+		if resp.Outputs == nil {
+			resp.Outputs = make(map[string]any)
 		}
-		return p.sendSuccessNotification(ctx, cfg, req.Context, req.DryRun)
-
-	case plugin.HookOnError:
-		if !cfg.NotifyOnError {
-			return &plugin.ExecuteResponse{
-				Success: true,
-				Message: "Error notification disabled",
-			}, nil
+		resp.Outputs["correlation_id"] = cfg.CorrelationID
+		if cfg.Debug {
+			resp.Outputs["debug"] = debugEcho(req)
 		}
-		return p.sendErrorNotification(ctx, cfg, req.Context, req.DryRun)
+	}
+	return resp, err
+}
 
-	default:
+// execute handles a hook with the parsed config.
+func (p *TelegramPlugin) execute(ctx context.Context, cfg *Config, req plugin.ExecuteRequest) (*plugin.ExecuteResponse, error) {
+	if cfg.StrictEnv && len(cfg.unsetEnv) > 0 {
 		return &plugin.ExecuteResponse{
-			Success: true,
-			Message: fmt.Sprintf("Hook %s not handled", req.Hook),
+			Success: false,
+			Error:   missingEnvError(cfg.unsetEnv).Error(),
 		}, nil
 	}
-}
+	// Broken TLS files fail the hook here rather than as a send failure
+	// that is retried or spooled.
+	if _, err := p.httpClient(cfg); err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   errorText(err),
+		}, nil
+	}
+	if err := p.resolveBotToken(ctx, cfg); err != nil {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   errorText(err),
+		}, nil
+	}
+	if cfg.YankVersion != "" {
+		return p.yankRelease(ctx, cfg, req.DryRun)
+	}
 
-// sendSuccessNotification sends a success notification.
-func (p *TelegramPlugin) sendSuccessNotification(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
-	var text string
+	// Invalid patterns are reported by Validate.
+	if patterns, err := compileExcludePatterns(cfg.ChangelogExcludePatterns); err == nil {
+		req.Context.ReleaseNotes = render.ExcludeLines(req.Context.ReleaseNotes, patterns)
+	}
 
-	if cfg.Template != "" {
-		// Use custom template
-		var err error
-		text, err = renderTemplate(cfg.Template, releaseCtx)
+	cfg.applyReleaseTypeRoute(req.Context)
+	cfg.applyHookChat(req.Hook)
+	cfg.applyHookTemplate(req.Hook)
+	p.applyTopicName(ctx, cfg, req.Hook, req.Context, req.DryRun)
+
+	resp, err := p.flushingSpool(ctx, cfg, req.DryRun, func() (*plugin.ExecuteResponse, error) {
+		return p.flushingDigest(ctx, cfg, req.DryRun, func() (*plugin.ExecuteResponse, error) {
+			return p.dispatch(ctx, cfg, req)
+		})
+	})
+	if req.DryRun {
+		return resp, err
+	}
+	// Strict mode is applied to the notification before its delivery is
+	// recorded, and again here for the spool and digest flushes and the
+	// delivery state. Dry runs flush nothing.
+	return cfg.enforceStrict(resp, err)
+}
+
+// dispatch sends the notification for req.Hook.
+func (p *TelegramPlugin) dispatch(ctx context.Context, cfg *Config, req plugin.ExecuteRequest) (*plugin.ExecuteResponse, error) {
+	switch req.Hook {
+	case plugin.HookPostPublish, plugin.HookOnSuccess:
+		if !cfg.notifies(req.Hook) {
+			return skippedResponse(cfg, "Success notification disabled", skipNotifyOnDisabled, notifyOnRule(req.Hook), nil), nil
+		}
+		tmpl, err := templateSource(cfg.Template, cfg.TemplateFile)
 		if err != nil {
 			return &plugin.ExecuteResponse{
 				Success: false,
-				Error:   fmt.Sprintf("failed to render template: %v", err),
+				Error:   errorText(err),
 			}, nil
 		}
-	} else {
-		// Build default message
-		text = p.buildSuccessMessage(cfg, releaseCtx)
+		cfg.Template = tmpl
+		if cfg.DigestSchedule != "" {
+			return p.addToDigest(cfg, req.Context, req.DryRun)
+		}
+		return p.deduplicated(cfg, req, func() (*plugin.ExecuteResponse, error) {
+			return cfg.enforceStrict(p.sendSuccessNotification(ctx, cfg, req.Context, req.DryRun))
+		})
+
+	case plugin.HookPostVersion:
+		if !cfg.notifies(req.Hook) {
+			return skippedResponse(cfg, "Version notification disabled", skipNotifyOnDisabled, notifyOnRule(req.Hook), nil), nil
+		}
+		return p.deduplicated(cfg, req, func() (*plugin.ExecuteResponse, error) {
+			return cfg.enforceStrict(p.sendVersionNotification(ctx, cfg, req.Context, req.DryRun))
+		})
+
+	case plugin.HookOnError:
+		if !cfg.notifies(req.Hook) {
+			return skippedResponse(cfg, "Error notification disabled", skipNotifyOnDisabled, notifyOnRule(req.Hook), nil), nil
+		}
+		return p.deduplicated(cfg, req, func() (*plugin.ExecuteResponse, error) {
+			return cfg.enforceStrict(p.sendErrorNotification(ctx, cfg, req.Context, req.DryRun))
+		})
+
+	default:
+		return skippedResponse(cfg, fmt.Sprintf("Hook %s not handled", req.Hook), skipHookNotHandled, "hook "+hookKey(req.Hook), nil), nil
 	}
+}
+
+// notification is a rendered notification ready to be delivered.
+type notification struct {
+	// kind names the notification in response messages, e.g. "success".
+	kind string
+	// msg is the message to send.
+	msg TelegramMessage
+	// fallbacks are tried when Telegram rejects the message formatting.
+	fallbacks []deliveryFallback
+	// raw is a complete sendMessage body from raw_payload_template. When set
+	// it is sent instead of msg, whose text only feeds the outputs.
+	raw map[string]any
+	// outputs are added to the response outputs.
+	outputs map[string]any
+	// rerender renders the notification again for a target with a
+	// parse_mode or language of its own. Nil sends msg to every target.
+	rerender func(target Target) (notification, error)
+}
 
-	msg := TelegramMessage{
+// newMessage creates a message for the configured chat.
+func newMessage(cfg *Config, text string) TelegramMessage {
+	return TelegramMessage{
 		ChatID:                cfg.ChatID,
 		Text:                  text,
 		ParseMode:             cfg.ParseMode,
@@ -169,281 +637,781 @@ func (p *TelegramPlugin) sendSuccessNotification(ctx context.Context, cfg *Confi
 		DisableWebPagePreview: cfg.DisableWebPagePreview,
 		DisableNotification:   cfg.DisableNotification,
 	}
+}
+
+// applyLinkPreview sets the link preview options of msg when a preview URL
+// or placement is configured. The options replace disable_web_page_preview;
+// a preview URL always enables the preview.
+func (p *TelegramPlugin) applyLinkPreview(cfg *Config, msg *TelegramMessage, releaseCtx plugin.ReleaseContext) error {
+	if cfg.PreviewURLTemplate == "" && !cfg.ShowAboveText {
+		return nil
+	}
+
+	opts := &LinkPreviewOptions{ShowAboveText: cfg.ShowAboveText}
+	if cfg.PreviewURLTemplate != "" {
+		url, err := p.renderTemplate(cfg, cfg.PreviewURLTemplate, releaseCtx)
+		if err != nil {
+			return err
+		}
+		opts.URL = strings.TrimSpace(url)
+	} else {
+		opts.IsDisabled = cfg.DisableWebPagePreview
+	}
+
+	msg.DisableWebPagePreview = false
+	msg.LinkPreviewOptions = opts
+	return nil
+}
+
+// notify delivers n, or describes it in dry-run mode.
+func (p *TelegramPlugin) notify(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, n notification) (*plugin.ExecuteResponse, error) {
+	var timing sendTiming
+	ready := p.now()
+	if start, ok := hookStart(ctx); ok {
+		timing.render = ready.Sub(start)
+	}
+
+	title := p.chatTitle(ctx, cfg)
+	outputs := map[string]any{
+		"chat_id": cfg.ChatID,
+		"version": releaseCtx.Version,
+	}
+	if cfg.Component != "" {
+		outputs["component"] = cfg.Component
+	}
+	if cfg.route != "" {
+		outputs["route"] = cfg.route
+	}
+	if cfg.topicError != "" {
+		outputs[topicErrorOutput] = cfg.topicError
+	}
+	if cfg.Profile != "" {
+		outputs["profile"] = cfg.Profile
+	}
+	if title != "" {
+		outputs["chat_title"] = title
+	}
+	maps.Copy(outputs, n.outputs)
+	outputs = addLabels(outputs, cfg.Labels)
 
 	if dryRun {
+		outputs["message_length"] = len(n.msg.Text)
+		if targets := cfg.componentTargets(); len(targets) > 0 {
+			outputs["targets"] = targetChatIDs(targets)
+		}
+		message := fmt.Sprintf("Would send Telegram %s notification", n.kind)
+		if title != "" {
+			message += " to " + describeChat(title, cfg.ChatID)
+		}
 		return &plugin.ExecuteResponse{
 			Success: true,
-			Message: "Would send Telegram success notification",
-			Outputs: map[string]any{
-				"chat_id":        cfg.ChatID,
-				"version":        releaseCtx.Version,
-				"message_length": len(text),
-			},
+			Message: message,
+			Outputs: outputs,
 		}, nil
 	}
 
-	if err := p.sendMessage(ctx, cfg.BotToken, msg); err != nil {
+	if cfg.delivered[cfg.primary()] {
+		// An earlier run reached the primary chat; only the chats it
+		// missed are sent to.
+		receipts := p.notifyTargets(ctx, cfg, n, outputs)
+		p.reportChatMigrations(outputs)
+		p.writeReceipts(cfg, releaseCtx.Version, receipts, outputs)
 		return &plugin.ExecuteResponse{
-			Success: false,
-			Error:   fmt.Sprintf("failed to send Telegram message: %v", err),
+			Success: true,
+			Message: fmt.Sprintf("Sent Telegram %s notification to the chats an earlier run missed", n.kind),
+			Outputs: outputs,
 		}, nil
 	}
 
+	timing.queue = p.now().Sub(ready)
+	var sent delivery
+	var err error
+	if n.raw != nil {
+		sent, err = p.deliverRaw(ctx, cfg, n.raw)
+	} else {
+		sent, err = p.deliverWithFallbacks(ctx, cfg, n.msg, n.fallbacks)
+	}
+	p.recordMetrics(cfg, []Target{{ChatID: cfg.ChatID}}, []delivery{sent}, []error{err})
+	if err != nil && cfg.SpoolDir != "" && spoolable(err) {
+		if name, spoolErr := p.spoolNotification(cfg, n, 0); spoolErr == nil {
+			cfg.markHandled(cfg.primary())
+			recordDelivery(outputs, n.kind, cfg.ChatID, err)
+			outputs[spooledOutput] = name
+			// The other chats are sent to all the same, each spooled on
+			// its own if it cannot be reached either.
+			receipts := p.notifyTargets(ctx, cfg, n, outputs)
+			p.reportChatMigrations(outputs)
+			p.writeReceipts(cfg, releaseCtx.Version, receipts, outputs)
+			return &plugin.ExecuteResponse{
+				Success: true,
+				Message: fmt.Sprintf("Telegram is unreachable; spooled %s notification for the next run", n.kind),
+				Outputs: outputs,
+			}, nil
+		}
+	}
+	if err != nil {
+		resp := sendFailure(err)
+		if resp.Outputs == nil {
+			resp.Outputs = map[string]any{}
+		}
+		recordDelivery(resp.Outputs, n.kind, cfg.ChatID, err)
+		// The other chats are notified all the same; only the first one
+		// fails the hook.
+		receipts := p.notifyTargets(ctx, cfg, n, resp.Outputs)
+		p.reportChatMigrations(resp.Outputs)
+		p.writeReceipts(cfg, releaseCtx.Version, receipts, resp.Outputs)
+		resp.Outputs = addLabels(resp.Outputs, cfg.Labels)
+		return resp, nil
+	}
+	cfg.markHandled(cfg.primary())
+	markDegraded(outputs, sent)
+	recordDelivery(outputs, n.kind, cfg.ChatID, nil)
+	recordPermalink(outputs, n.kind, cfg.ChatID, n.msg.MessageThreadID, sent.messageID)
+	if sent.messageID != 0 {
+		outputs["message_id"] = sent.messageID
+	}
+	var receipts []receipt
+	if sent.messageID != 0 {
+		receipts = append(receipts, newReceipt(n, cfg.ChatID, n.msg.MessageThreadID, sent.messageID))
+	}
+	if cfg.RememberAnnouncements && n.kind == "success" && sent.messageID != 0 {
+		if err := p.rememberAnnouncement(cfg, releaseCtx.Version, newAnnouncement(n, cfg.ChatID, sent.messageID, p.now())); err != nil {
+			outputs[rememberAnnouncementErrorOutput] = errorText(err)
+		}
+	}
+	receipts = append(receipts, p.notifyTargets(ctx, cfg, n, outputs)...)
+	p.reportChatMigrations(outputs)
+	p.writeReceipts(cfg, releaseCtx.Version, receipts, outputs)
+	if cfg.MaxSendDuration > 0 {
+		timing.api, timing.retries = sent.api, sent.retries
+		checkSendDuration(outputs, cfg.MaxSendDuration, timing)
+	}
+
 	return &plugin.ExecuteResponse{
 		Success: true,
-		Message: "Sent Telegram success notification",
-		Outputs: map[string]any{
-			"chat_id": cfg.ChatID,
-			"version": releaseCtx.Version,
-		},
+		Message: fmt.Sprintf("Sent Telegram %s notification", n.kind),
+		Outputs: outputs,
 	}, nil
 }
 
-// sendErrorNotification sends an error notification.
-func (p *TelegramPlugin) sendErrorNotification(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
-	text := p.buildErrorMessage(cfg, releaseCtx)
+// sendSuccessNotification sends a success notification.
+func (p *TelegramPlugin) sendSuccessNotification(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	if cfg.RawPayloadTemplate != "" {
+		return p.sendRawSuccessNotification(ctx, cfg, releaseCtx, dryRun)
+	}
 
-	msg := TelegramMessage{
-		ChatID:                cfg.ChatID,
-		Text:                  text,
-		ParseMode:             cfg.ParseMode,
-		MessageThreadID:       cfg.MessageThreadID,
-		DisableWebPagePreview: cfg.DisableWebPagePreview,
-		DisableNotification:   false, // Always notify on error
+	var text string
+	outputs := map[string]any{}
+	msgCfg := cfg
+
+	if cfg.Template != "" {
+		// Use custom template
+		fm, tmpl, err := parseFrontMatter(cfg.Template)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("invalid template front matter: %v", err),
+			}, nil
+		}
+		msgCfg = fm.apply(cfg)
+		text, err = p.renderTemplate(msgCfg, tmpl, releaseCtx)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to render template: %v", err),
+			}, nil
+		}
+		text = checkTemplateFormatting(msgCfg, text, outputs)
+	} else {
+		// Build default message
+		text = p.buildSuccessMessage(withCompareStats(cfg, outputs), releaseCtx)
 	}
 
-	if dryRun {
+	msg := newMessage(msgCfg, text)
+	if err := p.applyLinkPreview(msgCfg, &msg, releaseCtx); err != nil {
 		return &plugin.ExecuteResponse{
-			Success: true,
-			Message: "Would send Telegram error notification",
-			Outputs: map[string]any{
-				"chat_id": cfg.ChatID,
-				"version": releaseCtx.Version,
-			},
+			Success: false,
+			Error:   fmt.Sprintf("failed to render preview URL template: %v", err),
 		}, nil
 	}
+	if cfg.Template == "" && cfg.ChangelogStyle == render.ChangelogStyleTeaser && cfg.ReleaseURL != "" {
+		msg.ReplyMarkup = &InlineKeyboardMarkup{
+			InlineKeyboard: [][]InlineKeyboardButton{{
+				{Text: cfg.TeaserButtonText, URL: cfg.ReleaseURL},
+			}},
+		}
+	}
 
-	if err := p.sendMessage(ctx, cfg.BotToken, msg); err != nil {
+	if cfg.Template == "" && p.renderer(cfg).Truncated(releaseCtx) {
+		outputs[truncatedOutput] = true
+	}
+	if cfg.ChangelogThread && !cfg.delivered[cfg.primary()] {
+		rootID, err := p.changelogRoot(ctx, cfg, dryRun)
+		if err != nil {
+			outputs[changelogThreadErrorOutput] = errorText(err)
+		}
+		if rootID != 0 {
+			msg.ReplyParameters = &ReplyParameters{MessageID: rootID, AllowSendingWithoutReply: true}
+			outputs["reply_to_message_id"] = rootID
+		}
+	}
+
+	resp, err := p.notify(ctx, cfg, releaseCtx, dryRun, p.withRerender(cfg, plugin.HookPostPublish, releaseCtx, notification{
+		kind:      "success",
+		msg:       msg,
+		fallbacks: p.successFallbacks(cfg, releaseCtx),
+		outputs:   outputs,
+	}))
+	if err != nil {
+		return resp, err
+	}
+	return p.finishSuccessNotification(ctx, cfg, releaseCtx, dryRun, resp), nil
+}
+
+// sendRawSuccessNotification sends the success notification rendered from
+// raw_payload_template, bypassing the message builders.
+func (p *TelegramPlugin) sendRawSuccessNotification(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	payload, err := p.renderRawPayload(cfg, releaseCtx)
+	if err != nil {
 		return &plugin.ExecuteResponse{
 			Success: false,
-			Error:   fmt.Sprintf("failed to send Telegram message: %v", err),
+			Error:   errorText(err),
 		}, nil
 	}
 
-	return &plugin.ExecuteResponse{
-		Success: true,
-		Message: "Sent Telegram error notification",
-	}, nil
+	outputs := map[string]any{}
+	if dryRun {
+		outputs["raw_payload"] = rawPayloadBody(payload)
+	}
+	text, _ := payload["text"].(string)
+	resp, err := p.notify(ctx, cfg, releaseCtx, dryRun, notification{
+		kind:    "success",
+		msg:     TelegramMessage{Text: text},
+		raw:     payload,
+		outputs: outputs,
+	})
+	if err != nil {
+		return resp, err
+	}
+	return p.finishSuccessNotification(ctx, cfg, releaseCtx, dryRun, resp), nil
 }
 
-// buildSuccessMessage builds the success notification message.
-func (p *TelegramPlugin) buildSuccessMessage(cfg *Config, releaseCtx plugin.ReleaseContext) string {
-	var sb strings.Builder
-
-	switch cfg.ParseMode {
-	case "MarkdownV2":
-		sb.WriteString(fmt.Sprintf("🚀 *Release %s Published\\!*\n\n", escapeMarkdownV2(releaseCtx.Version)))
-		sb.WriteString(fmt.Sprintf("📦 *Version:* `%s`\n", escapeMarkdownV2(releaseCtx.Version)))
-		sb.WriteString(fmt.Sprintf("📋 *Type:* %s\n", escapeMarkdownV2(cases.Title(language.English).String(releaseCtx.ReleaseType))))
-		sb.WriteString(fmt.Sprintf("🌿 *Branch:* `%s`\n", escapeMarkdownV2(releaseCtx.Branch)))
-		sb.WriteString(fmt.Sprintf("🏷️ *Tag:* `%s`\n", escapeMarkdownV2(releaseCtx.TagName)))
-
-		if releaseCtx.Changes != nil {
-			features := len(releaseCtx.Changes.Features)
-			fixes := len(releaseCtx.Changes.Fixes)
-			breaking := len(releaseCtx.Changes.Breaking)
-
-			sb.WriteString("\n*Changes:*\n")
-			sb.WriteString(fmt.Sprintf("• %d features\n", features))
-			sb.WriteString(fmt.Sprintf("• %d bug fixes\n", fixes))
-			if breaking > 0 {
-				sb.WriteString(fmt.Sprintf("• %d breaking changes\n", breaking))
-			}
+// finishSuccessNotification runs the follow-ups of a success notification:
+// forwarding, the changelog and compare stats documents, the download QR
+// code, the breaking changes alert, the pinned latest release message, and
+// the run summary. The follow-ups of the primary announcement are not sent
+// again when an earlier run delivered it.
+func (p *TelegramPlugin) finishSuccessNotification(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, resp *plugin.ExecuteResponse) *plugin.ExecuteResponse {
+	if resp.Success && !cfg.delivered[cfg.primary()] {
+		p.forwardAnnouncement(ctx, cfg, dryRun, resp.Outputs)
+		if cfg.ChangelogDocument {
+			p.sendChangelogDocument(ctx, cfg, releaseCtx, dryRun, resp.Outputs)
 		}
-
-		if cfg.IncludeChangelog && releaseCtx.ReleaseNotes != "" {
-			notes := releaseCtx.ReleaseNotes
-			if cfg.MaxChangelogLength > 0 && len(notes) > cfg.MaxChangelogLength {
-				notes = notes[:cfg.MaxChangelogLength] + "..."
-			}
-			sb.WriteString("\n*Release Notes:*\n")
-			sb.WriteString(escapeMarkdownV2(notes))
-		}
-	case "HTML":
-		sb.WriteString(fmt.Sprintf("🚀 <b>Release %s Published!</b>\n\n", html.EscapeString(releaseCtx.Version)))
-		sb.WriteString(fmt.Sprintf("📦 <b>Version:</b> <code>%s</code>\n", html.EscapeString(releaseCtx.Version)))
-		sb.WriteString(fmt.Sprintf("📋 <b>Type:</b> %s\n", html.EscapeString(cases.Title(language.English).String(releaseCtx.ReleaseType))))
-		sb.WriteString(fmt.Sprintf("🌿 <b>Branch:</b> <code>%s</code>\n", html.EscapeString(releaseCtx.Branch)))
-		sb.WriteString(fmt.Sprintf("🏷️ <b>Tag:</b> <code>%s</code>\n", html.EscapeString(releaseCtx.TagName)))
-
-		if releaseCtx.Changes != nil {
-			features := len(releaseCtx.Changes.Features)
-			fixes := len(releaseCtx.Changes.Fixes)
-			breaking := len(releaseCtx.Changes.Breaking)
-
-			sb.WriteString("\n<b>Changes:</b>\n")
-			sb.WriteString(fmt.Sprintf("• %d features\n", features))
-			sb.WriteString(fmt.Sprintf("• %d bug fixes\n", fixes))
-			if breaking > 0 {
-				sb.WriteString(fmt.Sprintf("• %d breaking changes\n", breaking))
-			}
+		if cfg.CompareStatsFile != "" && cfg.CompareStatsDocument {
+			p.sendCompareStatsDocument(ctx, cfg, releaseCtx, dryRun, resp.Outputs)
 		}
-
-		if cfg.IncludeChangelog && releaseCtx.ReleaseNotes != "" {
-			notes := releaseCtx.ReleaseNotes
-			if cfg.MaxChangelogLength > 0 && len(notes) > cfg.MaxChangelogLength {
-				notes = notes[:cfg.MaxChangelogLength] + "..."
-			}
-			sb.WriteString("\n<b>Release Notes:</b>\n")
-			sb.WriteString(html.EscapeString(notes))
+		if cfg.QRCodeURLTemplate != "" {
+			p.sendQRCode(ctx, cfg, releaseCtx, dryRun, resp.Outputs)
 		}
-	default:
-		sb.WriteString(fmt.Sprintf("🚀 Release %s Published!\n\n", releaseCtx.Version))
-		sb.WriteString(fmt.Sprintf("📦 Version: %s\n", releaseCtx.Version))
-		sb.WriteString(fmt.Sprintf("📋 Type: %s\n", cases.Title(language.English).String(releaseCtx.ReleaseType)))
-		sb.WriteString(fmt.Sprintf("🌿 Branch: %s\n", releaseCtx.Branch))
-		sb.WriteString(fmt.Sprintf("🏷️ Tag: %s\n", releaseCtx.TagName))
-
-		if releaseCtx.Changes != nil {
-			features := len(releaseCtx.Changes.Features)
-			fixes := len(releaseCtx.Changes.Fixes)
-			breaking := len(releaseCtx.Changes.Breaking)
-
-			sb.WriteString("\nChanges:\n")
-			sb.WriteString(fmt.Sprintf("• %d features\n", features))
-			sb.WriteString(fmt.Sprintf("• %d bug fixes\n", fixes))
-			if breaking > 0 {
-				sb.WriteString(fmt.Sprintf("• %d breaking changes\n", breaking))
-			}
+		p.sendBreakingAlert(ctx, cfg, releaseCtx, dryRun, resp.Outputs)
+		if cfg.LatestReleasePin {
+			p.updateLatestReleasePins(ctx, cfg, releaseCtx, dryRun, resp.Outputs)
 		}
+	}
+	p.sendRunSummary(ctx, cfg, releaseCtx, dryRun, resp)
+	p.sendPermalinkReport(ctx, cfg, releaseCtx, dryRun, resp)
+	return resp
+}
 
-		if cfg.IncludeChangelog && releaseCtx.ReleaseNotes != "" {
-			notes := releaseCtx.ReleaseNotes
-			if cfg.MaxChangelogLength > 0 && len(notes) > cfg.MaxChangelogLength {
-				notes = notes[:cfg.MaxChangelogLength] + "..."
-			}
-			sb.WriteString("\nRelease Notes:\n")
-			sb.WriteString(notes)
+// sendErrorNotification sends an error notification.
+func (p *TelegramPlugin) sendErrorNotification(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	outputs := map[string]any{}
+	text, fm, err := p.renderErrorTemplate(cfg, releaseCtx)
+	if err != nil {
+		// A broken template must not swallow the failure alert.
+		outputs[errorTemplateErrorOutput] = errorText(err)
+	}
+	msgCfg := fm.apply(cfg)
+	if text != "" {
+		text = checkTemplateFormatting(msgCfg, text, outputs)
+	} else {
+		text = p.buildErrorMessage(cfg, releaseCtx)
+	}
+
+	msg := newMessage(msgCfg, text)
+	if fm.DisableNotification == nil {
+		msg.DisableNotification = false // Always notify on error
+	}
+	if cfg.ErrorAck {
+		msg.ReplyMarkup = ackKeyboard()
+	}
+
+	threadID, err := p.errorThreadID(ctx, cfg, dryRun)
+	if err != nil {
+		// Failures still go out, just to the regular thread.
+		outputs[errorTopicErrorOutput] = errorText(err)
+	} else if threadID != 0 {
+		msg.MessageThreadID = threadID
+		outputs["message_thread_id"] = threadID
+	}
+
+	resp, err := p.notify(ctx, cfg, releaseCtx, dryRun, p.withRerender(cfg, plugin.HookOnError, releaseCtx, notification{
+		kind:      "error",
+		msg:       msg,
+		fallbacks: errorFallbacks(releaseCtx),
+		outputs:   outputs,
+	}))
+	if err != nil {
+		return resp, err
+	}
+
+	if cfg.ErrorAck && !dryRun && resp.Success {
+		if messageID, ok := resp.Outputs["message_id"].(int64); ok {
+			p.awaitAck(ctx, cfg, messageID, resp.Outputs)
 		}
 	}
 
-	return sb.String()
+	p.sendRunSummary(ctx, cfg, releaseCtx, dryRun, resp)
+	p.sendPermalinkReport(ctx, cfg, releaseCtx, dryRun, resp)
+	return resp, nil
 }
 
-// buildErrorMessage builds the error notification message.
-func (p *TelegramPlugin) buildErrorMessage(cfg *Config, releaseCtx plugin.ReleaseContext) string {
-	var sb strings.Builder
+// sendVersionNotification announces the computed next version before the
+// release is published.
+func (p *TelegramPlugin) sendVersionNotification(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool) (*plugin.ExecuteResponse, error) {
+	var text string
+	outputs := map[string]any{}
+	msgCfg := cfg
 
-	switch cfg.ParseMode {
-	case "MarkdownV2":
-		sb.WriteString(fmt.Sprintf("❌ *Release %s Failed*\n\n", escapeMarkdownV2(releaseCtx.Version)))
-		sb.WriteString(fmt.Sprintf("📦 *Version:* `%s`\n", escapeMarkdownV2(releaseCtx.Version)))
-		sb.WriteString(fmt.Sprintf("🌿 *Branch:* `%s`\n", escapeMarkdownV2(releaseCtx.Branch)))
-		sb.WriteString("\nPlease check the CI logs for details\\.")
-	case "HTML":
-		sb.WriteString(fmt.Sprintf("❌ <b>Release %s Failed</b>\n\n", html.EscapeString(releaseCtx.Version)))
-		sb.WriteString(fmt.Sprintf("📦 <b>Version:</b> <code>%s</code>\n", html.EscapeString(releaseCtx.Version)))
-		sb.WriteString(fmt.Sprintf("🌿 <b>Branch:</b> <code>%s</code>\n", html.EscapeString(releaseCtx.Branch)))
-		sb.WriteString("\nPlease check the CI logs for details.")
-	default:
-		sb.WriteString(fmt.Sprintf("❌ Release %s Failed\n\n", releaseCtx.Version))
-		sb.WriteString(fmt.Sprintf("📦 Version: %s\n", releaseCtx.Version))
-		sb.WriteString(fmt.Sprintf("🌿 Branch: %s\n", releaseCtx.Branch))
-		sb.WriteString("\nPlease check the CI logs for details.")
+	if cfg.VersionTemplate != "" {
+		fm, tmpl, err := parseFrontMatter(cfg.VersionTemplate)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("invalid version template front matter: %v", err),
+			}, nil
+		}
+		msgCfg = fm.apply(cfg)
+		text, err = p.renderTemplate(msgCfg, tmpl, releaseCtx)
+		if err != nil {
+			return &plugin.ExecuteResponse{
+				Success: false,
+				Error:   fmt.Sprintf("failed to render version template: %v", err),
+			}, nil
+		}
+		text = checkTemplateFormatting(msgCfg, text, outputs)
+	} else {
+		text = p.buildVersionMessage(cfg, releaseCtx)
 	}
 
-	return sb.String()
+	return p.notify(ctx, cfg, releaseCtx, dryRun, p.withRerender(cfg, plugin.HookPostVersion, releaseCtx, notification{
+		kind: "version",
+		msg:  newMessage(msgCfg, text),
+		fallbacks: []deliveryFallback{{
+			name: fallbackMinimalPlainText,
+			text: fmt.Sprintf("🔖 Next release will be %s", releaseCtx.Version),
+		}},
+		outputs: outputs,
+	}))
 }
 
-// sendMessage sends a message to Telegram.
-func (p *TelegramPlugin) sendMessage(ctx context.Context, botToken string, msg TelegramMessage) error {
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", botToken)
+// now returns the current time from the plugin's clock.
+func (p *TelegramPlugin) now() time.Time {
+	return p.clockOrDefault().Now()
+}
 
-	payload, err := json.Marshal(msg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+// clockOrDefault returns the injected clock, or the real clock if none is set.
+func (p *TelegramPlugin) clockOrDefault() clock {
+	if p.clock == nil {
+		return realClock{}
 	}
+	return p.clock
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(payload))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// deliver sends msg, retrying failures when max_retries is set and rate
+// limits otherwise. It returns the ID of the sent message.
+func (p *TelegramPlugin) deliver(ctx context.Context, cfg *Config, msg TelegramMessage) (int64, error) {
+	if cfg.MaxRetries > 0 {
+		return p.deliverRetrying(ctx, cfg, msg)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	return p.deliverRateLimited(ctx, cfg, msg)
+}
 
-	resp, err := defaultHTTPClient.Do(req)
+// deliverOnce sends msg unless the circuit breaker is open, recording API
+// errors against the breaker. It returns the ID of the sent message.
+func (p *TelegramPlugin) deliverOnce(ctx context.Context, cfg *Config, msg TelegramMessage) (int64, error) {
+	breaker := p.circuitBreaker(cfg)
+	if err := breaker.check(p.now()); err != nil {
+		return 0, err
+	}
+	sent, err := p.postMessage(ctx, cfg, msg)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		breaker.recordError(err, p.now())
+		return 0, err
 	}
-	defer func() { _ = resp.Body.Close() }()
+	return sent.MessageID, nil
+}
 
-	var telegramResp TelegramResponse
-	if err := json.NewDecoder(resp.Body).Decode(&telegramResp); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+// circuitBreaker returns the run-wide circuit breaker of cfg's bot,
+// creating it on first use. Breakers are kept per bot and breaker settings,
+// so targets with their own bot get a breaker of their own and a config
+// with another threshold or window does not reuse a breaker built for a
+// different one.
+func (p *TelegramPlugin) circuitBreaker(cfg *Config) *circuitBreaker {
+	window := time.Duration(cfg.CircuitBreakerWindowSeconds) * time.Second
+	key := breakerKey{bot: cfg.botPool, threshold: cfg.CircuitBreakerThreshold, window: window}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	breaker, ok := p.breakers[key]
+	if !ok {
+		if p.breakers == nil {
+			p.breakers = make(map[breakerKey]*circuitBreaker)
+		}
+		breaker = newCircuitBreaker(key.threshold, key.window)
+		p.breakers[key] = breaker
 	}
+	return breaker
+}
 
-	if !telegramResp.OK {
-		return fmt.Errorf("telegram API error (%d): %s", telegramResp.ErrorCode, telegramResp.Description)
+// sendFailure builds the response for a failed send.
+func sendFailure(err error) *plugin.ExecuteResponse {
+	if errors.Is(err, errCircuitOpen) {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Telegram notification skipped: %s", errorText(err)),
+			Outputs: map[string]any{
+				"circuit_breaker_open": true,
+			},
+		}
+	}
+	return &plugin.ExecuteResponse{
+		Success: false,
+		Error:   fmt.Sprintf("failed to send Telegram message: %s", errorText(err)),
 	}
+}
 
-	return nil
+// chatTitle resolves the human-readable title of the configured chat. Titles
+// are cached per chat for the lifetime of the plugin. Resolution is best
+// effort: an empty string is returned when it is disabled or fails.
+func (p *TelegramPlugin) chatTitle(ctx context.Context, cfg *Config) string {
+	if !cfg.ResolveChatTitle || cfg.BotToken == "" || cfg.ChatID == "" {
+		return ""
+	}
+
+	p.mu.Lock()
+	title, ok := p.chatTitles[cfg.ChatID]
+	p.mu.Unlock()
+	if ok {
+		return title
+	}
+
+	chat, err := p.getChat(ctx, cfg, cfg.ChatID)
+	if err != nil {
+		return ""
+	}
+	title = chat.Title
+	if title == "" && chat.Username != "" {
+		title = "@" + chat.Username
+	}
+
+	p.mu.Lock()
+	if p.chatTitles == nil {
+		p.chatTitles = make(map[string]string)
+	}
+	p.chatTitles[cfg.ChatID] = title
+	p.mu.Unlock()
+
+	return title
+}
+
+// describeChat formats a chat for messages, e.g. "'ACME Releases' (-1001234567890)".
+func describeChat(title, chatID string) string {
+	if title == "" {
+		return chatID
+	}
+	return fmt.Sprintf("'%s' (%s)", title, chatID)
 }
 
 // parseConfig parses the plugin configuration.
 func (p *TelegramPlugin) parseConfig(raw map[string]any) *Config {
+	// Org-level defaults sit beneath the repo config; invalid defaults are
+	// reported by Validate.
+	if defaults, err := loadEnvDefaults(); err == nil {
+		raw = mergeConfig(defaults, raw)
+	}
+	raw, profile := applyProfile(raw)
+	raw, unsetEnv := interpolateEnv(raw)
+	raw, _ = resolveChatAliases(raw)
+
 	parser := helpers.NewConfigParser(raw)
 
 	// Get bot token with env fallback
 	botToken := parser.GetString("bot_token", "TELEGRAM_BOT_TOKEN", "")
 
-	// Get chat ID with env fallback
-	chatID := parser.GetString("chat_id", "TELEGRAM_CHAT_ID", "")
+	// Get chat ID with env fallback; t.me links may also carry a thread ID
+	chatID, linkThreadID := resolveChatID(parser.GetString("chat_id", "TELEGRAM_CHAT_ID", ""))
+
+	// The breaking changes alert chat may also be a t.me link
+	alertChatID, alertLinkThreadID := resolveChatID(parser.GetString("breaking_alert_chat_id", "", ""))
+	alertThreadID := getThreadID(raw, "breaking_alert_thread_id")
+	if alertThreadID == 0 {
+		alertThreadID = alertLinkThreadID
+	}
+
+	// The summary chat may also be a t.me link
+	summaryChatID, summaryLinkThreadID := resolveChatID(parser.GetString("summary_chat_id", "", ""))
+	summaryThreadID := getThreadID(raw, "summary_thread_id")
+	if summaryThreadID == 0 {
+		summaryThreadID = summaryLinkThreadID
+	}
 
 	// Get message thread ID
-	var messageThreadID int64
-	if v, ok := raw["message_thread_id"]; ok {
-		switch val := v.(type) {
-		case int64:
-			messageThreadID = val
-		case int:
-			messageThreadID = int64(val)
-		case float64:
-			messageThreadID = int64(val)
-		}
+	messageThreadID := getThreadID(raw, "message_thread_id")
+	if messageThreadID == 0 {
+		messageThreadID = linkThreadID
+	}
+
+	// Invalid durations are reported by Validate.
+	maxSendDuration, _ := parseDuration(raw["max_send_duration"])
+	errorAckTimeout, _ := parseDuration(raw["error_ack_timeout"])
+
+	notifyOnSuccess := parser.GetBool("notify_on_success", true)
+	notifyOnError := parser.GetBool("notify_on_error", true)
+	notifyOnVersion := parser.GetBool("notify_on_version", false)
+	notifyOn := resolveNotifyOn(notifyOnDefaults(notifyOnSuccess, notifyOnError, notifyOnVersion), raw["notify_on"])
+
+	// chat_ids fan the notification out to more chats of the primary bot
+	chatIDs := parseStringList(raw["chat_ids"])
+	chatID, messageThreadID, chatTargets := chatIDTargets(chatID, messageThreadID, chatIDs)
+
+	return &Config{
+		BotToken:                    botToken,
+		APIURL:                      parser.GetString("api_url", "TELEGRAM_API_URL", ""),
+		BotAPIVersion:               parser.GetString("bot_api_version", "", ""),
+		ChatID:                      chatID,
+		MessageThreadID:             messageThreadID,
+		ChatIDs:                     chatIDs,
+		chatTargets:                 chatTargets,
+		RouteByReleaseType:          parseReleaseTypeRoutes(raw["route_by_release_type"]),
+		Chats:                       parseStringMap(raw["chats"]),
+		ErrorChatID:                 parser.GetString("error_chat_id", "", ""),
+		HookChatIDs:                 parseHookStringMap(raw["hook_chat_ids"]),
+		Targets:                     parseTargets(raw["targets"]),
+		ParseMode:                   parser.GetString("parse_mode", "", "MarkdownV2"),
+		DisableWebPagePreview:       parser.GetBool("disable_web_page_preview", true),
+		PreviewURLTemplate:          parser.GetString("preview_url_template", "", ""),
+		ShowAboveText:               parser.GetBool("show_above_text", false),
+		DisableNotification:         parser.GetBool("disable_notification", false),
+		NotifyOn:                    notifyOn,
+		NotifyOnSuccess:             notifyOnSuccess,
+		NotifyOnError:               notifyOnError,
+		ErrorMessageThreadID:        getThreadID(raw, "error_message_thread_id"),
+		TopicName:                   parser.GetString("topic_name", "", ""),
+		SeriesTopicName:             parser.GetString("series_topic_name", "", ""),
+		ErrorTopicName:              parser.GetString("error_topic_name", "", ""),
+		ErrorAck:                    parser.GetBool("error_ack", false),
+		ErrorAckTimeout:             errorAckTimeout,
+		NotifyOnVersion:             notifyOnVersion,
+		VersionTemplate:             parser.GetString("version_template", "", ""),
+		IncludeChangelog:            parser.GetBool("include_changelog", false),
+		NormalizeWhitespace:         parser.GetBool("normalize_whitespace", false),
+		MaxChangelogLength:          getInt(raw, "max_changelog_length", 3000),
+		ChangelogExcludePatterns:    parseStringList(raw["changelog_exclude_patterns"]),
+		ChangelogStyle:              parser.GetString("changelog_style", "", render.ChangelogStyleFull),
+		TeaserLines:                 getInt(raw, "teaser_lines", 5),
+		TeaserButtonText:            parser.GetString("teaser_button_text", "", "Read full changelog"),
+		ChangesSummaryMode:          parser.GetString("changes_summary_mode", "", render.ChangesSummaryCounts),
+		ChangesSampleSize:           getInt(raw, "changes_sample_size", 5),
+		CommitFormat:                parser.GetString("commit_format", "", render.CommitFormatPretty),
+		CommitTemplate:              parser.GetString("commit_template", "", ""),
+		ScopePriority:               parseStringList(raw["scope_priority"]),
+		Template:                    parser.GetString("template", "", ""),
+		TemplateFile:                parser.GetString("template_file", "", ""),
+		RawPayloadTemplate:          parser.GetString("raw_payload_template", "", ""),
+		Templates:                   parseHookStringMap(raw["templates"]),
+		ErrorTemplate:               parser.GetString("error_template", "", ""),
+		ErrorTemplateFile:           parser.GetString("error_template_file", "", ""),
+		AutoRepairFormatting:        parser.GetBool("auto_repair_formatting", false),
+		Variables:                   parseStringMap(raw["variables"]),
+		Component:                   strings.TrimSpace(parser.GetString("component", "TELEGRAM_COMPONENT", "")),
+		ShowContributors:            parser.GetBool("show_contributors", false),
+		ContributorHandles:          parseStringMap(raw["contributor_handles"]),
+		Language:                    parseLanguage(raw["language"]),
+		ChangelogThread:             parser.GetBool("changelog_thread", false),
+		ChangelogThreadTitle:        parser.GetString("changelog_thread_title", "", "📜 Changelog"),
+		LatestReleasePin:            parser.GetBool("latest_release_pin", false),
+		LatestReleaseTemplate:       parser.GetString("latest_release_template", "", defaultLatestReleaseTemplate),
+		ChangelogDocument:           parser.GetBool("changelog_document", false),
+		ChangelogDocumentMaxBytes:   getInt(raw, "changelog_document_max_bytes", 0),
+		CompareStatsFile:            parser.GetString("compare_stats_file", "", ""),
+		CompareStatsDocument:        parser.GetBool("compare_stats_document", false),
+		QRCodeURLTemplate:           parser.GetString("qr_code_url_template", "", ""),
+		ReleaseURL:                  parser.GetString("release_url", "", ""),
+		StartedAt:                   parser.GetString("started_at", "", ""),
+		ResolveChatTitle:            parser.GetBool("resolve_chat_title", false),
+		CircuitBreakerThreshold:     getInt(raw, "circuit_breaker_threshold", 0),
+		CircuitBreakerWindowSeconds: getInt(raw, "circuit_breaker_window_seconds", 60),
+		MaxRetries:                  getInt(raw, "max_retries", 0),
+		RetryBackoffSeconds:         getInt(raw, "retry_backoff_seconds", 2),
+		RetryMaxBackoffSeconds:      getInt(raw, "retry_max_backoff_seconds", 300),
+		RetryJitter:                 parser.GetBool("retry_jitter", false),
+		MaxConcurrency:              getInt(raw, "max_concurrency", 1),
+		MaxMessagesPerSecond:        getInt(raw, "max_messages_per_second", 30),
+		MaxGroupMessagesPerMinute:   getInt(raw, "max_group_messages_per_minute", 20),
+		Sections:                    parseSections(raw["sections"]),
+		HeadlineRules:               parseHeadlineRules(raw["headline_rules"]),
+		BreakingFirst:               parser.GetBool("breaking_first", true),
+		ForwardToChatIDs:            parseStringList(raw["forward_to_chat_ids"]),
+		BreakingAlert:               parser.GetBool("breaking_alert", false),
+		BreakingAlertChatID:         alertChatID,
+		BreakingAlertThreadID:       alertThreadID,
+		HTTP:                        parseHTTPConfig(raw["http"]),
+		FaultInjection:              parseFaultInjection(raw["fault_injection"]),
+		BotTokenSource:              parseSecretSource(raw["bot_token_source"]),
+		RunID:                       parser.GetString("run_id", "TELEGRAM_RUN_ID", ""),
+		CorrelationID:               parser.GetString("correlation_id", "TELEGRAM_CORRELATION_ID", ""),
+		CorrelationFooter:           parser.GetBool("correlation_footer", false),
+		Idempotent:                  parser.GetBool("idempotent", false),
+		DedupTTLSeconds:             getInt(raw, "dedup_ttl_seconds", 86400),
+		StateFile:                   parser.GetString("state_file", "", defaultStateFile),
+		ReceiptsFile:                parser.GetString("receipts_file", "", ""),
+		MetricsFile:                 parser.GetString("metrics_file", "", ""),
+		SpoolDir:                    parser.GetString("spool_dir", "", ""),
+		RememberAnnouncements:       parser.GetBool("remember_announcements", false),
+		YankVersion:                 strings.TrimSpace(parser.GetString("yank_version", "", "")),
+		YankReason:                  strings.TrimSpace(parser.GetString("yank_reason", "", "")),
+		PersistChatMigrations:       parser.GetBool("persist_chat_migrations", false),
+		DigestSchedule:              parser.GetString("digest_schedule", "", ""),
+		SummaryChatID:               summaryChatID,
+		SummaryThreadID:             summaryThreadID,
+		PermalinkReport:             parser.GetBool("permalink_report", false),
+		MaxSendDuration:             maxSendDuration,
+		Strict:                      parser.GetBool("strict", false),
+		StrictEnv:                   parser.GetBool("strict_env", false),
+		Labels:                      parseStringMap(raw["labels"]),
+		Profile:                     profile,
+		Profiles:                    parseProfiles(raw["profiles"]),
+		Debug:                       parser.GetBool("debug", false),
+		unsetEnv:                    unsetEnv,
 	}
+}
 
-	// Get max changelog length
-	maxChangelogLength := 3000
-	if v, ok := raw["max_changelog_length"]; ok {
-		switch val := v.(type) {
-		case int:
-			maxChangelogLength = val
-		case int64:
-			maxChangelogLength = int(val)
-		case float64:
-			maxChangelogLength = int(val)
+// parseSections parses the sections config. Entries are either built-in
+// section names or objects with a template key; anything else is kept as an
+// unnamed section so Validate can report it.
+func parseSections(v any) []render.Section {
+	var sections []render.Section
+	switch val := v.(type) {
+	case []string:
+		for _, name := range val {
+			sections = append(sections, render.Section{Name: name})
+		}
+	case []any:
+		for _, item := range val {
+			switch entry := item.(type) {
+			case string:
+				sections = append(sections, render.Section{Name: entry})
+			case map[string]any:
+				tmpl, _ := entry["template"].(string)
+				sections = append(sections, render.Section{Template: tmpl})
+			default:
+				sections = append(sections, render.Section{})
+			}
 		}
 	}
+	return sections
+}
 
-	return &Config{
-		BotToken:              botToken,
-		ChatID:                chatID,
-		MessageThreadID:       messageThreadID,
-		ParseMode:             parser.GetString("parse_mode", "", "MarkdownV2"),
-		DisableWebPagePreview: parser.GetBool("disable_web_page_preview", true),
-		DisableNotification:   parser.GetBool("disable_notification", false),
-		NotifyOnSuccess:       parser.GetBool("notify_on_success", true),
-		NotifyOnError:         parser.GetBool("notify_on_error", true),
-		IncludeChangelog:      parser.GetBool("include_changelog", false),
-		MaxChangelogLength:    maxChangelogLength,
-		Template:              parser.GetString("template", "", ""),
+// envDefaults names the environment variable holding org-level default config.
+const envDefaults = "TELEGRAM_PLUGIN_DEFAULTS"
+
+// loadEnvDefaults decodes the JSON object of default config values from the
+// TELEGRAM_PLUGIN_DEFAULTS environment variable.
+func loadEnvDefaults() (map[string]any, error) {
+	data := os.Getenv(envDefaults)
+	if strings.TrimSpace(data) == "" {
+		return nil, nil
+	}
+
+	var defaults map[string]any
+	if err := json.Unmarshal([]byte(data), &defaults); err != nil {
+		return nil, fmt.Errorf("%s must be a JSON object: %w", envDefaults, err)
+	}
+	return defaults, nil
+}
+
+// mergeConfig returns defaults overlaid with config. Keys present in config
+// always win, so repositories keep control over their own settings.
+func mergeConfig(defaults, config map[string]any) map[string]any {
+	if len(defaults) == 0 {
+		return config
+	}
+
+	merged := make(map[string]any, len(defaults)+len(config))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range config {
+		merged[k] = v
 	}
+	return merged
+}
+
+// getInt reads an integer config value, accepting the numeric types produced
+// by the different config decoders.
+func getInt(raw map[string]any, key string, def int) int {
+	switch val := raw[key].(type) {
+	case int:
+		return val
+	case int64:
+		return int(val)
+	case float64:
+		return int(val)
+	default:
+		return def
+	}
+}
+
+// getThreadID reads a thread ID config value, returning 0 when unset.
+// Invalid thread IDs are reported by Validate.
+func getThreadID(raw map[string]any, key string) int64 {
+	id, _ := parseThreadID(raw[key])
+	return id
 }
 
 // Validate validates the plugin configuration.
 func (p *TelegramPlugin) Validate(ctx context.Context, config map[string]any) (*plugin.ValidateResponse, error) {
 	vb := helpers.NewValidationBuilder()
 
+	defaults, err := loadEnvDefaults()
+	if err != nil {
+		vb.AddErrorWithCode(envDefaults, err.Error(), "format")
+	}
+	config = mergeConfig(defaults, config)
+	if field, err := validateProfiles(config); err != nil {
+		vb.AddErrorWithCode(field, err.Error(), "format")
+	}
+	config, _ = applyProfile(config)
+	config, unsetEnv := interpolateEnv(config)
+	if err := validateChatAliases(config["chats"]); err != nil {
+		vb.AddErrorWithCode("chats", err.Error(), "format")
+	}
+	config, unknownAliases := resolveChatAliases(config)
+	for _, ref := range unknownAliases {
+		vb.AddErrorWithCode(ref.Key,
+			fmt.Sprintf("unknown chat alias %q; declare it in chats or write the username as @%s", ref.Name, ref.Name),
+			"format")
+	}
+
 	parser := helpers.NewConfigParser(config)
+	if parser.GetBool("strict_env", false) {
+		for _, ref := range unsetEnv {
+			vb.AddErrorWithCode(ref.Key,
+				fmt.Sprintf("environment variable %s is not set", ref.Name),
+				"required")
+		}
+	}
 	botToken := parser.GetString("bot_token", "TELEGRAM_BOT_TOKEN", "")
 	chatID := parser.GetString("chat_id", "TELEGRAM_CHAT_ID", "")
 
@@ -455,8 +1423,13 @@ func (p *TelegramPlugin) Validate(ctx context.Context, config map[string]any) (*
 		chatID = os.Getenv("TELEGRAM_CHAT_ID")
 	}
 
-	// Validate bot token
-	if botToken == "" {
+	// Validate bot token; a token from bot_token_source is only known at run
+	// time.
+	if config["bot_token_source"] != nil {
+		if err := validateSecretSource(config["bot_token_source"]); err != nil {
+			vb.AddErrorWithCode("bot_token_source", err.Error(), "format")
+		}
+	} else if botToken == "" {
 		vb.AddErrorWithCode("bot_token",
 			"Telegram bot token is required (set TELEGRAM_BOT_TOKEN env var or configure bot_token)",
 			"required")
@@ -465,10 +1438,215 @@ func (p *TelegramPlugin) Validate(ctx context.Context, config map[string]any) (*
 	}
 
 	// Validate chat ID
-	if chatID == "" {
+	if chatID == "" && len(parseStringList(config["chat_ids"])) == 0 {
 		vb.AddErrorWithCode("chat_id",
-			"Chat ID is required (set TELEGRAM_CHAT_ID env var or configure chat_id)",
+			"Chat ID is required (set TELEGRAM_CHAT_ID env var or configure chat_id or chat_ids)",
 			"required")
+	} else if chatID != "" {
+		resolved, _ := resolveChatID(chatID)
+		if err := validateChatID(resolved); err != nil {
+			vb.AddErrorWithCode("chat_id", err.Error(), "format")
+		}
+	}
+
+	switch config["chat_ids"].(type) {
+	case nil, []any, []string:
+	default:
+		vb.AddErrorWithCode("chat_ids", "must be a list of chat IDs", "format")
+	}
+	for i, entry := range parseStringList(config["chat_ids"]) {
+		resolved, _ := resolveChatID(strings.TrimSpace(entry))
+		if err := validateChatID(resolved); err != nil {
+			vb.AddErrorWithCode(fmt.Sprintf("chat_ids[%d]", i), err.Error(), "format")
+		}
+	}
+
+	// Validate per-hook chats
+	if errorChatID := parser.GetString("error_chat_id", "", ""); errorChatID != "" {
+		resolved, _ := resolveChatID(errorChatID)
+		if err := validateChatID(resolved); err != nil {
+			vb.AddErrorWithCode("error_chat_id", err.Error(), "format")
+		}
+	}
+	if err := validateHookChatIDs(config["hook_chat_ids"]); err != nil {
+		vb.AddErrorWithCode("hook_chat_ids", err.Error(), "format")
+	}
+
+	// Validate release type routes
+	if err := validateReleaseTypeRoutes(config["route_by_release_type"]); err != nil {
+		vb.AddErrorWithCode("route_by_release_type", err.Error(), "format")
+	}
+
+	// Validate breaking changes alert chat
+	if alertChatID := parser.GetString("breaking_alert_chat_id", "", ""); alertChatID != "" {
+		resolved, _ := resolveChatID(alertChatID)
+		if err := validateChatID(resolved); err != nil {
+			vb.AddErrorWithCode("breaking_alert_chat_id", err.Error(), "format")
+		}
+	}
+
+	// Validate targets
+	for i, target := range parseTargets(config["targets"]) {
+		if field, err := validateTarget(target); err != nil {
+			code := "format"
+			if field == "bot_token_env" || target.ChatID == "" {
+				code = "required"
+			} else if field == "language" {
+				code = "enum"
+			}
+			vb.AddErrorWithCode(fmt.Sprintf("targets[%d].%s", i, field), err.Error(), code)
+		}
+	}
+	if items, ok := config["targets"].([]any); ok {
+		for i, item := range items {
+			raw, _ := item.(map[string]any)
+			if err := validateStringMap(raw["labels"]); err != nil {
+				vb.AddErrorWithCode(fmt.Sprintf("targets[%d].labels", i), err.Error(), "format")
+			}
+		}
+	}
+
+	// Validate forward targets
+	for _, target := range parseStringList(config["forward_to_chat_ids"]) {
+		resolved, _ := resolveChatID(target)
+		if err := validateChatID(resolved); err != nil {
+			vb.AddErrorWithCode("forward_to_chat_ids", err.Error(), "format")
+		}
+	}
+
+	// Validate summary chat
+	if summaryChatID := parser.GetString("summary_chat_id", "", ""); summaryChatID != "" {
+		resolved, _ := resolveChatID(summaryChatID)
+		if err := validateChatID(resolved); err != nil {
+			vb.AddErrorWithCode("summary_chat_id", err.Error(), "format")
+		}
+	}
+
+	// Validate thread IDs
+	for _, key := range threadIDKeys {
+		if id, err := parseThreadID(config[key]); err != nil {
+			vb.AddErrorWithCode(key, err.Error(), "format")
+		} else if id < 0 {
+			vb.AddErrorWithCode(key, "must be a positive thread ID", "range")
+		}
+	}
+
+	// Validate send duration objective and acknowledgment timeout
+	for _, key := range []string{"max_send_duration", "error_ack_timeout"} {
+		if d, err := parseDuration(config[key]); err != nil {
+			vb.AddErrorWithCode(key, err.Error(), "format")
+		} else if d < 0 {
+			vb.AddErrorWithCode(key, "must not be negative", "range")
+		}
+	}
+
+	// Validate the raw payload against the self test release
+	if parser.GetString("raw_payload_template", "", "") != "" {
+		if _, err := p.renderRawPayload(p.parseConfig(config), selfTestRelease); err != nil {
+			vb.AddErrorWithCode("raw_payload_template", err.Error(), "format")
+		}
+	}
+
+	if err := validateHookTemplates(config["templates"]); err != nil {
+		vb.AddErrorWithCode("templates", err.Error(), "format")
+	}
+
+	// Validate template files
+	for _, key := range []string{"template", "error_template"} {
+		fileKey := key + "_file"
+		templateFile := parser.GetString(fileKey, "", "")
+		if templateFile == "" {
+			continue
+		}
+		if parser.GetString(key, "", "") != "" {
+			vb.AddErrorWithCode(fileKey, fmt.Sprintf("set either %s or %s, not both", key, fileKey), "format")
+		} else if err := validateTemplateFile(templateFile); err != nil {
+			vb.AddErrorWithCode(fileKey, err.Error(), "required")
+		} else if text, err := loadTemplateFile(templateFile); err != nil {
+			vb.AddErrorWithCode(fileKey, err.Error(), "required")
+		} else if err := parseMessageTemplate(text); err != nil {
+			vb.AddErrorWithCode(fileKey, fmt.Sprintf("%s: %v", templateFile, err), "format")
+		}
+	}
+
+	// Validate template syntax
+	for _, key := range []string{"template", "error_template", "version_template"} {
+		if err := parseMessageTemplate(parser.GetString(key, "", "")); err != nil {
+			vb.AddErrorWithCode(key, err.Error(), "format")
+		}
+	}
+	if err := render.ParseTemplate(parser.GetString("preview_url_template", "", "")); err != nil {
+		vb.AddErrorWithCode("preview_url_template", err.Error(), "format")
+	}
+	if err := render.ParseTemplate(parser.GetString("qr_code_url_template", "", "")); err != nil {
+		vb.AddErrorWithCode("qr_code_url_template", err.Error(), "format")
+	}
+	if err := render.ParseTemplate(parser.GetString("latest_release_template", "", "")); err != nil {
+		vb.AddErrorWithCode("latest_release_template", err.Error(), "format")
+	}
+	hookTemplates := parseStringMap(config["templates"])
+	for _, hook := range slices.Sorted(maps.Keys(hookTemplates)) {
+		if err := parseMessageTemplate(hookTemplates[hook]); err != nil {
+			vb.AddErrorWithCode("templates."+hook, err.Error(), "format")
+		}
+	}
+	for i, section := range parseSections(config["sections"]) {
+		if err := render.ParseTemplate(section.Template); err != nil {
+			vb.AddErrorWithCode(fmt.Sprintf("sections[%d].template", i), err.Error(), "format")
+		}
+	}
+
+	// Validate the Bot API server
+	if apiURL := parser.GetString("api_url", "TELEGRAM_API_URL", ""); apiURL != "" {
+		if u, err := url.Parse(apiURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			vb.AddErrorWithCode("api_url", fmt.Sprintf("%q must be an http or https URL", apiURL), "format")
+		}
+	}
+	if parser.GetBool("compare_stats_document", false) && parser.GetString("compare_stats_file", "", "") == "" {
+		vb.AddErrorWithCode("compare_stats_document", "compare_stats_document requires compare_stats_file", "required")
+	}
+	if parser.GetString("yank_reason", "", "") != "" && parser.GetString("yank_version", "", "") == "" {
+		vb.AddErrorWithCode("yank_reason", "yank_reason requires yank_version", "required")
+	}
+	if err := validateFaultInjection(config["fault_injection"]); err != nil {
+		vb.AddErrorWithCode("fault_injection", err.Error(), "format")
+	}
+	if startedAt := parser.GetString("started_at", "", ""); startedAt != "" {
+		if _, err := parseStartedAt(startedAt); err != nil {
+			vb.AddErrorWithCode("started_at", err.Error(), "format")
+		}
+	}
+	if version := parser.GetString("bot_api_version", "", ""); version != "" {
+		if _, err := parseAPIVersion(version); err != nil {
+			vb.AddErrorWithCode("bot_api_version", err.Error(), "format")
+		}
+	}
+	if getInt(config, "changelog_document_max_bytes", 0) < 0 {
+		vb.AddErrorWithCode("changelog_document_max_bytes", "must not be negative", "range")
+	}
+	if getInt(config, "max_retries", 0) < 0 {
+		vb.AddErrorWithCode("max_retries", "must not be negative", "range")
+	}
+	for _, key := range []string{"retry_backoff_seconds", "retry_max_backoff_seconds"} {
+		if getInt(config, key, 1) < 1 {
+			vb.AddErrorWithCode(key, "must be at least 1", "range")
+		}
+	}
+	if httpRaw, ok := config["http"].(map[string]any); ok && getInt(httpRaw, "timeout_seconds", 0) < 0 {
+		vb.AddErrorWithCode("http.timeout_seconds", "must not be negative", "range")
+	}
+	if httpCfg := parseHTTPConfig(config["http"]); (httpCfg.ClientCertFile == "") != (httpCfg.ClientKeyFile == "") {
+		vb.AddErrorWithCode("http.client_key_file", "client_cert_file and client_key_file must be set together", "required")
+	} else if _, err := httpCfg.tlsConfig(); err != nil {
+		vb.AddErrorWithCode("http", err.Error(), "format")
+	}
+	for _, key := range []string{"max_messages_per_second", "max_group_messages_per_minute"} {
+		if getInt(config, key, 0) < 0 {
+			vb.AddErrorWithCode(key, "must not be negative", "range")
+		}
+	}
+	if getInt(config, "max_concurrency", 1) < 1 {
+		vb.AddErrorWithCode("max_concurrency", "must be at least 1", "range")
 	}
 
 	// Validate parse mode
@@ -479,13 +1657,135 @@ func (p *TelegramPlugin) Validate(ctx context.Context, config map[string]any) (*
 			"enum")
 	}
 
+	// Validate changelog style
+	switch parser.GetString("changelog_style", "", render.ChangelogStyleFull) {
+	case render.ChangelogStyleFull:
+	case render.ChangelogStyleTeaser:
+		if parser.GetString("release_url", "", "") == "" {
+			vb.AddErrorWithCode("release_url",
+				"release_url is required for the teaser changelog style",
+				"required")
+		}
+	default:
+		vb.AddErrorWithCode("changelog_style",
+			"Changelog style must be 'full' or 'teaser'",
+			"enum")
+	}
+
+	// Validate digest schedule
+	switch parser.GetString("digest_schedule", "", "") {
+	case "", render.DigestDaily, render.DigestWeekly:
+	default:
+		vb.AddErrorWithCode("digest_schedule",
+			"Digest schedule must be 'daily', 'weekly', or empty",
+			"enum")
+	}
+
+	// Validate changes summary
+	switch parser.GetString("changes_summary_mode", "", render.ChangesSummaryCounts) {
+	case render.ChangesSummaryCounts, render.ChangesSummarySampled:
+	default:
+		vb.AddErrorWithCode("changes_summary_mode",
+			"Changes summary mode must be 'counts' or 'sampled'",
+			"enum")
+	}
+	if getInt(config, "changes_sample_size", 5) < 1 {
+		vb.AddErrorWithCode("changes_sample_size", "changes_sample_size must be at least 1", "range")
+	}
+
+	// Validate commit format
+	switch parser.GetString("commit_format", "", render.CommitFormatPretty) {
+	case render.CommitFormatPretty, render.CommitFormatRaw:
+	case render.CommitFormatTemplate:
+		if tmpl := parser.GetString("commit_template", "", ""); tmpl == "" {
+			vb.AddErrorWithCode("commit_template",
+				"commit_template is required for the template commit format",
+				"required")
+		} else if err := render.ValidateCommitTemplate(tmpl); err != nil {
+			vb.AddErrorWithCode("commit_template", err.Error(), "format")
+		}
+	default:
+		vb.AddErrorWithCode("commit_format",
+			"Commit format must be 'pretty', 'raw', or 'template'",
+			"enum")
+	}
+
+	// Validate headline rules
+	for i, rule := range parseHeadlineRules(config["headline_rules"]) {
+		if err := rule.Validate(); err != nil {
+			vb.AddErrorWithCode(fmt.Sprintf("headline_rules[%d]", i), err.Error(), "format")
+		}
+	}
+
+	// Validate sections
+	for i, section := range parseSections(config["sections"]) {
+		if section.Template == "" && !render.IsBuiltinSection(section.Name) {
+			vb.AddErrorWithCode(fmt.Sprintf("sections[%d]", i),
+				fmt.Sprintf("Unknown section %q (expected header, version_info, changes, breaking_changes, changelog, contributors, footer, or a template block)", section.Name),
+				"enum")
+		}
+	}
+
+	// Validate topic names
+	if err := validateTopicName(parser.GetString("topic_name", "", "")); err != nil {
+		vb.AddErrorWithCode("topic_name", err.Error(), "format")
+	}
+	if err := validateSeriesTopicName(parser.GetString("series_topic_name", "", "")); err != nil {
+		vb.AddErrorWithCode("series_topic_name", err.Error(), "format")
+	}
+	if err := validateTopicName(parser.GetString("error_topic_name", "", "")); err != nil {
+		vb.AddErrorWithCode("error_topic_name", err.Error(), "format")
+	}
+
+	// Validate changelog exclude patterns
+	if _, err := compileExcludePatterns(parseStringList(config["changelog_exclude_patterns"])); err != nil {
+		vb.AddErrorWithCode("changelog_exclude_patterns", err.Error(), "format")
+	}
+
+	// Validate language
+	for _, lang := range parseLanguage(config["language"]) {
+		if !render.IsSupportedLanguage(lang) {
+			vb.AddErrorWithCode("language",
+				fmt.Sprintf("Unsupported language %q (expected de, en, es, fr, pt, or a regional variant such as pt-BR)", lang),
+				"enum")
+		}
+	}
+
+	// Validate variables
+	if err := validateStringMap(config["variables"]); err != nil {
+		vb.AddErrorWithCode("variables", err.Error(), "format")
+	}
+
+	if _, err := parseNotifyOn(config["notify_on"]); err != nil {
+		vb.AddErrorWithCode("notify_on", err.Error(), "format")
+	}
+
+	// Validate contributor handles
+	if err := validateStringMap(config["contributor_handles"]); err != nil {
+		vb.AddErrorWithCode("contributor_handles", err.Error(), "format")
+	}
+
+	// Validate labels
+	if err := validateStringMap(config["labels"]); err != nil {
+		vb.AddErrorWithCode("labels", err.Error(), "format")
+	}
+
+	if id := parser.GetString("correlation_id", "TELEGRAM_CORRELATION_ID", ""); id != "" && !correlationIDPattern.MatchString(id) {
+		vb.AddErrorWithCode("correlation_id", fmt.Sprintf("%q must be 1 to 128 letters, digits, or . _ : / -", id), "format")
+	}
+
 	// Note: We don't verify chat access during validation to avoid network calls
 	// The actual send will fail if the chat is inaccessible
 
-	return vb.Build(), nil
+	resp := vb.Build()
+	// Chats that keep failing are warnings: they are reported without making
+	// the config invalid.
+	if metricsFile := parser.GetString("metrics_file", "", ""); metricsFile != "" {
+		resp.Errors = append(resp.Errors, failingChatWarnings(metricsFile)...)
+	}
+	return resp, nil
 }
 
-
 // validateBotToken validates a Telegram bot token format.
 func validateBotToken(token string) error {
 	// Bot token format: 123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789
@@ -495,28 +1795,3 @@ func validateBotToken(token string) error {
 	}
 	return nil
 }
-
-// escapeMarkdownV2 escapes special characters for Telegram MarkdownV2.
-func escapeMarkdownV2(text string) string {
-	// Characters that need escaping in MarkdownV2
-	specialChars := []string{"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!"}
-
-	result := text
-	for _, char := range specialChars {
-		result = strings.ReplaceAll(result, char, "\\"+char)
-	}
-	return result
-}
-
-// renderTemplate renders a custom template with release context.
-func renderTemplate(templateStr string, releaseCtx plugin.ReleaseContext) (string, error) {
-	// Simple template replacement
-	result := templateStr
-	result = strings.ReplaceAll(result, "{{.Version}}", releaseCtx.Version)
-	result = strings.ReplaceAll(result, "{{.TagName}}", releaseCtx.TagName)
-	result = strings.ReplaceAll(result, "{{.Branch}}", releaseCtx.Branch)
-	result = strings.ReplaceAll(result, "{{.ReleaseType}}", releaseCtx.ReleaseType)
-	result = strings.ReplaceAll(result, "{{.ReleaseNotes}}", releaseCtx.ReleaseNotes)
-	result = strings.ReplaceAll(result, "{{.Date}}", time.Now().Format("2006-01-02"))
-	return result, nil
-}
@@ -5,12 +5,28 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
 )
 
+// useTestServer starts a fake Bot API server and points the plugin at it for
+// the duration of the test.
+func useTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	oldURL := telegramAPIBaseURL
+	telegramAPIBaseURL = server.URL
+	t.Cleanup(func() {
+		telegramAPIBaseURL = oldURL
+		server.Close()
+	})
+	return server
+}
+
 func TestGetInfo(t *testing.T) {
 	p := &TelegramPlugin{}
 	info := p.GetInfo()
@@ -46,59 +62,6 @@ func TestGetInfo(t *testing.T) {
 	}
 }
 
-func TestEscapeMarkdownV2(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{
-			name:     "simple text",
-			input:    "hello world",
-			expected: "hello world",
-		},
-		{
-			name:     "version with dots",
-			input:    "v1.2.3",
-			expected: "v1\\.2\\.3",
-		},
-		{
-			name:     "text with underscores",
-			input:    "my_variable_name",
-			expected: "my\\_variable\\_name",
-		},
-		{
-			name:     "text with asterisks",
-			input:    "*bold*",
-			expected: "\\*bold\\*",
-		},
-		{
-			name:     "text with brackets",
-			input:    "[link](url)",
-			expected: "\\[link\\]\\(url\\)",
-		},
-		{
-			name:     "complex text",
-			input:    "Release v1.0.0 - feat: add *new* feature!",
-			expected: "Release v1\\.0\\.0 \\- feat: add \\*new\\* feature\\!",
-		},
-		{
-			name:     "empty string",
-			input:    "",
-			expected: "",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := escapeMarkdownV2(tt.input)
-			if result != tt.expected {
-				t.Errorf("escapeMarkdownV2(%q) = %q, want %q", tt.input, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestValidateBotToken(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -147,57 +110,6 @@ func TestValidateBotToken(t *testing.T) {
 	}
 }
 
-func TestRenderTemplate(t *testing.T) {
-	releaseCtx := plugin.ReleaseContext{
-		Version:      "1.2.3",
-		TagName:      "v1.2.3",
-		Branch:       "main",
-		ReleaseType:  "minor",
-		ReleaseNotes: "Bug fixes and improvements",
-	}
-
-	tests := []struct {
-		name     string
-		template string
-		contains []string
-	}{
-		{
-			name:     "version placeholder",
-			template: "Release {{.Version}}",
-			contains: []string{"Release 1.2.3"},
-		},
-		{
-			name:     "multiple placeholders",
-			template: "{{.Version}} on {{.Branch}}",
-			contains: []string{"1.2.3 on main"},
-		},
-		{
-			name:     "tag name",
-			template: "Tag: {{.TagName}}",
-			contains: []string{"Tag: v1.2.3"},
-		},
-		{
-			name:     "release notes",
-			template: "Notes: {{.ReleaseNotes}}",
-			contains: []string{"Notes: Bug fixes and improvements"},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := renderTemplate(tt.template, releaseCtx)
-			if err != nil {
-				t.Fatalf("renderTemplate() error = %v", err)
-			}
-			for _, c := range tt.contains {
-				if !strings.Contains(result, c) {
-					t.Errorf("renderTemplate() = %q, want to contain %q", result, c)
-				}
-			}
-		})
-	}
-}
-
 func TestParseConfig(t *testing.T) {
 	p := &TelegramPlugin{}
 
@@ -238,6 +150,25 @@ func TestParseConfig(t *testing.T) {
 					cfg.MaxChangelogLength == 3000
 			},
 		},
+		{
+			name: "with t.me topic link",
+			config: map[string]any{
+				"chat_id": "https://t.me/c/1234567890/42",
+			},
+			check: func(cfg *Config) bool {
+				return cfg.ChatID == "-1001234567890" && cfg.MessageThreadID == 42
+			},
+		},
+		{
+			name: "explicit thread ID wins over link",
+			config: map[string]any{
+				"chat_id":           "https://t.me/c/1234567890/42",
+				"message_thread_id": 7,
+			},
+			check: func(cfg *Config) bool {
+				return cfg.MessageThreadID == 7
+			},
+		},
 		{
 			name: "with thread ID",
 			config: map[string]any{
@@ -247,6 +178,21 @@ func TestParseConfig(t *testing.T) {
 				return cfg.MessageThreadID == 12345
 			},
 		},
+		{
+			name: "with string thread IDs",
+			config: map[string]any{
+				"message_thread_id":        "42",
+				"error_message_thread_id":  "7",
+				"breaking_alert_thread_id": " 8 ",
+				"summary_thread_id":        "9",
+			},
+			check: func(cfg *Config) bool {
+				return cfg.MessageThreadID == 42 &&
+					cfg.ErrorMessageThreadID == 7 &&
+					cfg.BreakingAlertThreadID == 8 &&
+					cfg.SummaryThreadID == 9
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -380,11 +326,11 @@ func TestExecuteDryRun(t *testing.T) {
 			expectMessage:   "Would send Telegram success notification",
 		},
 		{
-			name:            "error notification in dry-run",
-			hook:            plugin.HookOnError,
-			notifyOnError:   true,
-			expectSuccess:   true,
-			expectMessage:   "Would send Telegram error notification",
+			name:          "error notification in dry-run",
+			hook:          plugin.HookOnError,
+			notifyOnError: true,
+			expectSuccess: true,
+			expectMessage: "Would send Telegram error notification",
 		},
 		{
 			name:            "success disabled",
@@ -461,15 +407,15 @@ func TestSendMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 				_ = json.NewEncoder(w).Encode(tt.response)
-			}))
-			defer server.Close()
+			})
 
-			// Note: We can't easily inject the server URL here without modifying the plugin
-			// This test demonstrates the structure; in practice, you'd use dependency injection
-			_ = p
+			err := p.sendMessage(context.Background(), &Config{BotToken: "123:abc"}, TelegramMessage{ChatID: "@test", Text: "hello"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sendMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
 		})
 	}
 }
@@ -514,82 +460,1092 @@ func TestValidate(t *testing.T) {
 			wantValid: false,
 		},
 		{
-			name: "invalid parse mode",
+			name: "teaser without release URL",
 			config: map[string]any{
-				"bot_token":  "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
-				"chat_id":    "@mychannel",
-				"parse_mode": "InvalidMode",
+				"bot_token":       "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":         "@mychannel",
+				"changelog_style": "teaser",
 			},
 			wantValid: false,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			resp, err := p.Validate(ctx, tt.config)
-			if err != nil {
-				t.Fatalf("Validate() error = %v", err)
-			}
-
-			isValid := len(resp.Errors) == 0
-			if isValid != tt.wantValid {
-				t.Errorf("Validate() valid = %v, want %v; errors: %v", isValid, tt.wantValid, resp.Errors)
-			}
-		})
-	}
-}
-
-func TestBuildSuccessMessageWithChangelog(t *testing.T) {
-	p := &TelegramPlugin{}
-
-	releaseCtx := plugin.ReleaseContext{
-		Version:      "1.0.0",
-		TagName:      "v1.0.0",
-		Branch:       "main",
-		ReleaseType:  "major",
-		ReleaseNotes: "This is the release notes content.",
-	}
-
-	cfg := &Config{
-		ParseMode:          "",
-		IncludeChangelog:   true,
-		MaxChangelogLength: 100,
-	}
-
-	result := p.buildSuccessMessage(cfg, releaseCtx)
-
-	if !strings.Contains(result, "Release Notes:") {
-		t.Error("Expected changelog section in message")
-	}
-	if !strings.Contains(result, "This is the release notes content.") {
-		t.Error("Expected release notes content in message")
-	}
-}
-
-func TestBuildSuccessMessageChangelogTruncation(t *testing.T) {
-	p := &TelegramPlugin{}
-
-	longNotes := strings.Repeat("a", 200)
-	releaseCtx := plugin.ReleaseContext{
-		Version:      "1.0.0",
-		Branch:       "main",
-		ReleaseNotes: longNotes,
-	}
-
-	cfg := &Config{
-		ParseMode:          "",
-		IncludeChangelog:   true,
-		MaxChangelogLength: 50,
-	}
-
-	result := p.buildSuccessMessage(cfg, releaseCtx)
-
-	// Should be truncated with "..."
-	if !strings.Contains(result, "...") {
-		t.Error("Expected truncated changelog with '...'")
-	}
-	// Should not contain the full 200 chars
-	if strings.Contains(result, strings.Repeat("a", 200)) {
-		t.Error("Changelog should be truncated")
+		{
+			name: "chat ID without @ is normalized",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "mychannel",
+			},
+			wantValid: true,
+		},
+		{
+			name: "chat ID as link",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "https://example.com/mychannel",
+			},
+			wantValid: false,
+		},
+		{
+			name: "unknown section",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@mychannel",
+				"sections":  []any{"header", "artifacts"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid changelog exclude pattern",
+			config: map[string]any{
+				"bot_token":                  "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":                    "@mychannel",
+				"changelog_exclude_patterns": []any{"(unclosed"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "unsupported language",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@mychannel",
+				"language":  []any{"pt-BR", "klingon"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid max send duration",
+			config: map[string]any{
+				"bot_token":         "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":           "@mychannel",
+				"max_send_duration": "soon",
+			},
+			wantValid: false,
+		},
+		{
+			name: "string thread ID",
+			config: map[string]any{
+				"bot_token":         "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":           "@mychannel",
+				"message_thread_id": "42",
+			},
+			wantValid: true,
+		},
+		{
+			name: "non-numeric thread ID",
+			config: map[string]any{
+				"bot_token":         "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":           "@mychannel",
+				"summary_thread_id": "releases",
+			},
+			wantValid: false,
+		},
+		{
+			name: "negative thread ID",
+			config: map[string]any{
+				"bot_token":               "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":                 "@mychannel",
+				"error_message_thread_id": -3,
+			},
+			wantValid: false,
+		},
+		{
+			name: "notify_on",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@mychannel",
+				"notify_on": map[string]any{"post_version": true, "on_error": "false"},
+			},
+			wantValid: true,
+		},
+		{
+			name: "notify_on unknown hook",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@mychannel",
+				"notify_on": map[string]any{"pre_plan": true},
+			},
+			wantValid: false,
+		},
+		{
+			name: "hook templates",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@mychannel",
+				"templates": map[string]any{"on_success": "shipped {{.Version}}", "on_error": "failed {{.Version}}"},
+			},
+			wantValid: true,
+		},
+		{
+			name: "hook templates unknown hook",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@mychannel",
+				"templates": map[string]any{"pre_publish": "publishing {{.Version}}"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "digest schedule",
+			config: map[string]any{
+				"bot_token":       "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":         "@mychannel",
+				"digest_schedule": "weekly",
+			},
+			wantValid: true,
+		},
+		{
+			name: "invalid digest schedule",
+			config: map[string]any{
+				"bot_token":       "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":         "@mychannel",
+				"digest_schedule": "hourly",
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid contributor handles",
+			config: map[string]any{
+				"bot_token":           "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":             "@mychannel",
+				"contributor_handles": []any{"alice"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "commit template",
+			config: map[string]any{
+				"bot_token":       "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":         "@mychannel",
+				"commit_format":   "template",
+				"commit_template": "{{.Type}}: {{.Description}}",
+			},
+			wantValid: true,
+		},
+		{
+			name: "commit template missing",
+			config: map[string]any{
+				"bot_token":     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":       "@mychannel",
+				"commit_format": "template",
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid commit template",
+			config: map[string]any{
+				"bot_token":       "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":         "@mychannel",
+				"commit_format":   "template",
+				"commit_template": "{{.Sha}}",
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid commit format",
+			config: map[string]any{
+				"bot_token":     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":       "@mychannel",
+				"commit_format": "verbatim",
+			},
+			wantValid: false,
+		},
+		{
+			name: "targets",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@mychannel",
+				"targets": []any{
+					map[string]any{"chat_id": "@brand", "bot_token": "987654321:ZYXwvuTSRqpoNMLkjiHGFedcba987654321"},
+					map[string]any{"chat_id": "-1001234567890@7"},
+				},
+			},
+			wantValid: true,
+		},
+		{
+			name: "target without chat",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@mychannel",
+				"targets":   []any{map[string]any{"bot_token_env": "UNSET_BRAND_BOT_TOKEN"}},
+			},
+			wantValid: false,
+		},
+		{
+			name: "template syntax error",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz",
+				"chat_id":   "-1001234567890",
+				"template":  "Release {{.Version",
+			},
+			wantValid: false,
+		},
+		{
+			name: "error template unknown function",
+			config: map[string]any{
+				"bot_token":      "123456789:ABCdefGHIjklMNOpqrsTUVwxyz",
+				"chat_id":        "-1001234567890",
+				"error_template": "{{shout .Error}}",
+			},
+			wantValid: false,
+		},
+		{
+			name: "hook template syntax error",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz",
+				"chat_id":   "-1001234567890",
+				"templates": map[string]any{"post_version": "{{if .Version}}next"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "section template syntax error",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz",
+				"chat_id":   "-1001234567890",
+				"sections":  []any{"header", map[string]any{"template": "{{end}}"}},
+			},
+			wantValid: false,
+		},
+		{
+			name: "negative max retries",
+			config: map[string]any{
+				"bot_token":   "123456789:ABCdefGHIjklMNOpqrsTUVwxyz",
+				"chat_id":     "-1001234567890",
+				"max_retries": -1,
+			},
+			wantValid: false,
+		},
+		{
+			name: "template front matter unknown key",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz",
+				"chat_id":   "-1001234567890",
+				"template":  "---\nsilent: true\n---\nRelease {{.Version}}",
+			},
+			wantValid: false,
+		},
+		{
+			name: "valid template front matter",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "-1001234567890",
+				"template":  "---\nparse_mode: HTML\ndisable_notification: true\n---\n<b>{{.Version}}</b>",
+			},
+			wantValid: true,
+		},
+		{
+			name: "chat_ids without chat_id",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_ids":  []any{"@repo_releases", "-1001234567890@7"},
+			},
+			wantValid: true,
+		},
+		{
+			name: "invalid chat_ids entry",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_ids":  []any{"@repo_releases", "not a chat"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "chat_ids not a list",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@news",
+				"chat_ids":  "@mirror",
+			},
+			wantValid: false,
+		},
+		{
+			name: "unknown release type route",
+			config: map[string]any{
+				"bot_token":             "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":               "@repo_releases",
+				"route_by_release_type": map[string]any{"hotfix": "@repo_hotfixes"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "valid error chat",
+			config: map[string]any{
+				"bot_token":     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":       "@repo_releases",
+				"error_chat_id": "-1001234567890",
+				"hook_chat_ids": map[string]any{"post_version": "@repo_dev"},
+			},
+			wantValid: true,
+		},
+		{
+			name: "invalid error chat",
+			config: map[string]any{
+				"bot_token":     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":       "@repo_releases",
+				"error_chat_id": "not a chat",
+			},
+			wantValid: false,
+		},
+		{
+			name: "unknown hook chat",
+			config: map[string]any{
+				"bot_token":     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":       "@repo_releases",
+				"hook_chat_ids": map[string]any{"pre_publish": "@repo_dev"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "unknown profile",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@repo_releases",
+				"profile":   "qa",
+				"profiles":  map[string]any{"prod": map[string]any{"chat_id": "@repo_announcements"}},
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid option in selected profile",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@repo_releases",
+				"profile":   "prod",
+				"profiles":  map[string]any{"prod": map[string]any{"chat_id": "not a chat"}},
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid max concurrency",
+			config: map[string]any{
+				"bot_token":       "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":         "@repo_releases",
+				"max_concurrency": 0,
+			},
+			wantValid: false,
+		},
+		{
+			name: "valid chat aliases",
+			config: map[string]any{
+				"bot_token":     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chats":         map[string]any{"releases": "@repo_releases", "ops": "-1001234567890"},
+				"chat_id":       "releases",
+				"error_chat_id": "ops",
+			},
+			wantValid: true,
+		},
+		{
+			name: "unknown chat alias",
+			config: map[string]any{
+				"bot_token":     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chats":         map[string]any{"ops": "-1001234567890"},
+				"chat_id":       "@repo_releases",
+				"error_chat_id": "opps_team",
+			},
+			wantValid: false,
+		},
+		{
+			name: "topic name too long",
+			config: map[string]any{
+				"bot_token":  "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":    "-1001234567890",
+				"topic_name": strings.Repeat("x", 129),
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid bot API version",
+			config: map[string]any{
+				"bot_token":       "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":         "@repo_releases",
+				"bot_api_version": "latest",
+			},
+			wantValid: false,
+		},
+		{
+			name: "series topic name without major",
+			config: map[string]any{
+				"bot_token":         "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":           "@repo_releases",
+				"series_topic_name": "Releases",
+			},
+			wantValid: false,
+		},
+		{
+			name: "zero retry backoff",
+			config: map[string]any{
+				"bot_token":             "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":               "@repo_releases",
+				"retry_backoff_seconds": 0,
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid started at",
+			config: map[string]any{
+				"bot_token":  "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":    "@repo_releases",
+				"started_at": "yesterday",
+			},
+			wantValid: false,
+		},
+		{
+			name: "bot token source",
+			config: map[string]any{
+				"chat_id":          "@repo_releases",
+				"bot_token_source": map[string]any{"type": "exec", "command": []any{"vault", "kv", "get", "-field=token", "secret/telegram"}},
+			},
+			wantValid: true,
+		},
+		{
+			name: "bot token source without command",
+			config: map[string]any{
+				"chat_id":          "@repo_releases",
+				"bot_token_source": map[string]any{"type": "exec"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "unknown bot token source",
+			config: map[string]any{
+				"chat_id":          "@repo_releases",
+				"bot_token_source": map[string]any{"type": "keychain"},
+			},
+			wantValid: false,
+		},
+		{
+			name: "yank reason without version",
+			config: map[string]any{
+				"bot_token":   "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":     "@repo_releases",
+				"yank_reason": "corrupted artifacts",
+			},
+			wantValid: false,
+		},
+		{
+			name: "negative http timeout",
+			config: map[string]any{
+				"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":   "@repo_releases",
+				"http":      map[string]any{"timeout_seconds": -5},
+			},
+			wantValid: false,
+		},
+		{
+			name: "compare stats document without file",
+			config: map[string]any{
+				"bot_token":              "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":                "@repo_releases",
+				"compare_stats_document": true,
+			},
+			wantValid: false,
+		},
+		{
+			name: "negative max messages per second",
+			config: map[string]any{
+				"bot_token":               "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":                 "@repo_releases",
+				"max_messages_per_second": -1,
+			},
+			wantValid: false,
+		},
+		{
+			name: "negative max group messages per minute",
+			config: map[string]any{
+				"bot_token":                     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":                       "@repo_releases",
+				"max_group_messages_per_minute": -1,
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid qr code url template",
+			config: map[string]any{
+				"bot_token":            "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":              "@repo_releases",
+				"qr_code_url_template": "https://example.com/{{.Version",
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid correlation id",
+			config: map[string]any{
+				"bot_token":      "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":        "@repo_releases",
+				"correlation_id": "build 42",
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid error ack timeout",
+			config: map[string]any{
+				"bot_token":         "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":           "@mychannel",
+				"error_ack_timeout": "-1m",
+			},
+			wantValid: false,
+		},
+		{
+			name: "missing template file",
+			config: map[string]any{
+				"bot_token":     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":       "@mychannel",
+				"template_file": "testdata/missing.tmpl",
+			},
+			wantValid: false,
+		},
+		{
+			name: "template and template file",
+			config: map[string]any{
+				"bot_token":     "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":       "@mychannel",
+				"template":      "{{.Version}}",
+				"template_file": "plugin.go",
+			},
+			wantValid: false,
+		},
+		{
+			name: "raw payload template",
+			config: map[string]any{
+				"bot_token":            "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":              "@mychannel",
+				"raw_payload_template": `{"text": {{.ReleaseNotes | toJson}}, "protect_content": true}`,
+			},
+			wantValid: true,
+		},
+		{
+			name: "invalid raw payload template",
+			config: map[string]any{
+				"bot_token":            "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":              "@mychannel",
+				"raw_payload_template": `{"text": "{{.Version}}", "protect_content": "yes"}`,
+			},
+			wantValid: false,
+		},
+		{
+			name: "missing error template file",
+			config: map[string]any{
+				"bot_token":           "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":             "@mychannel",
+				"error_template_file": "testdata/missing.tmpl",
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid changes summary mode",
+			config: map[string]any{
+				"bot_token":            "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":              "@mychannel",
+				"changes_summary_mode": "everything",
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid changes sample size",
+			config: map[string]any{
+				"bot_token":           "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":             "@mychannel",
+				"changes_sample_size": 0,
+			},
+			wantValid: false,
+		},
+		{
+			name: "invalid parse mode",
+			config: map[string]any{
+				"bot_token":  "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+				"chat_id":    "@mychannel",
+				"parse_mode": "InvalidMode",
+			},
+			wantValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := p.Validate(ctx, tt.config)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			isValid := len(resp.Errors) == 0
+			if isValid != tt.wantValid {
+				t.Errorf("Validate() valid = %v, want %v; errors: %v", isValid, tt.wantValid, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestBuildSuccessMessageWithChangelog(t *testing.T) {
+	p := &TelegramPlugin{}
+
+	releaseCtx := plugin.ReleaseContext{
+		Version:      "1.0.0",
+		TagName:      "v1.0.0",
+		Branch:       "main",
+		ReleaseType:  "major",
+		ReleaseNotes: "This is the release notes content.",
+	}
+
+	cfg := &Config{
+		ParseMode:          "",
+		IncludeChangelog:   true,
+		MaxChangelogLength: 100,
+	}
+
+	result := p.buildSuccessMessage(cfg, releaseCtx)
+
+	if !strings.Contains(result, "Release Notes:") {
+		t.Error("Expected changelog section in message")
+	}
+	if !strings.Contains(result, "This is the release notes content.") {
+		t.Error("Expected release notes content in message")
+	}
+}
+
+func TestBuildSuccessMessageChangelogTruncation(t *testing.T) {
+	p := &TelegramPlugin{}
+
+	longNotes := strings.Repeat("a", 200)
+	releaseCtx := plugin.ReleaseContext{
+		Version:      "1.0.0",
+		Branch:       "main",
+		ReleaseNotes: longNotes,
+	}
+
+	cfg := &Config{
+		ParseMode:          "",
+		IncludeChangelog:   true,
+		MaxChangelogLength: 50,
+	}
+
+	result := p.buildSuccessMessage(cfg, releaseCtx)
+
+	// Should be truncated with "..."
+	if !strings.Contains(result, "...") {
+		t.Error("Expected truncated changelog with '...'")
+	}
+	// Should not contain the full 200 chars
+	if strings.Contains(result, strings.Repeat("a", 200)) {
+		t.Error("Changelog should be truncated")
+	}
+}
+
+func TestBuildSuccessMessageSectionLinks(t *testing.T) {
+	p := &TelegramPlugin{}
+
+	releaseCtx := plugin.ReleaseContext{
+		Version:      "1.0.0",
+		TagName:      "v1.0.0",
+		ReleaseNotes: "## Breaking Changes\n- removed foo",
+		Changes: &plugin.CategorizedChanges{
+			Breaking: []plugin.ConventionalCommit{{Hash: "abc123", Description: "removed foo"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		parseMode string
+		contains  []string
+	}{
+		{
+			name:      "MarkdownV2",
+			parseMode: "MarkdownV2",
+			contains: []string{
+				"[1 breaking changes](https://example.com/releases/v1.0.0#breaking-changes)",
+				"*[Breaking Changes](https://example.com/releases/v1.0.0#breaking-changes)*",
+				"\\- removed foo",
+			},
+		},
+		{
+			name:      "HTML",
+			parseMode: "HTML",
+			contains: []string{
+				`<a href="https://example.com/releases/v1.0.0#bug-fixes">0 bug fixes</a>`,
+				`<b><a href="https://example.com/releases/v1.0.0">Release Notes</a>:</b>`,
+				`<b><a href="https://example.com/releases/v1.0.0#breaking-changes">Breaking Changes</a></b>`,
+			},
+		},
+		{
+			name:      "plain text",
+			parseMode: "",
+			contains:  []string{"• 1 breaking changes", "## Breaking Changes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ParseMode:        tt.parseMode,
+				IncludeChangelog: true,
+				ReleaseURL:       "https://example.com/releases/v1.0.0",
+			}
+			result := p.buildSuccessMessage(cfg, releaseCtx)
+			for _, c := range tt.contains {
+				if !strings.Contains(result, c) {
+					t.Errorf("buildSuccessMessage() = %q, want to contain %q", result, c)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteDryRunChatTitle(t *testing.T) {
+	var calls int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if !strings.HasSuffix(r.URL.Path, "/getChat") {
+			t.Errorf("unexpected API method %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"id":-1001234567890,"type":"supergroup","title":"ACME Releases"}}`))
+	})
+
+	p := &TelegramPlugin{}
+	req := plugin.ExecuteRequest{
+		Hook:   plugin.HookPostPublish,
+		DryRun: true,
+		Config: map[string]any{
+			"bot_token":          "123:abc",
+			"chat_id":            "-1001234567890",
+			"resolve_chat_title": true,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := p.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		want := "Would send Telegram success notification to 'ACME Releases' (-1001234567890)"
+		if resp.Message != want {
+			t.Errorf("Execute() message = %q, want %q", resp.Message, want)
+		}
+		if resp.Outputs["chat_title"] != "ACME Releases" {
+			t.Errorf("Execute() chat_title = %v, want %q", resp.Outputs["chat_title"], "ACME Releases")
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected chat title to be cached, got %d getChat calls", calls)
+	}
+}
+
+func TestExecuteCircuitBreaker(t *testing.T) {
+	var calls int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 500, Description: "Internal Server Error"})
+	})
+
+	p := &TelegramPlugin{}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":                 "123:abc",
+			"chat_id":                   "@test",
+			"circuit_breaker_threshold": 1,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := p.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if resp.Success || resp.Outputs["circuit_breaker_open"] != nil {
+			t.Fatalf("Execute() #%d = %+v, want plain send failure", i+1, resp)
+		}
+	}
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Success || resp.Outputs["circuit_breaker_open"] != true {
+		t.Errorf("Execute() = %+v, want circuit breaker failure", resp)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 API calls before the breaker tripped, got %d", calls)
+	}
+}
+
+func TestParseConfigEnvDefaults(t *testing.T) {
+	t.Setenv("TELEGRAM_PLUGIN_DEFAULTS", `{"bot_token": "999:org", "parse_mode": "HTML", "max_changelog_length": 500}`)
+
+	p := &TelegramPlugin{}
+	cfg := p.parseConfig(map[string]any{
+		"chat_id":    "@repo",
+		"parse_mode": "MarkdownV2",
+	})
+
+	if cfg.BotToken != "999:org" {
+		t.Errorf("BotToken = %q, want org default", cfg.BotToken)
+	}
+	if cfg.ParseMode != "MarkdownV2" {
+		t.Errorf("ParseMode = %q, want repo value to override default", cfg.ParseMode)
+	}
+	if cfg.MaxChangelogLength != 500 {
+		t.Errorf("MaxChangelogLength = %d, want 500", cfg.MaxChangelogLength)
+	}
+	if cfg.ChatID != "@repo" {
+		t.Errorf("ChatID = %q, want @repo", cfg.ChatID)
+	}
+}
+
+func TestParseConfigAPIURLEnv(t *testing.T) {
+	t.Setenv("TELEGRAM_API_URL", "http://bot-api.internal:8081")
+
+	p := &TelegramPlugin{}
+	if cfg := p.parseConfig(map[string]any{"chat_id": "@repo"}); cfg.APIURL != "http://bot-api.internal:8081" {
+		t.Errorf("APIURL = %q, want the TELEGRAM_API_URL value", cfg.APIURL)
+	}
+	if cfg := p.parseConfig(map[string]any{"chat_id": "@repo", "api_url": "http://localhost:8081"}); cfg.APIURL != "http://localhost:8081" {
+		t.Errorf("APIURL = %q, want api_url to override TELEGRAM_API_URL", cfg.APIURL)
+	}
+
+	t.Setenv("TELEGRAM_API_URL", "bot-api.internal")
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+		"chat_id":   "@repo_releases",
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if resp.Valid {
+		t.Error("Validate() accepted a TELEGRAM_API_URL without a scheme")
+	}
+}
+
+func TestValidateEnvDefaults(t *testing.T) {
+	p := &TelegramPlugin{}
+	config := map[string]any{"chat_id": "@repo_releases"}
+
+	t.Setenv("TELEGRAM_PLUGIN_DEFAULTS", `{"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789"}`)
+	resp, err := p.Validate(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if len(resp.Errors) != 0 {
+		t.Errorf("Validate() errors = %v, want none", resp.Errors)
+	}
+
+	t.Setenv("TELEGRAM_PLUGIN_DEFAULTS", `not json`)
+	resp, err = p.Validate(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	found := false
+	for _, e := range resp.Errors {
+		if e.Field == "TELEGRAM_PLUGIN_DEFAULTS" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Validate() errors = %v, want TELEGRAM_PLUGIN_DEFAULTS error", resp.Errors)
+	}
+}
+
+func TestExecuteCircuitBreakerRecoversWithClock(t *testing.T) {
+	failing := true
+	var sent int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 502, Description: "Bad Gateway"})
+			return
+		}
+		sent++
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	clk := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookOnSuccess,
+		Config: map[string]any{
+			"bot_token":                      "123:abc",
+			"chat_id":                        "@test",
+			"circuit_breaker_threshold":      1,
+			"circuit_breaker_window_seconds": 30,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.Execute(context.Background(), req); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+
+	failing = false
+	resp, _ := p.Execute(context.Background(), req)
+	if resp.Outputs["circuit_breaker_open"] != true {
+		t.Fatalf("Execute() = %+v, want open circuit within the window", resp)
+	}
+
+	clk.Advance(31 * time.Second)
+	resp, _ = p.Execute(context.Background(), req)
+	if !resp.Success {
+		t.Errorf("Execute() after window = %+v, want success", resp)
+	}
+	if sent != 1 {
+		t.Errorf("expected 1 delivered message, got %d", sent)
+	}
+}
+
+func TestExecuteCircuitBreakerSkipsTargetsTogether(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: chat not found"})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookOnSuccess,
+		Config: map[string]any{
+			"bot_token":                 "123:abc",
+			"chat_ids":                  []any{"@a", "@b", "@c", "@d"},
+			"circuit_breaker_threshold": 1,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Outputs["circuit_breaker_open"] != true {
+		t.Fatalf("Execute() outputs = %v, want circuit_breaker_open", resp.Outputs)
+	}
+	targetErrors, _ := resp.Outputs["target_errors"].(map[string]string)
+	if len(targetErrors) != 1 || targetErrors["@b"] == "" {
+		t.Errorf("target_errors = %v, want only @b", targetErrors)
+	}
+	skipped, _ := resp.Outputs["circuit_breaker_skipped"].([]string)
+	if !reflect.DeepEqual(skipped, []string{"@c", "@d"}) {
+		t.Errorf("circuit_breaker_skipped = %v, want [@c @d]", skipped)
+	}
+}
+
+func TestExecuteChangelogTeaser(t *testing.T) {
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":       "123:abc",
+			"chat_id":         "@test",
+			"parse_mode":      "HTML",
+			"changelog_style": "teaser",
+			"teaser_lines":    1,
+			"release_url":     "https://example.com/r/v1.0.0",
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0", ReleaseNotes: "first\nsecond"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+
+	if !strings.Contains(got.Text, "Release Notes</a>:</b>\nfirst\n…") || strings.Contains(got.Text, "second") {
+		t.Errorf("message text = %q, want teaser of the release notes", got.Text)
+	}
+	if got.ReplyMarkup == nil || got.ReplyMarkup.InlineKeyboard[0][0].URL != "https://example.com/r/v1.0.0" {
+		t.Fatalf("reply markup = %+v, want button linking to the release", got.ReplyMarkup)
+	}
+	if got.ReplyMarkup.InlineKeyboard[0][0].Text != "Read full changelog" {
+		t.Errorf("button text = %q, want default label", got.ReplyMarkup.InlineKeyboard[0][0].Text)
+	}
+}
+
+func TestExecuteVersionNotification(t *testing.T) {
+	p := &TelegramPlugin{}
+	releaseCtx := plugin.ReleaseContext{Version: "1.3.0", ReleaseType: "minor"}
+
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostVersion,
+		DryRun:  true,
+		Config:  map[string]any{"bot_token": "123:abc", "chat_id": "@test"},
+		Context: releaseCtx,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Message != "Version notification disabled" {
+		t.Errorf("Execute() message = %q, want disabled by default", resp.Message)
+	}
+
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	resp, err = p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostVersion,
+		Config: map[string]any{
+			"bot_token":         "123:abc",
+			"chat_id":           "@test",
+			"notify_on_version": true,
+			"version_template":  "Heads up: {{.Version}} ({{.ReleaseType}}) is next",
+		},
+		Context: releaseCtx,
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if resp.Message != "Sent Telegram version notification" {
+		t.Errorf("Execute() message = %q", resp.Message)
+	}
+	if got.Text != "Heads up: 1.3.0 (minor) is next" {
+		t.Errorf("message text = %q, want rendered version template", got.Text)
+	}
+}
+
+func TestApplyLinkPreview(t *testing.T) {
+	releaseCtx := plugin.ReleaseContext{Version: "1.2.0", TagName: "v1.2.0"}
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected *LinkPreviewOptions
+		disabled bool
+	}{
+		{
+			name:     "not configured",
+			cfg:      Config{DisableWebPagePreview: true},
+			disabled: true,
+		},
+		{
+			name:     "preview URL",
+			cfg:      Config{DisableWebPagePreview: true, PreviewURLTemplate: "https://github.com/acme/app/releases/tag/{{.TagName}}"},
+			expected: &LinkPreviewOptions{URL: "https://github.com/acme/app/releases/tag/v1.2.0"},
+		},
+		{
+			name:     "above text",
+			cfg:      Config{PreviewURLTemplate: "https://example.com/{{.Version}}", ShowAboveText: true},
+			expected: &LinkPreviewOptions{URL: "https://example.com/1.2.0", ShowAboveText: true},
+		},
+		{
+			name:     "above text keeps previews disabled",
+			cfg:      Config{DisableWebPagePreview: true, ShowAboveText: true},
+			expected: &LinkPreviewOptions{IsDisabled: true, ShowAboveText: true},
+		},
+	}
+
+	p := &TelegramPlugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := newMessage(&tt.cfg, "text")
+			if err := p.applyLinkPreview(&tt.cfg, &msg, releaseCtx); err != nil {
+				t.Fatalf("applyLinkPreview() error = %v", err)
+			}
+			if msg.DisableWebPagePreview != tt.disabled {
+				t.Errorf("DisableWebPagePreview = %v, want %v", msg.DisableWebPagePreview, tt.disabled)
+			}
+			if !reflect.DeepEqual(msg.LinkPreviewOptions, tt.expected) {
+				t.Errorf("LinkPreviewOptions = %+v, want %+v", msg.LinkPreviewOptions, tt.expected)
+			}
+		})
 	}
 }
@@ -0,0 +1,32 @@
+package main
+
+import "sync"
+
+// forEachConcurrently calls fn with 0 through n-1 from at most limit
+// goroutines at a time and waits for all calls to return. A limit below 2
+// calls fn serially, in order.
+func forEachConcurrently(n, limit int, fn func(i int)) {
+	if limit < 2 || n < 2 {
+		for i := range n {
+			fn(i)
+		}
+		return
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(limit, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestForEachConcurrently(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     int
+		limit int
+	}{
+		{"none", 0, 4},
+		{"serial", 5, 1},
+		{"unset limit", 5, 0},
+		{"bounded", 10, 3},
+		{"limit above n", 2, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var calls []int
+			inFlight, peak := 0, 0
+			forEachConcurrently(tt.n, tt.limit, func(i int) {
+				mu.Lock()
+				calls = append(calls, i)
+				inFlight++
+				peak = max(peak, inFlight)
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			})
+
+			if len(calls) != tt.n {
+				t.Fatalf("calls = %v, want %d", calls, tt.n)
+			}
+			if tt.limit < 2 && !slices.IsSorted(calls) {
+				t.Errorf("calls = %v, want serial calls in order", calls)
+			}
+			slices.Sort(calls)
+			for i, call := range calls {
+				if call != i {
+					t.Fatalf("calls = %v, want each index once", calls)
+				}
+			}
+			if limit := max(tt.limit, 1); peak > limit {
+				t.Errorf("peak concurrency = %d, want at most %d", peak, limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// profileEnv names the environment variable selecting the profile when the
+// profile option is not set.
+const profileEnv = "TELEGRAM_PROFILE"
+
+// selectedProfile returns the name of the profile selected by the profile
+// option or the TELEGRAM_PROFILE environment variable. References in the
+// option are expanded, as the profile is selected before the config is
+// interpolated. The variable is ignored by configs without profiles, so it
+// can be set for a whole CI run without failing configs that do not use it.
+func selectedProfile(raw map[string]any) string {
+	name, _ := interpolateValue(raw["profile"], func(string) {}).(string)
+	if profiles, _ := raw["profiles"].(map[string]any); strings.TrimSpace(name) == "" && len(profiles) > 0 {
+		name = os.Getenv(profileEnv)
+	}
+	return strings.TrimSpace(name)
+}
+
+// parseProfiles parses the profiles map, skipping entries that are not
+// objects. Invalid profiles are reported by Validate.
+func parseProfiles(v any) map[string]map[string]any {
+	raw, ok := v.(map[string]any)
+	if !ok || len(raw) == 0 {
+		return nil
+	}
+
+	profiles := make(map[string]map[string]any, len(raw))
+	for name, value := range raw {
+		if profile, ok := value.(map[string]any); ok {
+			profiles[name] = profile
+		}
+	}
+	return profiles
+}
+
+// applyProfile returns raw overlaid with the selected profile, whose keys
+// win over the rest of the config. Without a selected profile, or when it is
+// not defined, raw is returned as is; Validate reports unknown profiles.
+func applyProfile(raw map[string]any) (map[string]any, string) {
+	name := selectedProfile(raw)
+	profile, ok := parseProfiles(raw["profiles"])[name]
+	if name == "" || !ok {
+		return raw, ""
+	}
+	return mergeConfig(raw, profile), name
+}
+
+// validateProfiles reports the first problem with the profiles map or the
+// selected profile, and the option it concerns.
+func validateProfiles(raw map[string]any) (string, error) {
+	var names []string
+	if v := raw["profiles"]; v != nil {
+		profiles, ok := v.(map[string]any)
+		if !ok {
+			return "profiles", fmt.Errorf("must be a map of profile names to config objects")
+		}
+		names = slices.Sorted(maps.Keys(profiles))
+		for _, name := range names {
+			profile, ok := profiles[name].(map[string]any)
+			if !ok {
+				return "profiles", fmt.Errorf("profile %q must be a config object", name)
+			}
+			for _, key := range []string{"profile", "profiles"} {
+				if _, ok := profile[key]; ok {
+					return "profiles", fmt.Errorf("profile %q must not set %s", name, key)
+				}
+			}
+		}
+	}
+
+	if name := selectedProfile(raw); name != "" && !slices.Contains(names, name) {
+		if len(names) == 0 {
+			return "profile", fmt.Errorf("profile %q is selected but no profiles are defined", name)
+		}
+		return "profile", fmt.Errorf("unknown profile %q; available profiles are %s", name, strings.Join(names, ", "))
+	}
+	return "", nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestApplyProfile(t *testing.T) {
+	profiles := map[string]any{
+		"dev":  map[string]any{"chat_id": "@repo_dev", "notify_on_success": false},
+		"prod": map[string]any{"parse_mode": "HTML"},
+	}
+
+	tests := []struct {
+		name        string
+		profile     any
+		env         string
+		wantProfile string
+		wantChatID  string
+	}{
+		{"none", nil, "", "", "@repo_releases"},
+		{"config key", "dev", "", "dev", "@repo_dev"},
+		{"env var", nil, "dev", "dev", "@repo_dev"},
+		{"config key over env var", "prod", "dev", "prod", "@repo_releases"},
+		{"unknown", "qa", "", "", "@repo_releases"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(profileEnv, tt.env)
+			raw := map[string]any{"chat_id": "@repo_releases", "profiles": profiles}
+			if tt.profile != nil {
+				raw["profile"] = tt.profile
+			}
+			got, name := applyProfile(raw)
+			if name != tt.wantProfile || got["chat_id"] != tt.wantChatID {
+				t.Errorf("applyProfile() = %q with chat %v, want %q with chat %q", name, got["chat_id"], tt.wantProfile, tt.wantChatID)
+			}
+		})
+	}
+}
+
+func TestValidateProfiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       map[string]any
+		wantField string
+	}{
+		{"unset", map[string]any{}, ""},
+		{"selected", map[string]any{"profile": "dev", "profiles": map[string]any{"dev": map[string]any{}}}, ""},
+		{"not a map", map[string]any{"profiles": []any{"dev"}}, "profiles"},
+		{"not an object", map[string]any{"profiles": map[string]any{"dev": "@repo_dev"}}, "profiles"},
+		{"nested profiles", map[string]any{"profiles": map[string]any{"dev": map[string]any{"profile": "prod"}}}, "profiles"},
+		{"unknown profile", map[string]any{"profile": "qa", "profiles": map[string]any{"dev": map[string]any{}}}, "profile"},
+		{"no profiles", map[string]any{"profile": "dev"}, "profile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(profileEnv, "")
+			field, err := validateProfiles(tt.raw)
+			if field != tt.wantField || (err != nil) != (tt.wantField != "") {
+				t.Errorf("validateProfiles() = %q, %v; want field %q", field, err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateProfileEnvWithoutProfiles(t *testing.T) {
+	t.Setenv(profileEnv, "staging")
+	for _, raw := range []map[string]any{{}, {"profiles": map[string]any{}}} {
+		if field, err := validateProfiles(raw); err != nil {
+			t.Errorf("validateProfiles(%v) = %q, %v; want TELEGRAM_PROFILE ignored", raw, field, err)
+		}
+		if _, name := applyProfile(raw); name != "" {
+			t.Errorf("applyProfile(%v) applied %q, want no profile", raw, name)
+		}
+	}
+}
+
+func TestExecuteProfile(t *testing.T) {
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+	t.Setenv(profileEnv, "staging")
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token": "123:abc",
+			"chat_id":   "@news",
+			"profiles": map[string]any{
+				"staging": map[string]any{"chat_id": "@news_staging", "parse_mode": "HTML"},
+			},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if got.ChatID != "@news_staging" || got.ParseMode != "HTML" {
+		t.Errorf("sent to %q as %q, want the staging profile", got.ChatID, got.ParseMode)
+	}
+	if resp.Outputs["profile"] != "staging" {
+		t.Errorf("profile output = %v, want staging", resp.Outputs["profile"])
+	}
+}
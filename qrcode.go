@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/relicta-tech/plugin-telegram/internal/qr"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// qrCodeErrorOutput records why the download QR code was not posted.
+var qrCodeErrorOutput = registerDegradation("qr_code_error", describeError("download QR code failed"))
+
+// qrCodeScale is the size of a QR code module in pixels, large enough for
+// the code to survive Telegram's photo compression.
+const qrCodeScale = 10
+
+// sendQRCode posts the URL of qr_code_url_template as a QR code photo
+// replying to the success notification, so readers on a desktop can open
+// the download on their phone. The URL is reported in qr_code_url; a
+// failure sets qr_code_error rather than failing the announcement.
+func (p *TelegramPlugin) sendQRCode(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, outputs map[string]any) {
+	url, err := p.renderTemplate(cfg, cfg.QRCodeURLTemplate, releaseCtx)
+	if err != nil {
+		outputs[qrCodeErrorOutput] = fmt.Sprintf("failed to render QR code URL template: %v", err)
+		return
+	}
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return
+	}
+	code, err := qr.Encode([]byte(url))
+	if err != nil {
+		outputs[qrCodeErrorOutput] = fmt.Sprintf("failed to encode QR code: %v", err)
+		return
+	}
+	if dryRun {
+		outputs["qr_code_url"] = url
+		return
+	}
+	image, err := code.PNG(qrCodeScale)
+	if err != nil {
+		outputs[qrCodeErrorOutput] = fmt.Sprintf("failed to encode QR code: %v", err)
+		return
+	}
+
+	photo := TelegramDocument{
+		ChatID:              cfg.ChatID,
+		MessageThreadID:     cfg.MessageThreadID,
+		Caption:             fmt.Sprintf("📱 Download %s\n%s", releaseCtx.Version, url),
+		DisableNotification: true,
+	}
+	if messageID, ok := outputs["message_id"].(int64); ok {
+		photo.ReplyParameters = &ReplyParameters{MessageID: messageID, AllowSendingWithoutReply: true}
+	}
+	if err := p.uploadPhoto(ctx, cfg, photo, "qr-code.png", image); err != nil {
+		outputs[qrCodeErrorOutput] = errorText(err)
+		return
+	}
+	outputs["qr_code_url"] = url
+}
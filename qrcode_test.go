@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteQRCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		dryRun    bool
+		wantPhoto bool
+		wantURL   string
+		wantError string
+	}{
+		{
+			name:      "photo",
+			url:       "https://example.com/app/{{.Version}}/setup.exe",
+			wantPhoto: true,
+			wantURL:   "https://example.com/app/1.2.0/setup.exe",
+		},
+		{
+			name:    "dry run",
+			url:     "https://example.com/app/{{.Version}}/setup.exe",
+			dryRun:  true,
+			wantURL: "https://example.com/app/1.2.0/setup.exe",
+		},
+		{
+			name:      "too long",
+			url:       "https://example.com/" + strings.Repeat("a", 300),
+			wantError: "failed to encode QR code",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var photo []byte
+			var caption, reply string
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				if !strings.HasSuffix(r.URL.Path, "/sendPhoto") {
+					_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":5}`)})
+					return
+				}
+				file, _, err := r.FormFile("photo")
+				if err != nil {
+					t.Errorf("FormFile() error = %v", err)
+					return
+				}
+				photo, _ = io.ReadAll(file)
+				caption, reply = r.FormValue("caption"), r.FormValue("reply_parameters")
+				_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":6}`)})
+			})
+
+			p := &TelegramPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"bot_token":            "123:abc",
+					"chat_id":              "@test",
+					"qr_code_url_template": tt.url,
+				},
+				Context: plugin.ReleaseContext{Version: "1.2.0"},
+				DryRun:  tt.dryRun,
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+			}
+			if got, _ := resp.Outputs["qr_code_url"].(string); got != tt.wantURL {
+				t.Errorf("qr_code_url = %q, want %q", got, tt.wantURL)
+			}
+			if got, _ := resp.Outputs["qr_code_error"].(string); !strings.Contains(got, tt.wantError) || (got == "") != (tt.wantError == "") {
+				t.Errorf("qr_code_error = %q, want %q", got, tt.wantError)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if (photo != nil) != tt.wantPhoto {
+				t.Fatalf("photo uploaded = %v, want %v", photo != nil, tt.wantPhoto)
+			}
+			if !tt.wantPhoto {
+				return
+			}
+			if want := "📱 Download 1.2.0\n" + tt.wantURL; caption != want {
+				t.Errorf("caption = %q, want %q", caption, want)
+			}
+			if want := `{"message_id":5,"allow_sending_without_reply":true}`; reply != want {
+				t.Errorf("reply_parameters = %s, want %s", reply, want)
+			}
+			if _, err := png.Decode(bytes.NewReader(photo)); err != nil {
+				t.Errorf("photo is not a PNG: %v", err)
+			}
+		})
+	}
+}
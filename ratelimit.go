@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenBucket paces events to rate per second, allowing bursts of up to
+// burst events.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+	// tokens may drop below zero: each missing token is a send waiting for
+	// its turn.
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket refilling at rate per second.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// reserve takes a token at now and returns how long to wait before using it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if !b.last.IsZero() {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// sendLimiter paces the messages of one bot: all chats share a bucket of
+// max_messages_per_second, and each group or channel has a bucket of
+// max_group_messages_per_minute.
+type sendLimiter struct {
+	mu     sync.Mutex
+	global *tokenBucket
+	chats  map[string]*tokenBucket
+	// perMinute is the per-chat limit; zero disables per-chat pacing.
+	perMinute int
+}
+
+// newSendLimiter returns the limiter configured by cfg, or nil when both
+// limits are disabled.
+func newSendLimiter(cfg *Config) *sendLimiter {
+	if cfg.MaxMessagesPerSecond <= 0 && cfg.MaxGroupMessagesPerMinute <= 0 {
+		return nil
+	}
+	l := &sendLimiter{perMinute: cfg.MaxGroupMessagesPerMinute}
+	if cfg.MaxMessagesPerSecond > 0 {
+		l.global = newTokenBucket(float64(cfg.MaxMessagesPerSecond), cfg.MaxMessagesPerSecond)
+	}
+	return l
+}
+
+// isGroupChat reports whether chatID is a group, supergroup, or channel,
+// which Telegram limits per chat, rather than a private chat.
+func isGroupChat(chatID string) bool {
+	return strings.HasPrefix(chatID, "-") || strings.HasPrefix(chatID, "@")
+}
+
+// reserve takes the tokens for a message to chatID at now and returns how
+// long to wait before sending it.
+func (l *sendLimiter) reserve(chatID string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var wait time.Duration
+	if l.global != nil {
+		wait = l.global.reserve(now)
+	}
+	if l.perMinute > 0 && isGroupChat(chatID) {
+		bucket, ok := l.chats[chatID]
+		if !ok {
+			if l.chats == nil {
+				l.chats = make(map[string]*tokenBucket)
+			}
+			bucket = newTokenBucket(float64(l.perMinute)/60, l.perMinute)
+			l.chats[chatID] = bucket
+		}
+		wait = max(wait, bucket.reserve(now))
+	}
+	return wait
+}
+
+// paceSend waits until a message to chatID fits the broadcast limits of
+// cfg's bot. Limits are shared by all sends of the plugin, so a fan-out to
+// many chats stays under Telegram's limits however max_concurrency is set.
+func (p *TelegramPlugin) paceSend(ctx context.Context, cfg *Config, chatID string) error {
+	limiter := p.sendLimiter(cfg)
+	if limiter == nil {
+		return nil
+	}
+	return sleepContext(ctx, p.clockOrDefault(), limiter.reserve(chatID, p.now()))
+}
+
+// limiterKey identifies a send limiter: the bot it paces and its rates, so
+// that a config with other rates gets a limiter of its own.
+type limiterKey struct {
+	bot       string
+	perSecond int
+	perMinute int
+}
+
+// sendLimiter returns the limiter of cfg's bot and rates, creating it from
+// cfg on first use. Targets with their own bot get a limiter per bot, as
+// Telegram limits each bot separately.
+func (p *TelegramPlugin) sendLimiter(cfg *Config) *sendLimiter {
+	key := limiterKey{bot: cfg.botPool, perSecond: cfg.MaxMessagesPerSecond, perMinute: cfg.MaxGroupMessagesPerMinute}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	limiter, ok := p.limiters[key]
+	if !ok {
+		limiter = newSendLimiter(cfg)
+		if p.limiters == nil {
+			p.limiters = make(map[limiterKey]*sendLimiter)
+		}
+		p.limiters[key] = limiter
+	}
+	return limiter
+}
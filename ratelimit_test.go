@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestTokenBucket(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newTokenBucket(2, 2)
+
+	var waits []time.Duration
+	for range 4 {
+		waits = append(waits, b.reserve(start))
+	}
+	// Two sends fit the burst; the rest queue up at the refill rate.
+	want := []time.Duration{0, 0, 500 * time.Millisecond, time.Second}
+	if !reflect.DeepEqual(waits, want) {
+		t.Errorf("waits = %v, want %v", waits, want)
+	}
+
+	// After the queue drains and the bucket refills, the burst is back.
+	if got := b.reserve(start.Add(3 * time.Second)); got != 0 {
+		t.Errorf("wait after refill = %v, want 0", got)
+	}
+}
+
+func TestIsGroupChat(t *testing.T) {
+	for chatID, want := range map[string]bool{
+		"-1001234567890": true,
+		"-123456":        true,
+		"@releases":      true,
+		"123456789":      false,
+	} {
+		if got := isGroupChat(chatID); got != want {
+			t.Errorf("isGroupChat(%q) = %v, want %v", chatID, got, want)
+		}
+	}
+}
+
+func TestExecuteBroadcastRateLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]any
+		want   []time.Duration
+	}{
+		{
+			name: "across chats",
+			config: map[string]any{
+				"chat_ids":                []any{"111", "222", "333", "444", "555"},
+				"max_messages_per_second": 2,
+			},
+			want: []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond},
+		},
+		{
+			name: "per group",
+			config: map[string]any{
+				"chat_ids":                      []any{"-100123@1", "-100123@2", "-100123@3", "-100123@4", "555"},
+				"max_messages_per_second":       0,
+				"max_group_messages_per_minute": 2,
+			},
+			want: []time.Duration{30 * time.Second, 30 * time.Second},
+		},
+		{
+			name: "defaults",
+			config: map[string]any{
+				"chat_ids": []any{"-100123@1", "-100123@2", "-100123@3", "@news", "555"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			sent := 0
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				sent++
+				mu.Unlock()
+				_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":1}`)})
+			})
+
+			config := map[string]any{"bot_token": "123:abc"}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+			clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			p := &TelegramPlugin{clock: clk}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+			}
+			if sent != 5 {
+				t.Errorf("sent %d messages, want 5", sent)
+			}
+			if got := clk.Slept(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("slept = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendLimiterFollowsConfiguredRates(t *testing.T) {
+	p := &TelegramPlugin{}
+	slow := p.sendLimiter(&Config{MaxMessagesPerSecond: 1})
+	if p.sendLimiter(&Config{MaxMessagesPerSecond: 1}) != slow {
+		t.Error("expected the same limiter for the same rates")
+	}
+	fast := p.sendLimiter(&Config{MaxMessagesPerSecond: 10})
+	if fast == slow || fast.global.rate != 10 {
+		t.Errorf("limiter for 10 messages per second = %+v, want a limiter of its own", fast)
+	}
+	if p.sendLimiter(&Config{}) != nil {
+		t.Error("expected no limiter with both limits disabled")
+	}
+}
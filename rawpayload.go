@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// rawPayloadFields maps the sendMessage parameters accepted in a raw
+// payload to their JSON types. Objects such as reply_markup are passed
+// through without checking their contents.
+var rawPayloadFields = map[string][]string{
+	"business_connection_id":    {"string"},
+	"chat_id":                   {"string", "integer"},
+	"message_thread_id":         {"integer"},
+	"direct_messages_topic_id":  {"integer"},
+	"text":                      {"string"},
+	"parse_mode":                {"string"},
+	"entities":                  {"array"},
+	"link_preview_options":      {"object"},
+	"disable_web_page_preview":  {"boolean"},
+	"disable_notification":      {"boolean"},
+	"protect_content":           {"boolean"},
+	"allow_paid_broadcast":      {"boolean"},
+	"message_effect_id":         {"string"},
+	"suggested_post_parameters": {"object"},
+	"reply_parameters":          {"object"},
+	"reply_markup":              {"object"},
+}
+
+// renderRawPayload renders raw_payload_template into a sendMessage body and
+// checks it against rawPayloadFields. The configured chat is used when the
+// payload sets no chat_id.
+func (p *TelegramPlugin) renderRawPayload(cfg *Config, releaseCtx plugin.ReleaseContext) (map[string]any, error) {
+	body, err := p.renderTemplate(cfg, cfg.RawPayloadTemplate, releaseCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render raw payload template: %w", err)
+	}
+	payload, err := parseRawPayload(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw payload: %w", err)
+	}
+	if _, ok := payload["chat_id"]; !ok {
+		payload["chat_id"] = cfg.ChatID
+	}
+	return payload, nil
+}
+
+// parseRawPayload decodes a sendMessage body and validates the parameter
+// names and types. Numbers are kept as json.Number so large IDs survive.
+func parseRawPayload(body string) (map[string]any, error) {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	var payload map[string]any
+	if err := dec.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("not a JSON object: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON object")
+	}
+	if payload == nil {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(payload)) {
+		types, ok := rawPayloadFields[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown sendMessage parameter %q", key)
+		}
+		if got := jsonType(payload[key]); !slices.Contains(types, got) {
+			return nil, fmt.Errorf("%s must be %s, got %s", key, strings.Join(types, " or "), got)
+		}
+	}
+
+	text, _ := payload["text"].(string)
+	if err := checkMessageText(text); err != nil {
+		return nil, fmt.Errorf("text: %w", err)
+	}
+	if mode, ok := payload["parse_mode"].(string); ok {
+		switch mode {
+		case "", "MarkdownV2", "HTML", "Markdown":
+		default:
+			return nil, fmt.Errorf("parse_mode must be MarkdownV2, HTML, or Markdown, got %q", mode)
+		}
+	}
+	return payload, nil
+}
+
+// jsonType returns the JSON schema type of a decoded value.
+func jsonType(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// deliverRaw sends a raw sendMessage body unless the circuit breaker is
+// open. Raw payloads bypass the formatting fallbacks.
+func (p *TelegramPlugin) deliverRaw(ctx context.Context, cfg *Config, payload map[string]any) (delivery, error) {
+	breaker := p.circuitBreaker(cfg)
+	if err := breaker.check(p.now()); err != nil {
+		return delivery{}, err
+	}
+	start := p.now()
+	sent, err := p.sendMessageMigrating(ctx, cfg, payload)
+	if err != nil {
+		breaker.recordError(err, p.now())
+		return delivery{}, err
+	}
+	return delivery{messageID: sent.MessageID, api: p.now().Sub(start)}, nil
+}
+
+// rawPayloadBody formats a payload for the dry-run output.
+func rawPayloadBody(payload map[string]any) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(payload)
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseRawPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "text only", body: `{"text": "hi"}`},
+		{
+			name: "wrapped and unwrapped parameters",
+			body: `{"chat_id": -1001234567890, "text": "hi", "parse_mode": "HTML", "message_effect_id": "5104841245755180586", "protect_content": true, "reply_markup": {"inline_keyboard": []}}`,
+		},
+		{name: "not json", body: `text: hi`, wantErr: true},
+		{name: "not an object", body: `["hi"]`, wantErr: true},
+		{name: "trailing data", body: `{"text": "hi"} {}`, wantErr: true},
+		{name: "missing text", body: `{"chat_id": "@test"}`, wantErr: true},
+		{name: "empty text", body: `{"text": " "}`, wantErr: true},
+		{name: "unknown parameter", body: `{"text": "hi", "disable_notifications": true}`, wantErr: true},
+		{name: "wrong type", body: `{"text": "hi", "message_thread_id": "42"}`, wantErr: true},
+		{name: "fractional ID", body: `{"text": "hi", "message_thread_id": 4.2}`, wantErr: true},
+		{name: "invalid parse mode", body: `{"text": "hi", "parse_mode": "html"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseRawPayload(tt.body); (err != nil) != tt.wantErr {
+				t.Errorf("parseRawPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteRawPayload(t *testing.T) {
+	var got map[string]any
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		_ = dec.Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":7}`)})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":            "123:abc",
+			"chat_id":              "-1001234567890",
+			"raw_payload_template": `{"text": {{printf "Release %s\n%s" .Version .ReleaseNotes | toJson}}, "message_effect_id": "5104841245755180586"}`,
+		},
+		Context: plugin.ReleaseContext{Version: "1.2.0", ReleaseNotes: `Fixed "quotes"`},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+
+	want := map[string]any{
+		"chat_id":           "-1001234567890",
+		"text":              "Release 1.2.0\nFixed \"quotes\"",
+		"message_effect_id": "5104841245755180586",
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("payload[%s] = %v, want %v", key, got[key], value)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("payload = %v, want only %v", got, want)
+	}
+	if resp.Outputs["message_id"] != int64(7) {
+		t.Errorf("message_id = %v, want 7", resp.Outputs["message_id"])
+	}
+}
+
+func TestExecuteRawPayloadInvalid(t *testing.T) {
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:   plugin.HookPostPublish,
+		DryRun: true,
+		Config: map[string]any{
+			"bot_token":            "123:abc",
+			"chat_id":              "@test",
+			"raw_payload_template": `{"text": "{{.Version}}", "silent": true}`,
+		},
+		Context: plugin.ReleaseContext{Version: "1.2.0"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Success {
+		t.Errorf("Execute() = %+v, want failure for an unknown parameter", resp)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// receiptsErrorOutput records why the send receipts were not written.
+var receiptsErrorOutput = registerDegradation("receipts_error", describeError("receipts not written"))
+
+// receipt records a sent message in the receipts file, so an external job
+// can later check that the announcement still exists unedited.
+type receipt struct {
+	Kind      string    `json:"kind"`
+	ChatID    string    `json:"chat_id"`
+	ThreadID  int64     `json:"message_thread_id,omitempty"`
+	MessageID int64     `json:"message_id"`
+	Version   string    `json:"version"`
+	Hash      string    `json:"hash"`
+	SentAt    time.Time `json:"sent_at"`
+	// CorrelationID is the correlation_id of the run that sent the message.
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// newReceipt returns the receipt of n sent to chatID. The hash is the
+// SHA-256 of the message text, or of the JSON body of a raw payload.
+func newReceipt(n notification, chatID string, threadID, messageID int64) receipt {
+	text := n.msg.Text
+	if n.raw != nil {
+		text = rawPayloadBody(n.raw)
+	}
+	sum := sha256.Sum256([]byte(text))
+	return receipt{
+		Kind:      n.kind,
+		ChatID:    chatID,
+		ThreadID:  threadID,
+		MessageID: messageID,
+		Hash:      "sha256:" + hex.EncodeToString(sum[:]),
+	}
+}
+
+// appendReceipts appends one JSON line per receipt to the file at path,
+// creating it and its parent directories. Existing lines are never
+// rewritten.
+func appendReceipts(path, version, correlationID string, sentAt time.Time, receipts []receipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range receipts {
+		r.Version = version
+		r.CorrelationID = correlationID
+		r.SentAt = sentAt.UTC()
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("failed to encode receipt: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create receipts directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open receipts file: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write receipts file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write receipts file: %w", err)
+	}
+	return nil
+}
+
+// writeReceipts appends receipts to receipts_file when one is configured.
+// A failure is reported in outputs["receipts_error"] rather than failing
+// the hook.
+func (p *TelegramPlugin) writeReceipts(cfg *Config, version string, receipts []receipt, outputs map[string]any) {
+	if cfg.ReceiptsFile == "" {
+		return
+	}
+	if err := appendReceipts(cfg.ReceiptsFile, version, cfg.CorrelationID, p.now(), receipts); err != nil {
+		outputs[receiptsErrorOutput] = errorText(err)
+	}
+}
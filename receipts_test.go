@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// readReceipts decodes the receipts file at path.
+func readReceipts(t *testing.T, path string) []receipt {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open receipts: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var receipts []receipt
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r receipt
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("invalid receipt line %q: %v", scanner.Text(), err)
+		}
+		receipts = append(receipts, r)
+	}
+	return receipts
+}
+
+func TestNewReceipt(t *testing.T) {
+	n := notification{kind: "success", msg: TelegramMessage{Text: "test"}}
+	got := newReceipt(n, "@news", 7, 42)
+	want := receipt{
+		Kind:      "success",
+		ChatID:    "@news",
+		ThreadID:  7,
+		MessageID: 42,
+		Hash:      "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	}
+	if got != want {
+		t.Errorf("newReceipt() = %+v, want %+v", got, want)
+	}
+
+	n.raw = map[string]any{"text": "test"}
+	if raw := newReceipt(n, "@news", 0, 42); raw.Hash == want.Hash {
+		t.Error("expected the hash of a raw payload to cover its JSON body")
+	}
+}
+
+func TestAppendReceipts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "receipts.jsonl")
+	sentAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	if err := appendReceipts(path, "1.0.0", "", sentAt, []receipt{{Kind: "success", ChatID: "@news", MessageID: 1}}); err != nil {
+		t.Fatalf("appendReceipts() error = %v", err)
+	}
+	if err := appendReceipts(path, "1.1.0", "", sentAt, []receipt{{Kind: "success", ChatID: "@news", MessageID: 2}}); err != nil {
+		t.Fatalf("appendReceipts() error = %v", err)
+	}
+	if err := appendReceipts(path, "1.2.0", "", sentAt, nil); err != nil {
+		t.Fatalf("appendReceipts(nil) error = %v", err)
+	}
+
+	got := readReceipts(t, path)
+	want := []receipt{
+		{Kind: "success", ChatID: "@news", MessageID: 1, Version: "1.0.0", SentAt: sentAt.UTC()},
+		{Kind: "success", ChatID: "@news", MessageID: 2, Version: "1.1.0", SentAt: sentAt.UTC()},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("receipts = %+v, want %+v", got, want)
+	}
+}
+
+func TestExecuteReceipts(t *testing.T) {
+	var sent int64
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg.ChatID == "@gone" {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was kicked"})
+			return
+		}
+		sent++
+		result, _ := json.Marshal(TelegramSentMessage{MessageID: 40 + sent})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+	})
+
+	path := filepath.Join(t.TempDir(), "receipts.jsonl")
+	p := &TelegramPlugin{clock: newFakeClock(time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":     "123:abc",
+			"chat_ids":      []any{"@news", "@gone", "-1001234567890@7"},
+			"receipts_file": path,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if _, ok := resp.Outputs["receipts_error"]; ok {
+		t.Fatalf("receipts_error = %v", resp.Outputs["receipts_error"])
+	}
+
+	var got []string
+	for _, r := range readReceipts(t, path) {
+		if r.Version != "1.0.0" || r.Hash == "" || !r.SentAt.Equal(p.now()) {
+			t.Errorf("receipt = %+v, want version, hash, and send time", r)
+		}
+		got = append(got, r.ChatID)
+	}
+	if want := []string{"@news", "-1001234567890"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("receipt chats = %v, want %v", got, want)
+	}
+}
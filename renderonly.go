@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// renderOnlyEnv names the environment variable that prints the rendered
+// messages for a release context read from stdin instead of serving the
+// plugin, for iterating on templates locally.
+const renderOnlyEnv = "TELEGRAM_PLUGIN_RENDER_ONLY"
+
+// renderOnlyMode reports whether the --render-only flag or the
+// TELEGRAM_PLUGIN_RENDER_ONLY variable requests render-only mode.
+func renderOnlyMode(args []string, env string) bool {
+	for _, arg := range args {
+		if arg == "--render-only" || arg == "-render-only" {
+			return true
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "", "0", "false":
+		return false
+	default:
+		return true
+	}
+}
+
+// renderOnly reads a release context as JSON from r and writes the message
+// of every hook that notifies to w, without network access. The config is
+// the TELEGRAM_PLUGIN_DEFAULTS org-level defaults with the usual environment
+// fallbacks.
+func (p *TelegramPlugin) renderOnly(r io.Reader, w io.Writer) error {
+	var releaseCtx plugin.ReleaseContext
+	if err := json.NewDecoder(r).Decode(&releaseCtx); err != nil {
+		return fmt.Errorf("failed to decode release context: %w", err)
+	}
+
+	for _, hook := range notifyHooks {
+		cfg := p.parseConfig(map[string]any{})
+		if !cfg.notifies(hook) {
+			continue
+		}
+		hookCtx := releaseCtx
+		if patterns, err := compileExcludePatterns(cfg.ChangelogExcludePatterns); err == nil {
+			hookCtx.ReleaseNotes = render.ExcludeLines(hookCtx.ReleaseNotes, patterns)
+		}
+		cfg.applyHookTemplate(hook)
+
+		text, parseMode, err := p.renderHookMessage(cfg, hook, hookCtx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", hookKey(hook), err)
+		}
+		if _, err := fmt.Fprintf(w, "--- %s (%s) ---\n%s\n\n", hookKey(hook), parseModeName(parseMode), strings.TrimRight(text, "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderHookMessage renders the notification hook sends for releaseCtx the
+// way it would be delivered: from its template and front matter, or the
+// default message. It returns the text and its parse mode, or the JSON body
+// of a raw payload.
+func (p *TelegramPlugin) renderHookMessage(cfg *Config, hook plugin.Hook, releaseCtx plugin.ReleaseContext) (string, string, error) {
+	var tmpl string
+	switch hook {
+	case plugin.HookOnError:
+		text, fm, err := p.renderErrorTemplate(cfg, releaseCtx)
+		if err != nil {
+			return "", "", err
+		}
+		if text == "" {
+			return p.buildErrorMessage(cfg, releaseCtx), cfg.ParseMode, nil
+		}
+		return text, fm.apply(cfg).ParseMode, nil
+	case plugin.HookPostVersion:
+		if cfg.VersionTemplate == "" {
+			return p.buildVersionMessage(cfg, releaseCtx), cfg.ParseMode, nil
+		}
+		tmpl = cfg.VersionTemplate
+	default:
+		if cfg.RawPayloadTemplate != "" {
+			payload, err := p.renderRawPayload(cfg, releaseCtx)
+			if err != nil {
+				return "", "", err
+			}
+			return rawPayloadBody(payload), "raw payload", nil
+		}
+		var err error
+		if tmpl, err = templateSource(cfg.Template, cfg.TemplateFile); err != nil {
+			return "", "", err
+		}
+		if tmpl == "" {
+			return p.buildSuccessMessage(cfg, releaseCtx), cfg.ParseMode, nil
+		}
+	}
+
+	fm, tmpl, err := parseFrontMatter(tmpl)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid template front matter: %w", err)
+	}
+	msgCfg := fm.apply(cfg)
+	text, err := p.renderTemplate(msgCfg, tmpl, releaseCtx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return text, msgCfg.ParseMode, nil
+}
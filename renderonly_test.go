@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderOnlyMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		expected bool
+	}{
+		{"serve", nil, "", false},
+		{"flag", []string{"--render-only"}, "", true},
+		{"env", nil, "1", true},
+		{"env disabled", nil, "0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderOnlyMode(tt.args, tt.env); got != tt.expected {
+				t.Errorf("renderOnlyMode() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderOnly(t *testing.T) {
+	t.Setenv(envDefaults, `{
+		"parse_mode": "HTML",
+		"notify_on": {"post_version": true, "on_success": false},
+		"template": "---\nparse_mode: \"\"\n---\nReleased {{.Version}} from {{.Branch}}",
+		"templates": {"on_error": "<b>{{.Version}}</b> failed"}
+	}`)
+
+	var out bytes.Buffer
+	err := (&TelegramPlugin{}).renderOnly(strings.NewReader(`{"version": "1.2.0", "branch": "main", "release_type": "minor"}`), &out)
+	if err != nil {
+		t.Fatalf("renderOnly() error = %v", err)
+	}
+
+	expected := "--- post_version (HTML) ---\n" +
+		"🔖 <b>Next release will be 1.2.0 (minor)</b>\n\n🌿 <b>Branch:</b> <code>main</code>\n\n" +
+		"--- post_publish (plain text) ---\nReleased 1.2.0 from main\n\n" +
+		"--- on_error (HTML) ---\n<b>1.2.0</b> failed\n\n"
+	if got := out.String(); got != expected {
+		t.Errorf("renderOnly() output = %q, want %q", got, expected)
+	}
+}
+
+func TestRenderOnlyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults string
+		input    string
+		wantErr  string
+	}{
+		{"invalid input", `{}`, `not json`, "failed to decode release context"},
+		{"broken template", `{"template": "{{.Versoin}}"}`, `{"version": "1.0.0"}`, "post_publish: failed to render template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envDefaults, tt.defaults)
+			err := (&TelegramPlugin{}).renderOnly(strings.NewReader(tt.input), &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("renderOnly() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
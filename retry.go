@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const (
+	// minSendBackoff is the default wait before the first retry of a send
+	// that failed without a retry_after hint; it doubles per consecutive
+	// failure.
+	minSendBackoff = 2 * time.Second
+	// maxSendBackoff is the default cap of the wait between retries of a
+	// send.
+	maxSendBackoff = 5 * time.Minute
+	// maxRateLimitRetries bounds how often a rate-limited send is retried
+	// when max_retries is not set.
+	maxRateLimitRetries = 3
+)
+
+// backoffState is the retry backoff of a chat. It is persisted in the state
+// file so that a rerun of a failed hook continues the backoff schedule
+// instead of hitting the API again right away.
+type backoffState struct {
+	// Failures is the number of consecutive failed send attempts.
+	Failures int `json:"failures"`
+	// NotBefore is the earliest time of the next attempt.
+	NotBefore time.Time `json:"not_before"`
+}
+
+// retryPolicy shapes the backoff between retries of a send.
+type retryPolicy struct {
+	// base is the wait after the first failure.
+	base time.Duration
+	// ceiling caps the wait.
+	ceiling time.Duration
+	// jitter randomizes each wait between half and all of it, so chats that
+	// failed together do not retry together.
+	jitter bool
+}
+
+// retryPolicy returns the retry backoff configured by retry_backoff_seconds,
+// retry_max_backoff_seconds, and retry_jitter.
+func (cfg *Config) retryPolicy() retryPolicy {
+	policy := retryPolicy{base: minSendBackoff, ceiling: maxSendBackoff, jitter: cfg.RetryJitter}
+	if cfg.RetryBackoffSeconds > 0 {
+		policy.base = time.Duration(cfg.RetryBackoffSeconds) * time.Second
+	}
+	if cfg.RetryMaxBackoffSeconds > 0 {
+		policy.ceiling = time.Duration(cfg.RetryMaxBackoffSeconds) * time.Second
+	}
+	return policy
+}
+
+// sendBackoff returns the wait after the given number of consecutive
+// failures. The Bot API's retry_after hint takes precedence; otherwise the
+// wait doubles from the policy's base up to its ceiling, with jitter when
+// enabled.
+func sendBackoff(policy retryPolicy, failures int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return time.Duration(apiErr.RetryAfter) * time.Second
+	}
+	wait := policy.base
+	for i := 1; i < failures && wait < policy.ceiling; i++ {
+		wait *= 2
+	}
+	wait = min(wait, policy.ceiling)
+	if policy.jitter && wait > 1 {
+		wait = wait/2 + rand.N(wait/2+1)
+	}
+	return wait
+}
+
+// deliverRetrying sends msg, retrying rate limits, server errors, and
+// network failures up to max_retries times. The backoff of the chat is
+// loaded from the state file first: an attempt scheduled by a previous run
+// is waited for, and the wait keeps growing from where that run stopped.
+// It is saved when the retries run out and cleared after a successful send.
+func (p *TelegramPlugin) deliverRetrying(ctx context.Context, cfg *Config, msg TelegramMessage) (int64, error) {
+	var backoff backoffState
+	if state, err := loadState(cfg.StateFile); err == nil && state.Backoffs[msg.ChatID] != nil {
+		backoff = *state.Backoffs[msg.ChatID]
+	}
+	if err := sleepContext(ctx, p.clockOrDefault(), backoff.NotBefore.Sub(p.now())); err != nil {
+		return 0, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		messageID, err := p.deliverOnce(ctx, cfg, msg)
+		if err == nil {
+			if backoff.Failures > 0 {
+				p.saveBackoff(cfg, msg.ChatID, nil)
+			}
+			return messageID, nil
+		}
+		if !retryable(err) {
+			return 0, err
+		}
+
+		backoff.Failures++
+		wait := sendBackoff(cfg.retryPolicy(), backoff.Failures, err)
+		backoff.NotBefore = p.now().Add(wait)
+		if attempt == cfg.MaxRetries || !p.fitsDeadline(ctx, wait) {
+			p.saveBackoff(cfg, msg.ChatID, &backoff)
+			return 0, err
+		}
+		if sleepErr := sleepContext(ctx, p.clockOrDefault(), wait); sleepErr != nil {
+			p.saveBackoff(cfg, msg.ChatID, &backoff)
+			return 0, err
+		}
+	}
+}
+
+// deliverRateLimited sends msg, waiting out up to maxRateLimitRetries rate
+// limits that carry a retry_after hint. A wait that would run past the
+// context deadline is not attempted, and the rate limit is returned.
+func (p *TelegramPlugin) deliverRateLimited(ctx context.Context, cfg *Config, msg TelegramMessage) (int64, error) {
+	for attempt := 0; ; attempt++ {
+		messageID, err := p.deliverOnce(ctx, cfg, msg)
+		var apiErr *APIError
+		if err == nil || attempt == maxRateLimitRetries || !errors.As(err, &apiErr) ||
+			apiErr.Code != http.StatusTooManyRequests || apiErr.RetryAfter <= 0 {
+			return messageID, err
+		}
+
+		wait := time.Duration(apiErr.RetryAfter) * time.Second
+		if !p.fitsDeadline(ctx, wait) {
+			return 0, err
+		}
+		if sleepErr := sleepContext(ctx, p.clockOrDefault(), wait); sleepErr != nil {
+			return 0, err
+		}
+	}
+}
+
+// fitsDeadline reports whether waiting for d leaves the context deadline,
+// if any, ahead. The time left is measured on the plugin's clock, the one
+// the wait is slept on.
+func (p *TelegramPlugin) fitsDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || deadline.Sub(p.now()) > d
+}
+
+// saveBackoff persists the backoff of a chat, or clears it when backoff is
+// nil. Saving is best effort: without it the next run starts a fresh
+// schedule, as it would without retries.
+func (p *TelegramPlugin) saveBackoff(cfg *Config, chatID string, backoff *backoffState) {
+	_ = p.updateState(cfg.StateFile, func(s *pluginState) {
+		if backoff == nil {
+			delete(s.Backoffs, chatID)
+			return
+		}
+		if s.Backoffs == nil {
+			s.Backoffs = make(map[string]*backoffState)
+		}
+		s.Backoffs[chatID] = backoff
+	})
+}
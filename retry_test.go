@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestSendBackoff(t *testing.T) {
+	defaults := retryPolicy{base: minSendBackoff, ceiling: maxSendBackoff}
+	tests := []struct {
+		name     string
+		policy   retryPolicy
+		failures int
+		err      error
+		want     time.Duration
+	}{
+		{"first failure", defaults, 1, errors.New("connection refused"), 2 * time.Second},
+		{"third failure", defaults, 3, &APIError{Code: 502}, 8 * time.Second},
+		{"capped", defaults, 20, &APIError{Code: 502}, maxSendBackoff},
+		{"retry after hint", defaults, 3, &APIError{Code: 429, RetryAfter: 30}, 30 * time.Second},
+		{"custom base", retryPolicy{base: time.Second, ceiling: time.Minute}, 3, &APIError{Code: 502}, 4 * time.Second},
+		{"custom cap", retryPolicy{base: time.Second, ceiling: 10 * time.Second}, 8, &APIError{Code: 502}, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sendBackoff(tt.policy, tt.failures, tt.err); got != tt.want {
+				t.Errorf("sendBackoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendBackoffJitter(t *testing.T) {
+	policy := retryPolicy{base: minSendBackoff, ceiling: maxSendBackoff, jitter: true}
+	for range 100 {
+		if got := sendBackoff(policy, 3, &APIError{Code: 502}); got < 4*time.Second || got > 8*time.Second {
+			t.Fatalf("sendBackoff() = %v, want between 4s and 8s", got)
+		}
+	}
+	if got := sendBackoff(policy, 3, &APIError{Code: 429, RetryAfter: 30}); got != 30*time.Second {
+		t.Errorf("sendBackoff() = %v, want the retry_after hint as is", got)
+	}
+}
+
+func TestExecuteRetriesResumeBackoff(t *testing.T) {
+	failing := true
+	var attempts int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if failing {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 502, Description: "Bad Gateway"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	clk := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookOnSuccess,
+		Config: map[string]any{
+			"bot_token":   "123:abc",
+			"chat_id":     "@test",
+			"max_retries": 2,
+			"state_file":  stateFile,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+
+	// A fresh plugin per run, as each hook rerun is a new invocation.
+	resp, err := (&TelegramPlugin{clock: clk}).Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Success || attempts != 3 {
+		t.Fatalf("Execute() = %+v after %d attempts, want failure after 3", resp, attempts)
+	}
+	state, err := loadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := backoffState{Failures: 3, NotBefore: clk.Now().Add(8 * time.Second)}
+	if got := state.Backoffs["@test"]; got == nil || *got != want {
+		t.Fatalf("saved backoff = %+v, want %+v", got, want)
+	}
+
+	// The rerun waits out the saved backoff before its first attempt and
+	// keeps doubling from there.
+	resp, _ = (&TelegramPlugin{clock: clk}).Execute(context.Background(), req)
+	if resp.Success || attempts != 6 {
+		t.Fatalf("Execute() = %+v after %d attempts, want failure after 6", resp, attempts)
+	}
+	if got, want := clk.Slept(), []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second}; !slices.Equal(got, want) {
+		t.Errorf("slept %v, want %v", got, want)
+	}
+
+	failing = false
+	resp, _ = (&TelegramPlugin{clock: clk}).Execute(context.Background(), req)
+	if !resp.Success {
+		t.Fatalf("Execute() = %+v, want success", resp)
+	}
+	if state, err = loadState(stateFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.Backoffs["@test"]; ok {
+		t.Errorf("backoff kept after a successful send: %+v", state.Backoffs)
+	}
+}
+
+func TestExecuteRetriesSkipPermanentErrors(t *testing.T) {
+	var attempts int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was kicked"})
+	})
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	p := &TelegramPlugin{clock: newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookOnSuccess,
+		Config: map[string]any{
+			"bot_token":   "123:abc",
+			"chat_id":     "@test",
+			"max_retries": 3,
+			"state_file":  stateFile,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Success || attempts != 1 {
+		t.Errorf("Execute() = %+v after %d attempts, want failure after 1", resp, attempts)
+	}
+	state, err := loadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Backoffs) != 0 {
+		t.Errorf("saved backoff %+v for a permanent error", state.Backoffs)
+	}
+}
+
+func TestExecuteHonorsRetryAfter(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantSuccess  bool
+		wantAttempts int
+		wantSlept    []time.Duration
+	}{
+		{"waits and retries", 0, true, 2, []time.Duration{7 * time.Second}},
+		{"past the deadline", time.Second, false, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if attempts == 1 {
+					_ = json.NewEncoder(w).Encode(TelegramResponse{
+						OK:          false,
+						ErrorCode:   429,
+						Description: "Too Many Requests: retry after 7",
+						Parameters:  &ResponseParameters{RetryAfter: 7},
+					})
+					return
+				}
+				_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+			})
+
+			// The deadline is measured on the plugin's clock, so the fake
+			// clock starts at the real time the deadline is set from.
+			clk := newFakeClock(time.Now())
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			resp, err := (&TelegramPlugin{clock: clk}).Execute(ctx, plugin.ExecuteRequest{
+				Hook:    plugin.HookOnSuccess,
+				Config:  map[string]any{"bot_token": "123:abc", "chat_id": "@test"},
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if resp.Success != tt.wantSuccess || attempts != tt.wantAttempts {
+				t.Errorf("Execute() = %+v after %d attempts, want success %v after %d", resp, attempts, tt.wantSuccess, tt.wantAttempts)
+			}
+			if got := clk.Slept(); !slices.Equal(got, tt.wantSlept) {
+				t.Errorf("slept %v, want %v", got, tt.wantSlept)
+			}
+		})
+	}
+}
+
+func TestFitsDeadlineUsesPluginClock(t *testing.T) {
+	clk := newFakeClock(time.Now())
+	ctx, cancel := context.WithDeadline(context.Background(), clk.Now().Add(time.Minute))
+	defer cancel()
+	p := &TelegramPlugin{clock: clk}
+
+	if !p.fitsDeadline(ctx, 7*time.Second) {
+		t.Error("fitsDeadline() = false with a minute left, want true")
+	}
+	clk.Advance(55 * time.Second)
+	if p.fitsDeadline(ctx, 7*time.Second) {
+		t.Error("fitsDeadline() = true with 5s left on the plugin clock, want false")
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// releaseTypePrerelease is the route_by_release_type key of releases with a
+// pre-release version such as 2.0.0-rc.1.
+const releaseTypePrerelease = "prerelease"
+
+// routeReleaseTypes are the keys route_by_release_type accepts.
+var routeReleaseTypes = []string{"major", "minor", "patch", releaseTypePrerelease}
+
+// parseReleaseTypeRoutes parses route_by_release_type: a chat ID or a list
+// of chat IDs per release type. Invalid entries are skipped; Validate
+// reports them.
+func parseReleaseTypeRoutes(v any) map[string][]string {
+	raw, _ := v.(map[string]any)
+	if len(raw) == 0 {
+		return nil
+	}
+	routes := make(map[string][]string, len(raw))
+	for releaseType, value := range raw {
+		chats := parseStringList(value)
+		if chat, ok := value.(string); ok {
+			chats = []string{chat}
+		}
+		if len(chats) > 0 {
+			routes[strings.ToLower(releaseType)] = chats
+		}
+	}
+	return routes
+}
+
+// validateReleaseTypeRoutes reports the first invalid route_by_release_type
+// entry in key order.
+func validateReleaseTypeRoutes(v any) error {
+	if v == nil {
+		return nil
+	}
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("must be a map of release types to chat IDs")
+	}
+	for _, releaseType := range slices.Sorted(maps.Keys(raw)) {
+		if !slices.Contains(routeReleaseTypes, strings.ToLower(releaseType)) {
+			return fmt.Errorf("unknown release type %q; supported release types are %s", releaseType, strings.Join(routeReleaseTypes, ", "))
+		}
+		var chats []string
+		switch value := raw[releaseType].(type) {
+		case string:
+			chats = []string{value}
+		case []any:
+			for _, item := range value {
+				chat, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("%q must list chat IDs", releaseType)
+				}
+				chats = append(chats, chat)
+			}
+		default:
+			return fmt.Errorf("%q must be a chat ID or a list of chat IDs", releaseType)
+		}
+		if len(chats) == 0 {
+			return fmt.Errorf("%q must name at least one chat", releaseType)
+		}
+		for _, chat := range chats {
+			resolved, _ := resolveChatID(chat)
+			if err := validateChatID(resolved); err != nil {
+				return fmt.Errorf("%q: %w", releaseType, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isPrerelease reports whether version has a pre-release suffix.
+func isPrerelease(version string) bool {
+	core, _, _ := strings.Cut(version, "+")
+	return strings.Contains(core, "-")
+}
+
+// applyReleaseTypeRoute sends the notifications of releaseCtx to the chats
+// routed for its release type instead of chat_id and chat_ids. Pre-releases
+// use the prerelease route when there is one, and the route of their release
+// type otherwise. Targets are notified regardless of the route.
+func (cfg *Config) applyReleaseTypeRoute(releaseCtx plugin.ReleaseContext) {
+	releaseType := strings.ToLower(releaseCtx.ReleaseType)
+	if _, ok := cfg.RouteByReleaseType[releaseTypePrerelease]; ok && isPrerelease(releaseCtx.Version) {
+		releaseType = releaseTypePrerelease
+	}
+	chats, ok := cfg.RouteByReleaseType[releaseType]
+	if !ok {
+		return
+	}
+	cfg.ChatID, cfg.MessageThreadID, cfg.chatTargets = chatIDTargets("", 0, chats)
+	cfg.route = releaseType
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestParseReleaseTypeRoutes(t *testing.T) {
+	got := parseReleaseTypeRoutes(map[string]any{
+		"Major": "@announcements",
+		"patch": []any{"-1001234567890@7", "@qa"},
+		"minor": []any{},
+	})
+	want := map[string][]string{
+		"major": {"@announcements"},
+		"patch": {"-1001234567890@7", "@qa"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseReleaseTypeRoutes() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateReleaseTypeRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  any
+		wantErr bool
+	}{
+		{"unset", nil, false},
+		{"chat and list", map[string]any{"major": "@announcements", "prerelease": []any{"-1001234567890", "@beta_testers"}}, false},
+		{"not a map", "@announcements", true},
+		{"unknown release type", map[string]any{"hotfix": "@ops_team"}, true},
+		{"invalid chat", map[string]any{"patch": "not a chat"}, true},
+		{"empty list", map[string]any{"patch": []any{}}, true},
+		{"non-string entry", map[string]any{"patch": []any{42}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateReleaseTypeRoutes(tt.routes); (err != nil) != tt.wantErr {
+				t.Errorf("validateReleaseTypeRoutes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyReleaseTypeRoute(t *testing.T) {
+	routes := map[string][]string{
+		"major":      {"@announcements", "@partners"},
+		"patch":      {"-1001234567890@7"},
+		"prerelease": {"@beta_testers"},
+	}
+
+	tests := []struct {
+		name        string
+		releaseCtx  plugin.ReleaseContext
+		wantChatID  string
+		wantThread  int64
+		wantTargets []string
+		wantRoute   string
+	}{
+		{"major", plugin.ReleaseContext{Version: "2.0.0", ReleaseType: "major"}, "@announcements", 0, []string{"@partners", "@mirror"}, "major"},
+		{"patch", plugin.ReleaseContext{Version: "1.0.1", ReleaseType: "Patch"}, "-1001234567890", 7, []string{"@mirror"}, "patch"},
+		{"prerelease", plugin.ReleaseContext{Version: "2.0.0-rc.1", ReleaseType: "major"}, "@beta_testers", 0, []string{"@mirror"}, "prerelease"},
+		{"build metadata", plugin.ReleaseContext{Version: "1.0.1+build-5", ReleaseType: "patch"}, "-1001234567890", 7, []string{"@mirror"}, "patch"},
+		{"no route", plugin.ReleaseContext{Version: "1.1.0", ReleaseType: "minor"}, "@news", 0, []string{"@news_archive", "@mirror"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ChatID:             "@news",
+				chatTargets:        []Target{{ChatID: "@news_archive"}},
+				Targets:            []Target{{ChatID: "@mirror"}},
+				RouteByReleaseType: routes,
+			}
+			cfg.applyReleaseTypeRoute(tt.releaseCtx)
+			if cfg.ChatID != tt.wantChatID || cfg.MessageThreadID != tt.wantThread || cfg.route != tt.wantRoute {
+				t.Errorf("routed to %q@%d by %q, want %q@%d by %q",
+					cfg.ChatID, cfg.MessageThreadID, cfg.route, tt.wantChatID, tt.wantThread, tt.wantRoute)
+			}
+			if got := targetChatIDs(cfg.componentTargets()); !reflect.DeepEqual(got, tt.wantTargets) {
+				t.Errorf("targets = %v, want %v", got, tt.wantTargets)
+			}
+		})
+	}
+}
+
+func TestExecuteReleaseTypeRoute(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		sent = append(sent, msg.ChatID)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token": "123:abc",
+			"chat_ids":  []any{"@news", "@news_archive"},
+			"route_by_release_type": map[string]any{
+				"patch": "@internal_team",
+			},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.1", ReleaseType: "patch"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if want := []string{"@internal_team"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+	if resp.Outputs["chat_id"] != "@internal_team" || resp.Outputs["route"] != "patch" {
+		t.Errorf("outputs = %v, want the patch route", resp.Outputs)
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+)
+
+// configSchema is the JSON schema reported by GetInfo. It is generated from
+// the Config struct so new options are reflected automatically: the json
+// tag names a property, and the description, default, enum, and required
+// tags document it.
+var configSchema = mustGenerateSchema(reflect.TypeOf(Config{}))
+
+// schemaOverrides replaces the inferred schema of options whose accepted
+// config values differ from their Go type.
+var schemaOverrides = map[string]map[string]any{
+	"message_thread_id":        {"type": []string{"integer", "string"}},
+	"error_message_thread_id":  {"type": []string{"integer", "string"}},
+	"breaking_alert_thread_id": {"type": []string{"integer", "string"}},
+	"summary_thread_id":        {"type": []string{"integer", "string"}},
+	"notify_on": {
+		"type":                 "object",
+		"properties":           notifyOnSchema(),
+		"additionalProperties": false,
+	},
+	"templates": {
+		"type":                 "object",
+		"properties":           hookStringsSchema(),
+		"additionalProperties": false,
+	},
+	"hook_chat_ids": {
+		"type":                 "object",
+		"properties":           hookStringsSchema(),
+		"additionalProperties": false,
+	},
+	"route_by_release_type": {
+		"type":                 "object",
+		"properties":           releaseTypeRoutesSchema(),
+		"additionalProperties": false,
+	},
+	"profiles": {
+		"type":                 "object",
+		"additionalProperties": map[string]any{"type": "object"},
+	},
+	"max_send_duration": {
+		"type": []string{"string", "number"},
+	},
+	"error_ack_timeout": {
+		"type": []string{"string", "number"},
+	},
+	"language": {
+		"type":  []string{"string", "array"},
+		"items": map[string]any{"type": "string"},
+	},
+	"sections": {
+		"type": "array",
+		"items": map[string]any{
+			"oneOf": []any{
+				map[string]any{"type": "string", "enum": []string{
+					render.SectionHeader, render.SectionVersionInfo, render.SectionChanges,
+					render.SectionBreaking, render.SectionChangelog, render.SectionContributors,
+					render.SectionFooter,
+				}},
+				map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"template": map[string]any{"type": "string"}},
+					"required":   []string{"template"},
+				},
+			},
+		},
+	},
+}
+
+// mustGenerateSchema generates the schema for t and panics on failure, which
+// only happens when a Config field has an unsupported type or a malformed
+// default.
+func mustGenerateSchema(t reflect.Type) string {
+	schema, err := objectSchema(t)
+	if err != nil {
+		panic(fmt.Sprintf("generating config schema: %v", err))
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("generating config schema: %v", err))
+	}
+	return string(data)
+}
+
+// objectSchema returns the schema of a struct type.
+func objectSchema(t reflect.Type) (map[string]any, error) {
+	properties := map[string]any{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+		property, err := fieldSchema(name, field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		properties[name] = property
+		if field.Tag.Get("required") == "true" {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// fieldSchema returns the schema of a struct field from its type and tags.
+func fieldSchema(name string, field reflect.StructField) (map[string]any, error) {
+	var schema map[string]any
+	if override, ok := schemaOverrides[name]; ok {
+		schema = maps.Clone(override)
+	} else {
+		var err error
+		if schema, err = typeSchema(field.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	if description := field.Tag.Get("description"); description != "" {
+		schema["description"] = description
+	}
+	if enum, ok := field.Tag.Lookup("enum"); ok {
+		schema["enum"] = strings.Split(enum, ",")
+	}
+	if value, ok := field.Tag.Lookup("default"); ok {
+		def, err := parseDefault(field.Type, value)
+		if err != nil {
+			return nil, err
+		}
+		schema["default"] = def
+	}
+	return schema, nil
+}
+
+// typeSchema infers the schema of a Go type.
+func typeSchema(t reflect.Type) (map[string]any, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Slice:
+		items, err := typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		// String maps accept scalar values, which parseStringMap converts.
+		if t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String {
+			return map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": []string{"string", "number", "boolean"}},
+			}, nil
+		}
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		return objectSchema(t)
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// parseDefault converts a default tag to the JSON value for t.
+func parseDefault(t reflect.Type, value string) (any, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int64:
+		return strconv.Atoi(value)
+	default:
+		return value, nil
+	}
+}
+
+// jsonName returns the JSON name of a field, or "" if it is not encoded.
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// notifyOnSchema returns the properties of the notify_on map: one per hook
+// the plugin notifies on.
+func notifyOnSchema() map[string]any {
+	defaults := notifyOnDefaults(true, true, false)
+	properties := make(map[string]any, len(notifyHooks))
+	for _, hook := range notifyHooks {
+		properties[hookKey(hook)] = map[string]any{
+			"type":    []string{"boolean", "string"},
+			"default": defaults[hookKey(hook)],
+		}
+	}
+	return properties
+}
+
+// hookStringsSchema returns the properties of maps keyed by hook, such as
+// templates and hook_chat_ids: one string per hook the plugin notifies on.
+func hookStringsSchema() map[string]any {
+	properties := make(map[string]any, len(notifyHooks))
+	for _, hook := range notifyHooks {
+		properties[hookKey(hook)] = map[string]any{"type": "string"}
+	}
+	return properties
+}
+
+// releaseTypeRoutesSchema returns the properties of the route_by_release_type
+// map: a chat ID or list of chat IDs per release type.
+func releaseTypeRoutesSchema() map[string]any {
+	properties := make(map[string]any, len(routeReleaseTypes))
+	for _, releaseType := range routeReleaseTypes {
+		properties[releaseType] = map[string]any{
+			"type":  []string{"string", "array"},
+			"items": map[string]any{"type": "string"},
+		}
+	}
+	return properties
+}
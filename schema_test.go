@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestConfigSchemaMatchesConfig(t *testing.T) {
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Required   []string                  `json:"required"`
+	}
+	if err := json.Unmarshal([]byte(configSchema), &schema); err != nil {
+		t.Fatalf("config schema is not valid JSON: %v", err)
+	}
+
+	configType := reflect.TypeOf(Config{})
+	options := 0
+	for i := 0; i < configType.NumField(); i++ {
+		name := jsonName(configType.Field(i))
+		if name == "" {
+			continue
+		}
+		options++
+		property, ok := schema.Properties[name]
+		if !ok {
+			t.Errorf("option %q is missing from the schema", name)
+			continue
+		}
+		if property["type"] == nil {
+			t.Errorf("option %q has no type", name)
+		}
+		if property["description"] == nil {
+			t.Errorf("option %q has no description", name)
+		}
+	}
+	if len(schema.Properties) != options {
+		t.Errorf("schema has %d properties, Config has %d options", len(schema.Properties), options)
+	}
+	// chat_id may come from TELEGRAM_CHAT_ID or chat_ids instead.
+	if len(schema.Required) != 0 {
+		t.Errorf("required = %v, want none", schema.Required)
+	}
+}
+
+func TestConfigSchemaDefaults(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(configSchema), &schema); err != nil {
+		t.Fatalf("config schema is not valid JSON: %v", err)
+	}
+
+	// Setting every documented default explicitly must not change the
+	// parsed config.
+	p := &TelegramPlugin{}
+	explicit := p.parseConfig(schemaDefaults(schema))
+	implicit := p.parseConfig(map[string]any{})
+
+	// An empty language chain renders in the default language.
+	if reflect.DeepEqual(explicit.Language, []string{"en"}) && implicit.Language == nil {
+		explicit.Language = nil
+	}
+	if !reflect.DeepEqual(explicit, implicit) {
+		t.Errorf("documented defaults differ from parsed defaults:\nexplicit: %+v\nimplicit: %+v", explicit, implicit)
+	}
+}
+
+// schemaDefaults returns a config setting every top-level option with a
+// documented default to that default. The http options are left out: they
+// parse to zero, which newHTTPClient replaces with the transport defaults.
+func schemaDefaults(schema map[string]any) map[string]any {
+	config := map[string]any{}
+	properties, _ := schema["properties"].(map[string]any)
+	for name, v := range properties {
+		property, _ := v.(map[string]any)
+		if def, ok := property["default"]; ok {
+			config[name] = def
+		}
+	}
+	return config
+}
+
+func TestTypeSchemaUnsupported(t *testing.T) {
+	if _, err := typeSchema(reflect.TypeOf(1.5)); err == nil {
+		t.Error("typeSchema(float64) succeeded, want an error")
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// secretFetchTimeout bounds how long a secret provider may take.
+	secretFetchTimeout = 30 * time.Second
+	// maxSecretSize caps how much of a provider's output is read.
+	maxSecretSize = 64 << 10
+)
+
+// SecretSource selects where the bot token is fetched from at run time, so
+// it does not have to be stored in the CI environment.
+type SecretSource struct {
+	// Type is the provider: env, file, exec, or http.
+	Type string `json:"type,omitempty" description:"Secret provider: env, file, exec, or http"`
+	// Env names the environment variable of the env provider.
+	Env string `json:"env,omitempty" description:"Environment variable holding the secret (env)"`
+	// Path is the file of the file provider.
+	Path string `json:"path,omitempty" description:"File holding the secret (file)"`
+	// Command is the program and arguments of the exec provider, which
+	// prints the secret.
+	Command []string `json:"command,omitempty" description:"Command printing the secret, as a program and its arguments (exec)"`
+	// URL is the endpoint of the http provider, such as a cloud metadata
+	// server.
+	URL string `json:"url,omitempty" description:"Endpoint returning the secret, such as a metadata server (http)"`
+	// Headers are sent with the http provider's request.
+	Headers map[string]string `json:"headers,omitempty" description:"Headers of the secret request (http)"`
+}
+
+// secretProvider fetches a secret.
+type secretProvider interface {
+	fetch(ctx context.Context) (string, error)
+}
+
+// envSecret reads a secret from an environment variable.
+type envSecret struct {
+	name string
+}
+
+func (s envSecret) fetch(context.Context) (string, error) {
+	value, ok := os.LookupEnv(s.name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", s.name)
+	}
+	return value, nil
+}
+
+// fileSecret reads a secret from a file, such as a mounted Kubernetes or
+// Docker secret.
+type fileSecret struct {
+	path string
+}
+
+func (s fileSecret) fetch(context.Context) (string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return string(data), nil
+}
+
+// execSecret runs a command, such as vault kv get, and reads the secret
+// from its output. A failure reports only the exit status: the command's
+// stderr may echo the secret or other credentials, and the error ends up in
+// the hook's outputs.
+type execSecret struct {
+	command []string
+}
+
+func (s execSecret) fetch(ctx context.Context) (string, error) {
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.command[0], s.command[1:]...)
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("secret command %s failed: %w", s.command[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// httpSecret fetches a secret from an HTTP endpoint, such as a cloud
+// metadata server.
+type httpSecret struct {
+	url     string
+	headers map[string]string
+}
+
+func (s httpSecret) fetch(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create secret request: %w", err)
+	}
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch secret: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch secret: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSecretSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret: %w", err)
+	}
+	return string(data), nil
+}
+
+// parseSecretSource parses a secret source block. Invalid blocks yield the
+// zero source; Validate reports them.
+func parseSecretSource(v any) SecretSource {
+	raw, ok := v.(map[string]any)
+	if !ok {
+		return SecretSource{}
+	}
+	str := func(key string) string {
+		s, _ := raw[key].(string)
+		return strings.TrimSpace(s)
+	}
+	source := SecretSource{
+		Type:    strings.ToLower(str("type")),
+		Env:     str("env"),
+		Path:    str("path"),
+		Command: parseStringList(raw["command"]),
+		URL:     str("url"),
+	}
+	if headers, ok := raw["headers"].(map[string]any); ok {
+		source.Headers = make(map[string]string, len(headers))
+		for name, value := range headers {
+			source.Headers[name] = fmt.Sprint(value)
+		}
+	}
+	return source
+}
+
+// provider returns the provider of s.
+func (s SecretSource) provider() (secretProvider, error) {
+	switch s.Type {
+	case "env":
+		if s.Env == "" {
+			return nil, fmt.Errorf("the env provider requires env")
+		}
+		return envSecret{s.Env}, nil
+	case "file":
+		if s.Path == "" {
+			return nil, fmt.Errorf("the file provider requires path")
+		}
+		return fileSecret{s.Path}, nil
+	case "exec":
+		if len(s.Command) == 0 {
+			return nil, fmt.Errorf("the exec provider requires command")
+		}
+		return execSecret{s.Command}, nil
+	case "http":
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("the http provider requires an http or https url")
+		}
+		return httpSecret{s.URL, s.Headers}, nil
+	case "":
+		return nil, fmt.Errorf("type is required")
+	default:
+		return nil, fmt.Errorf("unknown type %q, expected env, file, exec, or http", s.Type)
+	}
+}
+
+// validateSecretSource reports the first problem with a secret source
+// block.
+func validateSecretSource(v any) error {
+	if v == nil {
+		return nil
+	}
+	if _, ok := v.(map[string]any); !ok {
+		return fmt.Errorf("must be an object")
+	}
+	_, err := parseSecretSource(v).provider()
+	return err
+}
+
+// resolveBotToken fetches the bot token from bot_token_source, when one is
+// configured, replacing bot_token.
+func (p *TelegramPlugin) resolveBotToken(ctx context.Context, cfg *Config) error {
+	if cfg.BotTokenSource.Type == "" {
+		return nil
+	}
+	provider, err := cfg.BotTokenSource.provider()
+	if err != nil {
+		return fmt.Errorf("invalid bot_token_source: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, secretFetchTimeout)
+	defer cancel()
+	token, err := provider.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch bot token from %s provider: %w", cfg.BotTokenSource.Type, err)
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("the %s provider returned an empty bot token", cfg.BotTokenSource.Type)
+	}
+	cfg.BotToken = token
+	return nil
+}
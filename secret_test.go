@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestSecretSource(t *testing.T) {
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("123:from-http\n"))
+	}))
+	defer metadata.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("123:from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CI_TELEGRAM_TOKEN", "123:from-env")
+
+	tests := []struct {
+		name     string
+		source   map[string]any
+		expected string
+		wantErr  bool
+	}{
+		{"env", map[string]any{"type": "env", "env": "CI_TELEGRAM_TOKEN"}, "123:from-env", false},
+		{"unset env", map[string]any{"type": "env", "env": "CI_MISSING_TOKEN"}, "", true},
+		{"file", map[string]any{"type": "file", "path": tokenFile}, "123:from-file", false},
+		{"missing file", map[string]any{"type": "file", "path": tokenFile + ".missing"}, "", true},
+		{"exec", map[string]any{"type": "exec", "command": []any{"echo", "123:from-exec"}}, "123:from-exec", false},
+		{"failing command", map[string]any{"type": "exec", "command": []any{"false"}}, "", true},
+		{"empty output", map[string]any{"type": "exec", "command": []any{"true"}}, "", true},
+		{"http", map[string]any{"type": "http", "url": metadata.URL, "headers": map[string]any{"Metadata-Flavor": "Google"}}, "123:from-http", false},
+		{"http error", map[string]any{"type": "http", "url": metadata.URL}, "", true},
+		{"unknown type", map[string]any{"type": "keychain"}, "", true},
+	}
+
+	p := &TelegramPlugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{BotToken: "123:configured", BotTokenSource: parseSecretSource(tt.source)}
+			err := p.resolveBotToken(context.Background(), cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveBotToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.BotToken != tt.expected {
+				t.Errorf("BotToken = %q, want %q", cfg.BotToken, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecuteBotTokenSource(t *testing.T) {
+	var path string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":        "123:abc",
+			"bot_token_source": map[string]any{"type": "exec", "command": []any{"echo", "456:fetched"}},
+			"chat_id":          "@repo_releases",
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+	if !strings.HasPrefix(path, "/bot456:fetched/") {
+		t.Errorf("request path = %q, want the fetched token", path)
+	}
+
+	resp, err = p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token_source": map[string]any{"type": "exec", "command": []any{"false"}},
+			"chat_id":          "@repo_releases",
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || resp.Success {
+		t.Fatalf("Execute() = %+v, %v, want a failure", resp, err)
+	}
+}
+
+func TestExecSecretHidesStderr(t *testing.T) {
+	_, err := execSecret{[]string{"sh", "-c", "echo 789:leaked >&2; exit 3"}}.fetch(context.Background())
+	if err == nil {
+		t.Fatal("fetch() error = nil, want the exit status")
+	}
+	if strings.Contains(err.Error(), "789:leaked") || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("fetch() error = %q, want only the exit status", err)
+	}
+}
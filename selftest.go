@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// selfTestEnv names the environment variable that runs the self test instead
+// of serving the plugin: "1" runs the offline checks, "online" also calls
+// getMe with the configured bot token.
+const selfTestEnv = "TELEGRAM_PLUGIN_SELFTEST"
+
+// Self test modes.
+const (
+	selfTestOffline = "offline"
+	selfTestOnline  = "online"
+)
+
+// maxMessageLength is Telegram's limit on message text length.
+const maxMessageLength = 4096
+
+// selfTestConfig is the fixture config checked by the self test. Org-level
+// defaults are applied beneath it as usual.
+var selfTestConfig = map[string]any{
+	"bot_token":          "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+	"chat_id":            "@relicta_selftest",
+	"resolve_chat_title": false,
+	"run_id":             "",
+}
+
+// selfTestRelease is the fixture release context rendered by the self test.
+var selfTestRelease = plugin.ReleaseContext{
+	Version:      "1.2.3",
+	TagName:      "v1.2.3",
+	Branch:       "main",
+	ReleaseType:  "minor",
+	ReleaseNotes: "## Features\n- add [links](https://example.com) & <tags>\n\n## Bug Fixes\n- fix *emphasis*_edge.case",
+	Changes: &plugin.CategorizedChanges{
+		Features: []plugin.ConventionalCommit{{Type: "feat", Description: "add links"}},
+		Fixes:    []plugin.ConventionalCommit{{Type: "fix", Description: "fix emphasis"}},
+		Breaking: []plugin.ConventionalCommit{{Type: "feat", Scope: "api", Description: "drop v1"}},
+	},
+}
+
+// selfTestMode returns the self test mode requested by the --selftest flag or
+// the TELEGRAM_PLUGIN_SELFTEST variable, or "" to serve the plugin.
+func selfTestMode(args []string, env string) string {
+	for _, arg := range args {
+		switch arg {
+		case "--selftest", "-selftest":
+			return selfTestOffline
+		case "--selftest=online", "-selftest=online":
+			return selfTestOnline
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "", "0", "false":
+		return ""
+	case selfTestOnline:
+		return selfTestOnline
+	default:
+		return selfTestOffline
+	}
+}
+
+// selfTestCheck is one named self test step.
+type selfTestCheck struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// selfTest runs the self test checks, reporting each to w. It returns an
+// error if any check failed.
+func (p *TelegramPlugin) selfTest(ctx context.Context, w io.Writer, mode string) error {
+	checks := []selfTestCheck{
+		{"config", p.selfTestConfigCheck},
+		{"render", p.selfTestRenderCheck},
+	}
+	if mode == selfTestOnline {
+		checks = append(checks, selfTestCheck{"getMe", p.selfTestGetMeCheck})
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if err := check.run(ctx); err != nil {
+			failed++
+			_, _ = fmt.Fprintf(w, "FAIL %s: %v\n", check.name, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "ok   %s\n", check.name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d self test checks failed", failed, len(checks))
+	}
+	return nil
+}
+
+// selfTestConfigCheck validates the fixture config with org-level defaults.
+func (p *TelegramPlugin) selfTestConfigCheck(ctx context.Context) error {
+	resp, err := p.Validate(ctx, selfTestConfig)
+	if err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", e.Field, e.Message))
+		}
+		return fmt.Errorf("invalid config: %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// selfTestRenderCheck renders every notification for the fixture release in
+// each parse mode and checks the results can be sent.
+func (p *TelegramPlugin) selfTestRenderCheck(ctx context.Context) error {
+	for _, parseMode := range []string{"MarkdownV2", "HTML", ""} {
+		cfg := p.parseConfig(selfTestConfig)
+		cfg.ParseMode = parseMode
+
+		messages := map[string]func() (string, error){
+			"success": func() (string, error) {
+				if cfg.Template != "" {
+					return p.renderTemplate(cfg, cfg.Template, selfTestRelease)
+				}
+				return p.buildSuccessMessage(cfg, selfTestRelease), nil
+			},
+			"error": func() (string, error) {
+				return p.buildErrorMessage(cfg, selfTestRelease), nil
+			},
+			"version": func() (string, error) {
+				if cfg.VersionTemplate != "" {
+					return p.renderTemplate(cfg, cfg.VersionTemplate, selfTestRelease)
+				}
+				return p.buildVersionMessage(cfg, selfTestRelease), nil
+			},
+		}
+		for kind, build := range messages {
+			text, err := build()
+			if err != nil {
+				return fmt.Errorf("%s message (%q parse mode): %w", kind, parseMode, err)
+			}
+			if err := checkMessageText(text); err != nil {
+				return fmt.Errorf("%s message (%q parse mode): %w", kind, parseMode, err)
+			}
+		}
+	}
+	return nil
+}
+
+// selfTestGetMeCheck verifies the configured bot token with getMe.
+func (p *TelegramPlugin) selfTestGetMeCheck(ctx context.Context) error {
+	cfg := p.parseConfig(map[string]any{})
+	if err := p.resolveBotToken(ctx, cfg); err != nil {
+		return err
+	}
+	if cfg.BotToken == "" {
+		return fmt.Errorf("bot token is not configured (set TELEGRAM_BOT_TOKEN)")
+	}
+	user, err := p.getMe(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if !user.IsBot {
+		return fmt.Errorf("token belongs to a user account, not a bot")
+	}
+	return nil
+}
+
+// checkMessageText reports text that Telegram would reject outright.
+func checkMessageText(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("message is empty")
+	}
+	if !utf8.ValidString(text) {
+		return fmt.Errorf("message is not valid UTF-8")
+	}
+	if n := utf8.RuneCountInString(text); n > maxMessageLength {
+		return fmt.Errorf("message is %d characters, over the %d limit", n, maxMessageLength)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSelfTestMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		env      string
+		expected string
+	}{
+		{"serve", nil, "", ""},
+		{"flag", []string{"--selftest"}, "", selfTestOffline},
+		{"online flag", []string{"--selftest=online"}, "", selfTestOnline},
+		{"env", nil, "1", selfTestOffline},
+		{"env online", nil, "online", selfTestOnline},
+		{"env disabled", nil, "false", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selfTestMode(tt.args, tt.env); got != tt.expected {
+				t.Errorf("selfTestMode() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSelfTestOffline(t *testing.T) {
+	t.Setenv(envDefaults, `{"parse_mode": "HTML", "include_changelog": true}`)
+
+	var out bytes.Buffer
+	if err := (&TelegramPlugin{}).selfTest(context.Background(), &out, selfTestOffline); err != nil {
+		t.Fatalf("selfTest() error = %v\n%s", err, out.String())
+	}
+	if want := "ok   config\nok   render\n"; out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestSelfTestFailures(t *testing.T) {
+	t.Setenv(envDefaults, `{"sections": ["header", "artifacts"]}`)
+
+	var out bytes.Buffer
+	err := (&TelegramPlugin{}).selfTest(context.Background(), &out, selfTestOffline)
+	if err == nil {
+		t.Fatal("expected self test failure")
+	}
+	if !strings.Contains(out.String(), "FAIL config: invalid config: sections[1]") {
+		t.Errorf("output = %q, want config failure", out.String())
+	}
+}
+
+func TestSelfTestOnline(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/getMe") {
+			t.Errorf("unexpected call to %s", r.URL.Path)
+		}
+		result, _ := json.Marshal(TelegramUser{ID: 1, IsBot: true, Username: "release_bot"})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+	})
+
+	t.Setenv("TELEGRAM_BOT_TOKEN", "123:abc")
+	var out bytes.Buffer
+	if err := (&TelegramPlugin{}).selfTest(context.Background(), &out, selfTestOnline); err != nil {
+		t.Fatalf("selfTest() error = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "ok   getMe\n") {
+		t.Errorf("output = %q, want getMe check", out.String())
+	}
+
+	t.Setenv("TELEGRAM_BOT_TOKEN", "")
+	out.Reset()
+	if err := (&TelegramPlugin{}).selfTest(context.Background(), &out, selfTestOnline); err == nil {
+		t.Errorf("expected failure without a bot token, got %q", out.String())
+	}
+}
+
+func TestCheckMessageText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{"ok", "🚀 Release 1.0.0", false},
+		{"empty", " \n", true},
+		{"invalid utf-8", "bad \xff", true},
+		{"at limit", strings.Repeat("é", maxMessageLength), false},
+		{"over limit", strings.Repeat("a", maxMessageLength+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkMessageText(tt.text); (err != nil) != tt.wantErr {
+				t.Errorf("checkMessageText() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"maps"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// Reasons reported in the skip_reason output when a hook sends nothing.
+const (
+	// skipNotifyOnDisabled means notify_on, or a notify_on_* flag, turns
+	// the hook's notification off.
+	skipNotifyOnDisabled = "notify_on_disabled"
+	// skipHookNotHandled means the plugin sends nothing for the hook.
+	skipHookNotHandled = "hook_not_handled"
+	// skipDuplicateRun means run_id already delivered the notification.
+	skipDuplicateRun = "duplicate_run"
+	// skipDuplicateNotification means idempotent found the notification
+	// already delivered.
+	skipDuplicateNotification = "duplicate_notification"
+	// skipDigest means the release was queued for digest_schedule instead.
+	skipDigest = "digest"
+)
+
+// skippedResponse returns the successful response of a hook that sends no
+// notification. The outputs report skipped, the machine-readable reason,
+// and in skip_rule the config rule that decided it, along with extra.
+func skippedResponse(cfg *Config, message, reason, rule string, extra map[string]any) *plugin.ExecuteResponse {
+	outputs := map[string]any{
+		"skipped":     true,
+		"skip_reason": reason,
+		"skip_rule":   rule,
+	}
+	maps.Copy(outputs, extra)
+	return &plugin.ExecuteResponse{
+		Success: true,
+		Message: message,
+		Outputs: addLabels(outputs, cfg.Labels),
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteSkipReasons(t *testing.T) {
+	tests := []struct {
+		name       string
+		hook       plugin.Hook
+		config     map[string]any
+		wantReason string
+		wantRule   string
+	}{
+		{
+			name:       "notify_on",
+			hook:       plugin.HookOnSuccess,
+			config:     map[string]any{"notify_on": map[string]any{"on_success": false}},
+			wantReason: skipNotifyOnDisabled,
+			wantRule:   "notify_on.on_success = false",
+		},
+		{
+			name:       "notify_on flag",
+			hook:       plugin.HookOnError,
+			config:     map[string]any{"notify_on_error": false},
+			wantReason: skipNotifyOnDisabled,
+			wantRule:   "notify_on.on_error = false",
+		},
+		{
+			name:       "post_version off by default",
+			hook:       plugin.HookPostVersion,
+			wantReason: skipNotifyOnDisabled,
+			wantRule:   "notify_on.post_version = false",
+		},
+		{
+			name:       "unhandled hook",
+			hook:       plugin.HookPrePlan,
+			wantReason: skipHookNotHandled,
+			wantRule:   "hook pre_plan",
+		},
+		{
+			name:       "digest",
+			hook:       plugin.HookPostPublish,
+			config:     map[string]any{"digest_schedule": "daily"},
+			wantReason: skipDigest,
+			wantRule:   "digest_schedule daily",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("unexpected request to %s", r.URL.Path)
+			})
+
+			config := map[string]any{
+				"bot_token":  "123:abc",
+				"chat_id":    "@test",
+				"state_file": filepath.Join(t.TempDir(), "state.json"),
+				"labels":     map[string]any{"team": "payments"},
+			}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+			p := &TelegramPlugin{clock: newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    tt.hook,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+			}
+			if resp.Outputs["skipped"] != true || resp.Outputs["skip_reason"] != tt.wantReason || resp.Outputs["skip_rule"] != tt.wantRule {
+				t.Errorf("outputs = %v, want skip_reason %q and skip_rule %q", resp.Outputs, tt.wantReason, tt.wantRule)
+			}
+			if resp.Outputs["labels"] == nil {
+				t.Error("expected labels on the skipped response")
+			}
+		})
+	}
+}
+
+func TestExecuteSkipRuleDuplicateRun(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{clock: newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":         "123:abc",
+			"chat_id":           "@test",
+			"run_id":            "run-42",
+			"dedup_ttl_seconds": 600,
+			"state_file":        filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	}
+	if _, err := p.Execute(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if got := resp.Outputs["skip_rule"]; got != "run_id run-42 within dedup_ttl_seconds 600" {
+		t.Errorf("skip_rule = %v", got)
+	}
+}
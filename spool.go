@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// spooledOutput records the spool file of a notification that was not sent.
+var spooledOutput = registerDegradation("spooled", describeError("notification spooled"))
+
+// spooledTargetsOutput records the spool files of the targets that were not
+// sent to.
+var spooledTargetsOutput = registerDegradation("spooled_targets", describeEach("target notification spooled"))
+
+// spoolErrorOutput records why the spool directory could not be read.
+var spoolErrorOutput = registerDegradation("spool_error", describeError("spool unavailable"))
+
+// spoolErrorsOutput records the spooled notifications Telegram rejected.
+var spoolErrorsOutput = registerDegradation("spool_errors", describeByKey("spooled notification %s dropped: %s"))
+
+// spoolPendingOutput records how many spooled notifications are still waiting.
+var spoolPendingOutput = registerDegradation("spool_pending", func(v any) []string {
+	return []string{fmt.Sprintf("%v spooled notifications still pending", v)}
+})
+
+// spoolEntry is a notification that could not be delivered, kept in the
+// spool directory until a later run delivers it.
+type spoolEntry struct {
+	// SpooledAt is when the delivery failed.
+	SpooledAt time.Time `json:"spooled_at"`
+	// Kind is the kind of notification, such as success.
+	Kind string `json:"kind"`
+	// Payload is the sendMessage request, including the chat.
+	Payload map[string]any `json:"payload"`
+	// CorrelationID is the correlation_id of the run that spooled it.
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// spoolable reports whether a notification that failed with err is worth
+// delivering later: Telegram was unreachable, overloaded, or skipped by the
+// open circuit breaker, rather than rejecting the message.
+func spoolable(err error) bool {
+	return retryable(err) || errors.Is(err, errCircuitOpen)
+}
+
+// spoolNotification writes the message of n to the spool directory and
+// returns the file name. seq orders the notifications spooled at once: the
+// primary chat's first, then each target's.
+func (p *TelegramPlugin) spoolNotification(cfg *Config, n notification, seq int) (string, error) {
+	payload := n.raw
+	if payload == nil {
+		var err error
+		if payload, err = messagePayload(n.msg); err != nil {
+			return "", err
+		}
+	}
+	now := p.now()
+	data, err := json.MarshalIndent(spoolEntry{SpooledAt: now.UTC(), Kind: n.kind, Payload: payload, CorrelationID: cfg.CorrelationID}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode spool entry: %w", err)
+	}
+	name := fmt.Sprintf("%020d-%03d-%s.json", now.UnixNano(), seq, n.kind)
+	if err := writeFileAtomic(filepath.Join(cfg.SpoolDir, name), "spool", data); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// spoolTarget spools n for target when its delivery failed with err and
+// err is worth spooling, and returns the file name. seq orders it after the
+// primary chat's notification, spooled with seq 0. It reports false when
+// the target was not spooled.
+func (p *TelegramPlugin) spoolTarget(cfg *Config, n notification, target Target, seq int, err error) (string, bool) {
+	if cfg.SpoolDir == "" || !spoolable(err) {
+		return "", false
+	}
+	tn, err := forTarget(n, target)
+	if err != nil {
+		return "", false
+	}
+	name, err := p.spoolNotification(cfg, tn, seq)
+	if err != nil {
+		return "", false
+	}
+	return name, true
+}
+
+// spoolConfig returns the config to deliver a spooled payload with: that of
+// the target it was spooled for when the target has a bot of its own.
+func (cfg *Config) spoolConfig(payload map[string]any) *Config {
+	chatID := fmt.Sprint(payload["chat_id"])
+	for _, target := range cfg.componentTargets() {
+		if target.ChatID == chatID && target.BotToken != "" {
+			return cfg.targetConfig(target)
+		}
+	}
+	return cfg
+}
+
+// spoolFiles returns the names of the spooled notifications, oldest first.
+func spoolFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spool directory: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// loadSpoolEntry reads a spooled notification.
+func loadSpoolEntry(path string) (spoolEntry, error) {
+	var entry spoolEntry
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entry, fmt.Errorf("failed to read spool entry: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&entry); err != nil {
+		return entry, fmt.Errorf("failed to decode spool entry: %w", err)
+	}
+	return entry, nil
+}
+
+// flushingSpool delivers the spooled notifications before running execute,
+// so they keep their order ahead of the new notification, and adds the
+// outcome to its outputs.
+func (p *TelegramPlugin) flushingSpool(ctx context.Context, cfg *Config, dryRun bool, execute func() (*plugin.ExecuteResponse, error)) (*plugin.ExecuteResponse, error) {
+	if cfg.SpoolDir == "" {
+		return execute()
+	}
+
+	outputs := p.flushSpool(ctx, cfg, dryRun)
+	resp, err := execute()
+	if err != nil || resp == nil || len(outputs) == 0 {
+		return resp, err
+	}
+	if resp.Outputs == nil {
+		resp.Outputs = map[string]any{}
+	}
+	maps.Copy(resp.Outputs, outputs)
+	return resp, nil
+}
+
+// flushSpool delivers the spooled notifications oldest first, removing each
+// once it is delivered. Delivery stops at the first failure that is worth
+// spooling, as Telegram is still unreachable; entries Telegram rejects are
+// removed and reported in spool_errors. In dry-run mode nothing is sent and
+// the number of pending entries is reported.
+func (p *TelegramPlugin) flushSpool(ctx context.Context, cfg *Config, dryRun bool) map[string]any {
+	names, err := spoolFiles(cfg.SpoolDir)
+	if err != nil {
+		return map[string]any{spoolErrorOutput: errorText(err)}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	if dryRun {
+		return map[string]any{spoolPendingOutput: len(names)}
+	}
+
+	outputs := map[string]any{}
+	flushed := 0
+	failed := map[string]string{}
+	for i, name := range names {
+		path := filepath.Join(cfg.SpoolDir, name)
+		entry, err := loadSpoolEntry(path)
+		if err == nil {
+			_, err = p.deliverRaw(ctx, cfg.spoolConfig(entry.Payload), entry.Payload)
+			if err != nil && spoolable(err) {
+				outputs[spoolPendingOutput] = len(names) - i
+				break
+			}
+		}
+		if err != nil {
+			failed[name] = failureReason(err)
+		} else {
+			flushed++
+		}
+		_ = os.Remove(path)
+	}
+	if flushed > 0 {
+		outputs["spool_flushed"] = flushed
+	}
+	if len(failed) > 0 {
+		outputs[spoolErrorsOutput] = failed
+	}
+	return outputs
+}
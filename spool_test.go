@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteSpool(t *testing.T) {
+	down := true
+	var delivered []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		switch {
+		case down:
+			w.WriteHeader(http.StatusBadGateway)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 502, Description: "Bad Gateway"})
+		case msg.ChatID == "@gone_channel":
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: chat not found"})
+		default:
+			delivered = append(delivered, msg.Text)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+		}
+	})
+
+	spoolDir := filepath.Join(t.TempDir(), "spool")
+	clk := newFakeClock(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	execute := func(version string, dryRun bool) *plugin.ExecuteResponse {
+		t.Helper()
+		clk.Sleep(time.Second)
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook: plugin.HookPostPublish,
+			Config: map[string]any{
+				"bot_token": "123:abc",
+				"chat_id":   "@repo_releases",
+				"template":  "Released " + version,
+				"spool_dir": spoolDir,
+			},
+			Context: plugin.ReleaseContext{Version: version},
+			DryRun:  dryRun,
+		})
+		if err != nil || !resp.Success {
+			t.Fatalf("Execute(%s) = %+v, %v", version, resp, err)
+		}
+		return resp
+	}
+
+	resp := execute("1.0.0", false)
+	if resp.Outputs["spooled"] == nil {
+		t.Errorf("Outputs = %v, want the notification spooled", resp.Outputs)
+	}
+	execute("1.0.1", false)
+	if names, _ := spoolFiles(spoolDir); len(names) != 2 {
+		t.Fatalf("spooled %v, want 2 notifications", names)
+	}
+
+	down = false
+	resp = execute("1.0.2", true)
+	if resp.Outputs["spool_pending"] != 2 || len(delivered) != 0 {
+		t.Errorf("dry run Outputs = %v, delivered %v, want 2 pending", resp.Outputs, delivered)
+	}
+
+	rejected, _ := json.Marshal(spoolEntry{Kind: "success", Payload: map[string]any{"chat_id": "@gone_channel", "text": "Released 0.9.0"}})
+	if err := os.WriteFile(filepath.Join(spoolDir, "00000000000000000000-success.json"), rejected, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resp = execute("1.1.0", false)
+	if want := []string{"Released 1.0.0", "Released 1.0.1", "Released 1.1.0"}; !slices.Equal(delivered, want) {
+		t.Errorf("delivered %v, want %v", delivered, want)
+	}
+	if resp.Outputs["spool_flushed"] != 2 || resp.Outputs["spool_errors"] == nil || resp.Outputs["spooled"] != nil {
+		t.Errorf("Outputs = %v, want 2 flushed and 1 rejected", resp.Outputs)
+	}
+	if names, _ := spoolFiles(spoolDir); len(names) != 0 {
+		t.Errorf("spool still holds %v", names)
+	}
+}
+
+func TestExecuteSpoolTargets(t *testing.T) {
+	down := true
+	var delivered []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if down {
+			w.WriteHeader(http.StatusBadGateway)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 502, Description: "Bad Gateway"})
+			return
+		}
+		delivered = append(delivered, r.URL.Path+" "+msg.ChatID)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	spoolDir := filepath.Join(t.TempDir(), "spool")
+	clk := newFakeClock(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC))
+	p := &TelegramPlugin{clock: clk}
+	execute := func(strict bool) *plugin.ExecuteResponse {
+		t.Helper()
+		clk.Sleep(time.Second)
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook: plugin.HookPostPublish,
+			Config: map[string]any{
+				"bot_token": "123:abc",
+				"chat_ids":  []any{"@first", "@second"},
+				"targets":   []any{map[string]any{"chat_id": "@other_bot", "bot_token": "456:def"}},
+				"spool_dir": spoolDir,
+				"strict":    strict,
+			},
+			Context: plugin.ReleaseContext{Version: "1.0.0"},
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return resp
+	}
+
+	resp := execute(false)
+	if !resp.Success {
+		t.Fatalf("Execute() = %+v, want success", resp)
+	}
+	if spooled, _ := resp.Outputs["spooled_targets"].([]string); len(spooled) != 2 {
+		t.Errorf("spooled_targets = %v, want both targets", resp.Outputs["spooled_targets"])
+	}
+	if deliveries, _ := resp.Outputs["deliveries"].([]map[string]any); len(deliveries) != 3 {
+		t.Errorf("deliveries = %v, want one per chat", resp.Outputs["deliveries"])
+	}
+	if names, _ := spoolFiles(spoolDir); len(names) != 3 {
+		t.Fatalf("spooled %v, want one notification per chat", names)
+	}
+
+	resp = execute(true)
+	if resp.Success {
+		t.Errorf("strict Execute() = %+v, want failure", resp)
+	}
+	if names, _ := spoolFiles(spoolDir); len(names) != 6 {
+		t.Fatalf("spooled %v, want 6 notifications", names)
+	}
+
+	down = false
+	resp = execute(false)
+	want := []string{
+		"/bot123:abc/sendMessage @first", "/bot123:abc/sendMessage @second", "/bot456:def/sendMessage @other_bot",
+		"/bot123:abc/sendMessage @first", "/bot123:abc/sendMessage @second", "/bot456:def/sendMessage @other_bot",
+	}
+	if resp.Outputs["spool_flushed"] != 6 || !slices.Equal(delivered[:6], want) {
+		t.Errorf("flushed %v, delivered %v, want %v", resp.Outputs["spool_flushed"], delivered, want)
+	}
+}
+
+func TestExecuteSpoolsEachFailedChat(t *testing.T) {
+	tests := []struct {
+		name        string
+		primary     TelegramResponse
+		wantSuccess bool
+	}{
+		{"primary delivered", TelegramResponse{OK: true}, true},
+		{"primary rejected", TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: chat not found"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				var msg TelegramMessage
+				_ = json.NewDecoder(r.Body).Decode(&msg)
+				switch msg.ChatID {
+				case "@first":
+					_ = json.NewEncoder(w).Encode(tt.primary)
+				case "@down":
+					w.WriteHeader(http.StatusBadGateway)
+					_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 502, Description: "Bad Gateway"})
+				default:
+					_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+				}
+			})
+
+			spoolDir := filepath.Join(t.TempDir(), "spool")
+			p := &TelegramPlugin{clock: newFakeClock(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC))}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"bot_token": "123:abc",
+					"chat_ids":  []any{"@first", "@down", "@up"},
+					"spool_dir": spoolDir,
+				},
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil || resp.Success != tt.wantSuccess {
+				t.Fatalf("Execute() = %+v, %v; want success %v", resp, err, tt.wantSuccess)
+			}
+			if resp.Outputs["spooled"] != nil || resp.Outputs["target_errors"] != nil {
+				t.Errorf("Outputs = %v, want only @down spooled", resp.Outputs)
+			}
+			names, _ := spoolFiles(spoolDir)
+			if spooled, _ := resp.Outputs["spooled_targets"].([]string); len(names) != 1 || !slices.Equal(spooled, names) {
+				t.Fatalf("spooled_targets = %v, spool holds %v, want one entry", spooled, names)
+			}
+			entry, err := loadSpoolEntry(filepath.Join(spoolDir, names[0]))
+			if err != nil || entry.Payload["chat_id"] != "@down" {
+				t.Errorf("spool entry = %+v, %v; want the @down message", entry, err)
+			}
+		})
+	}
+}
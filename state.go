@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// stateErrorOutput records why the state file could not be updated after a
+// send.
+var stateErrorOutput = registerDegradation("state_error", describeError("state not saved"))
+
+// defaultStateFile is where persisted plugin state is kept by default.
+const defaultStateFile = ".relicta/telegram-state.json"
+
+// pluginState is the state persisted between plugin invocations.
+type pluginState struct {
+	// Deliveries maps delivery keys to the time they were delivered.
+	Deliveries map[string]time.Time `json:"deliveries,omitempty"`
+	// Topics maps chat and topic name keys to created forum topic thread IDs.
+	Topics map[string]int64 `json:"topics,omitempty"`
+	// ChangelogRoots maps chat and thread keys to pinned changelog root
+	// message IDs.
+	ChangelogRoots map[string]int64 `json:"changelog_roots,omitempty"`
+	// Digests maps chat and thread keys to the releases collected for the
+	// next digest.
+	Digests map[string]*digestState `json:"digests,omitempty"`
+	// Backoffs maps chat IDs to the retry backoff of sends that failed.
+	Backoffs map[string]*backoffState `json:"backoffs,omitempty"`
+	// LatestReleasePins maps chat and thread keys to pinned latest release
+	// message IDs.
+	LatestReleasePins map[string]int64 `json:"latest_release_pins,omitempty"`
+	// Announcements maps versions to their success announcements.
+	Announcements map[string]announcement `json:"announcements,omitempty"`
+	// ChatMigrations maps group chat IDs to the supergroups they were
+	// upgraded to.
+	ChatMigrations map[string]string `json:"chat_migrations,omitempty"`
+}
+
+// loadState reads the state file at path. A missing file yields empty state.
+func loadState(path string) (*pluginState, error) {
+	state := &pluginState{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to decode state file: %w", err)
+	}
+	return state, nil
+}
+
+// saveState atomically writes state to path, creating parent directories.
+func saveState(path string, state *pluginState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	return writeFileAtomic(path, "state", data)
+}
+
+// writeFileAtomic writes data to path through a temporary file in the same
+// directory, creating parent directories. kind names the file in errors.
+func writeFileAtomic(path, kind string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", kind, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".telegram-"+kind+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s file: %w", kind, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write %s file: %w", kind, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s file: %w", kind, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s file: %w", kind, err)
+	}
+	return nil
+}
+
+// updateState loads the state file, applies fn, and saves the result. The
+// plugin mutex serializes updates from concurrent hooks.
+func (p *TelegramPlugin) updateState(path string, fn func(*pluginState)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state, err := loadState(path)
+	if err != nil {
+		return err
+	}
+	fn(state)
+	return saveState(path, state)
+}
+
+// pruneDeliveries drops deliveries recorded before cutoff.
+func (s *pluginState) pruneDeliveries(cutoff time.Time) {
+	for key, at := range s.Deliveries {
+		if at.Before(cutoff) {
+			delete(s.Deliveries, key)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+
+	state, err := loadState(path)
+	if err != nil {
+		t.Fatalf("loadState() on missing file error = %v", err)
+	}
+	if len(state.Deliveries) != 0 {
+		t.Errorf("expected empty state, got %+v", state)
+	}
+
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	state.Deliveries = map[string]time.Time{"key": at}
+	if err := saveState(path, state); err != nil {
+		t.Fatalf("saveState() error = %v", err)
+	}
+
+	loaded, err := loadState(path)
+	if err != nil {
+		t.Fatalf("loadState() error = %v", err)
+	}
+	if !loaded.Deliveries["key"].Equal(at) {
+		t.Errorf("loaded deliveries = %+v, want key at %v", loaded.Deliveries, at)
+	}
+}
+
+func TestPruneDeliveries(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	state := &pluginState{Deliveries: map[string]time.Time{
+		"old": now.Add(-48 * time.Hour),
+		"new": now.Add(-time.Hour),
+	}}
+
+	state.pruneDeliveries(now.Add(-24 * time.Hour))
+
+	if _, ok := state.Deliveries["old"]; ok {
+		t.Error("expected old delivery to be pruned")
+	}
+	if _, ok := state.Deliveries["new"]; !ok {
+		t.Error("expected recent delivery to be kept")
+	}
+}
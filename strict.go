@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// degradationOutputs maps the outputs that record a soft degradation to
+// the function describing it. Features register their outputs with
+// registerDegradation, so strict mode covers every output that records a
+// degradation.
+var degradationOutputs = map[string]func(v any) []string{}
+
+// registerDegradation registers the output key as recording a soft
+// degradation described by describe, and returns key for the feature to
+// record the degradation under.
+func registerDegradation(key string, describe func(v any) []string) string {
+	degradationOutputs[key] = describe
+	return key
+}
+
+// describeFlag describes a boolean output that is set when degraded.
+func describeFlag(text string) func(v any) []string {
+	return func(v any) []string {
+		if set, _ := v.(bool); set {
+			return []string{text}
+		}
+		return nil
+	}
+}
+
+// describeError describes an output holding the reason something failed.
+func describeError(what string) func(v any) []string {
+	return func(v any) []string {
+		return []string{fmt.Sprintf("%s: %v", what, v)}
+	}
+}
+
+// describeEach describes an output holding a list of warnings, one
+// degradation per warning.
+func describeEach(what string) func(v any) []string {
+	return func(v any) []string {
+		warnings, _ := v.([]string)
+		found := make([]string, len(warnings))
+		for i, warning := range warnings {
+			found[i] = fmt.Sprintf("%s: %s", what, warning)
+		}
+		return found
+	}
+}
+
+// describeByKey describes an output mapping chats, or other names, to
+// failure reasons, one degradation per entry in key order. format takes the
+// key and the reason.
+func describeByKey(format string) func(v any) []string {
+	return func(v any) []string {
+		failed, _ := v.(map[string]string)
+		found := make([]string, 0, len(failed))
+		for _, key := range slices.Sorted(maps.Keys(failed)) {
+			found = append(found, fmt.Sprintf(format, key, failed[key]))
+		}
+		return found
+	}
+}
+
+// degradations lists the soft degradations recorded in outputs, in order of
+// the outputs recording them.
+func degradations(outputs map[string]any) []string {
+	var found []string
+	for _, key := range slices.Sorted(maps.Keys(degradationOutputs)) {
+		if v, ok := outputs[key]; ok {
+			found = append(found, degradationOutputs[key](v)...)
+		}
+	}
+	return found
+}
+
+// enforceStrict turns a degraded but successful response into a failure when
+// strict mode is enabled. The degradations stay listed in the outputs.
+func (cfg *Config) enforceStrict(resp *plugin.ExecuteResponse, err error) (*plugin.ExecuteResponse, error) {
+	if !cfg.Strict || err != nil || resp == nil || !resp.Success {
+		return resp, err
+	}
+
+	found := degradations(resp.Outputs)
+	if len(found) == 0 {
+		return resp, nil
+	}
+	resp.Success = false
+	resp.Error = fmt.Sprintf("strict mode: notification degraded: %s", strings.Join(found, "; "))
+	resp.Outputs["degradations"] = found
+	return resp, nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestDegradations(t *testing.T) {
+	tests := []struct {
+		name     string
+		outputs  map[string]any
+		expected []string
+	}{
+		{"clean", map[string]any{"chat_id": "@test"}, nil},
+		{"truncated", map[string]any{"truncated": true}, []string{"release notes truncated"}},
+		{"repaired", map[string]any{"formatting_repaired": true}, []string{"template formatting repaired"}},
+		{
+			name:     "broken error template",
+			outputs:  map[string]any{"error_template_error": "unknown variable"},
+			expected: []string{"default error message sent: unknown variable"},
+		},
+		{
+			name:     "topic not created",
+			outputs:  map[string]any{"topic_error": "not enough rights to create a topic"},
+			expected: []string{"topic unavailable: not enough rights to create a topic"},
+		},
+		{
+			name:     "failed forwards",
+			outputs:  map[string]any{"forward_errors": map[string]string{"@b": "blocked", "@a": "gone"}},
+			expected: []string{"forward to @a failed: gone", "forward to @b failed: blocked"},
+		},
+		{
+			name:     "spooled",
+			outputs:  map[string]any{"spooled": "00000000000000000001-000-success.json"},
+			expected: []string{"notification spooled: 00000000000000000001-000-success.json"},
+		},
+		{
+			name:     "failed latest release pins",
+			outputs:  map[string]any{"latest_release_pin_errors": map[string]string{"@b": "not enough rights", "@a": "chat not found"}},
+			expected: []string{"latest release pin in @a failed: chat not found", "latest release pin in @b failed: not enough rights"},
+		},
+		{
+			name:     "missing compare stats",
+			outputs:  map[string]any{"compare_stats_error": "open stats.txt: no such file or directory"},
+			expected: []string{"compare stats unavailable: open stats.txt: no such file or directory"},
+		},
+		{
+			name:     "failed QR code",
+			outputs:  map[string]any{"qr_code_error": "failed to encode QR code: data too long"},
+			expected: []string{"download QR code failed: failed to encode QR code: data too long"},
+		},
+		{
+			name:     "fallback and failed alert",
+			outputs:  map[string]any{"fallback": fallbackMinimalPlainText, "breaking_alert_error": "blocked"},
+			expected: []string{"breaking changes alert failed: blocked", "formatting fallback minimal_plain_text"},
+		},
+		{
+			name: "bookkeeping failures",
+			outputs: map[string]any{
+				"state_error":             "permission denied",
+				"ack_error":               "message to edit not found",
+				"chat_migration_warnings": []string{"chat -42 was upgraded to the supergroup -10042"},
+				"spool_errors":            map[string]string{"1-000-success.json": "chat not found"},
+				"target_fallbacks":        map[string]string{"@b": fallbackPlainText},
+			},
+			expected: []string{
+				"acknowledgment failed: message to edit not found",
+				"chat migrated: chat -42 was upgraded to the supergroup -10042",
+				"spooled notification 1-000-success.json dropped: chat not found",
+				"state not saved: permission denied",
+				"target @b: formatting fallback plain_text",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := degradations(tt.outputs); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("degradations() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecuteStrict(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	releaseCtx := plugin.ReleaseContext{Version: "1.0.0", ReleaseNotes: strings.Repeat("a", 50)}
+	tests := []struct {
+		name        string
+		strict      bool
+		maxLength   int
+		wantSuccess bool
+	}{
+		{"best effort truncation", false, 10, true},
+		{"strict truncation", true, 10, false},
+		{"strict without degradation", true, 100, true},
+	}
+
+	p := &TelegramPlugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook: plugin.HookPostPublish,
+				Config: map[string]any{
+					"bot_token":            "123:abc",
+					"chat_id":              "@test",
+					"include_changelog":    true,
+					"max_changelog_length": tt.maxLength,
+					"strict":               tt.strict,
+				},
+				Context: releaseCtx,
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Errorf("Execute() success = %v, want %v (error %q)", resp.Success, tt.wantSuccess, resp.Error)
+			}
+			if !tt.wantSuccess && !strings.Contains(resp.Error, "release notes truncated") {
+				t.Errorf("Execute() error = %q, want truncation reason", resp.Error)
+			}
+		})
+	}
+}
+
+func TestExecuteStrictNotDeduplicated(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":1}`)})
+	})
+
+	config := map[string]any{
+		"bot_token":            "123:abc",
+		"chat_id":              "@test",
+		"include_changelog":    true,
+		"max_changelog_length": 10,
+		"strict":               true,
+		"run_id":               "run-1",
+		"state_file":           filepath.Join(t.TempDir(), "state.json"),
+	}
+	p := &TelegramPlugin{}
+	for run := 1; run <= 2; run++ {
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook:    plugin.HookPostPublish,
+			Config:  config,
+			Context: plugin.ReleaseContext{Version: "1.0.0", ReleaseNotes: strings.Repeat("a", 50)},
+		})
+		if err != nil {
+			t.Fatalf("run %d: Execute() error = %v", run, err)
+		}
+		if resp.Success || !strings.Contains(resp.Error, "release notes truncated") {
+			t.Errorf("run %d: Execute() = %+v, want strict failure rather than a skip", run, resp)
+		}
+	}
+}
+
+func TestExecuteStrictSpoolErrors(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg.ChatID == "@gone_channel" {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: chat not found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	spoolDir := t.TempDir()
+	rejected, _ := json.Marshal(spoolEntry{Kind: "success", Payload: map[string]any{"chat_id": "@gone_channel", "text": "Released 0.9.0"}})
+	if err := os.WriteFile(filepath.Join(spoolDir, "00000000000000000000-000-success.json"), rejected, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token": "123:abc",
+			"chat_id":   "@test",
+			"spool_dir": spoolDir,
+			"strict":    true,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Success || !strings.Contains(resp.Error, "spooled notification 00000000000000000000-000-success.json dropped: Bad Request: chat not found") {
+		t.Errorf("Execute() = %+v, want a strict failure for the dropped spool entry", resp)
+	}
+}
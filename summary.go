@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// summaryErrorOutput records why the run summary was not sent.
+var summaryErrorOutput = registerDegradation("summary_error", describeError("run summary failed"))
+
+// recordDelivery appends the outcome of delivering a kind of message to a
+// chat to the structured per-chat results in outputs["deliveries"], and
+// returns the recorded result.
+func recordDelivery(outputs map[string]any, kind, chatID string, err error) map[string]any {
+	result := map[string]any{
+		"kind":    kind,
+		"chat_id": chatID,
+		"ok":      err == nil,
+	}
+	if err != nil {
+		result["error"] = failureReason(err)
+	}
+	deliveries, _ := outputs["deliveries"].([]map[string]any)
+	outputs["deliveries"] = append(deliveries, result)
+	return result
+}
+
+// failureReason returns a short description of a delivery failure, with
+// bot tokens redacted.
+func failureReason(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return redactBotTokens(apiErr.Description)
+	}
+	return errorText(err)
+}
+
+// errorText returns the text of err for outputs and messages, with bot
+// tokens redacted.
+func errorText(err error) string {
+	return redactBotTokens(err.Error())
+}
+
+// summaryText renders the run summary for the recorded deliveries.
+func summaryText(version string, deliveries []map[string]any) string {
+	var failed []string
+	for _, d := range deliveries {
+		if ok, _ := d["ok"].(bool); !ok {
+			failed = append(failed, fmt.Sprintf("%s (%s)", d["chat_id"], d["error"]))
+		}
+	}
+
+	text := fmt.Sprintf("📊 Release %s: notified %d/%d chats", version, len(deliveries)-len(failed), len(deliveries))
+	if len(failed) > 0 {
+		text += fmt.Sprintf(", %d failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return text
+}
+
+// sendRunSummary sends a plain-text summary of the run's deliveries to the
+// summary chat, recording the result in resp.Outputs. Nothing is sent in
+// dry-run mode or when no delivery was attempted.
+func (p *TelegramPlugin) sendRunSummary(ctx context.Context, cfg *Config, releaseCtx plugin.ReleaseContext, dryRun bool, resp *plugin.ExecuteResponse) {
+	if cfg.SummaryChatID == "" {
+		return
+	}
+	if resp.Outputs == nil {
+		resp.Outputs = map[string]any{}
+	}
+	resp.Outputs["summary_chat_id"] = cfg.SummaryChatID
+
+	deliveries, _ := resp.Outputs["deliveries"].([]map[string]any)
+	if dryRun || len(deliveries) == 0 {
+		return
+	}
+
+	summaryCfg := *cfg
+	summaryCfg.ChatID = cfg.SummaryChatID
+	summaryCfg.MessageThreadID = cfg.SummaryThreadID
+	summaryCfg.ParseMode = ""
+	msg := newMessage(&summaryCfg, summaryText(releaseCtx.Version, deliveries))
+	if _, err := p.deliver(ctx, &summaryCfg, msg); err != nil {
+		resp.Outputs[summaryErrorOutput] = failureReason(err)
+		return
+	}
+	resp.Outputs["summary_sent"] = true
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestSummaryText(t *testing.T) {
+	ok := map[string]any{"kind": "success", "chat_id": "@news", "ok": true}
+	blocked := map[string]any{"kind": "breaking changes alert", "chat_id": "@old", "ok": false, "error": "Forbidden: bot was blocked by the user"}
+
+	tests := []struct {
+		name       string
+		deliveries []map[string]any
+		expected   string
+	}{
+		{"all delivered", []map[string]any{ok}, "📊 Release 1.0.0: notified 1/1 chats"},
+		{
+			name:       "some failed",
+			deliveries: []map[string]any{ok, blocked},
+			expected:   "📊 Release 1.0.0: notified 1/2 chats, 1 failed: @old (Forbidden: bot was blocked by the user)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summaryText("1.0.0", tt.deliveries); got != tt.expected {
+				t.Errorf("summaryText() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecordDelivery(t *testing.T) {
+	outputs := map[string]any{}
+	recordDelivery(outputs, "success", "@news", nil)
+	recordDelivery(outputs, "error", "@ops", &APIError{Code: 403, Description: "Forbidden: bot was kicked"})
+	recordDelivery(outputs, "error", "@dev", errors.New("connection refused"))
+
+	deliveries := outputs["deliveries"].([]map[string]any)
+	if len(deliveries) != 3 {
+		t.Fatalf("recorded %d deliveries, want 3", len(deliveries))
+	}
+	if deliveries[0]["ok"] != true || deliveries[0]["error"] != nil {
+		t.Errorf("deliveries[0] = %v, want success", deliveries[0])
+	}
+	if deliveries[1]["error"] != "Forbidden: bot was kicked" {
+		t.Errorf("deliveries[1] = %v, want API error description", deliveries[1])
+	}
+	if deliveries[2]["error"] != "connection refused" {
+		t.Errorf("deliveries[2] = %v, want error text", deliveries[2])
+	}
+}
+
+func TestErrorTextRedactsBotTokens(t *testing.T) {
+	err := errors.New(`Post "https://api.telegram.org/bot123456:ABCdefGHIjklMNOpqrSTUvwxYZ0123456789ab/sendMessage": EOF`)
+	want := `Post "https://api.telegram.org/bot123456:***/sendMessage": EOF`
+	if got := errorText(err); got != want {
+		t.Errorf("errorText() = %q, want %q", got, want)
+	}
+	if got := failureReason(err); got != want {
+		t.Errorf("failureReason() = %q, want %q", got, want)
+	}
+}
+
+func TestExecuteRunSummary(t *testing.T) {
+	var summaries []TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		switch msg.ChatID {
+		case "@admins":
+			summaries = append(summaries, msg)
+		case "@old":
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was blocked by the user"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	req := plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":              "123:abc",
+			"chat_id":                "@news",
+			"breaking_alert":         true,
+			"breaking_alert_chat_id": "@old",
+			"summary_chat_id":        "@admins",
+		},
+		Context: plugin.ReleaseContext{
+			Version: "2.0.0",
+			Changes: &plugin.CategorizedChanges{Breaking: []plugin.ConventionalCommit{{Description: "drop v1"}}},
+		},
+	}
+
+	resp, err := p.Execute(context.Background(), req)
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("sent %d summaries, want 1", len(summaries))
+	}
+	want := "📊 Release 2.0.0: notified 1/2 chats, 1 failed: @old (Forbidden: bot was blocked by the user)"
+	if summaries[0].Text != want || summaries[0].ParseMode != "" {
+		t.Errorf("summary = %+v, want plain %q", summaries[0], want)
+	}
+	if resp.Outputs["summary_sent"] != true {
+		t.Errorf("Outputs = %v, want summary_sent", resp.Outputs)
+	}
+
+	// A failed announcement is still summarized.
+	req.Config["chat_id"] = "@old"
+	resp, _ = p.Execute(context.Background(), req)
+	if resp.Success || len(summaries) != 2 {
+		t.Fatalf("Execute() = %+v with %d summaries, want failure with a second summary", resp, len(summaries))
+	}
+
+	// Dry runs send nothing.
+	req.DryRun = true
+	if _, err := p.Execute(context.Background(), req); err != nil || len(summaries) != 2 {
+		t.Errorf("dry run sent a summary")
+	}
+}
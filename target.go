@@ -0,0 +1,399 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/relicta-tech/plugin-telegram/internal/render"
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// targetErrorsOutput records the targets that were not notified.
+var targetErrorsOutput = registerDegradation("target_errors", describeByKey("target %s failed: %s"))
+
+// targetFallbacksOutput records the formatting fallbacks targets were sent
+// with.
+var targetFallbacksOutput = registerDegradation("target_fallbacks", describeByKey("target %s: formatting fallback %s"))
+
+// Target is an additional chat that notifications are sent to, optionally
+// through a different bot such as a regional or brand bot.
+type Target struct {
+	// ChatID is the target chat, in any form chat_id accepts.
+	ChatID string `json:"chat_id" description:"Chat ID or @channel_username" required:"true"`
+	// MessageThreadID is the thread for topic-based groups.
+	MessageThreadID int64 `json:"message_thread_id,omitempty" description:"Thread ID for topic-based groups"`
+	// BotToken is the token of the bot that posts to the chat. Empty uses
+	// the primary bot.
+	BotToken string `json:"bot_token,omitempty" description:"Token of the bot that posts to this chat; defaults to bot_token"`
+	// BotTokenEnv names the environment variable holding the bot token.
+	BotTokenEnv string `json:"bot_token_env,omitempty" description:"Environment variable holding the bot token of this chat"`
+	// Components limits the target to releases of these monorepo
+	// components. Empty receives every release.
+	Components []string `json:"components,omitempty" description:"Only notify this chat for releases of these components"`
+	// ParseMode is the parse mode of the messages sent to the target; nil
+	// uses parse_mode. The message is rendered again in this mode.
+	ParseMode *string `json:"parse_mode,omitempty" description:"Parse mode of this chat, e.g. HTML for a bot that only parses HTML; defaults to parse_mode" enum:"MarkdownV2,HTML,"`
+	// Language is the message language or fallback chain of the target;
+	// empty uses language. The message is rendered again in it.
+	Language []string `json:"language,omitempty" description:"Message language or fallback chain of this chat; defaults to language"`
+	// AlwaysDryRun renders and reports the notifications of the target
+	// without sending them, for trying out a new chat in shadow mode.
+	AlwaysDryRun bool `json:"always_dry_run,omitempty" description:"Report what would be sent to this chat without sending it"`
+	// Labels are free-form metadata (team, region, audience) attached to
+	// the target's entries in deliveries, target_errors, and permalinks.
+	Labels map[string]string `json:"labels,omitempty" description:"Free-form labels (team, region, audience) attached to this chat's deliveries and target_errors"`
+}
+
+// parseTargets parses the targets list. A chat ID in the chat_id@thread or
+// t.me link form is split into the chat and thread, and bot_token_env is
+// resolved when bot_token is not set.
+func parseTargets(v any) []Target {
+	items, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+
+	targets := make([]Target, 0, len(items))
+	for _, item := range items {
+		raw, _ := item.(map[string]any)
+		chatID, _ := raw["chat_id"].(string)
+		token, _ := raw["bot_token"].(string)
+		tokenEnv, _ := raw["bot_token_env"].(string)
+		threadID, _ := parseThreadID(raw["message_thread_id"])
+		dryRun, _ := raw["always_dry_run"].(bool)
+		var parseMode *string
+		if mode, ok := raw["parse_mode"].(string); ok {
+			parseMode = &mode
+		}
+
+		chatID, linkThreadID := resolveChatID(chatID)
+		if threadID == 0 {
+			threadID = linkThreadID
+		}
+		if token == "" && tokenEnv != "" {
+			token = os.Getenv(tokenEnv)
+		}
+		targets = append(targets, Target{
+			ChatID:          chatID,
+			MessageThreadID: threadID,
+			BotToken:        strings.TrimSpace(token),
+			BotTokenEnv:     tokenEnv,
+			Components:      parseStringList(raw["components"]),
+			ParseMode:       parseMode,
+			Language:        parseLanguage(raw["language"]),
+			AlwaysDryRun:    dryRun,
+			Labels:          parseStringMap(raw["labels"]),
+		})
+	}
+	return targets
+}
+
+// chatIDTargets resolves chat_ids against the primary chat. Without chat_id
+// the first entry becomes the primary chat; the other entries are sent to as
+// targets of the primary bot. Chats listed twice are notified once.
+func chatIDTargets(chatID string, threadID int64, chatIDs []string) (string, int64, []Target) {
+	type chat struct {
+		id     string
+		thread int64
+	}
+	seen := map[chat]bool{}
+	var targets []Target
+	for _, entry := range chatIDs {
+		id, entryThreadID := resolveChatID(strings.TrimSpace(entry))
+		if id == "" {
+			continue
+		}
+		if chatID == "" {
+			chatID, threadID = id, entryThreadID
+		}
+		if c := (chat{id, entryThreadID}); !seen[c] {
+			seen[c] = true
+			if c != (chat{chatID, threadID}) {
+				targets = append(targets, Target{ChatID: id, MessageThreadID: entryThreadID})
+			}
+		}
+	}
+	return chatID, threadID, targets
+}
+
+// validateTarget reports the first problem with a target.
+func validateTarget(t Target) (string, error) {
+	if t.ChatID == "" {
+		return "chat_id", fmt.Errorf("chat_id is required")
+	}
+	if err := validateChatID(t.ChatID); err != nil {
+		return "chat_id", err
+	}
+	if t.MessageThreadID < 0 {
+		return "message_thread_id", fmt.Errorf("must not be negative")
+	}
+	if t.ParseMode != nil && *t.ParseMode != "" && *t.ParseMode != "MarkdownV2" && *t.ParseMode != "HTML" {
+		return "parse_mode", fmt.Errorf("parse mode must be 'MarkdownV2', 'HTML', or empty")
+	}
+	for _, lang := range t.Language {
+		if !render.IsSupportedLanguage(lang) {
+			return "language", fmt.Errorf("unsupported language %q (expected de, en, es, fr, pt, or a regional variant such as pt-BR)", lang)
+		}
+	}
+	if t.BotTokenEnv != "" && t.BotToken == "" {
+		return "bot_token_env", fmt.Errorf("environment variable %s is not set", t.BotTokenEnv)
+	}
+	if t.BotToken != "" {
+		if err := validateBotToken(t.BotToken); err != nil {
+			return "bot_token", err
+		}
+	}
+	return "", nil
+}
+
+// targetConfig returns the config for sending to t. A target with its own
+// bot gets its own connection pool and circuit breaker, so one bot being
+// rate limited or failing does not hold back the others.
+func (cfg *Config) targetConfig(t Target) *Config {
+	targetCfg := *cfg
+	targetCfg.ChatID = t.ChatID
+	targetCfg.MessageThreadID = t.MessageThreadID
+	if t.BotToken != "" && t.BotToken != cfg.BotToken {
+		targetCfg.BotToken = t.BotToken
+		targetCfg.botPool = t.BotToken
+	}
+	return &targetCfg
+}
+
+// notifyTargets sends n to the additional targets after the primary chat,
+// whether or not the primary chat received it, up to max_concurrency chats
+// at a time, recording a delivery and permalink per target in target order,
+// the failures in outputs["target_errors"], and the formatting fallbacks in
+// outputs["target_fallbacks"]. With spool_dir set, each target whose send
+// is worth retrying later is spooled on its own and listed in
+// outputs["spooled_targets"] instead. Targets the open circuit breaker
+// skipped are
+// listed together in outputs["circuit_breaker_skipped"] instead of failing
+// one by one. Failed targets do not fail the hook; only the
+// primary chat does. Targets in shadow mode are only reported in
+// outputs["dry_run_targets"], and targets an earlier run delivered to are
+// skipped. Targets sharing a chat, such as several
+// topics of one group, are sent to one after another in target order, each
+// send awaited with its retries before the next, so their messages never
+// interleave. It returns the receipts of the messages sent.
+func (p *TelegramPlugin) notifyTargets(ctx context.Context, cfg *Config, n notification, outputs map[string]any) []receipt {
+	var targets []Target
+	for _, target := range cfg.componentTargets() {
+		if target.AlwaysDryRun {
+			recordDryRunTarget(outputs, n, target)
+			continue
+		}
+		if cfg.delivered[target.recipient()] {
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	sent := make([]delivery, len(targets))
+	errs := make([]error, len(targets))
+	groups := targetsByChat(targets)
+	forEachConcurrently(len(groups), cfg.MaxConcurrency, func(g int) {
+		for _, i := range groups[g] {
+			sent[i], errs[i] = p.sendToTarget(ctx, cfg, n, targets[i])
+		}
+	})
+	p.recordMetrics(cfg, targets, sent, errs)
+
+	var receipts []receipt
+	var spooled []string
+	failed := map[string]string{}
+	fallbacks := map[string]string{}
+	for i, target := range targets {
+		recordTargetDelivery(outputs, n.kind, target, errs[i])
+		if errs[i] != nil {
+			if name, ok := p.spoolTarget(cfg, n, target, i+1, errs[i]); ok {
+				cfg.markHandled(target.recipient())
+				spooled = append(spooled, name)
+				continue
+			}
+			if !recordCircuitSkip(outputs, target.ChatID, errs[i]) {
+				failed[target.ChatID] = targetFailure(target, errs[i])
+			}
+			continue
+		}
+		cfg.markHandled(target.recipient())
+		if sent[i].fallback != "" {
+			fallbacks[target.ChatID] = sent[i].fallback
+		}
+		if link := recordPermalink(outputs, n.kind, target.ChatID, target.MessageThreadID, sent[i].messageID); link != nil && len(target.Labels) > 0 {
+			link["labels"] = maps.Clone(target.Labels)
+		}
+		if sent[i].messageID != 0 {
+			receipts = append(receipts, newReceipt(n, target.ChatID, target.MessageThreadID, sent[i].messageID))
+		}
+	}
+	if len(spooled) > 0 {
+		outputs[spooledTargetsOutput] = spooled
+	}
+	if len(failed) > 0 {
+		outputs[targetErrorsOutput] = failed
+	}
+	if len(fallbacks) > 0 {
+		outputs[targetFallbacksOutput] = fallbacks
+	}
+	return receipts
+}
+
+// recordTargetDelivery records the delivery to target like recordDelivery,
+// with the target's labels.
+func recordTargetDelivery(outputs map[string]any, kind string, target Target, err error) {
+	result := recordDelivery(outputs, kind, target.ChatID, err)
+	if len(target.Labels) > 0 {
+		result["labels"] = maps.Clone(target.Labels)
+	}
+}
+
+// targetFailure returns the target_errors entry of a failed target: the
+// failure reason, followed by the target's labels, if any.
+func targetFailure(target Target, err error) string {
+	if len(target.Labels) == 0 {
+		return failureReason(err)
+	}
+	return fmt.Sprintf("%s [%s]", failureReason(err), labelGroup(target.Labels))
+}
+
+// targetsByChat groups the indexes of targets by chat, in order of first
+// appearance, keeping target order within each group.
+func targetsByChat(targets []Target) [][]int {
+	var groups [][]int
+	group := map[string]int{}
+	for i, target := range targets {
+		g, ok := group[target.ChatID]
+		if !ok {
+			g = len(groups)
+			group[target.ChatID] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// sendToTarget delivers n to target, with the target's chat, thread, bot,
+// and parse mode.
+func (p *TelegramPlugin) sendToTarget(ctx context.Context, cfg *Config, n notification, target Target) (delivery, error) {
+	n, err := forTarget(n, target)
+	if err != nil {
+		return delivery{}, err
+	}
+	targetCfg := cfg.targetConfig(target)
+	if n.raw != nil {
+		return p.deliverRaw(ctx, targetCfg, n.raw)
+	}
+	return p.deliverWithFallbacks(ctx, targetCfg, n.msg, n.fallbacks)
+}
+
+// forTarget returns n addressed to target, rendered again in the target's
+// parse mode and language when it sets them.
+func forTarget(n notification, target Target) (notification, error) {
+	ownMode := target.ParseMode != nil && *target.ParseMode != n.msg.ParseMode
+	if (ownMode || len(target.Language) > 0) && n.rerender != nil {
+		mode := n.msg.ParseMode
+		if target.ParseMode != nil {
+			mode = *target.ParseMode
+		}
+		var err error
+		if n, err = n.rerender(target); err != nil {
+			return notification{}, fmt.Errorf("failed to render %s message: %w", parseModeName(mode), err)
+		}
+	}
+	if n.raw != nil {
+		raw := maps.Clone(n.raw)
+		raw["chat_id"] = target.ChatID
+		delete(raw, "message_thread_id")
+		if target.MessageThreadID != 0 {
+			raw["message_thread_id"] = target.MessageThreadID
+		}
+		n.raw = raw
+		return n, nil
+	}
+	n.msg.ChatID = target.ChatID
+	n.msg.MessageThreadID = target.MessageThreadID
+	return n, nil
+}
+
+// withRerender returns n with a rerender function that renders the message
+// hook sends for releaseCtx again in a target's parse mode and language, the
+// way renderHookMessage does, keeping the rest of the message. A template
+// that sets parse_mode in its front matter keeps its own mode.
+func (p *TelegramPlugin) withRerender(cfg *Config, hook plugin.Hook, releaseCtx plugin.ReleaseContext, n notification) notification {
+	n.rerender = func(target Target) (notification, error) {
+		modeCfg := *cfg
+		if target.ParseMode != nil {
+			modeCfg.ParseMode = *target.ParseMode
+		}
+		if len(target.Language) > 0 {
+			modeCfg.Language = target.Language
+		}
+		text, mode, err := p.renderHookMessage(&modeCfg, hook, releaseCtx)
+		if err != nil {
+			return notification{}, err
+		}
+		modeCfg.ParseMode = mode
+
+		rendered := n
+		rendered.rerender = nil
+		rendered.msg.Text = checkTemplateFormatting(&modeCfg, text, map[string]any{})
+		rendered.msg.ParseMode = mode
+		if n.kind == "success" {
+			rendered.fallbacks = p.successFallbacks(&modeCfg, releaseCtx)
+		}
+		return rendered, nil
+	}
+	return n
+}
+
+// parseModeName names a parse mode in messages, where "" is plain text.
+func parseModeName(parseMode string) string {
+	if parseMode == "" {
+		return "plain text"
+	}
+	return parseMode
+}
+
+// recordDryRunTarget adds what would have been sent to a target in shadow
+// mode to outputs["dry_run_targets"]: the text of the message, or the JSON
+// body of a raw payload.
+func recordDryRunTarget(outputs map[string]any, n notification, target Target) {
+	text := n.msg.Text
+	if n.raw != nil {
+		text = rawPayloadBody(n.raw)
+	}
+	shadowed, _ := outputs["dry_run_targets"].([]map[string]any)
+	outputs["dry_run_targets"] = append(shadowed, map[string]any{
+		"kind":    n.kind,
+		"chat_id": target.ChatID,
+		"text":    text,
+	})
+}
+
+// componentTargets returns the chats of chat_ids after the primary one and
+// the targets that receive releases of the configured component: those
+// without a components list, and those listing it.
+func (cfg *Config) componentTargets() []Target {
+	var targets []Target
+	for _, target := range slices.Concat(cfg.chatTargets, cfg.Targets) {
+		if len(target.Components) == 0 || slices.Contains(target.Components, cfg.Component) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// targetChatIDs lists the chats of the targets, for dry-run output.
+func targetChatIDs(targets []Target) []string {
+	chatIDs := make([]string, len(targets))
+	for i, target := range targets {
+		chatIDs[i] = target.ChatID
+	}
+	return chatIDs
+}
@@ -0,0 +1,597 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+const brandBotToken = "987654321:ZYXwvuTSRqpoNMLkjiHGFedcba987654321"
+
+func TestParseTargets(t *testing.T) {
+	t.Setenv("BRAND_BOT_TOKEN", brandBotToken)
+
+	html := "HTML"
+	got := parseTargets([]any{
+		map[string]any{"chat_id": "@brand", "bot_token_env": "BRAND_BOT_TOKEN"},
+		map[string]any{"chat_id": "-1001234567890@7", "bot_token": " 1:abc "},
+		map[string]any{"chat_id": "https://t.me/c/1234567890/9", "message_thread_id": "3", "components": []any{"payments"}, "language": "de"},
+		map[string]any{"chat_id": "@community", "always_dry_run": true, "parse_mode": "HTML", "labels": map[string]any{"audience": "community", "tier": 2}},
+	})
+	want := []Target{
+		{ChatID: "@brand", BotToken: brandBotToken, BotTokenEnv: "BRAND_BOT_TOKEN"},
+		{ChatID: "-1001234567890", MessageThreadID: 7, BotToken: "1:abc"},
+		{ChatID: "-1001234567890", MessageThreadID: 3, Components: []string{"payments"}, Language: []string{"de"}},
+		{ChatID: "@community", AlwaysDryRun: true, ParseMode: &html, Labels: map[string]string{"audience": "community", "tier": "2"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTargets() = %+v, want %+v", got, want)
+	}
+
+	if got := parseTargets("@brand"); got != nil {
+		t.Errorf("parseTargets(string) = %+v, want nil", got)
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	markdown := "Markdown"
+	tests := []struct {
+		name      string
+		target    Target
+		wantField string
+	}{
+		{"primary bot", Target{ChatID: "@brand"}, ""},
+		{"own bot", Target{ChatID: "@brand", BotToken: brandBotToken}, ""},
+		{"missing chat", Target{}, "chat_id"},
+		{"invalid chat", Target{ChatID: "not a chat"}, "chat_id"},
+		{"negative thread", Target{ChatID: "@brand", MessageThreadID: -1}, "message_thread_id"},
+		{"unset token env", Target{ChatID: "@brand", BotTokenEnv: "UNSET_BOT_TOKEN"}, "bot_token_env"},
+		{"invalid token", Target{ChatID: "@brand", BotToken: "abc"}, "bot_token"},
+		{"plain text", Target{ChatID: "@brand", ParseMode: new(string)}, ""},
+		{"invalid parse mode", Target{ChatID: "@brand", ParseMode: &markdown}, "parse_mode"},
+		{"language chain", Target{ChatID: "@brand", Language: []string{"pt-BR", "pt"}}, ""},
+		{"unsupported language", Target{ChatID: "@brand", Language: []string{"xx"}}, "language"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := validateTarget(tt.target)
+			if field != tt.wantField || (err != nil) != (tt.wantField != "") {
+				t.Errorf("validateTarget() = %q, %v; want field %q", field, err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestComponentTargets(t *testing.T) {
+	targets := []Target{
+		{ChatID: "@all"},
+		{ChatID: "@payments", Components: []string{"payments", "billing"}},
+		{ChatID: "@search", Components: []string{"search"}},
+	}
+
+	tests := []struct {
+		component string
+		want      []string
+	}{
+		{"", []string{"@all"}},
+		{"billing", []string{"@all", "@payments"}},
+		{"search", []string{"@all", "@search"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.component, func(t *testing.T) {
+			cfg := &Config{Component: tt.component, Targets: targets}
+			if got := targetChatIDs(cfg.componentTargets()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("componentTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatIDTargets(t *testing.T) {
+	tests := []struct {
+		name         string
+		chatID       string
+		threadID     int64
+		chatIDs      []string
+		wantChatID   string
+		wantThreadID int64
+		wantTargets  []Target
+	}{
+		{
+			name:       "chat_id only",
+			chatID:     "@news",
+			wantChatID: "@news",
+		},
+		{
+			name:         "first entry is the primary chat",
+			chatIDs:      []string{"-1001234567890@7", "@mirror", " @mirror "},
+			wantChatID:   "-1001234567890",
+			wantThreadID: 7,
+			wantTargets:  []Target{{ChatID: "@mirror"}},
+		},
+		{
+			name:        "entries after chat_id",
+			chatID:      "@news",
+			chatIDs:     []string{"@news", "@mirror", "https://t.me/c/1234567890/9", ""},
+			wantChatID:  "@news",
+			wantTargets: []Target{{ChatID: "@mirror"}, {ChatID: "-1001234567890", MessageThreadID: 9}},
+		},
+		{
+			name:         "same chat in another thread",
+			chatID:       "@forum",
+			threadID:     3,
+			chatIDs:      []string{"@forum@4"},
+			wantChatID:   "@forum",
+			wantThreadID: 3,
+			wantTargets:  []Target{{ChatID: "@forum", MessageThreadID: 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatID, threadID, targets := chatIDTargets(tt.chatID, tt.threadID, tt.chatIDs)
+			if chatID != tt.wantChatID || threadID != tt.wantThreadID {
+				t.Errorf("chatIDTargets() chat = %q@%d, want %q@%d", chatID, threadID, tt.wantChatID, tt.wantThreadID)
+			}
+			if !reflect.DeepEqual(targets, tt.wantTargets) {
+				t.Errorf("chatIDTargets() targets = %+v, want %+v", targets, tt.wantTargets)
+			}
+		})
+	}
+}
+
+func TestTargetConfigIsolatesBots(t *testing.T) {
+	p := &TelegramPlugin{}
+	cfg := &Config{BotToken: "123:abc", ChatID: "@news", CircuitBreakerThreshold: 1}
+
+	shared := cfg.targetConfig(Target{ChatID: "@mirror", BotToken: "123:abc"})
+	own := cfg.targetConfig(Target{ChatID: "@brand", BotToken: brandBotToken})
+	if shared.botPool != "" || own.botPool != brandBotToken || own.BotToken != brandBotToken {
+		t.Fatalf("targetConfig() pools = %q, %q", shared.botPool, own.botPool)
+	}
+
+	if mustHTTPClient(t, p, shared) != mustHTTPClient(t, p, cfg) {
+		t.Error("expected a target of the primary bot to share its client")
+	}
+	if mustHTTPClient(t, p, own) == mustHTTPClient(t, p, cfg) || mustHTTPClient(t, p, own) != mustHTTPClient(t, p, own) {
+		t.Error("expected a cached client of its own for a target with its own bot")
+	}
+	if p.circuitBreaker(own) == p.circuitBreaker(cfg) {
+		t.Error("expected a circuit breaker of its own for a target with its own bot")
+	}
+}
+
+func TestExecuteTargets(t *testing.T) {
+	var mu sync.Mutex
+	sent := map[string]string{} // chat ID to bot token
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		token := strings.TrimPrefix(strings.Split(r.URL.Path, "/")[1], "bot")
+		if msg.ChatID == "@gone" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was kicked"})
+			return
+		}
+		mu.Lock()
+		sent[msg.ChatID] = token
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token": "123:abc",
+			"chat_id":   "@news",
+			"targets": []any{
+				map[string]any{"chat_id": "@brand", "bot_token": brandBotToken},
+				map[string]any{"chat_id": "@mirror"},
+				map[string]any{"chat_id": "@gone"},
+			},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+
+	want := map[string]string{"@news": "123:abc", "@brand": brandBotToken, "@mirror": "123:abc"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+	failed := map[string]string{"@gone": "Forbidden: bot was kicked"}
+	if got := resp.Outputs["target_errors"]; !reflect.DeepEqual(got, failed) {
+		t.Errorf("target_errors = %v, want %v", got, failed)
+	}
+	if deliveries, _ := resp.Outputs["deliveries"].([]map[string]any); len(deliveries) != 4 {
+		t.Errorf("deliveries = %v, want the primary chat and 3 targets", deliveries)
+	}
+}
+
+func TestExecuteTargetLabels(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg.ChatID == "@gone" {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was kicked"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token": "123:abc",
+			"chat_id":   "@news",
+			"targets": []any{
+				map[string]any{"chat_id": "@eu", "labels": map[string]any{"region": "eu"}},
+				map[string]any{"chat_id": "@gone", "labels": map[string]any{"team": "core", "audience": "devs"}},
+			},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+
+	failed := map[string]string{"@gone": "Forbidden: bot was kicked [audience=devs, team=core]"}
+	if got := resp.Outputs["target_errors"]; !reflect.DeepEqual(got, failed) {
+		t.Errorf("target_errors = %v, want %v", got, failed)
+	}
+	deliveries, _ := resp.Outputs["deliveries"].([]map[string]any)
+	if len(deliveries) != 3 {
+		t.Fatalf("deliveries = %v, want the primary chat and 2 targets", deliveries)
+	}
+	if _, ok := deliveries[0]["labels"]; ok {
+		t.Errorf("deliveries[0] = %v, want no labels for the primary chat", deliveries[0])
+	}
+	if got := deliveries[1]["labels"]; !reflect.DeepEqual(got, map[string]string{"region": "eu"}) {
+		t.Errorf("deliveries[1] labels = %v, want region=eu", got)
+	}
+	if got := deliveries[2]["labels"]; !reflect.DeepEqual(got, map[string]string{"team": "core", "audience": "devs"}) {
+		t.Errorf("deliveries[2] labels = %v, want team and audience", got)
+	}
+}
+
+func TestValidateTargetLabels(t *testing.T) {
+	p := &TelegramPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"bot_token": "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789",
+		"chat_id":   "@myproject_news",
+		"targets": []any{
+			map[string]any{"chat_id": "@myproject_eu", "labels": map[string]any{"region": "eu"}},
+			map[string]any{"chat_id": "@myproject_us", "labels": map[string]any{"regions": []any{"us"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if resp.Valid || len(resp.Errors) != 1 || resp.Errors[0].Field != "targets[1].labels" {
+		t.Errorf("Validate() = %+v, want a targets[1].labels error", resp)
+	}
+}
+
+func TestExecuteChatIDs(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		if msg.ChatID == "@gone" {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was kicked"})
+			return
+		}
+		mu.Lock()
+		sent = append(sent, msg.ChatID)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token": "123:abc",
+			"chat_ids":  []any{"@news", "@gone", "@mirror"},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+
+	if want := []string{"@news", "@mirror"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+	deliveries, _ := resp.Outputs["deliveries"].([]map[string]any)
+	var results []string
+	for _, d := range deliveries {
+		results = append(results, fmt.Sprintf("%s:%v", d["chat_id"], d["ok"]))
+	}
+	if want := []string{"@news:true", "@gone:false", "@mirror:true"}; !reflect.DeepEqual(results, want) {
+		t.Errorf("deliveries = %v, want %v", results, want)
+	}
+}
+
+func TestExecuteChatIDsFirstChatFails(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		requested = append(requested, msg.ChatID)
+		mu.Unlock()
+		if msg.ChatID == "@gone" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was kicked"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":1}`)})
+	})
+
+	receipts := filepath.Join(t.TempDir(), "receipts.jsonl")
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":     "123:abc",
+			"chat_ids":      []any{"@gone", "@news", "@mirror"},
+			"receipts_file": receipts,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want failure for the first chat", resp, err)
+	}
+
+	if want := []string{"@gone", "@news", "@mirror"}; !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested = %v, want %v", requested, want)
+	}
+	deliveries, _ := resp.Outputs["deliveries"].([]map[string]any)
+	var results []string
+	for _, d := range deliveries {
+		results = append(results, fmt.Sprintf("%s:%v", d["chat_id"], d["ok"]))
+	}
+	if want := []string{"@gone:false", "@news:true", "@mirror:true"}; !reflect.DeepEqual(results, want) {
+		t.Errorf("deliveries = %v, want %v", results, want)
+	}
+	if got := readReceipts(t, receipts); len(got) != 2 {
+		t.Errorf("receipts = %+v, want one per delivered chat", got)
+	}
+}
+
+func TestExecuteDryRunTargets(t *testing.T) {
+	var sent []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		sent = append(sent, msg.ChatID)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":  "123:abc",
+			"chat_id":    "@news",
+			"parse_mode": "",
+			"template":   "Released {{.Version}}",
+			"targets": []any{
+				map[string]any{"chat_id": "@mirror"},
+				map[string]any{"chat_id": "@community", "always_dry_run": true},
+			},
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+
+	if want := []string{"@news", "@mirror"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+	want := []map[string]any{{"kind": "success", "chat_id": "@community", "text": "Released 1.0.0"}}
+	if got := resp.Outputs["dry_run_targets"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("dry_run_targets = %v, want %v", got, want)
+	}
+	if deliveries, _ := resp.Outputs["deliveries"].([]map[string]any); len(deliveries) != 2 {
+		t.Errorf("deliveries = %v, want the primary chat and the sent target", deliveries)
+	}
+}
+
+func TestExecuteTargetsConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		time.Sleep(5 * time.Millisecond)
+		if msg.ChatID == "@gone" {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was kicked"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":       "123:abc",
+			"chat_ids":        []any{"@news", "@eu", "@gone", "@us", "@asia"},
+			"max_concurrency": 2,
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+
+	deliveries, _ := resp.Outputs["deliveries"].([]map[string]any)
+	var results []string
+	for _, d := range deliveries {
+		results = append(results, fmt.Sprintf("%s:%v", d["chat_id"], d["ok"]))
+	}
+	if want := []string{"@news:true", "@eu:true", "@gone:false", "@us:true", "@asia:true"}; !reflect.DeepEqual(results, want) {
+		t.Errorf("deliveries = %v, want %v", results, want)
+	}
+	failed := map[string]string{"@gone": "Forbidden: bot was kicked"}
+	if got := resp.Outputs["target_errors"]; !reflect.DeepEqual(got, failed) {
+		t.Errorf("target_errors = %v, want %v", got, failed)
+	}
+}
+
+func TestTargetsByChat(t *testing.T) {
+	targets := []Target{
+		{ChatID: "-100123", MessageThreadID: 5},
+		{ChatID: "@us"},
+		{ChatID: "-100123", MessageThreadID: 6},
+		{ChatID: "@eu"},
+		{ChatID: "-100123", MessageThreadID: 7},
+	}
+	want := [][]int{{0, 2, 4}, {1}, {3}}
+	if got := targetsByChat(targets); !reflect.DeepEqual(got, want) {
+		t.Errorf("targetsByChat() = %v, want %v", got, want)
+	}
+}
+
+func TestExecuteTargetsSameChatInOrder(t *testing.T) {
+	var mu sync.Mutex
+	inFlight := map[string]int{}
+	var threads []int64
+	failedOnce := false
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		mu.Lock()
+		inFlight[msg.ChatID]++
+		if inFlight[msg.ChatID] > 1 {
+			t.Errorf("concurrent sends to %s", msg.ChatID)
+		}
+		if msg.ChatID == "-100123" {
+			threads = append(threads, msg.MessageThreadID)
+		}
+		// The second thread needs a retry, which must not let the third
+		// overtake it.
+		retry := msg.MessageThreadID == 6 && !failedOnce
+		failedOnce = failedOnce || retry
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight[msg.ChatID]--
+			mu.Unlock()
+		}()
+
+		time.Sleep(2 * time.Millisecond)
+		if retry {
+			w.WriteHeader(http.StatusBadGateway)
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: http.StatusBadGateway, Description: "Bad Gateway"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: json.RawMessage(`{"message_id":1}`)})
+	})
+
+	p := &TelegramPlugin{clock: newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":       "123:abc",
+			"chat_ids":        []any{"-100123@4", "-100123@5", "@us", "-100123@6", "@eu", "-100123@7"},
+			"max_concurrency": 4,
+			"max_retries":     1,
+			"state_file":      filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if _, ok := resp.Outputs["target_errors"]; ok {
+		t.Errorf("target_errors = %v", resp.Outputs["target_errors"])
+	}
+	if want := []int64{4, 5, 6, 6, 7}; !reflect.DeepEqual(threads, want) {
+		t.Errorf("threads = %v, want %v", threads, want)
+	}
+}
+
+func TestExecuteTargetParseMode(t *testing.T) {
+	sent := map[string]TelegramMessage{}
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var msg TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		sent[msg.ChatID] = msg
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token": "123:abc",
+			"chat_id":   "@news",
+			"targets": []any{
+				map[string]any{"chat_id": "@html_bot", "parse_mode": "HTML"},
+				map[string]any{"chat_id": "@plain_bot", "parse_mode": ""},
+				map[string]any{"chat_id": "@mirror"},
+				map[string]any{"chat_id": "@news_de", "language": "de"},
+				map[string]any{"chat_id": "@news_de_html", "language": []any{"de"}, "parse_mode": "HTML"},
+			},
+		},
+		Context: plugin.ReleaseContext{Version: "1.2.0", ReleaseType: "minor"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+
+	tests := []struct {
+		chatID    string
+		parseMode string
+		contains  string
+	}{
+		{"@news", "MarkdownV2", "*Release 1\\.2\\.0 Published\\!*"},
+		{"@html_bot", "HTML", "<b>Release 1.2.0 Published!</b>"},
+		{"@plain_bot", "", "Release 1.2.0 Published!"},
+		{"@mirror", "MarkdownV2", "*Release 1\\.2\\.0 Published\\!*"},
+		{"@news_de", "MarkdownV2", "*Release 1\\.2\\.0 veröffentlicht\\!*"},
+		{"@news_de_html", "HTML", "<b>Release 1.2.0 veröffentlicht!</b>"},
+	}
+	for _, tt := range tests {
+		msg := sent[tt.chatID]
+		if msg.ParseMode != tt.parseMode || !strings.Contains(msg.Text, tt.contains) {
+			t.Errorf("%s got %q in %q, want %q in %q", tt.chatID, msg.Text, msg.ParseMode, tt.contains, tt.parseMode)
+		}
+	}
+	if plain := sent["@plain_bot"].Text; strings.ContainsAny(plain, "*\\") {
+		t.Errorf("plain text message = %q, want no markup or escapes", plain)
+	}
+}
@@ -0,0 +1,66 @@
+package telegramplugintest
+
+import (
+	"maps"
+	"slices"
+)
+
+// examples are the canonical example configs, keyed by name.
+var examples = map[string]map[string]any{
+	// minimal announces releases in a public channel.
+	"minimal": {
+		"bot_token": Token,
+		"chat_id":   "@repo_releases",
+	},
+	// forum_topic announces releases in a topic of a forum supergroup.
+	"forum_topic": {
+		"bot_token":         Token,
+		"chat_id":           "-1001234567890",
+		"message_thread_id": 42,
+	},
+	// multiple_chats announces releases in several chats.
+	"multiple_chats": {
+		"bot_token": Token,
+		"chat_ids":  []any{"@repo_releases", "-1001234567890@42"},
+	},
+	// custom_template renders the announcement from a template.
+	"custom_template": {
+		"bot_token":  Token,
+		"chat_id":    "@repo_releases",
+		"parse_mode": "MarkdownV2",
+		"template":   "🚀 *{{escape .Version}}*\n{{escape .ReleaseNotes}}",
+	},
+	// resilient retries failed sends.
+	"resilient": {
+		"bot_token":                 Token,
+		"chat_id":                   "@repo_releases",
+		"max_retries":               3,
+		"retry_backoff_seconds":     1,
+		"retry_max_backoff_seconds": 5,
+	},
+}
+
+// ConfigNames returns the names of the example configs, sorted.
+func ConfigNames() []string {
+	return slices.Sorted(maps.Keys(examples))
+}
+
+// Config returns a copy of the example config name, or nil if there is no
+// such example. With a server, the config sends to it through api_url.
+func Config(name string, srv *Server) map[string]any {
+	example, ok := examples[name]
+	if !ok {
+		return nil
+	}
+	cfg := make(map[string]any, len(example)+1)
+	for key, value := range example {
+		if list, ok := value.([]any); ok {
+			value = slices.Clone(list)
+		}
+		cfg[key] = value
+	}
+	if srv != nil {
+		cfg["api_url"] = srv.URL()
+	}
+	return cfg
+}
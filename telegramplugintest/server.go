@@ -0,0 +1,215 @@
+// Package telegramplugintest provides a fake Telegram Bot API and example
+// configs for testing the Telegram plugin and code built around it, such as
+// Relicta integration tests and other notification plugins.
+//
+// The fake server answers every method with a successful response by
+// default. Tests script failures per method and inspect the requests the
+// server received:
+//
+//	srv := telegramplugintest.NewServer(t)
+//	srv.RateLimit("sendMessage", 1, 2)
+//	cfg := telegramplugintest.Config("minimal", srv)
+//	// run the plugin with cfg
+//	reqs := srv.Requests("sendMessage")
+package telegramplugintest
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Token is the bot token of the example configs. It has the format of a real
+// token, so it passes config validation.
+const Token = "123456789:ABCdefGHIjklMNOpqrsTUVwxyz123456789"
+
+// Response is a scripted Bot API response.
+type Response struct {
+	// OK reports whether the request succeeded.
+	OK bool
+	// ErrorCode and Description describe a failed request.
+	ErrorCode   int
+	Description string
+	// RetryAfter is the number of seconds a rate limited client should wait.
+	RetryAfter int
+	// MigrateToChatID is the supergroup a group was upgraded to.
+	MigrateToChatID int64
+	// Result is encoded as the result of a successful request. Nil results
+	// in a message with the next message ID.
+	Result any
+}
+
+// Error returns a failed response with code and description.
+func Error(code int, description string) Response {
+	return Response{ErrorCode: code, Description: description}
+}
+
+// Request is a request the fake server received.
+type Request struct {
+	// Method is the Bot API method, such as sendMessage.
+	Method string
+	// Token is the bot token from the request path.
+	Token string
+	// Params are the request parameters, decoded from JSON, a form, or a
+	// multipart upload. Uploaded files are kept in Files.
+	Params map[string]any
+	// Files maps the form fields of uploaded files to their contents.
+	Files map[string][]byte
+}
+
+// Text returns the text parameter of a sendMessage request.
+func (r Request) Text() string {
+	s, _ := r.Params["text"].(string)
+	return s
+}
+
+// Server is a fake Bot API server. It is safe for concurrent use.
+type Server struct {
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	scripts   map[string][]Response
+	requests  []Request
+	messageID int64
+}
+
+// NewServer starts a fake Bot API server that is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{scripts: make(map[string][]Response)}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+// URL returns the base URL of the server, for the api_url option.
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Respond queues responses for method. Each request to method takes the
+// next one; once the queue is empty, requests succeed again.
+func (s *Server) Respond(method string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[method] = append(s.scripts[method], responses...)
+}
+
+// RateLimit makes the next n requests to method fail with 429 Too Many
+// Requests and a retry_after of retryAfter seconds.
+func (s *Server) RateLimit(method string, n, retryAfter int) {
+	responses := make([]Response, n)
+	for i := range responses {
+		responses[i] = Response{
+			ErrorCode:   http.StatusTooManyRequests,
+			Description: "Too Many Requests: retry after " + strconv.Itoa(retryAfter),
+			RetryAfter:  retryAfter,
+		}
+	}
+	s.Respond(method, responses...)
+}
+
+// Requests returns the requests received for the given methods, in order.
+// Without methods it returns all requests.
+func (s *Server) Requests(methods ...string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Request
+	for _, req := range s.requests {
+		if len(methods) == 0 || slices.Contains(methods, req.Method) {
+			out = append(out, req)
+		}
+	}
+	return out
+}
+
+// Reset forgets the received requests and the queued responses.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.scripts = make(map[string][]Response)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	// Paths are /bot<token>/<method>.
+	token, method, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/bot"), "/")
+	req := Request{Method: method, Token: token, Params: map[string]any{}}
+	decodeParams(r, &req)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	resp := Response{OK: true}
+	if queue := s.scripts[method]; len(queue) > 0 {
+		resp, s.scripts[method] = queue[0], queue[1:]
+	}
+	if resp.OK && resp.Result == nil {
+		s.messageID++
+		resp.Result = map[string]any{"message_id": s.messageID}
+	}
+	s.mu.Unlock()
+
+	body := map[string]any{"ok": resp.OK}
+	if resp.OK {
+		body["result"] = resp.Result
+	} else {
+		body["error_code"] = resp.ErrorCode
+		body["description"] = resp.Description
+		params := map[string]any{}
+		if resp.RetryAfter > 0 {
+			params["retry_after"] = resp.RetryAfter
+		}
+		if resp.MigrateToChatID != 0 {
+			params["migrate_to_chat_id"] = resp.MigrateToChatID
+		}
+		if len(params) > 0 {
+			body["parameters"] = params
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !resp.OK && resp.ErrorCode != 0 {
+		w.WriteHeader(resp.ErrorCode)
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// decodeParams reads the parameters of r into req.
+func decodeParams(r *http.Request, req *Request) {
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "multipart/form-data":
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				return
+			}
+			data, _ := io.ReadAll(part)
+			if part.FileName() != "" {
+				if req.Files == nil {
+					req.Files = make(map[string][]byte)
+				}
+				req.Files[part.FormName()] = data
+			} else {
+				req.Params[part.FormName()] = string(data)
+			}
+		}
+	case "application/x-www-form-urlencoded":
+		data, _ := io.ReadAll(r.Body)
+		values, _ := url.ParseQuery(string(data))
+		for key := range values {
+			req.Params[key] = values.Get(key)
+		}
+	default:
+		_ = json.NewDecoder(r.Body).Decode(&req.Params)
+	}
+}
@@ -0,0 +1,112 @@
+package telegramplugintest
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// call posts params to method as JSON and decodes the response.
+func call(t *testing.T, srv *Server, method string, params map[string]any) (int, map[string]any) {
+	t.Helper()
+	body, _ := json.Marshal(params)
+	resp, err := http.Post(srv.URL()+"/bot"+Token+"/"+method, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, out
+}
+
+func TestServerScriptedResponses(t *testing.T) {
+	srv := NewServer(t)
+	srv.RateLimit("sendMessage", 1, 3)
+	srv.Respond("sendMessage", Error(400, "Bad Request: chat not found"))
+
+	tests := []struct {
+		name   string
+		status int
+		ok     bool
+		check  func(map[string]any) bool
+	}{
+		{"rate limited", 429, false, func(out map[string]any) bool {
+			params, _ := out["parameters"].(map[string]any)
+			return params["retry_after"] == float64(3)
+		}},
+		{"scripted error", 400, false, func(out map[string]any) bool {
+			return out["description"] == "Bad Request: chat not found"
+		}},
+		{"default success", 200, true, func(out map[string]any) bool {
+			result, _ := out["result"].(map[string]any)
+			return result["message_id"] == float64(1)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, out := call(t, srv, "sendMessage", map[string]any{"chat_id": "@repo_releases", "text": "hi"})
+			if status != tt.status || out["ok"] != tt.ok || !tt.check(out) {
+				t.Errorf("response = %d %v", status, out)
+			}
+		})
+	}
+
+	reqs := srv.Requests("sendMessage")
+	if len(reqs) != 3 || reqs[0].Token != Token || reqs[0].Text() != "hi" || reqs[0].Params["chat_id"] != "@repo_releases" {
+		t.Errorf("Requests() = %+v", reqs)
+	}
+	if len(srv.Requests("getMe")) != 0 {
+		t.Error("Requests(getMe) is not empty")
+	}
+	srv.Reset()
+	if len(srv.Requests()) != 0 {
+		t.Error("Requests() is not empty after Reset")
+	}
+}
+
+func TestServerMultipart(t *testing.T) {
+	srv := NewServer(t)
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("chat_id", "@repo_releases")
+	fw, _ := mw.CreateFormFile("document", "CHANGELOG.md")
+	_, _ = fw.Write([]byte("# 1.0.0"))
+	_ = mw.Close()
+
+	resp, err := http.Post(srv.URL()+"/bot"+Token+"/sendDocument", mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	reqs := srv.Requests("sendDocument")
+	if len(reqs) != 1 || reqs[0].Params["chat_id"] != "@repo_releases" || string(reqs[0].Files["document"]) != "# 1.0.0" {
+		t.Errorf("Requests() = %+v", reqs)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	srv := NewServer(t)
+	for _, name := range ConfigNames() {
+		cfg := Config(name, srv)
+		if cfg["bot_token"] != Token || cfg["api_url"] != srv.URL() {
+			t.Errorf("Config(%q) = %v", name, cfg)
+		}
+	}
+	if Config("unknown", nil) != nil {
+		t.Error("Config(unknown) is not nil")
+	}
+
+	cfg := Config("multiple_chats", nil)
+	cfg["chat_ids"].([]any)[0] = "@changed"
+	if again := Config("multiple_chats", nil); strings.Contains(again["chat_ids"].([]any)[0].(string), "changed") || again["api_url"] != nil {
+		t.Errorf("Config() returned a shared config: %v", again)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// loadTemplateFile reads the message template from a template_file path.
+// Relative paths are resolved against the working directory, which is the
+// repository root when relicta runs.
+func loadTemplateFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template_file: %w", err)
+	}
+	return string(data), nil
+}
+
+// validateTemplateFile reports whether path names a readable regular file.
+func validateTemplateFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("template file %q does not exist", path)
+		}
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("template file %q is not a regular file", path)
+	}
+	return nil
+}
+
+// templateSource returns the template configured inline, or read from file
+// when one is set.
+func templateSource(inline, file string) (string, error) {
+	if file == "" {
+		return inline, nil
+	}
+	return loadTemplateFile(file)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestValidateTemplateFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "release.tmpl")
+	if err := os.WriteFile(file, []byte("Release {{.Version}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"file", file, false},
+		{"missing", filepath.Join(dir, "missing.tmpl"), true},
+		{"directory", dir, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTemplateFile(tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("validateTemplateFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTemplateSyntaxErrors(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "release.tmpl")
+	if err := os.WriteFile(file, []byte("Release {{.Version}}\n{{range .Changes.Features}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &TelegramPlugin{}
+	resp, err := p.Validate(context.Background(), map[string]any{
+		"bot_token":      "123456789:ABCdefGHIjklMNOpqrsTUVwxyz",
+		"chat_id":        "-1001234567890",
+		"template_file":  file,
+		"error_template": "Failed\n  {{.Error | shout}}",
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	want := map[string]string{
+		"template_file":  file + ": line 2",
+		"error_template": "line 2, column 14",
+	}
+	for _, e := range resp.Errors {
+		if prefix, ok := want[e.Field]; ok {
+			if !strings.HasPrefix(e.Message, prefix) {
+				t.Errorf("%s error = %q, want prefix %q", e.Field, e.Message, prefix)
+			}
+			delete(want, e.Field)
+		}
+	}
+	for field := range want {
+		t.Errorf("Validate() errors = %v, want %s error", resp.Errors, field)
+	}
+}
+
+func TestExecuteTemplateFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "release.tmpl")
+	if err := os.WriteFile(file, []byte("Shipped {{.Version}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	config := map[string]any{
+		"bot_token":     "123:abc",
+		"chat_id":       "@test",
+		"parse_mode":    "",
+		"template_file": file,
+	}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  config,
+		Context: plugin.ReleaseContext{Version: "1.2.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+	}
+	if got.Text != "Shipped 1.2.0" {
+		t.Errorf("text = %q, want the rendered template file", got.Text)
+	}
+
+	config["template_file"] = filepath.Join(t.TempDir(), "missing.tmpl")
+	resp, err = p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook:    plugin.HookPostPublish,
+		Config:  config,
+		Context: plugin.ReleaseContext{Version: "1.2.0"},
+	})
+	if err != nil || resp.Success {
+		t.Fatalf("Execute() = %+v, %v; want failure for a missing file", resp, err)
+	}
+}
+
+func TestExecuteErrorTemplate(t *testing.T) {
+	var got TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	file := filepath.Join(t.TempDir(), "error.tmpl")
+	if err := os.WriteFile(file, []byte("💥 {{.Version}} failed on {{.Branch}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		config   map[string]any
+		wantText string
+		wantErr  bool
+	}{
+		{
+			name:     "inline",
+			config:   map[string]any{"error_template": "{{.Version}} broke {{.Branch}}"},
+			wantText: "1.2.0 broke main",
+		},
+		{
+			name:     "file",
+			config:   map[string]any{"error_template_file": file},
+			wantText: "💥 1.2.0 failed on main",
+		},
+		{
+			name:     "broken template falls back to the default message",
+			config:   map[string]any{"error_template": "{{.Missing}}"},
+			wantText: "Please check the CI logs",
+			wantErr:  true,
+		},
+	}
+
+	p := &TelegramPlugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]any{"bot_token": "123:abc", "chat_id": "@test", "parse_mode": ""}
+			maps.Copy(config, tt.config)
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookOnError,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.2.0", Branch: "main"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v; want success", resp, err)
+			}
+			if !strings.Contains(got.Text, tt.wantText) {
+				t.Errorf("text = %q, want to contain %q", got.Text, tt.wantText)
+			}
+			if _, ok := resp.Outputs["error_template_error"]; ok != tt.wantErr {
+				t.Errorf("error_template_error set = %v, want %v", ok, tt.wantErr)
+			}
+		})
+	}
+}
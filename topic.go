@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// topicErrorOutput records why the topic_name topic could not be resolved.
+var topicErrorOutput = registerDegradation("topic_error", describeError("topic unavailable"))
+
+// errorTopicErrorOutput records why the incidents topic could not be resolved.
+var errorTopicErrorOutput = registerDegradation("error_topic_error", describeError("error topic unavailable"))
+
+// topicKey identifies a named forum topic in a chat within the state file.
+func topicKey(chatID, name string) string {
+	return strings.Join([]string{"topic", chatID, name}, "|")
+}
+
+// errorThreadID returns the thread error notifications are posted to. An
+// explicit error_message_thread_id wins; otherwise the error_topic_name topic
+// is looked up in the state file and created on first use. Without either,
+// the regular message thread is used. In dry-run mode topics are not created
+// and 0 is returned for a topic that does not exist yet.
+func (p *TelegramPlugin) errorThreadID(ctx context.Context, cfg *Config, dryRun bool) (int64, error) {
+	if cfg.ErrorMessageThreadID != 0 {
+		return cfg.ErrorMessageThreadID, nil
+	}
+	if cfg.ErrorTopicName == "" {
+		return cfg.MessageThreadID, nil
+	}
+	return p.topicThreadID(ctx, cfg, cfg.ErrorTopicName, dryRun)
+}
+
+// applyTopicName posts the notification of hook to the topic_name forum
+// topic when no message thread is configured. Release announcements go to
+// the series_topic_name topic of their version instead when one is set. A
+// topic that cannot be created is reported in the topic_error output, and
+// the notification goes to the chat without a thread.
+func (p *TelegramPlugin) applyTopicName(ctx context.Context, cfg *Config, hook plugin.Hook, releaseCtx plugin.ReleaseContext, dryRun bool) {
+	if cfg.MessageThreadID != 0 || !cfg.notifies(hook) {
+		return
+	}
+	if hook == plugin.HookOnError && (cfg.ErrorMessageThreadID != 0 || cfg.ErrorTopicName != "") {
+		return
+	}
+	name := cfg.TopicName
+	if hook != plugin.HookOnError {
+		if series := seriesTopicName(cfg.SeriesTopicName, releaseCtx.Version); series != "" {
+			name = series
+		}
+	}
+	if name == "" {
+		return
+	}
+
+	threadID, err := p.topicThreadID(ctx, cfg, name, dryRun)
+	if err != nil {
+		cfg.topicError = errorText(err)
+		return
+	}
+	cfg.MessageThreadID = threadID
+}
+
+// topicThreadID returns the thread ID of the named forum topic in the
+// configured chat. The topic is looked up in the state file and created on
+// first use. In dry-run mode topics are not created and 0 is returned for a
+// topic that does not exist yet.
+func (p *TelegramPlugin) topicThreadID(ctx context.Context, cfg *Config, name string, dryRun bool) (int64, error) {
+	key := topicKey(cfg.ChatID, name)
+	state, err := loadState(cfg.StateFile)
+	if err != nil {
+		return 0, err
+	}
+	if id, ok := state.Topics[key]; ok {
+		return id, nil
+	}
+	if dryRun {
+		return 0, nil
+	}
+
+	topic, err := p.createForumTopic(ctx, cfg, cfg.ChatID, name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create topic %q: %w", name, err)
+	}
+	// The topic exists now, so post to it even if it could not be remembered.
+	_ = p.updateState(cfg.StateFile, func(s *pluginState) {
+		if s.Topics == nil {
+			s.Topics = make(map[string]int64)
+		}
+		s.Topics[key] = topic.MessageThreadID
+	})
+	return topic.MessageThreadID, nil
+}
+
+// seriesTopicName returns the forum topic name of the release series of
+// version: format with {major} and {minor} replaced by its version numbers,
+// or "" when format is empty or version has no major version.
+func seriesTopicName(format, version string) string {
+	if format == "" {
+		return ""
+	}
+	core, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(version), "v"), "-")
+	core, _, _ = strings.Cut(core, "+")
+	parts := strings.Split(core, ".")
+	if _, err := strconv.Atoi(parts[0]); err != nil {
+		return ""
+	}
+	minor := "0"
+	if len(parts) > 1 {
+		minor = parts[1]
+	}
+	return strings.NewReplacer("{major}", parts[0], "{minor}", minor).Replace(format)
+}
+
+// validateSeriesTopicName validates a series_topic_name format.
+func validateSeriesTopicName(format string) error {
+	if format == "" {
+		return nil
+	}
+	if !strings.Contains(format, "{major}") {
+		return fmt.Errorf("series topic name must contain {major}")
+	}
+	return validateTopicName(seriesTopicName(format, "0.0.0"))
+}
+
+// validateTopicName validates a forum topic name.
+func validateTopicName(name string) error {
+	if n := len([]rune(name)); n > 128 {
+		return fmt.Errorf("topic name must be at most 128 characters, got %d", n)
+	}
+	return nil
+}
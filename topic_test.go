@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestErrorThreadID(t *testing.T) {
+	var created int
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/createForumTopic") {
+			t.Errorf("unexpected call to %s", r.URL.Path)
+		}
+		created++
+		result, _ := json.Marshal(TelegramForumTopic{MessageThreadID: 77, Name: "Incidents"})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+	})
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	tests := []struct {
+		name     string
+		cfg      Config
+		dryRun   bool
+		expected int64
+		created  int
+	}{
+		{"regular thread", Config{MessageThreadID: 5}, false, 5, 0},
+		{"explicit thread wins", Config{MessageThreadID: 5, ErrorMessageThreadID: 9, ErrorTopicName: "Incidents"}, false, 9, 0},
+		{"dry run does not create", Config{ErrorTopicName: "Incidents"}, true, 0, 0},
+		{"created on first use", Config{ErrorTopicName: "Incidents"}, false, 77, 1},
+		{"remembered", Config{ErrorTopicName: "Incidents"}, false, 77, 1},
+		{"remembered in dry run", Config{ErrorTopicName: "Incidents"}, true, 77, 1},
+	}
+
+	p := &TelegramPlugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.BotToken = "123:abc"
+			cfg.ChatID = "-1001234567890"
+			cfg.StateFile = stateFile
+
+			got, err := p.errorThreadID(context.Background(), &cfg, tt.dryRun)
+			if err != nil {
+				t.Fatalf("errorThreadID() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("errorThreadID() = %d, want %d", got, tt.expected)
+			}
+			if created != tt.created {
+				t.Errorf("created %d topics, want %d", created, tt.created)
+			}
+		})
+	}
+}
+
+func TestExecuteErrorTopic(t *testing.T) {
+	var sent TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/createForumTopic") {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: not enough rights to create a topic"})
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookOnError,
+		Config: map[string]any{
+			"bot_token":         "123:abc",
+			"chat_id":           "-1001234567890",
+			"message_thread_id": 5,
+			"error_topic_name":  "Incidents",
+			"state_file":        filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+	if sent.MessageThreadID != 5 {
+		t.Errorf("message_thread_id = %d, want fallback to 5", sent.MessageThreadID)
+	}
+	if resp.Outputs["error_topic_error"] == nil {
+		t.Errorf("Outputs = %v, want error_topic_error", resp.Outputs)
+	}
+}
+
+func TestApplyTopicName(t *testing.T) {
+	var created []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		name, _ := req["name"].(string)
+		created = append(created, name)
+		result, _ := json.Marshal(TelegramForumTopic{MessageThreadID: 88, Name: name})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+	})
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	tests := []struct {
+		name     string
+		cfg      Config
+		hook     plugin.Hook
+		dryRun   bool
+		expected int64
+		created  []string
+	}{
+		{"no topic", Config{MessageThreadID: 5}, plugin.HookPostPublish, false, 5, nil},
+		{"explicit thread wins", Config{MessageThreadID: 5, TopicName: "Releases"}, plugin.HookPostPublish, false, 5, nil},
+		{"dry run does not create", Config{TopicName: "Releases"}, plugin.HookPostPublish, true, 0, nil},
+		{"disabled hook", Config{TopicName: "Releases"}, plugin.HookPostVersion, false, 0, nil},
+		{"error topic wins", Config{TopicName: "Releases", ErrorTopicName: "Incidents"}, plugin.HookOnError, false, 0, nil},
+		{"created on first use", Config{TopicName: "Releases"}, plugin.HookPostPublish, false, 88, []string{"Releases"}},
+		{"remembered", Config{TopicName: "Releases"}, plugin.HookOnError, false, 88, []string{"Releases"}},
+		{"series topic", Config{TopicName: "Releases", SeriesTopicName: "Releases v{major}.x"}, plugin.HookPostPublish, false, 88, []string{"Releases", "Releases v2.x"}},
+		{"series topic remembered", Config{SeriesTopicName: "Releases v{major}.x"}, plugin.HookPostPublish, false, 88, []string{"Releases", "Releases v2.x"}},
+		{"errors skip the series topic", Config{SeriesTopicName: "Releases v{major}.x"}, plugin.HookOnError, false, 0, []string{"Releases", "Releases v2.x"}},
+	}
+
+	p := &TelegramPlugin{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.BotToken = "123:abc"
+			cfg.ChatID = "-1001234567890"
+			cfg.StateFile = stateFile
+			cfg.NotifyOn = map[string]bool{"post_publish": true, "on_error": true}
+
+			p.applyTopicName(context.Background(), &cfg, tt.hook, plugin.ReleaseContext{Version: "2.3.1"}, tt.dryRun)
+			if cfg.MessageThreadID != tt.expected || cfg.topicError != "" {
+				t.Errorf("thread = %d (%q), want %d", cfg.MessageThreadID, cfg.topicError, tt.expected)
+			}
+			if !slices.Equal(created, tt.created) {
+				t.Errorf("created topics %v, want %v", created, tt.created)
+			}
+		})
+	}
+}
+
+func TestSeriesTopicName(t *testing.T) {
+	tests := []struct {
+		format   string
+		version  string
+		expected string
+	}{
+		{"", "2.3.1", ""},
+		{"Releases v{major}.x", "2.3.1", "Releases v2.x"},
+		{"Releases v{major}.x", "v10.0.0-rc.1", "Releases v10.x"},
+		{"v{major}.{minor}", "1.4.0+build.7", "v1.4"},
+		{"Releases v{major}.x", "next", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.version, func(t *testing.T) {
+			if got := seriesTopicName(tt.format, tt.version); got != tt.expected {
+				t.Errorf("seriesTopicName(%q, %q) = %q, want %q", tt.format, tt.version, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecuteTopicNameError(t *testing.T) {
+	var sent TelegramMessage
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/createForumTopic") {
+			_ = json.NewEncoder(w).Encode(TelegramResponse{OK: false, ErrorCode: 400, Description: "Bad Request: the chat is not a forum"})
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+	})
+
+	p := &TelegramPlugin{}
+	resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+		Hook: plugin.HookPostPublish,
+		Config: map[string]any{
+			"bot_token":  "123:abc",
+			"chat_id":    "-1001234567890",
+			"topic_name": "Releases",
+			"state_file": filepath.Join(t.TempDir(), "state.json"),
+		},
+		Context: plugin.ReleaseContext{Version: "1.0.0"},
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("Execute() = %+v, %v", resp, err)
+	}
+	if sent.ChatID != "-1001234567890" || sent.MessageThreadID != 0 {
+		t.Errorf("sent to %s@%d, want the chat without a thread", sent.ChatID, sent.MessageThreadID)
+	}
+	if resp.Outputs["topic_error"] == nil {
+		t.Errorf("Outputs = %v, want topic_error", resp.Outputs)
+	}
+}
+
+func TestExecuteGeneralTopic(t *testing.T) {
+	tests := []struct {
+		name     string
+		threadID any
+	}{
+		{"sentinel", "general"},
+		{"explicit ID", 1},
+		{"chat ID suffix", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]any
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/sendMessage") {
+					t.Errorf("unexpected call to %s", r.URL.Path)
+				}
+				_ = json.NewDecoder(r.Body).Decode(&sent)
+				_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true})
+			})
+
+			config := map[string]any{
+				"bot_token":  "123:abc",
+				"chat_id":    "-1001234567890",
+				"topic_name": "Releases",
+				"state_file": filepath.Join(t.TempDir(), "state.json"),
+			}
+			if tt.threadID != nil {
+				config["message_thread_id"] = tt.threadID
+			} else {
+				config["chat_id"] = "-1001234567890@general"
+			}
+			p := &TelegramPlugin{}
+			resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+				Hook:    plugin.HookPostPublish,
+				Config:  config,
+				Context: plugin.ReleaseContext{Version: "1.0.0"},
+			})
+			if err != nil || !resp.Success {
+				t.Fatalf("Execute() = %+v, %v", resp, err)
+			}
+			if sent["chat_id"] != "-1001234567890" {
+				t.Errorf("chat_id = %v, want -1001234567890", sent["chat_id"])
+			}
+			if threadID, ok := sent["message_thread_id"]; ok {
+				t.Errorf("message_thread_id = %v, want it omitted for the General topic", threadID)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultPollTimeout is how long a getUpdates request waits for
+	// updates. It stays below the default 30s request timeout; shorter
+	// http.timeout_seconds settings shorten the poll to pollTimeoutMargin
+	// below the request timeout.
+	defaultPollTimeout = 25 * time.Second
+	// pollTimeoutMargin leaves time for the response of a long poll to
+	// arrive before the request times out.
+	pollTimeoutMargin = 5 * time.Second
+	// minPollBackoff and maxPollBackoff bound the wait after a failed poll.
+	minPollBackoff = time.Second
+	maxPollBackoff = time.Minute
+	// ackTimeout bounds the request confirming handled updates on shutdown.
+	ackTimeout = 5 * time.Second
+)
+
+// errUpdatesConflict is returned when another consumer receives the bot's
+// updates, either a webhook or another getUpdates loop.
+var errUpdatesConflict = errors.New("another consumer is receiving updates for this bot token")
+
+// updatePoller long-polls getUpdates for the interactive features. Updates
+// are confirmed to the Bot API by advancing the offset once handled, so an
+// update whose handler failed is delivered again on the next run.
+type updatePoller struct {
+	plugin *TelegramPlugin
+	cfg    *Config
+	// allowedUpdates limits the update kinds Telegram sends, e.g.
+	// ["callback_query"]. Nil keeps the bot's previous setting.
+	allowedUpdates []string
+	// timeout is the long-poll timeout; zero uses defaultPollTimeout.
+	timeout time.Duration
+	// offset is the next update ID to request.
+	offset int64
+}
+
+// run polls until ctx is done, handle returns an error, or another consumer
+// takes over the updates. It returns ctx.Err() on cancellation, the handler
+// error, or an error wrapping errUpdatesConflict. Handled updates are
+// confirmed before returning.
+func (u *updatePoller) run(ctx context.Context, handle func(TelegramUpdate) error) error {
+	timeout := u.timeout
+	if timeout <= 0 {
+		timeout = defaultPollTimeout
+	}
+	if limit := u.cfg.HTTP.requestTimeout() - pollTimeoutMargin; timeout > limit {
+		timeout = max(limit, time.Second)
+	}
+	clk := u.plugin.clockOrDefault()
+	confirmed := u.offset
+	defer func() {
+		if u.offset != confirmed {
+			u.confirm()
+		}
+	}()
+
+	backoff := minPollBackoff
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		updates, err := u.plugin.getUpdates(ctx, u.cfg, u.offset, timeout, u.allowedUpdates)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict {
+				return fmt.Errorf("%w: %s", errUpdatesConflict, apiErr.Description)
+			}
+			wait := backoff
+			if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+				wait = time.Duration(apiErr.RetryAfter) * time.Second
+			}
+			if err := sleepContext(ctx, clk, wait); err != nil {
+				return err
+			}
+			backoff = min(backoff*2, maxPollBackoff)
+			continue
+		}
+		backoff = minPollBackoff
+		// The request carried the previous offset, which confirmed the
+		// updates handled before it.
+		confirmed = u.offset
+
+		for _, update := range updates {
+			if update.UpdateID < u.offset {
+				continue
+			}
+			if err := handle(update); err != nil {
+				return err
+			}
+			u.offset = update.UpdateID + 1
+		}
+	}
+}
+
+// confirm acknowledges the handled updates so they are not delivered again.
+// It runs on shutdown, so it does not use the cancelled poll context.
+func (u *updatePoller) confirm() {
+	ctx, cancel := context.WithTimeout(context.Background(), ackTimeout)
+	defer cancel()
+	_, _ = u.plugin.getUpdates(ctx, u.cfg, u.offset, 0, u.allowedUpdates)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// updatesRequest is a decoded getUpdates request.
+type updatesRequest struct {
+	Offset         int64    `json:"offset"`
+	Timeout        int      `json:"timeout"`
+	AllowedUpdates []string `json:"allowed_updates"`
+}
+
+// useUpdatesServer serves the given responses to successive getUpdates
+// calls, repeating the last one, and returns the recorded requests.
+func useUpdatesServer(t *testing.T, responses ...TelegramResponse) func() []updatesRequest {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []updatesRequest
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var req updatesRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		defer mu.Unlock()
+		resp := responses[min(len(requests), len(responses)-1)]
+		requests = append(requests, req)
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	return func() []updatesRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]updatesRequest(nil), requests...)
+	}
+}
+
+// updatesResult returns a successful getUpdates response with the given IDs.
+func updatesResult(ids ...int64) TelegramResponse {
+	updates := make([]TelegramUpdate, len(ids))
+	for i, id := range ids {
+		updates[i] = TelegramUpdate{UpdateID: id}
+	}
+	result, _ := json.Marshal(updates)
+	return TelegramResponse{OK: true, Result: result}
+}
+
+func TestUpdatePollerConfirmsOnCancel(t *testing.T) {
+	requests := useUpdatesServer(t, updatesResult(10, 11), updatesResult())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	poller := &updatePoller{
+		plugin:         &TelegramPlugin{},
+		cfg:            &Config{BotToken: "123:abc"},
+		allowedUpdates: []string{"callback_query"},
+	}
+
+	var handled []int64
+	err := poller.run(ctx, func(update TelegramUpdate) error {
+		handled = append(handled, update.UpdateID)
+		if update.UpdateID == 11 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("run() error = %v, want context.Canceled", err)
+	}
+	if !reflect.DeepEqual(handled, []int64{10, 11}) {
+		t.Errorf("handled = %v, want [10 11]", handled)
+	}
+
+	want := []updatesRequest{
+		{Offset: 0, Timeout: 25, AllowedUpdates: []string{"callback_query"}},
+		{Offset: 12, Timeout: 0, AllowedUpdates: []string{"callback_query"}},
+	}
+	if got := requests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %+v, want %+v", got, want)
+	}
+}
+
+func TestUpdatePollerShortRequestTimeout(t *testing.T) {
+	requests := useUpdatesServer(t, updatesResult(10))
+
+	poller := &updatePoller{
+		plugin: &TelegramPlugin{},
+		cfg:    &Config{BotToken: "123:abc", HTTP: HTTPConfig{TimeoutSeconds: 15}},
+	}
+	failure := errors.New("stop")
+	if err := poller.run(context.Background(), func(TelegramUpdate) error { return failure }); !errors.Is(err, failure) {
+		t.Fatalf("run() error = %v, want the handler error", err)
+	}
+	// The long poll stays below the 15s request timeout.
+	if got := requests(); len(got) == 0 || got[0].Timeout != 10 {
+		t.Errorf("requests = %+v, want a 10s long poll", got)
+	}
+}
+
+func TestUpdatePollerHandlerError(t *testing.T) {
+	requests := useUpdatesServer(t, updatesResult(10, 11))
+
+	poller := &updatePoller{plugin: &TelegramPlugin{}, cfg: &Config{BotToken: "123:abc"}}
+	failure := errors.New("handler failed")
+	err := poller.run(context.Background(), func(update TelegramUpdate) error {
+		if update.UpdateID == 11 {
+			return failure
+		}
+		return nil
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("run() error = %v, want the handler error", err)
+	}
+	// Update 10 is confirmed, 11 is delivered again next time.
+	if poller.offset != 11 {
+		t.Errorf("offset = %d, want 11", poller.offset)
+	}
+	if got := requests(); len(got) != 2 || got[1].Offset != 11 {
+		t.Errorf("requests = %+v, want a confirmation at offset 11", got)
+	}
+}
+
+func TestUpdatePollerConflict(t *testing.T) {
+	requests := useUpdatesServer(t, TelegramResponse{
+		OK:          false,
+		ErrorCode:   http.StatusConflict,
+		Description: "Conflict: terminated by other getUpdates request",
+	})
+
+	poller := &updatePoller{plugin: &TelegramPlugin{}, cfg: &Config{BotToken: "123:abc"}}
+	err := poller.run(context.Background(), func(TelegramUpdate) error { return nil })
+	if !errors.Is(err, errUpdatesConflict) {
+		t.Fatalf("run() error = %v, want errUpdatesConflict", err)
+	}
+	if got := requests(); len(got) != 1 {
+		t.Errorf("made %d requests, want 1 without retrying", len(got))
+	}
+}
+
+func TestUpdatePollerBackoff(t *testing.T) {
+	useUpdatesServer(t,
+		TelegramResponse{OK: false, ErrorCode: http.StatusTooManyRequests, Description: "Too Many Requests", Parameters: &ResponseParameters{RetryAfter: 7}},
+		TelegramResponse{OK: false, ErrorCode: http.StatusBadGateway, Description: "Bad Gateway"},
+		TelegramResponse{OK: false, ErrorCode: http.StatusBadGateway, Description: "Bad Gateway"},
+		updatesResult(1),
+	)
+
+	clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	poller := &updatePoller{plugin: &TelegramPlugin{clock: clk}, cfg: &Config{BotToken: "123:abc"}}
+	err := poller.run(ctx, func(TelegramUpdate) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("run() error = %v, want context.Canceled", err)
+	}
+	want := []time.Duration{7 * time.Second, 2 * time.Second, 4 * time.Second}
+	if got := clk.Slept(); !reflect.DeepEqual(got, want) {
+		t.Errorf("slept = %v, want %v", got, want)
+	}
+}
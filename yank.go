@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+// rememberAnnouncementErrorOutput records why an announcement could not be
+// remembered for yanking.
+var rememberAnnouncementErrorOutput = registerDegradation("remember_announcement_error", describeError("announcement not remembered"))
+
+const (
+	// yankedPrefix is prepended to the announcement of a yanked release.
+	// It holds no characters that need escaping in any parse mode.
+	yankedPrefix = "⚠️ YANKED\n\n"
+	// maxRememberedVersions is how many versions the state file keeps
+	// announcements of for yanking.
+	maxRememberedVersions = 50
+)
+
+// announcement is a success announcement remembered in the state file so
+// the release can be marked as yanked later.
+type announcement struct {
+	ChatID    string    `json:"chat_id"`
+	ThreadID  int64     `json:"message_thread_id,omitempty"`
+	MessageID int64     `json:"message_id"`
+	Text      string    `json:"text"`
+	ParseMode string    `json:"parse_mode,omitempty"`
+	SentAt    time.Time `json:"sent_at"`
+	// Yanked reports whether the announcement was already marked as yanked.
+	Yanked bool `json:"yanked,omitempty"`
+}
+
+// newAnnouncement returns the announcement of n sent to chatID, with the
+// text and parse mode it was rendered in, so it can be edited later.
+func newAnnouncement(n notification, chatID string, messageID int64, sentAt time.Time) announcement {
+	a := announcement{
+		ChatID:    chatID,
+		ThreadID:  n.msg.MessageThreadID,
+		MessageID: messageID,
+		Text:      n.msg.Text,
+		ParseMode: n.msg.ParseMode,
+		SentAt:    sentAt.UTC(),
+	}
+	if n.raw != nil {
+		a.Text, _ = n.raw["text"].(string)
+		a.ParseMode, _ = n.raw["parse_mode"].(string)
+		a.ThreadID, _ = parseThreadID(n.raw["message_thread_id"])
+	}
+	return a
+}
+
+// rememberAnnouncement records the announcement of version in the state
+// file, forgetting the oldest versions beyond maxRememberedVersions.
+func (p *TelegramPlugin) rememberAnnouncement(cfg *Config, version string, a announcement) error {
+	return p.updateState(cfg.StateFile, func(s *pluginState) {
+		if s.Announcements == nil {
+			s.Announcements = make(map[string]announcement)
+		}
+		s.Announcements[version] = a
+		if n := len(s.Announcements) - maxRememberedVersions; n > 0 {
+			versions := slices.SortedFunc(maps.Keys(s.Announcements), func(a, b string) int {
+				return s.Announcements[a].SentAt.Compare(s.Announcements[b].SentAt)
+			})
+			for _, v := range versions[:n] {
+				delete(s.Announcements, v)
+			}
+		}
+	})
+}
+
+// yankRelease marks the remembered announcement of yank_version as yanked
+// by prepending yankedPrefix, and replies to it with yank_reason when one is
+// set. It replaces the notification of the hook. Yanking an announcement
+// that is already marked does nothing, so every hook of a run can carry the
+// same config.
+func (p *TelegramPlugin) yankRelease(ctx context.Context, cfg *Config, dryRun bool) (*plugin.ExecuteResponse, error) {
+	state, err := loadState(cfg.StateFile)
+	if err != nil {
+		return &plugin.ExecuteResponse{Success: false, Error: errorText(err)}, nil
+	}
+	a, ok := state.Announcements[cfg.YankVersion]
+	if !ok {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("no announcement of version %s is remembered; enable remember_announcements before announcing releases", cfg.YankVersion),
+		}, nil
+	}
+
+	outputs := map[string]any{
+		"yanked_version": cfg.YankVersion,
+		"chat_id":        a.ChatID,
+		"message_id":     a.MessageID,
+	}
+	switch {
+	case a.Yanked:
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("Release %s is already marked as yanked", cfg.YankVersion),
+			Outputs: outputs,
+		}, nil
+	case dryRun:
+		return &plugin.ExecuteResponse{
+			Success: true,
+			Message: fmt.Sprintf("Would mark release %s as yanked", cfg.YankVersion),
+			Outputs: outputs,
+		}, nil
+	}
+
+	msgCfg := *cfg
+	msgCfg.ChatID, msgCfg.MessageThreadID = a.ChatID, a.ThreadID
+	edit := TelegramMessage{ChatID: a.ChatID, Text: yankedPrefix + a.Text, ParseMode: a.ParseMode}
+	if err := p.editMessageText(ctx, &msgCfg, edit, a.MessageID); err != nil && !isMessageNotModifiedError(err) {
+		return &plugin.ExecuteResponse{
+			Success: false,
+			Error:   fmt.Sprintf("failed to mark release %s as yanked: %s", cfg.YankVersion, errorText(err)),
+			Outputs: outputs,
+		}, nil
+	}
+	// The edit went through, so the follow-up must not be posted again
+	// even if this could not be remembered.
+	_ = p.updateState(cfg.StateFile, func(s *pluginState) {
+		if a, ok := s.Announcements[cfg.YankVersion]; ok {
+			a.Yanked = true
+			s.Announcements[cfg.YankVersion] = a
+		}
+	})
+
+	if cfg.YankReason != "" {
+		// The reason is sent as plain text, so it needs no escaping.
+		followUp := TelegramMessage{
+			ChatID:          a.ChatID,
+			Text:            fmt.Sprintf("⚠️ Release %s was yanked: %s", cfg.YankVersion, cfg.YankReason),
+			MessageThreadID: a.ThreadID,
+			ReplyParameters: &ReplyParameters{MessageID: a.MessageID, AllowSendingWithoutReply: true},
+		}
+		messageID, err := p.deliver(ctx, &msgCfg, followUp)
+		if err != nil {
+			outputs["yank_reason_error"] = errorText(err)
+		} else if messageID != 0 {
+			outputs["yank_reason_message_id"] = messageID
+		}
+	}
+
+	return &plugin.ExecuteResponse{
+		Success: true,
+		Message: fmt.Sprintf("Marked release %s as yanked", cfg.YankVersion),
+		Outputs: outputs,
+	}, nil
+}
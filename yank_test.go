@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/relicta-tech/relicta-plugin-sdk/plugin"
+)
+
+func TestExecuteYank(t *testing.T) {
+	var calls []string
+	var edited, reason map[string]any
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		method := path.Base(r.URL.Path)
+		calls = append(calls, method)
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		id := int64(42)
+		switch method {
+		case "editMessageText":
+			edited = req
+		case "sendMessage":
+			if req["reply_parameters"] != nil {
+				reason, id = req, 43
+			}
+		}
+		result, _ := json.Marshal(TelegramSentMessage{MessageID: id})
+		_ = json.NewEncoder(w).Encode(TelegramResponse{OK: true, Result: result})
+	})
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	p := &TelegramPlugin{clock: newFakeClock(time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC))}
+	execute := func(config map[string]any, dryRun bool) *plugin.ExecuteResponse {
+		t.Helper()
+		calls = nil
+		config["bot_token"] = "123:abc"
+		config["chat_id"] = "-1001234567890@7"
+		config["state_file"] = stateFile
+		resp, err := p.Execute(context.Background(), plugin.ExecuteRequest{
+			Hook:    plugin.HookPostPublish,
+			Config:  config,
+			Context: plugin.ReleaseContext{Version: "1.2.0"},
+			DryRun:  dryRun,
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return resp
+	}
+
+	if resp := execute(map[string]any{"yank_version": "1.2.0"}, false); resp.Success {
+		t.Errorf("Execute() = %+v, want a failure for an unknown announcement", resp)
+	}
+
+	execute(map[string]any{"template": "Release {{escape .Version}}", "remember_announcements": true}, false)
+
+	yank := map[string]any{"yank_version": "1.2.0", "yank_reason": "corrupted artifacts"}
+	if resp := execute(yank, true); !resp.Success || len(calls) != 0 {
+		t.Errorf("dry run Execute() = %+v with calls %v", resp, calls)
+	}
+
+	resp := execute(yank, false)
+	if !resp.Success || !slices.Equal(calls, []string{"editMessageText", "sendMessage"}) {
+		t.Fatalf("Execute() = %+v with calls %v", resp, calls)
+	}
+	if edited["message_id"] != float64(42) || edited["text"] != "⚠️ YANKED\n\nRelease 1\\.2\\.0" || edited["parse_mode"] != "MarkdownV2" {
+		t.Errorf("edit = %v, want the announcement marked as yanked", edited)
+	}
+	if reason["text"] != "⚠️ Release 1.2.0 was yanked: corrupted artifacts" || reason["message_thread_id"] != float64(7) || reason["parse_mode"] != nil {
+		t.Errorf("reason = %v, want a plain text reply in the thread", reason)
+	}
+	if resp.Outputs["yank_reason_message_id"] != int64(43) {
+		t.Errorf("Outputs = %v", resp.Outputs)
+	}
+
+	if resp := execute(yank, false); !resp.Success || len(calls) != 0 {
+		t.Errorf("second Execute() = %+v with calls %v, want nothing to do", resp, calls)
+	}
+}